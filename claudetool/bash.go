@@ -77,7 +77,9 @@ func (b *BashTool) Tool() *llm.Tool {
 		Name:        bashName,
 		Description: strings.TrimSpace(bashDescription),
 		InputSchema: llm.MustSchema(bashInputSchema),
-		Run:         b.Run,
+		// Commands run after the patches before them in a response, and see the files they edit
+		Serialize: WorkspaceSerialize,
+		Run:       b.Run,
 	}
 }
 
@@ -419,6 +421,12 @@ func autodetectPackageManager() string {
 	return ""
 }
 
+// installVerdict is the LLM's judgment of whether to install a missing tool
+type installVerdict struct {
+	Approved bool   `json:"approved" description:"Whether the tool is legitimate, clearly non-harmful, commonly used, and installable with the package manager"`
+	Package  string `json:"package,omitempty" description:"The package that provides the tool, if approved"`
+}
+
 // installTool attempts to install a single missing tool using LLM validation and system package manager.
 func (b *BashTool) installTool(ctx context.Context, cmd string) error {
 	slog.InfoContext(ctx, "attempting to install tool", "tool", cmd)
@@ -440,8 +448,7 @@ func (b *BashTool) installTool(ctx context.Context, cmd string) error {
 
 Command: %s
 
-- YES: Respond ONLY with the package name used to install it
-- NO or UNSURE: Respond ONLY with the word NO`, packageManager, cmd)
+Approve it only if the answer to all of these is yes, and give the package name used to install it.`, packageManager, cmd)
 
 	req := &llm.Request{
 		Messages: []llm.Message{{
@@ -454,22 +461,16 @@ Command: %s
 		}},
 	}
 
-	resp, err := llmService.Do(ctx, req)
+	verdict, err := llm.GenerateStruct[installVerdict](ctx, llmService, req)
 	if err != nil {
 		return fmt.Errorf("failed to validate tool with LLM: %w", err)
 	}
-
-	if len(resp.Content) == 0 {
-		return fmt.Errorf("empty response from LLM for tool validation")
-	}
-
-	response := strings.TrimSpace(resp.Content[0].Text)
-	if response == "NO" || response == "UNSURE" {
-		slog.InfoContext(ctx, "tool installation declined by LLM", "tool", cmd, "response", response)
+	if !verdict.Approved {
+		slog.InfoContext(ctx, "tool installation declined by LLM", "tool", cmd)
 		return fmt.Errorf("tool %s not approved for installation", cmd)
 	}
 
-	packageName := strings.TrimSpace(response)
+	packageName := strings.TrimSpace(verdict.Package)
 	if packageName == "" {
 		return fmt.Errorf("no package name provided for tool %s", cmd)
 	}
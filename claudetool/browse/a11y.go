@@ -0,0 +1,189 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// axeCoreURL is where axe-core is downloaded from on first use; a var so tests can serve their own
+var axeCoreURL = "https://cdn.jsdelivr.net/npm/axe-core@4.10.2/axe.min.js"
+
+// axeCoreSource caches the downloaded axe-core script
+var (
+	axeCoreSource      string
+	axeCoreSourceMutex sync.Mutex
+)
+
+// axeImpacts are axe-core's impact levels, most severe first
+var axeImpacts = []string{"critical", "serious", "moderate", "minor"}
+
+// maxA11yNodesPerRule bounds the offending elements reported for each violated rule
+const maxA11yNodesPerRule = 10
+
+// loadAxeCore returns the axe-core source, downloading it once
+func loadAxeCore(ctx context.Context) (string, error) {
+	axeCoreSourceMutex.Lock()
+	defer axeCoreSourceMutex.Unlock()
+	if axeCoreSource != "" {
+		return axeCoreSource, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, axeCoreURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download axe-core: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download axe-core from %s: %s", axeCoreURL, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to download axe-core: %w", err)
+	}
+	axeCoreSource = string(data)
+	return axeCoreSource, nil
+}
+
+// a11yAuditJS runs axe-core on the elements matching context (or the whole document) and
+// returns its violations, keeping at most maxNodes offending elements per rule
+const a11yAuditJS = `async function(context, tags, maxNodes) {
+	const options = {resultTypes: ['violations']};
+	if (tags.length) options.runOnly = {type: 'tag', values: tags};
+	const results = await axe.run(context || document, options);
+	return results.violations.map(v => ({
+		id: v.id,
+		impact: v.impact || 'minor',
+		help: v.help,
+		help_url: v.helpUrl,
+		count: v.nodes.length,
+		nodes: v.nodes.slice(0, maxNodes).map(n => ({
+			selector: n.target.map(t => Array.isArray(t) ? t.join(' >>> ') : t).join(' >>> '),
+			html: n.html.slice(0, 200),
+			summary: n.failureSummary || '',
+		})),
+	}));
+}`
+
+// a11yViolation is a violated axe-core rule
+type a11yViolation struct {
+	ID      string `json:"id"`
+	Impact  string `json:"impact"`
+	Help    string `json:"help"`
+	HelpURL string `json:"help_url"`
+	// Count is the number of offending elements, of which at most maxA11yNodesPerRule are listed
+	Count int `json:"count"`
+	Nodes []struct {
+		// Selector is the element's CSS selector; " >>> " separates iframe and shadow root boundaries
+		Selector string `json:"selector"`
+		HTML     string `json:"html"`
+		Summary  string `json:"summary"`
+	} `json:"nodes"`
+}
+
+// A11yAuditTool definition
+type a11yAuditInput struct {
+	Selector string   `json:"selector,omitempty" description:"CSS selector of the part of the page to audit (default: the whole page)"`
+	Tags     []string `json:"tags,omitempty" description:"Only run rules with these axe-core tags, e.g. [\"wcag2a\", \"wcag2aa\"] (default: all rules)"`
+	Timeout  string   `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 30s)"`
+}
+
+// NewA11yAuditTool creates a tool for auditing the current page's accessibility with axe-core
+func (b *BrowseTools) NewA11yAuditTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_a11y_audit",
+		Description: `Audit the current page's accessibility with axe-core (missing labels, low contrast, bad ARIA, etc.).
+Returns violations grouped by impact (critical, serious, moderate, minor) with the offending elements' selectors and how to fix them.`,
+		InputSchema: llm.SchemaFor[a11yAuditInput](),
+		Run:         b.a11yAuditRun,
+	}
+}
+
+func (b *BrowseTools) a11yAuditRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input a11yAuditInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Tags == nil {
+		input.Tags = []string{}
+	}
+	timeout := 30 * time.Second
+	if input.Timeout != "" {
+		timeout = parseTimeout(input.Timeout)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, timeout)
+	defer cancel()
+
+	source, err := loadAxeCore(timeoutCtx)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	var violations []a11yViolation
+	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var loaded bool
+		if err := chromedp.Evaluate(`typeof axe !== 'undefined'`, &loaded).Do(ctx); err != nil {
+			return err
+		}
+		if !loaded {
+			// Evaluating the script directly is not subject to the page's Content-Security-Policy
+			_, exp, err := runtime.Evaluate(source).Do(ctx)
+			if err != nil {
+				return err
+			}
+			if exp != nil {
+				return fmt.Errorf("failed to load axe-core: %s", exp.Error())
+			}
+		}
+		return callWithArgs(a11yAuditJS, []any{input.Selector, input.Tags, maxA11yNodesPerRule}, true, &violations).Do(ctx)
+	}))
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if len(violations) == 0 {
+		return llm.ToolOut{LLMContent: llm.TextContent("No accessibility violations found")}
+	}
+
+	type impactGroup struct {
+		Impact     string          `json:"impact"`
+		Violations []a11yViolation `json:"violations"`
+	}
+	var grouped []impactGroup
+	var counts []string
+	for _, impact := range axeImpacts {
+		g := impactGroup{Impact: impact}
+		for _, v := range violations {
+			if v.Impact == impact {
+				g.Violations = append(g.Violations, v)
+			}
+		}
+		if len(g.Violations) > 0 {
+			grouped = append(grouped, g)
+			counts = append(counts, fmt.Sprintf("%d %s", len(g.Violations), impact))
+		}
+	}
+	data, err := json.Marshal(grouped)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal violations: %w", err)
+	}
+	return jsonToolOut("a11y_violations", fmt.Sprintf("%d accessibility rules violated (%s)",
+		len(violations), strings.Join(counts, ", ")), data)
+}
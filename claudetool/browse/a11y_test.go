@@ -0,0 +1,63 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestA11yAudit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// A stand-in for axe-core that reports each unlabeled input
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript")
+		w.Write([]byte(`window.axe = {run: async (context, options) => ({violations: [
+			{id: 'label', impact: 'critical', help: 'Form elements must have labels', helpUrl: 'https://example.com/label',
+				nodes: Array.from(document.querySelectorAll('input:not([aria-label])')).map(el => ({target: ['#' + el.id], html: el.outerHTML, failureSummary: 'no label'}))},
+			{id: 'region', help: 'Content should be in landmarks', helpUrl: 'https://example.com/region', nodes: []},
+		]})};`))
+	}))
+	defer server.Close()
+	oldURL, oldSource := axeCoreURL, axeCoreSource
+	axeCoreURL, axeCoreSource = server.URL, ""
+	t.Cleanup(func() { axeCoreURL, axeCoreSource = oldURL, oldSource })
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: `data:text/html,<input id="a"><input id="b" aria-label="B">`})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.a11yAuditRun(ctx, []byte(`{}`))
+	if out.Error != nil {
+		t.Fatalf("a11yAuditRun error: %v", out.Error)
+	}
+	text := out.LLMContent[0].Text
+	if !strings.Contains(text, "2 accessibility rules violated (1 critical, 1 minor)") {
+		t.Errorf("unexpected summary: %s", text)
+	}
+	if !strings.Contains(text, `"selector":"#a"`) || strings.Contains(text, `"selector":"#b"`) {
+		t.Errorf("expected only #a to be reported, got: %s", text)
+	}
+	if strings.Index(text, `"critical"`) > strings.Index(text, `"minor"`) {
+		t.Errorf("expected critical violations first, got: %s", text)
+	}
+}
@@ -0,0 +1,79 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/imageutil"
+)
+
+// autoScreenshotMaxDimension keeps automatic screenshots small, since every action adds one
+const autoScreenshotMaxDimension = 800
+
+// SetAutoScreenshot makes navigation, clicks, typing, and other page actions attach a
+// downscaled screenshot of the viewport to their output
+func (b *BrowseTools) SetAutoScreenshot(enabled bool) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.autoScreenshot = enabled
+}
+
+// actionToolOut is toolOutWithDownloads for actions that change the page, attaching a
+// screenshot of the result if automatic screenshots are enabled
+func (b *BrowseTools) actionToolOut(message string) llm.ToolOut {
+	out := b.toolOutWithDownloads(message)
+	b.mux.Lock()
+	enabled := b.autoScreenshot
+	b.mux.Unlock()
+	if !enabled {
+		return out
+	}
+
+	content, err := b.autoScreenshotContent()
+	if err != nil {
+		out.LLMContent = append(out.LLMContent, llm.StringContent(fmt.Sprintf("(automatic screenshot failed: %v)", err)))
+		return out
+	}
+	out.LLMContent = append(out.LLMContent, content...)
+	return out
+}
+
+// autoScreenshotContent captures the viewport as a small JPEG and returns it with its saved path
+func (b *BrowseTools) autoScreenshotContent() ([]llm.Content, error) {
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return nil, err
+	}
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, 5*time.Second)
+	defer cancel()
+
+	var data []byte
+	if err := chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		data, err = page.CaptureScreenshot().WithFormat(page.CaptureScreenshotFormatJpeg).WithQuality(70).Do(ctx)
+		return err
+	})); err != nil {
+		return nil, err
+	}
+	id := b.SaveScreenshot(data, "jpeg")
+	if id == "" {
+		return nil, fmt.Errorf("failed to save screenshot")
+	}
+
+	resized, _, _, err := imageutil.ResizeImageMode(data, autoScreenshotMaxDimension, imageutil.ResizeText)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resize screenshot: %w", err)
+	}
+	img, err := llm.ImageContent(resized)
+	if err != nil {
+		return nil, err
+	}
+	return []llm.Content{
+		llm.StringContent(fmt.Sprintf("Screenshot after the action (saved as %s):", GetScreenshotPath(id, "jpeg"))),
+		img,
+	}, nil
+}
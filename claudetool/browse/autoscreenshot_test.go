@@ -0,0 +1,45 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAutoScreenshot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	tools.SetAutoScreenshot(true)
+
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<button>Go</button>"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+	if len(toolOut.LLMContent) != 3 || toolOut.LLMContent[2].MediaType != "image/jpeg" {
+		t.Fatalf("expected the navigation output to end with a JPEG screenshot, got %d items", len(toolOut.LLMContent))
+	}
+	if !strings.Contains(toolOut.LLMContent[1].Text, "Screenshot after the action") {
+		t.Errorf("unexpected screenshot caption: %s", toolOut.LLMContent[1].Text)
+	}
+
+	// Reading the page does not take screenshots
+	evalInput, _ := json.Marshal(evalInput{Expression: "1"})
+	if out := tools.evalRun(ctx, evalInput); len(out.LLMContent) != 1 {
+		t.Errorf("expected eval output without a screenshot, got %d items", len(out.LLMContent))
+	}
+}
@@ -0,0 +1,165 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// validBindingName matches JavaScript identifiers usable as a global function name
+var validBindingName = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// maxBindingCalls is how many unread calls are kept per function exposed by the tool
+const maxBindingCalls = 100
+
+// Bind exposes a global function name(payload) to every page, including after navigations
+// and browser restarts. Each call from page JavaScript invokes fn with the string payload.
+// fn runs on the browser's event loop, so it must not block.
+func (b *BrowseTools) Bind(name string, fn func(payload string)) error {
+	if !validBindingName.MatchString(name) {
+		return fmt.Errorf("invalid function name %q", name)
+	}
+
+	b.bindingsMutex.Lock()
+	b.bindings[name] = fn
+	b.bindingsMutex.Unlock()
+
+	b.mux.Lock()
+	browserCtx := b.browserCtx
+	b.mux.Unlock()
+	if browserCtx == nil {
+		// Added when the browser starts
+		return nil
+	}
+	return chromedp.Run(browserCtx, runtime.AddBinding(name))
+}
+
+// addBindings adds every registered binding to a newly started browser
+func (b *BrowseTools) addBindings(ctx context.Context) error {
+	b.bindingsMutex.Lock()
+	defer b.bindingsMutex.Unlock()
+	for name := range b.bindings {
+		if err := runtime.AddBinding(name).Do(ctx); err != nil {
+			return fmt.Errorf("failed to add binding %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// handleBindingCalled dispatches a call from page JavaScript to its Go function
+func (b *BrowseTools) handleBindingCalled(e *runtime.EventBindingCalled) {
+	b.bindingsMutex.Lock()
+	fn := b.bindings[e.Name]
+	b.bindingsMutex.Unlock()
+	if fn != nil {
+		fn(e.Payload)
+	}
+}
+
+// recordBindingCall buffers a call to a function exposed by browser_expose_function
+func (b *BrowseTools) recordBindingCall(name, payload string) {
+	b.bindingsMutex.Lock()
+	defer b.bindingsMutex.Unlock()
+	calls := append(b.bindingCalls[name], payload)
+	if len(calls) > maxBindingCalls {
+		calls = calls[len(calls)-maxBindingCalls:]
+	}
+	b.bindingCalls[name] = calls
+	b.bindingCond.Broadcast()
+}
+
+// takeBindingCalls returns and clears the buffered calls to name, waiting until
+// there is at least one or ctx is done
+func (b *BrowseTools) takeBindingCalls(ctx context.Context, name string) []string {
+	stop := context.AfterFunc(ctx, func() {
+		b.bindingsMutex.Lock()
+		b.bindingCond.Broadcast()
+		b.bindingsMutex.Unlock()
+	})
+	defer stop()
+
+	b.bindingsMutex.Lock()
+	defer b.bindingsMutex.Unlock()
+	for len(b.bindingCalls[name]) == 0 && ctx.Err() == nil {
+		b.bindingCond.Wait()
+	}
+	calls := b.bindingCalls[name]
+	delete(b.bindingCalls, name)
+	return calls
+}
+
+// ExposeFunctionTool definition
+type exposeFunctionInput struct {
+	Name   string `json:"name" description:"Global function name, a JavaScript identifier"`
+	Action string `json:"action,omitempty" enum:"expose,read" description:"expose the function, or read payloads it has received (default: expose)"`
+	Wait   string `json:"wait,omitempty" description:"For read: how long to wait for a call if none are buffered, as a Go duration string (default: 0s)"`
+}
+
+// NewExposeFunctionTool creates a tool for receiving data from page JavaScript
+func (b *BrowseTools) NewExposeFunctionTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_expose_function",
+		Description: `Expose a global function window[name](payload) to page JavaScript, on every page including after navigations.
+Calls are buffered (up to 100 per function); page code should pass a string, e.g. JSON.stringify(data).
+Use action read to get and clear the buffered payloads, optionally waiting for the first one, instead of polling with browser_eval.`,
+		InputSchema: llm.SchemaFor[exposeFunctionInput](),
+		Run:         b.exposeFunctionRun,
+	}
+}
+
+func (b *BrowseTools) exposeFunctionRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input exposeFunctionInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if !validBindingName.MatchString(input.Name) {
+		return llm.ErrorfToolOut("invalid function name %q", input.Name)
+	}
+
+	switch input.Action {
+	case "", "expose":
+		if _, err := b.GetBrowserContext(); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		name := input.Name
+		if err := b.Bind(name, func(payload string) { b.recordBindingCall(name, payload) }); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("Exposed window.%s(payload) to page JavaScript", name))}
+
+	case "read":
+		b.bindingsMutex.Lock()
+		_, exposed := b.bindings[input.Name]
+		b.bindingsMutex.Unlock()
+		if !exposed {
+			return llm.ErrorfToolOut("function %q has not been exposed", input.Name)
+		}
+		var wait time.Duration
+		if input.Wait != "" {
+			var err error
+			if wait, err = time.ParseDuration(input.Wait); err != nil {
+				return llm.ErrorfToolOut("invalid wait: %w", err)
+			}
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, wait)
+		defer cancel()
+		calls := b.takeBindingCalls(waitCtx, input.Name)
+		if len(calls) == 0 {
+			return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("No calls to %s", input.Name))}
+		}
+		data, err := json.Marshal(calls)
+		if err != nil {
+			return llm.ErrorfToolOut("failed to marshal calls: %w", err)
+		}
+		return jsonToolOut("payloads", fmt.Sprintf("%d calls to %s", len(calls), input.Name), data)
+
+	default:
+		return llm.ErrorfToolOut("unsupported action %q: must be expose or read", input.Action)
+	}
+}
@@ -0,0 +1,81 @@
+package browse
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindValidatesName(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, name := range []string{"", "1abc", "a-b", "a.b"} {
+		if err := tools.Bind(name, func(string) {}); err == nil {
+			t.Errorf("Bind(%q) succeeded, want error", name)
+		}
+	}
+	// Binding before the browser starts only registers the function
+	if err := tools.Bind("report", func(string) {}); err != nil {
+		t.Errorf("Bind before start error: %v", err)
+	}
+}
+
+func TestBindingCallBuffer(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for i := range maxBindingCalls + 5 {
+		tools.recordBindingCall("f", strings.Repeat("x", i))
+	}
+	calls := tools.takeBindingCalls(ctx, "f")
+	if len(calls) != maxBindingCalls || calls[0] != strings.Repeat("x", 5) {
+		t.Errorf("Expected the last %d calls, got %d starting with %q", maxBindingCalls, len(calls), calls[0])
+	}
+
+	// Nothing buffered: returns when ctx is done
+	expired, cancel := context.WithTimeout(ctx, 0)
+	defer cancel()
+	if calls := tools.takeBindingCalls(expired, "f"); len(calls) != 0 {
+		t.Errorf("Expected no calls, got %v", calls)
+	}
+
+	// A waiting reader is woken by a call
+	done := make(chan []string)
+	go func() {
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		done <- tools.takeBindingCalls(waitCtx, "g")
+	}()
+	tools.recordBindingCall("g", "hello")
+	if calls := <-done; !slices.Equal(calls, []string{"hello"}) {
+		t.Errorf("Expected [hello], got %v", calls)
+	}
+}
+
+func TestExposeFunctionRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, tc := range []struct{ input, want string }{
+		{`{`, "invalid input"},
+		{`{"name": "bad name"}`, "invalid function name"},
+		{`{"name": "f", "action": "read"}`, "has not been exposed"},
+		{`{"name": "f", "action": "call"}`, "unsupported action"},
+	} {
+		out := tools.exposeFunctionRun(ctx, []byte(tc.input))
+		if out.Error == nil || !strings.Contains(out.Error.Error(), tc.want) {
+			t.Errorf("exposeFunctionRun(%s) error = %v, want %q", tc.input, out.Error, tc.want)
+		}
+	}
+}
@@ -2,21 +2,29 @@
 package browse
 
 import (
+	"bytes"
+	"cmp"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"image"
 	"log"
-	"net/http"
+	"math"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/tracing"
 	"github.com/chromedp/chromedp"
 	"github.com/google/uuid"
 	"shelley.exe.dev/llm"
@@ -35,6 +43,9 @@ const ConsoleLogsDir = "/tmp/shelley-console-logs"
 // ConsoleLogSizeThreshold is the size in bytes above which console logs are written to a file
 const ConsoleLogSizeThreshold = 1024
 
+// DefaultEvalResultLimit is the default maximum size in bytes of a browser_eval result returned inline
+const DefaultEvalResultLimit = ConsoleLogSizeThreshold
+
 // DefaultIdleTimeout is how long to wait before shutting down an idle browser
 const DefaultIdleTimeout = 30 * time.Minute
 
@@ -50,7 +61,21 @@ type DownloadInfo struct {
 
 // BrowseTools contains all browser tools and manages a shared browser instance
 type BrowseTools struct {
-	ctx              context.Context
+	ctx context.Context
+	// sessionID namespaces screenshots and downloads; empty for a standalone instance
+	sessionID string
+	// pool supplies pre-launched browsers, if set
+	pool *BrowserPool
+	// policy restricts which URLs may be loaded; nil allows all
+	policy *NavigationPolicy
+	// stealth makes the browser look like desktop Chrome to bot detection
+	stealth bool
+	// polite enforces robots.txt and per-host delays on navigation; nil disables
+	polite *politeness
+	// autoScreenshot attaches a screenshot to the output of actions that change the page
+	autoScreenshot bool
+	// launch configures the command line of launched browsers
+	launch           LaunchOptions
 	allocCtx         context.Context
 	allocCancel      context.CancelFunc
 	browserCtx       context.Context
@@ -64,39 +89,75 @@ type BrowseTools struct {
 	consoleLogsMutex sync.Mutex
 	maxConsoleLogs   int
 	// Idle timeout management
-	idleTimeout time.Duration
-	idleTimer   *time.Timer
-	// Max image dimension for resizing (0 means use default)
-	maxImageDimension int
+	idleTimeout  time.Duration
+	idleTimer    *time.Timer
+	idleDeadline time.Time
 	// Download tracking
 	downloads      map[string]*DownloadInfo // keyed by GUID
 	downloadsMutex sync.Mutex
 	downloadCond   *sync.Cond
+	// In-progress Chrome trace, if any
+	trace      *traceSession
+	traceMutex sync.Mutex
+	// Default JavaScript context of each frame, for evaluating inside iframes
+	frameContexts      map[cdp.FrameID]runtime.ExecutionContextID
+	frameContextsMutex sync.Mutex
+	// Go functions exposed to page JavaScript, and calls buffered for browser_expose_function
+	bindings      map[string]func(payload string)
+	bindingCalls  map[string][]string
+	bindingsMutex sync.Mutex
+	bindingCond   *sync.Cond
+	// JavaScript run in every new document
+	initScripts      []string
+	initScriptsMutex sync.Mutex
+	// Snapshots saved by browser_save_state, by name
+	states      map[string]*pageState
+	statesMutex sync.Mutex
+	// Network requests in flight, and recently finished ones for browser_wait_for_request
+	pendingRequests  map[network.RequestID]*finishedRequest
+	finishedRequests []*finishedRequest
+	finishedCount    int
+	requestsMutex    sync.Mutex
+	requestsCond     *sync.Cond
+	// Canned responses served by browser_mock_response, oldest first
+	mocks      []*mockRule
+	mocksMutex sync.Mutex
+	// Memory readings recorded by browser_memory, by mark name
+	memoryMarks      map[string]*memorySample
+	memoryMarksMutex sync.Mutex
 }
 
 // NewBrowseTools creates a new set of browser automation tools.
 // idleTimeout is how long to wait before shutting down an idle browser (0 uses default).
-// maxImageDimension is the max pixel dimension for images (0 means unlimited).
-func NewBrowseTools(ctx context.Context, idleTimeout time.Duration, maxImageDimension int) *BrowseTools {
+// Images are returned at full size; the llm package fits them to the provider's limits.
+func NewBrowseTools(ctx context.Context, idleTimeout time.Duration) *BrowseTools {
 	if idleTimeout <= 0 {
 		idleTimeout = DefaultIdleTimeout
 	}
-	for _, dir := range []string{ScreenshotDir, DownloadDir, ConsoleLogsDir} {
+	for _, dir := range []string{ScreenshotDir, DownloadDir, ConsoleLogsDir, TraceDir, HeapSnapshotDir} {
 		if err := os.MkdirAll(dir, 0o755); err != nil {
 			log.Printf("Failed to create directory %s: %v", dir, err)
 		}
 	}
 
 	bt := &BrowseTools{
-		ctx:               ctx,
-		screenshots:       make(map[string]time.Time),
-		consoleLogs:       make([]*runtime.EventConsoleAPICalled, 0),
-		maxConsoleLogs:    100,
-		maxImageDimension: maxImageDimension,
-		idleTimeout:       idleTimeout,
-		downloads:         make(map[string]*DownloadInfo),
+		ctx:             ctx,
+		screenshots:     make(map[string]time.Time),
+		consoleLogs:     make([]*runtime.EventConsoleAPICalled, 0),
+		maxConsoleLogs:  100,
+		idleTimeout:     idleTimeout,
+		downloads:       make(map[string]*DownloadInfo),
+		frameContexts:   make(map[cdp.FrameID]runtime.ExecutionContextID),
+		policy:          DefaultNavigationPolicy(),
+		bindings:        make(map[string]func(string)),
+		bindingCalls:    make(map[string][]string),
+		states:          make(map[string]*pageState),
+		pendingRequests: make(map[network.RequestID]*finishedRequest),
+		memoryMarks:     make(map[string]*memorySample),
 	}
 	bt.downloadCond = sync.NewCond(&bt.downloadsMutex)
+	bt.bindingCond = sync.NewCond(&bt.bindingsMutex)
+	bt.requestsCond = sync.NewCond(&bt.requestsMutex)
 	return bt
 }
 
@@ -118,24 +179,20 @@ func (b *BrowseTools) GetBrowserContext() (context.Context, error) {
 		}
 	}
 
-	// Initialize a new browser
-	opts := chromedp.DefaultExecAllocatorOptions[:]
-	opts = append(opts, chromedp.NoSandbox)
-	opts = append(opts, chromedp.Flag("--disable-dbus", true))
-	opts = append(opts, chromedp.WSURLReadTimeout(60*time.Second))
-	// Disable WebAuthn to prevent segfaults on FIDO/WebAuthn sites (issue #78)
-	// Must include all default disabled features plus WebAuthentication
-	// (chromedp v0.14.1 defaults: site-per-process,Translate,BlinkGenPropertyTrees)
-	opts = append(opts, chromedp.Flag("disable-features",
-		"site-per-process,Translate,BlinkGenPropertyTrees,WebAuthentication"))
-
-	allocCtx, allocCancel := chromedp.NewExecAllocator(b.ctx, opts...)
-	browserCtx, browserCancel := chromedp.NewContext(
-		allocCtx,
-		chromedp.WithLogf(log.Printf),
-		chromedp.WithErrorf(log.Printf),
-		chromedp.WithBrowserOption(chromedp.WithDialTimeout(60*time.Second)),
-	)
+	// Take a pre-launched browser if one is ready, otherwise start one
+	var lb *launchedBrowser
+	if b.pool != nil {
+		lb = b.pool.take()
+	}
+	if lb == nil {
+		var err error
+		lb, err = launchBrowser(b.ctx, b.launch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	allocCtx, allocCancel := lb.allocCtx, lb.allocCancel
+	browserCtx, browserCancel := lb.browserCtx, lb.browserCancel
 
 	// Set up event listeners for console logs and downloads
 	chromedp.ListenTarget(browserCtx, func(ev any) {
@@ -146,31 +203,66 @@ func (b *BrowseTools) GetBrowserContext() (context.Context, error) {
 			b.handleDownloadWillBegin(e)
 		case *browser.EventDownloadProgress:
 			b.handleDownloadProgress(e)
+		case *tracing.EventDataCollected:
+			b.handleTraceDataCollected(e)
+		case *tracing.EventTracingComplete:
+			b.handleTraceComplete(e)
+		case *runtime.EventExecutionContextCreated:
+			b.handleExecutionContextCreated(e)
+		case *runtime.EventExecutionContextDestroyed:
+			b.handleExecutionContextDestroyed(e)
+		case *runtime.EventExecutionContextsCleared:
+			b.handleExecutionContextsCleared()
+		case *runtime.EventBindingCalled:
+			b.handleBindingCalled(e)
+		case *network.EventRequestWillBeSent:
+			b.handleRequestWillBeSent(e)
+		case *network.EventResponseReceived:
+			b.handleResponseReceived(e)
+		case *network.EventLoadingFinished:
+			b.finishRequest(e.RequestID, "")
+		case *network.EventLoadingFailed:
+			b.finishRequest(e.RequestID, e.ErrorText)
 		}
 	})
 
-	// Start the browser
-	if err := chromedp.Run(browserCtx); err != nil {
+	// Configure download behavior to allow downloads and emit events
+	if err := chromedp.Run(browserCtx,
+		browser.SetDownloadBehavior(browser.SetDownloadBehaviorBehaviorAllowAndName).
+			WithDownloadPath(b.downloadDir()).
+			WithEventsEnabled(true),
+	); err != nil {
+		browserCancel()
 		allocCancel()
-		return nil, fmt.Errorf("failed to start browser (please apt get chromium or equivalent): %w", err)
+		return nil, fmt.Errorf("failed to configure download behavior: %w", err)
 	}
 
-	// Set default viewport size to 1280x720 (16:9 widescreen)
-	if err := chromedp.Run(browserCtx, chromedp.EmulateViewport(1280, 720)); err != nil {
+	if b.stealth {
+		if err := chromedp.Run(browserCtx, chromedp.ActionFunc(applyStealth)); err != nil {
+			browserCancel()
+			allocCancel()
+			return nil, err
+		}
+	}
+
+	if b.launch.WebRTC == WebRTCDisabled {
+		if err := chromedp.Run(browserCtx, chromedp.ActionFunc(disableWebRTC)); err != nil {
+			browserCancel()
+			allocCancel()
+			return nil, err
+		}
+	}
+
+	if err := chromedp.Run(browserCtx, chromedp.ActionFunc(b.addBindings), chromedp.ActionFunc(b.addInitScripts)); err != nil {
 		browserCancel()
 		allocCancel()
-		return nil, fmt.Errorf("failed to set default viewport: %w", err)
+		return nil, err
 	}
 
-	// Configure download behavior to allow downloads and emit events
-	if err := chromedp.Run(browserCtx,
-		browser.SetDownloadBehavior(browser.SetDownloadBehaviorBehaviorAllowAndName).
-			WithDownloadPath(DownloadDir).
-			WithEventsEnabled(true),
-	); err != nil {
+	if err := b.interceptRequests(browserCtx, b.policy); err != nil {
 		browserCancel()
 		allocCancel()
-		return nil, fmt.Errorf("failed to configure download behavior: %w", err)
+		return nil, fmt.Errorf("failed to intercept requests: %w", err)
 	}
 
 	b.allocCtx = allocCtx
@@ -183,12 +275,68 @@ func (b *BrowseTools) GetBrowserContext() (context.Context, error) {
 	return b.browserCtx, nil
 }
 
+// launchedBrowser is a started browser and the cancel functions that shut it down
+type launchedBrowser struct {
+	allocCtx      context.Context
+	allocCancel   context.CancelFunc
+	browserCtx    context.Context
+	browserCancel context.CancelFunc
+}
+
+// disabledFeatures are the Chrome features turned off in launched browsers.
+// WebAuthn is disabled to prevent segfaults on FIDO/WebAuthn sites (issue #78);
+// the rest are chromedp v0.14.1's defaults, which the flag replaces.
+const disabledFeatures = "site-per-process,Translate,BlinkGenPropertyTrees,WebAuthentication"
+
+// launchBrowser starts a new headless browser with the default viewport, configured by launch
+func launchBrowser(ctx context.Context, launch LaunchOptions) (*launchedBrowser, error) {
+	launchOpts, err := launch.flags()
+	if err != nil {
+		return nil, err
+	}
+	opts := chromedp.DefaultExecAllocatorOptions[:]
+	opts = append(opts, chromedp.NoSandbox)
+	opts = append(opts, chromedp.Flag("--disable-dbus", true))
+	opts = append(opts, chromedp.WSURLReadTimeout(60*time.Second))
+	opts = append(opts, chromedp.Flag("disable-features", disabledFeatures))
+	opts = append(opts, launchOpts...)
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
+	browserCtx, browserCancel := chromedp.NewContext(
+		allocCtx,
+		chromedp.WithLogf(log.Printf),
+		chromedp.WithErrorf(log.Printf),
+		chromedp.WithBrowserOption(chromedp.WithDialTimeout(60*time.Second)),
+	)
+
+	// Start the browser
+	if err := chromedp.Run(browserCtx); err != nil {
+		allocCancel()
+		return nil, fmt.Errorf("failed to start browser (please apt get chromium or equivalent): %w", err)
+	}
+
+	// Set default viewport size to 1280x720 (16:9 widescreen)
+	if err := chromedp.Run(browserCtx, chromedp.EmulateViewport(1280, 720)); err != nil {
+		browserCancel()
+		allocCancel()
+		return nil, fmt.Errorf("failed to set default viewport: %w", err)
+	}
+
+	return &launchedBrowser{
+		allocCtx:      allocCtx,
+		allocCancel:   allocCancel,
+		browserCtx:    browserCtx,
+		browserCancel: browserCancel,
+	}, nil
+}
+
 // resetIdleTimerLocked resets or starts the idle timer. Caller must hold b.mux.
 func (b *BrowseTools) resetIdleTimerLocked() {
 	if b.idleTimer != nil {
 		b.idleTimer.Stop()
 	}
 	b.idleTimer = time.AfterFunc(b.idleTimeout, b.idleShutdown)
+	b.idleDeadline = time.Now().Add(b.idleTimeout)
 }
 
 // idleShutdown is called when the idle timer fires
@@ -223,6 +371,12 @@ func (b *BrowseTools) closeBrowserLocked() {
 
 	b.browserCtx = nil
 	b.allocCtx = nil
+
+	// Any in-progress trace and frame contexts died with the browser
+	b.traceMutex.Lock()
+	b.trace = nil
+	b.traceMutex.Unlock()
+	b.handleExecutionContextsCleared()
 }
 
 // Close shuts down the browser
@@ -234,18 +388,9 @@ func (b *BrowseTools) Close() {
 
 // NavigateTool definition
 type navigateInput struct {
-	URL     string `json:"url"`
-	Timeout string `json:"timeout,omitempty"`
-}
-
-// isPort80 reports whether urlStr definitely uses port 80.
-func isPort80(urlStr string) bool {
-	parsedURL, err := url.Parse(urlStr)
-	if err != nil {
-		return false
-	}
-	port := parsedURL.Port()
-	return port == "80" || (port == "" && parsedURL.Scheme == "http")
+	URL            string `json:"url" description:"The URL to navigate to"`
+	DismissConsent string `json:"dismiss_consent,omitempty" enum:"reject,accept" description:"After loading, look for a cookie/consent banner for up to 2s and click its reject or accept button (the other is used if the preferred one is missing). Omit to leave banners alone."`
+	Timeout        string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
 }
 
 // NewNavigateTool creates a tool for navigating to URLs
@@ -253,21 +398,8 @@ func (b *BrowseTools) NewNavigateTool() *llm.Tool {
 	return &llm.Tool{
 		Name:        "browser_navigate",
 		Description: "Navigate the browser to a specific URL and wait for page to load",
-		InputSchema: json.RawMessage(`{
-			"type": "object",
-			"properties": {
-				"url": {
-					"type": "string",
-					"description": "The URL to navigate to"
-				},
-				"timeout": {
-					"type": "string",
-					"description": "Timeout as a Go duration string (default: 15s)"
-				}
-			},
-			"required": ["url"]
-		}`),
-		Run: b.navigateRun,
+		InputSchema: llm.SchemaFor[navigateInput](),
+		Run:         b.navigateRun,
 	}
 }
 
@@ -276,9 +408,14 @@ func (b *BrowseTools) navigateRun(ctx context.Context, m json.RawMessage) llm.To
 	if err := json.Unmarshal(m, &input); err != nil {
 		return llm.ErrorfToolOut("invalid input: %w", err)
 	}
+	switch input.DismissConsent {
+	case "", "reject", "accept":
+	default:
+		return llm.ErrorfToolOut("unsupported dismiss_consent %q: must be reject or accept", input.DismissConsent)
+	}
 
-	if isPort80(input.URL) {
-		return llm.ErrorToolOut(fmt.Errorf("port 80 is not the port you're looking for--port 80 is the main sketch server"))
+	if err := b.allowNavigation(ctx, input.URL); err != nil {
+		return llm.ErrorToolOut(err)
 	}
 
 	browserCtx, err := b.GetBrowserContext()
@@ -290,10 +427,25 @@ func (b *BrowseTools) navigateRun(ctx context.Context, m json.RawMessage) llm.To
 	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
 	defer cancel()
 
-	err = chromedp.Run(timeoutCtx,
-		chromedp.Navigate(input.URL),
-		chromedp.WaitReady("body"),
-	)
+	// Record the main frame's redirects
+	var redirectsMutex sync.Mutex
+	var redirects []*network.Response
+	listenCtx, stopListening := context.WithCancel(timeoutCtx)
+	defer stopListening()
+	mainFrame := cdp.FrameID(chromedp.FromContext(browserCtx).Target.TargetID)
+	chromedp.ListenTarget(listenCtx, func(ev any) {
+		if e, ok := ev.(*network.EventRequestWillBeSent); ok && e.RedirectResponse != nil && e.FrameID == mainFrame && e.Type == network.ResourceTypeDocument {
+			redirectsMutex.Lock()
+			redirects = append(redirects, e.RedirectResponse)
+			redirectsMutex.Unlock()
+		}
+	})
+
+	var finalURL string
+	resp, err := chromedp.RunResponse(timeoutCtx, chromedp.Navigate(input.URL))
+	if err == nil {
+		err = chromedp.Run(timeoutCtx, chromedp.WaitReady("body"), chromedp.Location(&finalURL))
+	}
 	if err != nil {
 		// Navigation to download URLs fails with ERR_ABORTED, but the download may have succeeded.
 		// Wait briefly for download events to be processed, then check if we got any downloads.
@@ -317,40 +469,61 @@ func (b *BrowseTools) navigateRun(ctx context.Context, m json.RawMessage) llm.To
 		return llm.ErrorToolOut(err)
 	}
 
-	return b.toolOutWithDownloads("done")
+	stopListening()
+	redirectsMutex.Lock()
+	msg := describeNavigation(finalURL, resp, redirects)
+	redirectsMutex.Unlock()
+
+	if input.DismissConsent == "" {
+		return b.actionToolOut(msg)
+	}
+	var clicked string
+	if err := chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		clicked, err = dismissConsentBanner(ctx, input.DismissConsent)
+		return err
+	})); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if clicked == "" {
+		return b.actionToolOut(msg + "\nNo consent banner found")
+	}
+	return b.actionToolOut(fmt.Sprintf("%s\nDismissed consent banner by clicking %q", msg, clicked))
+}
+
+// describeNavigation reports where a navigation ended up: the final URL and status,
+// and each redirect on the way with its status
+func describeNavigation(finalURL string, resp *network.Response, redirects []*network.Response) string {
+	var sb strings.Builder
+	sb.WriteString("done: " + finalURL)
+	if resp != nil && resp.Status != 0 {
+		fmt.Fprintf(&sb, " (status %d)", resp.Status)
+	}
+	if len(redirects) > 0 {
+		fmt.Fprintf(&sb, " after %d redirects:", len(redirects))
+		for _, r := range redirects {
+			fmt.Fprintf(&sb, "\n  %d %s", r.Status, r.URL)
+		}
+	}
+	return sb.String()
 }
 
 // ResizeTool definition
 type resizeInput struct {
-	Width   int    `json:"width"`
-	Height  int    `json:"height"`
-	Timeout string `json:"timeout,omitempty"`
+	Width             int     `json:"width,omitempty" description:"Viewport width in pixels"`
+	Height            int     `json:"height,omitempty" description:"Viewport height in pixels"`
+	DeviceScaleFactor float64 `json:"device_scale_factor,omitempty" description:"Device pixels per CSS pixel, e.g. 2 or 3 for high-DPI screens (default: 1)"`
+	Mobile            bool    `json:"mobile,omitempty" description:"Lay the page out as on a phone (meta viewport tags apply, scrollbars overlay) with touch input (default: false)"`
+	Device            string  `json:"device,omitempty" enum:"desktop,iphone-15,iphone-se,pixel-7,ipad" description:"Device preset to emulate instead of giving width and height"`
+	Timeout           string  `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
 }
 
 // NewResizeTool creates a tool for resizing the browser viewport
 func (b *BrowseTools) NewResizeTool() *llm.Tool {
 	return &llm.Tool{
 		Name:        "browser_resize",
-		Description: "Resize the browser viewport to a specific width and height",
-		InputSchema: json.RawMessage(`{
-			"type": "object",
-			"properties": {
-				"width": {
-					"type": "integer",
-					"description": "Viewport width in pixels"
-				},
-				"height": {
-					"type": "integer",
-					"description": "Viewport height in pixels"
-				},
-				"timeout": {
-					"type": "string",
-					"description": "Timeout as a Go duration string (default: 15s)"
-				}
-			},
-			"required": ["width", "height"]
-		}`),
-		Run: b.resizeRun,
+		Description: "Resize the browser viewport to a specific width and height (optionally high-DPI or mobile), or emulate a device preset (mobile presets also enable touch input)",
+		InputSchema: llm.SchemaFor[resizeInput](),
+		Run:         b.resizeRun,
 	}
 }
 
@@ -360,8 +533,29 @@ func (b *BrowseTools) resizeRun(ctx context.Context, m json.RawMessage) llm.Tool
 		return llm.ErrorfToolOut("invalid input: %w", err)
 	}
 
-	if input.Width <= 0 || input.Height <= 0 {
-		return llm.ErrorToolOut(fmt.Errorf("invalid dimensions: width and height must be positive"))
+	var emulate chromedp.Action
+	if input.Device != "" {
+		device, ok := devicePresets[input.Device]
+		if !ok {
+			return llm.ErrorfToolOut("unknown device %q", input.Device)
+		}
+		if input.Width != 0 || input.Height != 0 || input.DeviceScaleFactor != 0 || input.Mobile {
+			return llm.ErrorfToolOut("give either device or width and height (with device_scale_factor and mobile), not both")
+		}
+		emulate = device.emulate()
+	} else {
+		if input.Width <= 0 || input.Height <= 0 {
+			return llm.ErrorToolOut(fmt.Errorf("invalid dimensions: width and height must be positive"))
+		}
+		if input.DeviceScaleFactor < 0 {
+			return llm.ErrorfToolOut("device_scale_factor must be positive")
+		}
+		emulate = devicePreset{
+			Width:  int64(input.Width),
+			Height: int64(input.Height),
+			Scale:  cmp.Or(input.DeviceScaleFactor, 1),
+			Mobile: input.Mobile,
+		}.emulate()
 	}
 
 	browserCtx, err := b.GetBrowserContext()
@@ -372,9 +566,7 @@ func (b *BrowseTools) resizeRun(ctx context.Context, m json.RawMessage) llm.Tool
 	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
 	defer cancel()
 
-	err = chromedp.Run(timeoutCtx,
-		chromedp.EmulateViewport(int64(input.Width), int64(input.Height)),
-	)
+	err = chromedp.Run(timeoutCtx, emulate)
 	if err != nil {
 		return llm.ErrorToolOut(err)
 	}
@@ -384,9 +576,13 @@ func (b *BrowseTools) resizeRun(ctx context.Context, m json.RawMessage) llm.Tool
 
 // EvalTool definition
 type evalInput struct {
-	Expression string `json:"expression"`
-	Timeout    string `json:"timeout,omitempty"`
-	Await      *bool  `json:"await,omitempty"`
+	Expression     string `json:"expression" description:"JavaScript expression to evaluate; a function expression such as (a, b) => a + b when args is given"`
+	Args           []any  `json:"args,omitempty" description:"JSON arguments to call the function expression with, instead of interpolating data into the JavaScript source"`
+	Frame          string `json:"frame,omitempty" description:"Run inside an iframe instead of the top-level page: a 0-based index of the page's iframes in document order, the iframe's name attribute, or a substring of its URL"`
+	Timeout        string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+	Await          *bool  `json:"await,omitempty" description:"If true, wait for promises to resolve and return their resolved value (default: true)"`
+	MaxResultBytes int    `json:"max_result_bytes,omitempty" description:"Truncate the JSON result returned inline to this many bytes (default: 1024)"`
+	SaveFullResult *bool  `json:"save_full_result,omitempty" description:"If the result is truncated, write the full result to a file and return its path (default: true)"`
 }
 
 // NewEvalTool creates a tool for evaluating JavaScript
@@ -395,25 +591,8 @@ func (b *BrowseTools) NewEvalTool() *llm.Tool {
 		Name: "browser_eval",
 		Description: `Evaluate JavaScript in the browser context.
 Your go-to tool for interacting with content: clicking buttons, typing, getting content, scrolling, resizing, waiting for content/selector to be ready, etc.`,
-		InputSchema: json.RawMessage(`{
-			"type": "object",
-			"properties": {
-				"expression": {
-					"type": "string",
-					"description": "JavaScript expression to evaluate"
-				},
-				"timeout": {
-					"type": "string",
-					"description": "Timeout as a Go duration string (default: 15s)"
-				},
-				"await": {
-					"type": "boolean",
-					"description": "If true, wait for promises to resolve and return their resolved value (default: true)"
-				}
-			},
-			"required": ["expression"]
-		}`),
-		Run: b.evalRun,
+		InputSchema: llm.SchemaFor[evalInput](),
+		Run:         b.evalRun,
 	}
 }
 
@@ -422,6 +601,10 @@ func (b *BrowseTools) evalRun(ctx context.Context, m json.RawMessage) llm.ToolOu
 	if err := json.Unmarshal(m, &input); err != nil {
 		return llm.ErrorfToolOut("invalid input: %w", err)
 	}
+	if input.MaxResultBytes < 0 {
+		return llm.ErrorfToolOut("max_result_bytes must not be negative")
+	}
+	limit := cmp.Or(input.MaxResultBytes, DefaultEvalResultLimit)
 
 	browserCtx, err := b.GetBrowserContext()
 	if err != nil {
@@ -439,13 +622,29 @@ func (b *BrowseTools) evalRun(ctx context.Context, m json.RawMessage) llm.ToolOu
 	if input.Await != nil {
 		await = *input.Await
 	}
-	if await {
+
+	if input.Frame != "" {
+		var iframe *cdp.Node
+		if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		contextID, err := b.frameContext(iframe)
+		if err != nil {
+			return llm.ErrorToolOut(err)
+		}
 		evalOps = append(evalOps, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
-			return p.WithAwaitPromise(true)
+			return p.WithContextID(contextID)
 		})
 	}
 
-	evalAction := chromedp.Evaluate(input.Expression, &result, evalOps...)
+	var evalAction chromedp.Action
+	if input.Args != nil {
+		evalAction = callWithArgs(input.Expression, input.Args, await, &result, evalOps...)
+	} else {
+		evalAction = chromedp.Evaluate(input.Expression, &result, append(evalOps, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(await)
+		})...)
+	}
 
 	err = chromedp.Run(timeoutCtx, evalAction)
 	if err != nil {
@@ -458,47 +657,170 @@ func (b *BrowseTools) evalRun(ctx context.Context, m json.RawMessage) llm.ToolOu
 		return llm.ErrorfToolOut("failed to marshal response: %w", err)
 	}
 
-	// If output exceeds threshold, write to file
-	if len(response) > ConsoleLogSizeThreshold {
-		filename := fmt.Sprintf("js_result_%s.json", uuid.New().String()[:8])
-		filePath := filepath.Join(ConsoleLogsDir, filename)
-		if err := os.WriteFile(filePath, response, 0o644); err != nil {
-			return llm.ErrorfToolOut("failed to write JS result to file: %w", err)
-		}
-		return b.toolOutWithDownloads(fmt.Sprintf(
-			"JavaScript result (%d bytes) written to: %s\nUse `cat %s` to view the full content.",
-			len(response), filePath, filePath))
+	if len(response) <= limit {
+		return b.toolOutWithDownloads("<javascript_result>" + string(response) + "</javascript_result>")
 	}
 
-	return b.toolOutWithDownloads("<javascript_result>" + string(response) + "</javascript_result>")
+	// Too large to return whole: truncate, and write the full result to a file unless asked not to
+	preview := truncateUTF8(response, limit)
+	truncated := fmt.Sprintf("<javascript_result>%s[truncated %d of %d bytes]</javascript_result>",
+		preview, len(response)-len(preview), len(response))
+	if input.SaveFullResult != nil && !*input.SaveFullResult {
+		return b.toolOutWithDownloads(truncated)
+	}
+	filePath, err := writeOutputFile("js_result", "json", response)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to write JS result to file: %w", err)
+	}
+	return b.toolOutWithDownloads(fmt.Sprintf(
+		"JavaScript result (%d bytes) written to: %s\nUse `cat %s` to view the full content.\n%s",
+		len(response), filePath, filePath, truncated))
+}
+
+// callWithArgs calls the function expression fn with args passed as JSON values and window as this.
+// evalOpts select the execution context, e.g. an iframe's.
+func callWithArgs(fn string, args []any, await bool, res any, evalOpts ...chromedp.EvaluateOption) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var global *runtime.RemoteObject
+		if err := chromedp.Evaluate("globalThis", &global, evalOpts...).Do(ctx); err != nil {
+			return err
+		}
+		defer runtime.ReleaseObject(global.ObjectID).Do(ctx)
+		return chromedp.CallFunctionOn(fn, res, func(p *runtime.CallFunctionOnParams) *runtime.CallFunctionOnParams {
+			return p.WithObjectID(global.ObjectID).WithAwaitPromise(await)
+		}, args...).Do(ctx)
+	})
+}
+
+// truncateUTF8 returns at most n bytes of data without splitting a UTF-8 sequence
+func truncateUTF8(data []byte, n int) []byte {
+	if len(data) <= n {
+		return data
+	}
+	for n > 0 && !utf8.RuneStart(data[n]) {
+		n--
+	}
+	return data[:n]
 }
 
 // ScreenshotTool definition
 type screenshotInput struct {
-	Selector string `json:"selector,omitempty"`
-	Timeout  string `json:"timeout,omitempty"`
+	Selector     string          `json:"selector,omitempty" description:"Element to screenshot (optional): CSS selector or XPath expression; use '>>>' in a CSS selector to step into an element's shadow root, e.g. 'my-app >>> button.submit'. Selectors starting with '/', './', or '(' are treated as XPath, e.g. //button[contains(., 'Submit')]"`
+	SelectorType string          `json:"selector_type,omitempty" enum:"css,xpath" description:"How to interpret the selector (default: XPath if it starts with '/', './', or '(', otherwise CSS)"`
+	Frame        string          `json:"frame,omitempty" description:"Run inside an iframe instead of the top-level page: a 0-based index of the page's iframes in document order, the iframe's name attribute, or a substring of its URL"`
+	Padding      float64         `json:"padding,omitempty" description:"CSS pixels of surrounding context to include around the selected element (default: 0)"`
+	Highlight    string          `json:"highlight_selector,omitempty" description:"CSS selector of elements to outline in the screenshot, e.g. to point the user at them; in frame if given"`
+	Clip         *screenshotClip `json:"clip,omitempty" description:"Rectangle to capture in CSS pixels relative to the top-left of the document; cannot be combined with selector"`
+	FullPage     bool            `json:"full_page,omitempty" description:"Capture the whole scrollable page rather than the viewport; cannot be combined with selector, frame, or clip (default: false)"`
+	Scroll       bool            `json:"scroll,omitempty" description:"With full_page, scroll through the page a viewport at a time and stitch the captures together, for pages that lazy-load content or lay out relative to the viewport height; png at scale 1 only (default: false)"`
+	Scale        float64         `json:"scale,omitempty" description:"Device pixel ratio to capture at, e.g. 2 for a high-DPI image or 0.5 for a smaller one (default: 1)"`
+	Format       string          `json:"format,omitempty" enum:"png,jpeg" description:"Image format (default: png)"`
+	Quality      *int            `json:"quality,omitempty" description:"Compression quality from 1 to 100; jpeg only (default: 80)"`
+	Stamp        bool            `json:"stamp,omitempty" description:"Stamp the capture time, page URL, and viewport size into the bottom-right corner of the saved file, so it describes itself outside the conversation (default: false)"`
+	Timeout      string          `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// screenshotClip is a rectangle in CSS pixels relative to the top-left of the document
+type screenshotClip struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
 }
 
 // NewScreenshotTool creates a tool for taking screenshots
 func (b *BrowseTools) NewScreenshotTool() *llm.Tool {
 	return &llm.Tool{
 		Name:        "browser_take_screenshot",
-		Description: "Take a screenshot of the page or a specific element",
-		InputSchema: json.RawMessage(`{
-			"type": "object",
-			"properties": {
-				"selector": {
-					"type": "string",
-					"description": "CSS selector for the element to screenshot (optional)"
-				},
-				"timeout": {
-					"type": "string",
-					"description": "Timeout as a Go duration string (default: 15s)"
-				}
-			}
-		}`),
-		Run: b.screenshotRun,
+		Description: "Take a screenshot of the viewport, the full page, a specific element, an iframe, or a rectangle of the page, optionally outlining elements to point them out. A long full page is returned as several overlapping viewport-sized tiles.",
+		InputSchema: llm.SchemaFor[screenshotInput](),
+		Run:         b.screenshotRun,
+	}
+}
+
+// validate checks that the screenshot options are consistent and fills in defaults
+func (in *screenshotInput) validate() error {
+	if in.Format == "" {
+		in.Format = "png"
+	}
+	switch in.Format {
+	case "png":
+		if in.Quality != nil {
+			return fmt.Errorf("quality is only supported for jpeg")
+		}
+	case "jpeg":
+		if in.Quality == nil {
+			quality := 80
+			in.Quality = &quality
+		}
+		// The protocol omits a quality of 0, which Chrome then takes for its default, so it is refused
+		if *in.Quality < 1 || *in.Quality > 100 {
+			return fmt.Errorf("quality must be between 1 and 100")
+		}
+	default:
+		return fmt.Errorf("unsupported format %q: must be png or jpeg", in.Format)
+	}
+	if in.Clip != nil {
+		if in.Selector != "" || in.Frame != "" {
+			return fmt.Errorf("clip cannot be combined with selector or frame")
+		}
+		if in.Clip.Width <= 0 || in.Clip.Height <= 0 {
+			return fmt.Errorf("clip width and height must be positive")
+		}
+	}
+	if in.FullPage && (in.Selector != "" || in.Frame != "" || in.Clip != nil) {
+		return fmt.Errorf("full_page cannot be combined with selector, frame, or clip")
+	}
+	if in.Scroll && (!in.FullPage || in.Format != "png" || in.Scale != 0) {
+		return fmt.Errorf("scroll requires full_page, png format, and no scale")
+	}
+	if in.Padding < 0 {
+		return fmt.Errorf("padding must not be negative")
+	}
+	if in.Padding > 0 && in.Selector == "" && in.Frame == "" {
+		return fmt.Errorf("padding requires a selector or frame")
+	}
+	if in.Scale < 0 {
+		return fmt.Errorf("scale must be positive")
+	}
+	return nil
+}
+
+// elementClip computes the page-relative bounding box of the first visible element matching sel
+func elementClip(sel string, clip *page.Viewport, opts ...chromedp.QueryOption) chromedp.QueryAction {
+	return chromedp.QueryAfter(sel, func(ctx context.Context, _ runtime.ExecutionContextID, nodes ...*cdp.Node) error {
+		if len(nodes) < 1 {
+			return fmt.Errorf("selector %q did not return any nodes", sel)
+		}
+		return nodeClip(ctx, nodes[0], clip)
+	}, append(opts, chromedp.NodeVisible)...)
+}
+
+// nodeClip computes the page-relative bounding box of node.
+// Content quads are relative to the top-level viewport even inside iframes,
+// so adding the scroll offset yields document coordinates.
+func nodeClip(ctx context.Context, node *cdp.Node, clip *page.Viewport) error {
+	quads, err := dom.GetContentQuads().WithNodeID(node.NodeID).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if len(quads) == 0 {
+		return fmt.Errorf("element is not rendered")
+	}
+	_, _, _, _, viewport, _, err := page.GetLayoutMetrics().Do(ctx)
+	if err != nil {
+		return err
+	}
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, q := range quads {
+		for i := 0; i+1 < len(q); i += 2 {
+			minX, maxX = min(minX, q[i]), max(maxX, q[i])
+			minY, maxY = min(minY, q[i+1]), max(maxY, q[i+1])
+		}
 	}
+	clip.X, clip.Y = minX+viewport.PageX, minY+viewport.PageY
+	clip.Width, clip.Height = maxX-minX, maxY-minY
+	return nil
 }
 
 func (b *BrowseTools) screenshotRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
@@ -506,6 +828,9 @@ func (b *BrowseTools) screenshotRun(ctx context.Context, m json.RawMessage) llm.
 	if err := json.Unmarshal(m, &input); err != nil {
 		return llm.ErrorfToolOut("invalid input: %w", err)
 	}
+	if err := input.validate(); err != nil {
+		return llm.ErrorToolOut(err)
+	}
 
 	// Try to get a browser context; if unavailable, return an error
 	browserCtx, err := b.GetBrowserContext()
@@ -517,18 +842,118 @@ func (b *BrowseTools) screenshotRun(ctx context.Context, m json.RawMessage) llm.
 	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
 	defer cancel()
 
-	var buf []byte
-	var actions []chromedp.Action
+	var iframe *cdp.Node
+	if input.Frame != "" {
+		if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+	queryOpts, err := selectorQuery(input.Selector, input.SelectorType, iframe)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	if input.Highlight != "" {
+		var evalOpts []chromedp.EvaluateOption
+		if iframe != nil {
+			contextID, err := b.frameContext(iframe)
+			if err != nil {
+				return llm.ErrorToolOut(err)
+			}
+			evalOpts = append(evalOpts, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+				return p.WithContextID(contextID)
+			})
+		}
+		var n int
+		if err := chromedp.Run(timeoutCtx, callWithArgs(highlightJS, []any{input.Highlight, maxHighlights}, false, &n, evalOpts...)); err != nil {
+			return llm.ErrorfToolOut("failed to highlight %q: %w", input.Highlight, err)
+		}
+		defer chromedp.Run(timeoutCtx, callWithArgs(unhighlightJS, nil, false, nil, evalOpts...))
+		if n == 0 {
+			return llm.ErrorfToolOut("highlight_selector %q matched no elements", input.Highlight)
+		}
+	}
 
-	if input.Selector != "" {
-		// Take screenshot of specific element
+	var actions []chromedp.Action
+	var clip *page.Viewport
+	// tileHeight is the height in image pixels of the tiles a full page is split into
+	var tileHeight int
+	switch {
+	case input.Clip != nil:
+		clip = &page.Viewport{X: input.Clip.X, Y: input.Clip.Y, Width: input.Clip.Width, Height: input.Clip.Height}
+	case input.Selector != "":
+		clip = &page.Viewport{}
 		actions = append(actions,
-			chromedp.WaitReady(input.Selector),
-			chromedp.Screenshot(input.Selector, &buf, chromedp.NodeVisible),
+			chromedp.WaitReady(input.Selector, queryOpts...),
+			elementClip(input.Selector, clip, queryOpts...),
 		)
-	} else {
-		// Take full page screenshot
-		actions = append(actions, chromedp.CaptureScreenshot(&buf))
+	case iframe != nil:
+		clip = &page.Viewport{}
+		actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+			return nodeClip(ctx, iframe, clip)
+		}))
+	case input.FullPage:
+		clip = &page.Viewport{}
+		actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+			_, _, _, cssLayoutViewport, _, cssContentSize, err := page.GetLayoutMetrics().Do(ctx)
+			if err != nil {
+				return err
+			}
+			clip.Width, clip.Height = cssContentSize.Width, cssContentSize.Height
+			tileHeight = int(float64(cssLayoutViewport.ClientHeight) * cmp.Or(input.Scale, 1))
+			return nil
+		}))
+	case input.Scale != 0:
+		// Scaling requires a clip, so clip to the visible viewport
+		clip = &page.Viewport{}
+		actions = append(actions, chromedp.Evaluate(
+			`({x: window.scrollX, y: window.scrollY, width: window.innerWidth, height: window.innerHeight})`, clip))
+	}
+
+	var buf []byte
+	actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+		if input.Scroll {
+			var err error
+			buf, tileHeight, err = scrollAndStitch(ctx)
+			return err
+		}
+		params := page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormat(input.Format)).
+			WithFromSurface(true)
+		if input.Format == "jpeg" {
+			params = params.WithQuality(int64(*input.Quality))
+		}
+		if clip != nil {
+			// Pad, clamp to the document origin, and snap to whole pixels
+			// (fractional clips render incorrectly)
+			x, y := max(0, math.Round(clip.X-input.Padding)), max(0, math.Round(clip.Y-input.Padding))
+			clip.Width = math.Round(clip.X + clip.Width + input.Padding - x)
+			clip.Height = math.Round(clip.Y + clip.Height + input.Padding - y)
+			clip.X, clip.Y = x, y
+			clip.Scale = input.Scale
+			if clip.Scale == 0 {
+				clip.Scale = 1
+			}
+			params = params.WithClip(clip).WithCaptureBeyondViewport(true)
+		}
+		var err error
+		buf, err = params.Do(ctx)
+		return err
+	}))
+
+	var stamp string
+	if input.Stamp {
+		actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+			var info struct {
+				URL           string
+				Width, Height int
+			}
+			if err := chromedp.Evaluate(`({url: location.href, width: innerWidth, height: innerHeight})`, &info).Do(ctx); err != nil {
+				return err
+			}
+			stamp = fmt.Sprintf("%s | %s | %dx%d", time.Now().UTC().Format(time.DateTime+" UTC"), info.URL, info.Width, info.Height)
+			return nil
+		}))
 	}
 
 	err = chromedp.Run(timeoutCtx, actions...)
@@ -536,54 +961,100 @@ func (b *BrowseTools) screenshotRun(ctx context.Context, m json.RawMessage) llm.
 		return llm.ErrorToolOut(err)
 	}
 
+	return b.screenshotToolOut(buf, input.Format, tileHeight, stamp, map[string]any{
+		"selector": input.Selector,
+		"frame":    input.Frame,
+	})
+}
+
+// thumbnailDimension is the max pixel dimension of the screenshot thumbnails UIs show in the conversation
+const thumbnailDimension = 320
+
+// screenshotDisplay fills in the Display data of the saved screenshot id of data:
+// its path, a URL to read it from, and a URL to a thumbnail of it, saved next to it
+func screenshotDisplay(id, format string, data []byte, display map[string]any) error {
+	screenshotPath := GetScreenshotPath(id, format)
+	display["type"] = "screenshot"
+	display["id"] = id
+	display["url"] = "/api/read?path=" + url.QueryEscape(screenshotPath)
+	display["path"] = screenshotPath
+
+	thumbnail, thumbnailFormat, resized, err := imageutil.ResizeImage(data, thumbnailDimension)
+	if err != nil {
+		return fmt.Errorf("failed to make screenshot thumbnail: %w", err)
+	}
+	thumbnailPath := screenshotPath
+	if resized {
+		thumbnailPath = GetScreenshotPath(id+".thumb", thumbnailFormat)
+		if err := os.WriteFile(thumbnailPath, thumbnail, 0o644); err != nil {
+			return fmt.Errorf("failed to save screenshot thumbnail: %w", err)
+		}
+	}
+	display["thumbnail_url"] = "/api/read?path=" + url.QueryEscape(thumbnailPath)
+	return nil
+}
+
+// maxScreenshotTiles bounds the tiles a full page screenshot is split into; longer pages get taller tiles
+const maxScreenshotTiles = 8
+
+// screenshotToolOut saves a screenshot and returns it for the LLM, resized as needed,
+// with display (e.g. the selector) extended to describe it for the UI.
+// If tileHeight is set, a taller screenshot is returned as overlapping tiles about that tall.
+// If stamp is set, it is written into a corner of the saved file but not the images returned.
+func (b *BrowseTools) screenshotToolOut(buf []byte, format string, tileHeight int, stamp string, display map[string]any) llm.ToolOut {
+	saved := buf
+	if stamp != "" {
+		var err error
+		if saved, _, err = imageutil.Stamp(buf, stamp); err != nil {
+			return llm.ErrorfToolOut("failed to stamp screenshot: %w", err)
+		}
+	}
+
 	// Save the screenshot and get its ID for potential future reference
-	id := b.SaveScreenshot(buf)
-	if id == "" {
-		return llm.ErrorToolOut(fmt.Errorf("failed to save screenshot"))
+	id, err := b.saveOptimizedScreenshot(saved, format)
+	if err != nil {
+		return llm.ErrorToolOut(err)
 	}
 
 	// Get the full path to the screenshot
-	screenshotPath := GetScreenshotPath(id)
+	screenshotPath := GetScreenshotPath(id, format)
 
-	// Resize image if needed to fit within model's image dimension limits
-	imageData := buf
-	format := "png"
-	resized := false
-	if b.maxImageDimension > 0 {
-		var err error
-		imageData, format, resized, err = imageutil.ResizeImage(buf, b.maxImageDimension)
+	// Split long pages so each part stays legible when resized
+	tiles := [][]byte{buf}
+	if tileHeight > 0 {
+		config, _, err := image.DecodeConfig(bytes.NewReader(buf))
 		if err != nil {
-			return llm.ErrorToolOut(fmt.Errorf("failed to resize screenshot: %w", err))
+			return llm.ErrorfToolOut("failed to read screenshot size: %w", err)
+		}
+		overlap := tileHeight / 10
+		tileHeight = max(tileHeight, (config.Height-overlap)/maxScreenshotTiles+overlap+1)
+		if tiles, format, err = imageutil.Tile(buf, tileHeight, overlap); err != nil {
+			return llm.ErrorfToolOut("failed to tile screenshot: %w", err)
 		}
 	}
 
-	base64Data := base64.StdEncoding.EncodeToString(imageData)
-	mediaType := "image/" + format
+	var images []llm.Content
+	for _, imageData := range tiles {
+		img, err := llm.ImageContent(imageData)
+		if err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		images = append(images, img)
+	}
 
-	display := map[string]any{
-		"type":     "screenshot",
-		"id":       id,
-		"url":      "/api/read?path=" + url.QueryEscape(screenshotPath),
-		"path":     screenshotPath,
-		"selector": input.Selector,
+	if err := screenshotDisplay(id, format, saved, display); err != nil {
+		return llm.ErrorToolOut(err)
 	}
 
 	description := fmt.Sprintf("Screenshot taken (saved as %s)", screenshotPath)
-	if resized {
-		description += " [resized]"
+	if len(tiles) > 1 {
+		description += fmt.Sprintf(", shown as %d overlapping tiles from top to bottom", len(tiles))
 	}
 
-	return llm.ToolOut{LLMContent: []llm.Content{
-		{
-			Type: llm.ContentTypeText,
-			Text: description,
-		},
-		{
-			Type:      llm.ContentTypeText,
-			MediaType: mediaType,
-			Data:      base64Data,
-		},
-	}, Display: display}
+	return llm.ToolOut{LLMContent: append([]llm.Content{{
+		Type: llm.ContentTypeText,
+		Text: description,
+	}}, images...), Display: display}
 }
 
 // GetTools returns browser tools, optionally filtering out screenshot-related tools
@@ -594,24 +1065,73 @@ func (b *BrowseTools) GetTools(includeScreenshotTools bool) []*llm.Tool {
 		b.NewResizeTool(),
 		b.NewRecentConsoleLogsTool(),
 		b.NewClearConsoleLogsTool(),
+		b.NewStartTraceTool(),
+		b.NewStopTraceTool(),
+		b.NewExtractLinksTool(),
+		b.NewListFormsTool(),
+		b.NewFillFormTool(),
+		b.NewHealthTool(),
+		b.NewEmulateMediaTool(),
+		b.NewPageInfoTool(),
+		b.NewSelectTool(),
+		b.NewExtractTableTool(),
+		b.NewClickTool(),
+		b.NewMouseTool(),
+		b.NewExposeFunctionTool(),
+		b.NewAddInitScriptTool(),
+		b.NewOCRImageTool(),
+		b.NewSetOfflineTool(),
+		b.NewServiceWorkersTool(),
+		b.NewSavePageTool(),
+		b.NewTouchTool(),
+		b.NewPressKeysTool(),
+		b.NewElementInfoTool(),
+		b.NewGetStylesTool(),
+		b.NewElementAtTool(),
+		b.NewA11yAuditTool(),
+		b.NewCheckPageTool(),
+		b.NewCrawlTool(),
+		b.NewSaveStateTool(),
+		b.NewRestoreStateTool(),
+		b.NewSetPermissionsTool(),
+		b.NewDetectStackTool(),
+		b.NewPageWeightTool(),
+		b.NewWaitForRequestTool(),
+		b.NewMockResponseTool(),
+		b.NewTLSInfoTool(),
+		b.NewHeapSnapshotTool(),
+		b.NewMemoryTool(),
+		b.NewExtensionsTool(),
 	}
 
 	// Add screenshot-related tools if supported
 	if includeScreenshotTools {
 		tools = append(tools, b.NewScreenshotTool())
 		tools = append(tools, b.NewReadImageTool())
+		tools = append(tools, b.NewImageInfoTool())
+		tools = append(tools, b.NewCompareImagesTool())
+		tools = append(tools, b.NewResponsiveScreenshotsTool())
+		tools = append(tools, b.NewReadDownloadTool())
+		tools = append(tools, b.NewScrollToScreenshotTool())
 	}
 
+	// The tools share the browser, and its current page
+	for _, tool := range tools {
+		tool.Serialize = "browser"
+	}
 	return tools
 }
 
-// SaveScreenshot saves a screenshot to disk and returns its ID
-func (b *BrowseTools) SaveScreenshot(data []byte) string {
-	// Generate a unique ID
+// SaveScreenshot saves a screenshot in the given format ("png" or "jpeg", or "mhtml" for a page archive) to disk and returns its ID
+func (b *BrowseTools) SaveScreenshot(data []byte, format string) string {
+	// Generate a unique ID, namespaced by session so it maps to the session's directory
 	id := uuid.New().String()
+	if b.sessionID != "" {
+		id = b.sessionID + "/" + id
+	}
 
 	// Save the file
-	filePath := filepath.Join(ScreenshotDir, id+".png")
+	filePath := GetScreenshotPath(id, format)
 	if err := os.WriteFile(filePath, data, 0o644); err != nil {
 		log.Printf("Failed to save screenshot: %v", err)
 		return ""
@@ -625,37 +1145,44 @@ func (b *BrowseTools) SaveScreenshot(data []byte) string {
 	return id
 }
 
-// GetScreenshotPath returns the full path to a screenshot by ID
-func GetScreenshotPath(id string) string {
-	return filepath.Join(ScreenshotDir, id+".png")
+// saveOptimizedScreenshot saves a screenshot like SaveScreenshot, first shrinking a PNG with imageutil.OptimizePNG
+// since screenshots are mostly flat color
+func (b *BrowseTools) saveOptimizedScreenshot(data []byte, format string) (string, error) {
+	if format == "png" {
+		var err error
+		if data, err = imageutil.OptimizePNG(data); err != nil {
+			return "", fmt.Errorf("failed to optimize screenshot: %w", err)
+		}
+	}
+	id := b.SaveScreenshot(data, format)
+	if id == "" {
+		return "", fmt.Errorf("failed to save screenshot")
+	}
+	return id, nil
+}
+
+// GetScreenshotPath returns the full path to a screenshot by ID and format
+func GetScreenshotPath(id, format string) string {
+	return filepath.Join(ScreenshotDir, id+"."+format)
 }
 
 // ReadImageTool definition
 type readImageInput struct {
-	Path    string `json:"path"`
-	Timeout string `json:"timeout,omitempty"`
+	Path    string `json:"path" description:"Path to the image file to read"`
+	Frames  int    `json:"frames,omitempty" description:"Number of evenly spaced frames to return from an animated GIF, up to 8 (default: 1, the middle frame)"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
 }
 
+// maxReadImageFrames bounds the frames read_image returns from an animated GIF
+const maxReadImageFrames = 8
+
 // NewReadImageTool creates a tool for reading images and returning them as base64 encoded data
 func (b *BrowseTools) NewReadImageTool() *llm.Tool {
 	return &llm.Tool{
 		Name:        "read_image",
 		Description: "Read an image file (such as a screenshot) and encode it for sending to the LLM",
-		InputSchema: json.RawMessage(`{
-			"type": "object",
-			"properties": {
-				"path": {
-					"type": "string",
-					"description": "Path to the image file to read"
-				},
-				"timeout": {
-					"type": "string",
-					"description": "Timeout as a Go duration string (default: 15s)"
-				}
-			},
-			"required": ["path"]
-		}`),
-		Run: b.readImageRun,
+		InputSchema: llm.SchemaFor[readImageInput](),
+		Run:         b.readImageRun,
 	}
 }
 
@@ -664,6 +1191,12 @@ func (b *BrowseTools) readImageRun(ctx context.Context, m json.RawMessage) llm.T
 	if err := json.Unmarshal(m, &input); err != nil {
 		return llm.ErrorfToolOut("invalid input: %w", err)
 	}
+	if input.Frames < 0 || input.Frames > maxReadImageFrames {
+		return llm.ErrorfToolOut("frames must be between 1 and %d", maxReadImageFrames)
+	}
+	if input.Frames == 0 {
+		input.Frames = 1
+	}
 
 	// Check if the path exists
 	if _, err := os.Stat(input.Path); os.IsNotExist(err) {
@@ -675,10 +1208,16 @@ func (b *BrowseTools) readImageRun(ctx context.Context, m json.RawMessage) llm.T
 	if err != nil {
 		return llm.ErrorfToolOut("failed to read image file: %w", err)
 	}
+	return b.imageToolOut(input.Path, imageData, input.Frames)
+}
 
+// imageToolOut returns an image read from path for the LLM, converted from HEIC if needed.
+// An animated GIF is returned as up to maxFrames still frames.
+func (b *BrowseTools) imageToolOut(path string, imageData []byte, maxFrames int) llm.ToolOut {
 	// Convert HEIC to PNG if needed (Go's image library doesn't support HEIC)
 	converted := false
 	if imageutil.IsHEIC(imageData) {
+		var err error
 		imageData, err = imageutil.ConvertHEICToPNG(imageData)
 		if err != nil {
 			return llm.ErrorfToolOut("failed to convert HEIC image: %w", err)
@@ -686,44 +1225,68 @@ func (b *BrowseTools) readImageRun(ctx context.Context, m json.RawMessage) llm.T
 		converted = true
 	}
 
-	detectedType := http.DetectContentType(imageData)
-	if !strings.HasPrefix(detectedType, "image/") {
-		return llm.ErrorfToolOut("file is not an image: %s", detectedType)
+	// Send still frames of an animation rather than the whole file
+	frames := [][]byte{imageData}
+	totalFrames := 1
+	if imageutil.IsGIF(imageData) {
+		gifFrames, total, err := imageutil.GIFFrames(imageData, maxFrames)
+		if err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		if total > 1 {
+			frames, totalFrames = gifFrames, total
+		}
 	}
 
-	// Resize image if needed to fit within model's image dimension limits
-	resized := false
-	format := strings.TrimPrefix(detectedType, "image/")
-	if b.maxImageDimension > 0 {
-		var err error
-		imageData, format, resized, err = imageutil.ResizeImage(imageData, b.maxImageDimension)
+	var images []llm.Content
+	for _, frame := range frames {
+		img, err := llm.ImageContent(frame)
 		if err != nil {
-			return llm.ErrorToolOut(fmt.Errorf("failed to resize image: %w", err))
+			return llm.ErrorToolOut(err)
 		}
+		images = append(images, img)
 	}
 
-	base64Data := base64.StdEncoding.EncodeToString(imageData)
-	mediaType := "image/" + format
-
-	description := fmt.Sprintf("Image from %s (type: %s)", input.Path, mediaType)
+	description := fmt.Sprintf("Image from %s (type: %s)", path, images[0].MediaType)
+	if totalFrames > 1 {
+		description = fmt.Sprintf("Animated GIF from %s: %d evenly spaced frames of %d", path, len(frames), totalFrames)
+	}
 	if converted {
 		description += " [converted from HEIC]"
 	}
-	if resized {
-		description += " [resized]"
+
+	return llm.ToolOut{LLMContent: append([]llm.Content{{
+		Type: llm.ContentTypeText,
+		Text: description,
+	}}, images...)}
+}
+
+// writeOutputFile writes large tool output to a uniquely named file with extension ext in ConsoleLogsDir and returns its path
+func writeOutputFile(prefix, ext string, data []byte) (string, error) {
+	filePath := filepath.Join(ConsoleLogsDir, fmt.Sprintf("%s_%s.%s", prefix, uuid.New().String()[:8], ext))
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return "", err
 	}
+	return filePath, nil
+}
 
-	return llm.ToolOut{LLMContent: []llm.Content{
-		{
-			Type: llm.ContentTypeText,
-			Text: description,
-		},
-		{
-			Type:      llm.ContentTypeText,
-			MediaType: mediaType,
-			Data:      base64Data,
-		},
-	}}
+// jsonToolOut returns JSON data inline inside <tag> tags, or writes it to a file if it exceeds ConsoleLogSizeThreshold
+func jsonToolOut(tag, summary string, data []byte) llm.ToolOut {
+	return fileToolOut(tag, "json", summary, data)
+}
+
+// fileToolOut is like jsonToolOut for data in any format; ext is the file extension used when it is too large
+func fileToolOut(tag, ext, summary string, data []byte) llm.ToolOut {
+	if len(data) > ConsoleLogSizeThreshold {
+		filePath, err := writeOutputFile(tag, ext, data)
+		if err != nil {
+			return llm.ErrorfToolOut("failed to write %s to file: %w", tag, err)
+		}
+		return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf(
+			"%s (%d bytes).\nOutput written to: %s\nUse `cat %s` to view the full content.",
+			summary, len(data), filePath, filePath))}
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("%s:\n<%s>%s</%s>", summary, tag, data, tag))}
 }
 
 // parseTimeout parses a timeout string and returns a time.Duration
@@ -777,9 +1340,9 @@ func (b *BrowseTools) handleDownloadProgress(e *browser.EventDownloadProgress) {
 	case browser.DownloadProgressStateCompleted:
 		info.Completed = true
 		// The file is downloaded with GUID as filename, rename to suggested filename with random suffix
-		guidPath := filepath.Join(DownloadDir, e.GUID)
+		guidPath := filepath.Join(b.downloadDir(), e.GUID)
 		finalName := b.generateDownloadFilename(info.SuggestedFilename)
-		finalPath := filepath.Join(DownloadDir, finalName)
+		finalPath := filepath.Join(b.downloadDir(), finalName)
 		// Retry rename a few times as file might still be being written
 		var renamed bool
 		for i := 0; i < 10; i++ {
@@ -856,7 +1419,7 @@ func (b *BrowseTools) toolOutWithDownloads(message string) llm.ToolOut {
 
 // RecentConsoleLogsTool definition
 type recentConsoleLogsInput struct {
-	Limit int `json:"limit,omitempty"`
+	Limit int `json:"limit,omitempty" description:"Maximum number of log entries to return (default: 100)"`
 }
 
 // NewRecentConsoleLogsTool creates a tool for retrieving recent console logs
@@ -864,16 +1427,8 @@ func (b *BrowseTools) NewRecentConsoleLogsTool() *llm.Tool {
 	return &llm.Tool{
 		Name:        "browser_recent_console_logs",
 		Description: "Get recent browser console logs",
-		InputSchema: json.RawMessage(`{
-			"type": "object",
-			"properties": {
-				"limit": {
-					"type": "integer",
-					"description": "Maximum number of log entries to return (default: 100)"
-				}
-			}
-		}`),
-		Run: b.recentConsoleLogsRun,
+		InputSchema: llm.SchemaFor[recentConsoleLogsInput](),
+		Run:         b.recentConsoleLogsRun,
 	}
 }
 
@@ -913,9 +1468,8 @@ func (b *BrowseTools) recentConsoleLogsRun(ctx context.Context, m json.RawMessag
 
 	// If output exceeds threshold, write to file
 	if len(logData) > ConsoleLogSizeThreshold {
-		filename := fmt.Sprintf("console_logs_%s.json", uuid.New().String()[:8])
-		filePath := filepath.Join(ConsoleLogsDir, filename)
-		if err := os.WriteFile(filePath, logData, 0o644); err != nil {
+		filePath, err := writeOutputFile("console_logs", "json", logData)
+		if err != nil {
 			return llm.ErrorfToolOut("failed to write console logs to file: %w", err)
 		}
 		return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf(
@@ -8,9 +8,12 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/gif"
 	"image/png"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"slices"
@@ -19,6 +22,7 @@ import (
 	"time"
 
 	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/chromedp"
 	"github.com/go-json-experiment/json/jsontext"
@@ -27,7 +31,7 @@ import (
 
 func TestToolCreation(t *testing.T) {
 	// Create browser tools instance
-	tools := NewBrowseTools(context.Background(), 0, 0)
+	tools := NewBrowseTools(context.Background(), 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -41,7 +45,7 @@ func TestToolCreation(t *testing.T) {
 	}{
 		{tools.NewNavigateTool(), "browser_navigate", "Navigate", []string{"url"}},
 		{tools.NewEvalTool(), "browser_eval", "Evaluate", []string{"expression"}},
-		{tools.NewResizeTool(), "browser_resize", "Resize", []string{"width", "height"}},
+		{tools.NewResizeTool(), "browser_resize", "Resize", nil},
 		{tools.NewScreenshotTool(), "browser_take_screenshot", "Take", nil},
 	}
 
@@ -76,7 +80,7 @@ func TestToolCreation(t *testing.T) {
 
 func TestGetTools(t *testing.T) {
 	// Create browser tools instance
-	tools := NewBrowseTools(context.Background(), 0, 0)
+	tools := NewBrowseTools(context.Background(), 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -84,14 +88,14 @@ func TestGetTools(t *testing.T) {
 	// Test with screenshot tools included
 	t.Run("with screenshots", func(t *testing.T) {
 		toolsWithScreenshots := tools.GetTools(true)
-		if len(toolsWithScreenshots) != 7 {
-			t.Errorf("expected 7 tools with screenshots, got %d", len(toolsWithScreenshots))
+		if len(toolsWithScreenshots) != 49 {
+			t.Errorf("expected 49 tools with screenshots, got %d", len(toolsWithScreenshots))
 		}
 
 		// Check tool naming convention
 		for _, tool := range toolsWithScreenshots {
-			// Most tools have browser_ prefix, except for read_image
-			if tool.Name != "read_image" && !strings.HasPrefix(tool.Name, "browser_") {
+			// Most tools have browser_ prefix, except for the image tools
+			if tool.Name != "read_image" && tool.Name != "ocr_image" && tool.Name != "image_info" && tool.Name != "compare_images" && !strings.HasPrefix(tool.Name, "browser_") {
 				t.Errorf("tool name %q does not have prefix 'browser_'", tool.Name)
 			}
 		}
@@ -100,12 +104,27 @@ func TestGetTools(t *testing.T) {
 	// Test without screenshot tools
 	t.Run("without screenshots", func(t *testing.T) {
 		noScreenshotTools := tools.GetTools(false)
-		if len(noScreenshotTools) != 5 {
-			t.Errorf("expected 5 tools without screenshots, got %d", len(noScreenshotTools))
+		if len(noScreenshotTools) != 42 {
+			t.Errorf("expected 42 tools without screenshots, got %d", len(noScreenshotTools))
 		}
 	})
 }
 
+// TestToolSchemas verifies that every tool's input schema is valid JSON
+func TestToolSchemas(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, tool := range tools.GetTools(true) {
+		var schema map[string]any
+		if err := json.Unmarshal(tool.InputSchema, &schema); err != nil {
+			t.Errorf("%s: invalid input schema: %v", tool.Name, err)
+		}
+	}
+}
+
 // TestBrowserInitialization verifies that the browser can start correctly
 func TestBrowserInitialization(t *testing.T) {
 	// Skip long tests in short mode
@@ -117,7 +136,7 @@ func TestBrowserInitialization(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -155,7 +174,7 @@ func TestNavigateTool(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -212,20 +231,20 @@ func TestNavigateTool(t *testing.T) {
 func TestScreenshotTool(t *testing.T) {
 	// Create browser tools instance
 	ctx := context.Background()
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
 
 	// Test SaveScreenshot function directly
 	testData := []byte("test image data")
-	id := tools.SaveScreenshot(testData)
+	id := tools.SaveScreenshot(testData, "png")
 	if id == "" {
 		t.Fatal("SaveScreenshot returned empty ID")
 	}
 
 	// Get the file path and check if the file exists
-	filePath := GetScreenshotPath(id)
+	filePath := GetScreenshotPath(id, "png")
 	_, err := os.Stat(filePath)
 	if err != nil {
 		t.Fatalf("Failed to find screenshot file: %v", err)
@@ -249,7 +268,7 @@ func TestScreenshotTool(t *testing.T) {
 func TestReadImageTool(t *testing.T) {
 	// Create a test BrowseTools instance
 	ctx := context.Background()
-	browseTools := NewBrowseTools(ctx, 0, 0)
+	browseTools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		browseTools.Close()
 	})
@@ -314,7 +333,7 @@ func TestDefaultViewportSize(t *testing.T) {
 		t.Skip("Skipping browser test in CI/headless environment")
 	}
 
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -374,7 +393,7 @@ func TestBrowserIdleShutdownAndRestart(t *testing.T) {
 
 	// Use a short idle timeout for testing
 	idleTimeout := 100 * time.Millisecond
-	tools := NewBrowseTools(ctx, idleTimeout, 0)
+	tools := NewBrowseTools(ctx, idleTimeout)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -422,7 +441,7 @@ func TestBrowserCrashRecovery(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	tools := NewBrowseTools(ctx, 30*time.Minute, 0)
+	tools := NewBrowseTools(ctx, 30*time.Minute)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -471,10 +490,10 @@ func TestBrowserCrashRecovery(t *testing.T) {
 	}
 }
 
-func TestReadImageToolResizesLargeImage(t *testing.T) {
-	// Create a test BrowseTools instance with max dimension of 2000
+func TestReadImageToolKeepsFullSize(t *testing.T) {
+	// Images are fitted to the provider's limits by the llm package, not by the tool
 	ctx := context.Background()
-	browseTools := NewBrowseTools(ctx, 0, 2000)
+	browseTools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		browseTools.Close()
 	})
@@ -519,12 +538,11 @@ func TestReadImageToolResizesLargeImage(t *testing.T) {
 		t.Fatalf("Expected at least 2 content objects, got %d", len(result))
 	}
 
-	// Check that the description mentions resizing
-	if !strings.Contains(result[0].Text, "resized") {
-		t.Errorf("Expected description to mention resizing, got: %s", result[0].Text)
+	if strings.Contains(result[0].Text, "resized") {
+		t.Errorf("Expected no mention of resizing, got: %s", result[0].Text)
 	}
 
-	// Decode the returned image and verify dimensions are within limits
+	// Decode the returned image and verify it is full size
 	imageData, err := base64.StdEncoding.DecodeString(result[1].Data)
 	if err != nil {
 		t.Fatalf("Failed to decode base64 image: %v", err)
@@ -535,44 +553,110 @@ func TestReadImageToolResizesLargeImage(t *testing.T) {
 		t.Fatalf("Failed to decode image config: %v", err)
 	}
 
-	if config.Width > 2000 || config.Height > 2000 {
-		t.Errorf("Image dimensions still exceed 2000 pixels: %dx%d", config.Width, config.Height)
+	if config.Width != 3000 || config.Height != 2500 {
+		t.Errorf("Expected 3000x2500 image, got %dx%d", config.Width, config.Height)
 	}
+}
+
+func TestReadImageToolAnimatedGIF(t *testing.T) {
+	ctx := context.Background()
+	browseTools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		browseTools.Close()
+	})
 
-	t.Logf("Large image resized from 3000x2500 to %dx%d", config.Width, config.Height)
+	palette := color.Palette{color.Black, color.White}
+	anim := &gif.GIF{}
+	for i := range 5 {
+		frame := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+		frame.Pix[0] = uint8(i % 2)
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "anim.gif")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	toolOut := browseTools.readImageRun(ctx, []byte(fmt.Sprintf(`{"path": %q, "frames": 3}`, path)))
+	if toolOut.Error != nil {
+		t.Fatalf("Read image tool failed: %v", toolOut.Error)
+	}
+	if len(toolOut.LLMContent) != 4 || !strings.Contains(toolOut.LLMContent[0].Text, "3 evenly spaced frames of 5") {
+		t.Fatalf("Expected a description and 3 frames, got %d contents: %s", len(toolOut.LLMContent), toolOut.LLMContent[0].Text)
+	}
+	for _, c := range toolOut.LLMContent[1:] {
+		if c.MediaType != "image/png" {
+			t.Errorf("Expected png frames, got %s", c.MediaType)
+		}
+	}
+
+	if out := browseTools.readImageRun(ctx, []byte(fmt.Sprintf(`{"path": %q, "frames": 20}`, path))); out.Error == nil {
+		t.Error("Expected an error for too many frames")
+	}
 }
 
-// TestIsPort80 tests the isPort80 function
-func TestIsPort80(t *testing.T) {
-	tests := []struct {
-		url      string
-		expected bool
-		name     string
-	}{
-		{"http://example.com:80", true, "http with explicit port 80"},
-		{"http://example.com", true, "http without explicit port"},
-		{"https://example.com:80", true, "https with explicit port 80"},
-		{"http://example.com:8080", false, "http with different port"},
-		{"https://example.com", false, "https without explicit port"},
-		{"https://example.com:443", false, "https with standard port"},
-		{"invalid-url", false, "invalid URL"},
-		{"ftp://example.com:80", true, "ftp with port 80"},
+// TestDescribeNavigation tests how navigation results and redirects are reported
+func TestDescribeNavigation(t *testing.T) {
+	redirects := []*network.Response{
+		{URL: "http://example.com/account", Status: 301},
+		{URL: "https://example.com/account", Status: 302},
+	}
+	got := describeNavigation("https://example.com/login", &network.Response{Status: 200}, redirects)
+	want := "done: https://example.com/login (status 200) after 2 redirects:\n  301 http://example.com/account\n  302 https://example.com/account"
+	if got != want {
+		t.Errorf("describeNavigation() = %q, want %q", got, want)
+	}
+	if got := describeNavigation("about:blank", nil, nil); got != "done: about:blank" {
+		t.Errorf("describeNavigation(about:blank) = %q", got)
 	}
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := isPort80(tt.url)
-			if result != tt.expected {
-				t.Errorf("isPort80(%q) = %v, want %v", tt.url, result, tt.expected)
-			}
-		})
+// TestNavigateRedirects tests that browser_navigate reports the redirect chain
+func TestNavigateRedirects(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/account", http.RedirectHandler("/auth", http.StatusFound))
+	mux.Handle("/auth", http.RedirectHandler("/login", http.StatusSeeOther))
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<p>login</p>"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL + "/account"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+	want := fmt.Sprintf("done: %s/login (status 200) after 2 redirects:\n  302 %s/account\n  303 %s/auth", server.URL, server.URL, server.URL)
+	if text := toolOut.LLMContent[0].Text; !strings.Contains(text, want) {
+		t.Errorf("Expected %q, got %q", want, text)
 	}
 }
 
 // TestResizeRunErrorPaths tests error paths in resizeRun
 func TestResizeRunErrorPaths(t *testing.T) {
 	ctx := context.Background()
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -597,12 +681,56 @@ func TestResizeRunErrorPaths(t *testing.T) {
 	if toolOut.Error == nil {
 		t.Error("Expected error for zero width")
 	}
+
+	// Test with an unknown device, a device with dimensions or other options, and a negative scale
+	for _, input := range []string{
+		`{"device": "nokia-3310"}`,
+		`{"device": "ipad", "width": 100, "height": 100}`,
+		`{"device": "ipad", "mobile": true}`,
+		`{"width": 100, "height": 100, "device_scale_factor": -2}`,
+	} {
+		if toolOut = tools.resizeRun(ctx, []byte(input)); toolOut.Error == nil {
+			t.Errorf("Expected error for %s", input)
+		}
+	}
+}
+
+// TestResizeScaleAndMobile tests that browser_resize sets the device pixel ratio and mobile mode
+func TestResizeScaleAndMobile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<body></body>"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+	if out := tools.resizeRun(ctx, []byte(`{"width": 400, "height": 800, "device_scale_factor": 2, "mobile": true}`)); out.Error != nil {
+		t.Fatalf("resizeRun error: %v", out.Error)
+	}
+
+	toolOut = tools.evalRun(ctx, []byte(`{"expression": "innerWidth + 'x' + devicePixelRatio"}`))
+	if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, "400x2") {
+		t.Errorf("Expected a 400px wide viewport at 2x, got %v %v", toolOut.LLMContent, toolOut.Error)
+	}
 }
 
 // TestScreenshotRunErrorPaths tests error paths in screenshotRun
 func TestScreenshotRunErrorPaths(t *testing.T) {
 	ctx := context.Background()
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -613,11 +741,136 @@ func TestScreenshotRunErrorPaths(t *testing.T) {
 	if toolOut.Error == nil {
 		t.Error("No error expected for invalid JSON input in clearConsoleLogsRun")
 	}
+
+	// Invalid option combinations are rejected before the browser starts
+	for _, in := range []string{
+		`{"format": "gif"}`,
+		`{"quality": 50}`,
+		`{"quality": 0}`,
+		`{"format": "jpeg", "quality": 0}`,
+		`{"format": "jpeg", "quality": 101}`,
+		`{"selector": "div", "clip": {"x": 0, "y": 0, "width": 10, "height": 10}}`,
+		`{"clip": {"x": 0, "y": 0, "width": 0, "height": 10}}`,
+		`{"padding": 10}`,
+		`{"selector": "div", "padding": -1}`,
+		`{"scale": -2}`,
+		`{"full_page": true, "selector": "div"}`,
+		`{"full_page": true, "clip": {"x": 0, "y": 0, "width": 10, "height": 10}}`,
+		`{"scroll": true}`,
+		`{"full_page": true, "scroll": true, "format": "jpeg"}`,
+	} {
+		if toolOut := tools.screenshotRun(ctx, []byte(in)); toolOut.Error == nil {
+			t.Errorf("Expected error for %s", in)
+		}
+	}
+}
+
+func TestFullPageScreenshotTiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: `data:text/html,<body style="margin:0"><div style="height:3000px;background:linear-gradient(red,blue)"></div></body>`})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	// 3000px in 720px tiles overlapping by 72px takes 5 tiles
+	out := tools.screenshotRun(ctx, []byte(`{"full_page": true}`))
+	if out.Error != nil {
+		t.Fatalf("screenshotRun error: %v", out.Error)
+	}
+	if len(out.LLMContent) != 6 || !strings.Contains(out.LLMContent[0].Text, "5 overlapping tiles") {
+		t.Errorf("Expected 5 tiles, got %d contents: %s", len(out.LLMContent), out.LLMContent[0].Text)
+	}
+
+	// Scrolling captures the viewport at 0, 720, 1440, 2160, and 2280, and the last overlap is stitched away
+	out = tools.screenshotRun(ctx, []byte(`{"full_page": true, "scroll": true}`))
+	if out.Error != nil {
+		t.Fatalf("screenshotRun(scroll) error: %v", out.Error)
+	}
+	if len(out.LLMContent) != 6 || !strings.Contains(out.LLMContent[0].Text, "5 overlapping tiles") {
+		t.Errorf("Expected 5 tiles, got %d contents: %s", len(out.LLMContent), out.LLMContent[0].Text)
+	}
+}
+
+func TestScreenshotDisplayThumbnail(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, tt := range []struct {
+		width, height   int
+		wantThumbHeight int
+	}{
+		{1280, 640, 160},
+		{200, 100, 100},
+	} {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, tt.width, tt.height))); err != nil {
+			t.Fatal(err)
+		}
+		id := tools.SaveScreenshot(buf.Bytes(), "png")
+		display := map[string]any{}
+		if err := screenshotDisplay(id, "png", buf.Bytes(), display); err != nil {
+			t.Fatalf("screenshotDisplay() error: %v", err)
+		}
+		thumbURL, _ := display["thumbnail_url"].(string)
+		if tt.width <= thumbnailDimension && thumbURL != display["url"] {
+			t.Errorf("Expected a small screenshot to be its own thumbnail, got %q", thumbURL)
+		}
+		u, err := url.Parse(thumbURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(u.Query().Get("path"))
+		if err != nil {
+			t.Fatalf("Failed to read thumbnail: %v", err)
+		}
+		config, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Height != tt.wantThumbHeight {
+			t.Errorf("Thumbnail of %dx%d is %d high, want %d", tt.width, tt.height, config.Height, tt.wantThumbHeight)
+		}
+	}
+}
+
+func TestScreenshotInputDefaults(t *testing.T) {
+	in := screenshotInput{Format: "jpeg"}
+	if err := in.validate(); err != nil {
+		t.Fatalf("validate() error: %v", err)
+	}
+	if in.Quality == nil || *in.Quality != 80 {
+		t.Errorf("Quality = %v, want default 80", in.Quality)
+	}
+
+	in = screenshotInput{}
+	if err := in.validate(); err != nil {
+		t.Fatalf("validate() error: %v", err)
+	}
+	if in.Format != "png" {
+		t.Errorf("Format = %q, want default png", in.Format)
+	}
 }
 
 func TestRecentConsoleLogsRunErrorPaths(t *testing.T) {
 	ctx := context.Background()
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -659,19 +912,19 @@ func TestRegisterBrowserTools(t *testing.T) {
 	ctx := context.Background()
 
 	// Test with screenshots enabled
-	tools, cleanup := RegisterBrowserTools(ctx, true, 0)
+	tools, cleanup := RegisterBrowserTools(ctx, true)
 	t.Cleanup(cleanup)
 
-	if len(tools) != 7 {
-		t.Errorf("Expected 7 tools with screenshots, got %d", len(tools))
+	if len(tools) != 49 {
+		t.Errorf("Expected 49 tools with screenshots, got %d", len(tools))
 	}
 
 	// Test with screenshots disabled
-	tools, cleanup = RegisterBrowserTools(ctx, false, 0)
+	tools, cleanup = RegisterBrowserTools(ctx, false)
 	t.Cleanup(cleanup)
 
-	if len(tools) != 5 {
-		t.Errorf("Expected 5 tools without screenshots, got %d", len(tools))
+	if len(tools) != 42 {
+		t.Errorf("Expected 42 tools without screenshots, got %d", len(tools))
 	}
 
 	// Verify that cleanup function works (doesn't panic)
@@ -682,7 +935,7 @@ func TestRegisterBrowserTools(t *testing.T) {
 func TestGetScreenshotPath(t *testing.T) {
 	id := "test-id"
 	expected := filepath.Join(ScreenshotDir, id+".png")
-	actual := GetScreenshotPath(id)
+	actual := GetScreenshotPath(id, "png")
 
 	if actual != expected {
 		t.Errorf("GetScreenshotPath(%q) = %q, want %q", id, actual, expected)
@@ -692,26 +945,91 @@ func TestGetScreenshotPath(t *testing.T) {
 // TestSaveScreenshotErrorPath tests error paths in SaveScreenshot
 func TestSaveScreenshotErrorPath(t *testing.T) {
 	ctx := context.Background()
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
 
 	// Test with empty data (this should still work)
-	id := tools.SaveScreenshot([]byte{})
+	id := tools.SaveScreenshot([]byte{}, "png")
 	if id == "" {
 		t.Error("Expected non-empty ID for empty data")
 	}
 
 	// Clean up the test file
-	filePath := GetScreenshotPath(id)
+	filePath := GetScreenshotPath(id, "png")
 	os.Remove(filePath)
 }
 
+func TestSaveOptimizedScreenshot(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	var buf bytes.Buffer
+	if err := (&png.Encoder{CompressionLevel: png.NoCompression}).Encode(&buf, image.NewRGBA(image.Rect(0, 0, 200, 200))); err != nil {
+		t.Fatal(err)
+	}
+	id, err := tools.saveOptimizedScreenshot(buf.Bytes(), "png")
+	if err != nil {
+		t.Fatalf("saveOptimizedScreenshot() error: %v", err)
+	}
+	path := GetScreenshotPath(id, "png")
+	t.Cleanup(func() { os.Remove(path) })
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() >= int64(buf.Len()) {
+		t.Errorf("Saved screenshot is %d bytes, want fewer than %d", info.Size(), buf.Len())
+	}
+
+	if _, err := tools.saveOptimizedScreenshot([]byte("not a png"), "png"); err == nil {
+		t.Error("Expected an error for invalid PNG data")
+	}
+}
+
+func TestScreenshotToolOutStamp(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	img := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	out := tools.screenshotToolOut(buf.Bytes(), "png", 0, "2026-10-16 12:00:00 UTC | https://example.com | 400x100", map[string]any{})
+	if out.Error != nil {
+		t.Fatalf("screenshotToolOut() error: %v", out.Error)
+	}
+	path := out.Display.(map[string]any)["path"].(string)
+	t.Cleanup(func() { os.Remove(path) })
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _, _ := saved.At(398, 98).RGBA(); r == 0xffff {
+		t.Error("Expected the saved screenshot to be stamped")
+	}
+	if out.LLMContent[1].Data != base64.StdEncoding.EncodeToString(buf.Bytes()) {
+		t.Error("Expected the screenshot sent to the LLM not to be stamped")
+	}
+}
+
 // TestConsoleLogsWriteToFile tests that large console logs are written to file
 func TestConsoleLogsWriteToFile(t *testing.T) {
 	ctx := context.Background()
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -762,10 +1080,37 @@ func TestConsoleLogsWriteToFile(t *testing.T) {
 	}
 }
 
+// TestJSONToolOut tests that small JSON output is inlined and large output is written to a file
+func TestJSONToolOut(t *testing.T) {
+	toolOut := jsonToolOut("links", "Found 1 links", []byte(`[{"href":"https://example.com/"}]`))
+	if toolOut.Error != nil {
+		t.Fatalf("unexpected error: %v", toolOut.Error)
+	}
+	result := toolOut.LLMContent[0].Text
+	if !strings.Contains(result, `<links>[{"href":"https://example.com/"}]</links>`) {
+		t.Errorf("Expected inline result, got: %s", result)
+	}
+
+	toolOut = jsonToolOut("links", "Found many links", bytes.Repeat([]byte("x"), ConsoleLogSizeThreshold+1))
+	result = toolOut.LLMContent[0].Text
+	if !strings.Contains(result, "written to: "+ConsoleLogsDir) {
+		t.Fatalf("Expected output written to file, got: %s", result)
+	}
+	filePath := strings.Split(strings.Split(result, "written to: ")[1], "\n")[0]
+	defer os.Remove(filePath)
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if len(content) != ConsoleLogSizeThreshold+1 {
+		t.Errorf("Expected %d bytes in file, got %d", ConsoleLogSizeThreshold+1, len(content))
+	}
+}
+
 // TestGenerateDownloadFilename tests filename generation with randomness
 func TestGenerateDownloadFilename(t *testing.T) {
 	ctx := context.Background()
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -811,7 +1156,7 @@ func TestGenerateDownloadFilename(t *testing.T) {
 // TestDownloadTracking tests the download event handling
 func TestDownloadTracking(t *testing.T) {
 	ctx := context.Background()
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -860,7 +1205,7 @@ func TestDownloadTracking(t *testing.T) {
 // TestToolOutWithDownloads tests the download info appending to tool output
 func TestToolOutWithDownloads(t *testing.T) {
 	ctx := context.Background()
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -947,7 +1292,7 @@ func TestBrowserDownload(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -1035,7 +1380,7 @@ func TestBrowserDownloadReported(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -1082,7 +1427,7 @@ func TestLargeJSOutputWriteToFile(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -1147,7 +1492,7 @@ func TestSmallJSOutputInline(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	tools := NewBrowseTools(ctx, 0, 0)
+	tools := NewBrowseTools(ctx, 0)
 	t.Cleanup(func() {
 		tools.Close()
 	})
@@ -1182,3 +1527,70 @@ func TestSmallJSOutputInline(t *testing.T) {
 		t.Errorf("Small result should not be written to file, got: %s", result)
 	}
 }
+
+// TestTruncateUTF8 tests that truncation never splits a multi-byte character
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"héllo", 2, "h"},
+		{"héllo", 3, "hé"},
+		{"日本", 4, "日"},
+		{"日本", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := string(truncateUTF8([]byte(tt.in), tt.n)); got != tt.want {
+			t.Errorf("truncateUTF8(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
+
+// TestEvalRunInvalidResultLimit tests that a negative max_result_bytes is rejected before starting the browser
+func TestEvalRunInvalidResultLimit(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	out := tools.evalRun(ctx, []byte(`{"expression": "1", "max_result_bytes": -1}`))
+	if out.Error == nil || !strings.Contains(out.Error.Error(), "max_result_bytes") {
+		t.Errorf("Expected max_result_bytes error, got %v", out.Error)
+	}
+}
+
+// TestEvalWithArgs tests that args are passed to a function expression as JSON values
+func TestEvalWithArgs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	toolOut := tools.NewNavigateTool().Run(ctx, []byte(`{"url": "about:blank"}`))
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	evalInput := []byte(`{"expression": "async function(s, o) { return s + ':' + o.n + ':' + (this === window); }", "args": ["it's \"quoted\"", {"n": 2}]}`)
+	toolOut = tools.evalRun(ctx, evalInput)
+	if toolOut.Error != nil {
+		t.Fatalf("Eval error: %v", toolOut.Error)
+	}
+	if want := `"it's \"quoted\":2:true"`; !strings.Contains(toolOut.LLMContent[0].Text, want) {
+		t.Errorf("Expected %s, got: %s", want, toolOut.LLMContent[0].Text)
+	}
+}
@@ -1,2 +1,42 @@
 // Package browse contains browser automation tools
 package browse
+
+import (
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// devicePreset is a device whose viewport browser_resize can emulate
+type devicePreset struct {
+	Width, Height int64
+	Scale         float64
+	// Mobile devices get a mobile viewport (meta viewport tags apply) and touch input
+	Mobile bool
+}
+
+// devicePresets are the devices browser_resize accepts by name
+var devicePresets = map[string]devicePreset{
+	"desktop":   {Width: 1280, Height: 720, Scale: 1},
+	"iphone-15": {Width: 393, Height: 852, Scale: 3, Mobile: true},
+	"iphone-se": {Width: 375, Height: 667, Scale: 2, Mobile: true},
+	"pixel-7":   {Width: 412, Height: 915, Scale: 2.625, Mobile: true},
+	"ipad":      {Width: 820, Height: 1180, Scale: 2, Mobile: true},
+}
+
+// emulate returns the action that emulates the device's viewport and input
+func (d devicePreset) emulate() chromedp.Action {
+	opts := []chromedp.EmulateViewportOption{chromedp.EmulateScale(d.Scale)}
+	if d.Mobile {
+		opts = append(opts, chromedp.EmulateMobile, emulateTouchPoints)
+	}
+	return chromedp.EmulateViewport(d.Width, d.Height, opts...)
+}
+
+// maxTouchPoints is the number of simultaneous touches emulated touch screens support
+const maxTouchPoints = 5
+
+// emulateTouchPoints enables touch events with multi-touch support
+func emulateTouchPoints(_ *emulation.SetDeviceMetricsOverrideParams, p *emulation.SetTouchEmulationEnabledParams) {
+	p.Enabled = true
+	p.MaxTouchPoints = maxTouchPoints
+}
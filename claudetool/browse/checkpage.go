@@ -0,0 +1,194 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"golang.org/x/sync/errgroup"
+	"shelley.exe.dev/llm"
+)
+
+// checkPageJS collects the current page's broken images, unique http(s) links (without
+// fragments) with their text, and the insecure resources of an https page
+const checkPageJS = `function() {
+	const brokenImages = Array.from(document.images)
+		.filter(img => img.complete && img.naturalWidth === 0 && (img.currentSrc || img.src))
+		.map(img => ({src: img.currentSrc || img.src, alt: img.alt}));
+
+	const links = new Map();
+	for (const a of document.querySelectorAll('a[href], area[href]')) {
+		if (a.protocol !== 'http:' && a.protocol !== 'https:') continue;
+		const u = new URL(a.href);
+		u.hash = '';
+		if (!links.has(u.href)) links.set(u.href, (a.innerText || a.getAttribute('aria-label') || '').replace(/\s+/g, ' ').trim().slice(0, 100));
+	}
+
+	const mixed = new Set();
+	if (location.protocol === 'https:') {
+		for (const e of performance.getEntriesByType('resource')) {
+			if (e.name.startsWith('http:')) mixed.add(e.name);
+		}
+		for (const el of document.querySelectorAll('[src], link[href], form[action]')) {
+			const u = el.src || el.href || el.action;
+			if (typeof u === 'string' && u.startsWith('http:')) mixed.add(u);
+		}
+	}
+
+	return {
+		broken_images: brokenImages,
+		links: Array.from(links, ([url, text]) => ({url, text})),
+		mixed_content: Array.from(mixed),
+	};
+}`
+
+// checkedLink is a link reported by browser_check_page
+type checkedLink struct {
+	URL    string `json:"url"`
+	Text   string `json:"text,omitempty"`
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// checkPageReport is the result of browser_check_page
+type checkPageReport struct {
+	BrokenImages []struct {
+		Src string `json:"src"`
+		Alt string `json:"alt,omitempty"`
+	} `json:"broken_images"`
+	BrokenLinks  []checkedLink `json:"broken_links"`
+	MixedContent []string      `json:"mixed_content"`
+	LinksChecked int           `json:"links_checked"`
+	// LinksSkipped counts links beyond max_links and links the navigation policy disallows
+	LinksSkipped int `json:"links_skipped,omitempty"`
+}
+
+// checkLink fetches url with HEAD, retrying with GET if the server rejects HEAD,
+// and returns the final status after redirects
+func checkLink(ctx context.Context, client *http.Client, url string) (int, error) {
+	status := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		status = resp.StatusCode
+		if status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented {
+			break
+		}
+	}
+	return status, nil
+}
+
+// CheckPageTool definition
+type checkPageInput struct {
+	Links       *bool  `json:"links,omitempty" description:"Check links (default: true)"`
+	MaxLinks    int    `json:"max_links,omitempty" description:"Maximum number of links to check (default: 200)"`
+	Concurrency int    `json:"concurrency,omitempty" description:"Maximum number of links checked at once (default: 8)"`
+	Timeout     string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 60s)"`
+}
+
+// NewCheckPageTool creates a tool for finding broken images and links and mixed content
+func (b *BrowseTools) NewCheckPageTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_check_page",
+		Description: `Check the current page for broken images, broken links (each http(s) link is requested with HEAD; 4xx, 5xx, and network errors count as broken),
+and mixed content (http resources on an https page). Link checks are made from the server without the browser's cookies.`,
+		InputSchema: llm.SchemaFor[checkPageInput](),
+		Run:         b.checkPageRun,
+	}
+}
+
+func (b *BrowseTools) checkPageRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input checkPageInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.MaxLinks < 0 || input.Concurrency < 0 {
+		return llm.ErrorfToolOut("max_links and concurrency must not be negative")
+	}
+	if input.MaxLinks == 0 {
+		input.MaxLinks = 200
+	}
+	if input.Concurrency == 0 {
+		input.Concurrency = 8
+	}
+	timeout := 60 * time.Second
+	if input.Timeout != "" {
+		timeout = parseTimeout(input.Timeout)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, timeout)
+	defer cancel()
+
+	var found struct {
+		checkPageReport
+		Links []checkedLink `json:"links"`
+	}
+	if err := chromedp.Run(timeoutCtx, callWithArgs(checkPageJS, nil, false, &found)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	report := found.checkPageReport
+	report.BrokenLinks = []checkedLink{}
+
+	if input.Links == nil || *input.Links {
+		var toCheck []checkedLink
+		for _, link := range found.Links {
+			if len(toCheck) == input.MaxLinks || (b.policy != nil && b.policy.Check(timeoutCtx, link.URL) != nil) {
+				report.LinksSkipped++
+				continue
+			}
+			toCheck = append(toCheck, link)
+		}
+
+		client := &http.Client{Timeout: 10 * time.Second}
+		if b.policy != nil {
+			client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+				return b.policy.Check(req.Context(), req.URL.String())
+			}
+		}
+		broken := make([]bool, len(toCheck))
+		var eg errgroup.Group
+		eg.SetLimit(input.Concurrency)
+		for i := range toCheck {
+			eg.Go(func() error {
+				link := &toCheck[i]
+				status, err := checkLink(timeoutCtx, client, link.URL)
+				link.Status = status
+				if err != nil {
+					link.Error = err.Error()
+				}
+				broken[i] = err != nil || status >= 400
+				return nil
+			})
+		}
+		eg.Wait()
+		for i, link := range toCheck {
+			if broken[i] {
+				report.BrokenLinks = append(report.BrokenLinks, link)
+			}
+		}
+		report.LinksChecked = len(toCheck)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal report: %w", err)
+	}
+	summary := fmt.Sprintf("%d broken images, %d broken links of %d checked, %d mixed content resources",
+		len(report.BrokenImages), len(report.BrokenLinks), report.LinksChecked, len(report.MixedContent))
+	return jsonToolOut("page_check", summary, data)
+}
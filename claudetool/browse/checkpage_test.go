@@ -0,0 +1,81 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckLink(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/missing", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for path, want := range map[string]int{"/ok": 200, "/no-head": 200, "/moved": 404} {
+		status, err := checkLink(context.Background(), server.Client(), server.URL+path)
+		if err != nil || status != want {
+			t.Errorf("checkLink(%s) = %d, %v; want %d", path, status, err, want)
+		}
+	}
+}
+
+func TestCheckPage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<a href="/ok">fine</a><a href="/ok#top">fine again</a><a href="/gone">dead</a><img src="/missing.png" alt="logo">`))
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	if out := tools.checkPageRun(ctx, []byte(`{"max_links": -1}`)); out.Error == nil {
+		t.Error("expected an error for negative max_links")
+	}
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.checkPageRun(ctx, []byte(`{}`))
+	if out.Error != nil {
+		t.Fatalf("checkPageRun error: %v", out.Error)
+	}
+	text := out.LLMContent[0].Text
+	if !strings.Contains(text, "1 broken images, 1 broken links of 2 checked") {
+		t.Errorf("unexpected summary: %s", text)
+	}
+	if !strings.Contains(text, `"url":"`+server.URL+`/gone","text":"dead","status":404`) || !strings.Contains(text, `"alt":"logo"`) {
+		t.Errorf("unexpected report: %s", text)
+	}
+}
@@ -0,0 +1,105 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// ClickTool definition
+type clickInput struct {
+	Selector string `json:"selector" description:"The element to click, as a CSS selector or XPath expression (see selector_type)"`
+	selectorTypeInput
+	frameInput
+	WaitForNavigation bool   `json:"wait_for_navigation,omitempty" description:"Wait until the navigation triggered by the click finishes loading, so a following screenshot doesn't catch a blank page (default: false)"`
+	Timeout           string `json:"timeout,omitempty" description:"Timeout as a Go duration string, including any navigation (default: 15s)"`
+}
+
+// NewClickTool creates a tool for clicking elements
+func (b *BrowseTools) NewClickTool() *llm.Tool {
+	return &llm.Tool{
+		Name:        "browser_click",
+		Description: "Click an element with a real mouse click, optionally waiting for the page load it triggers to finish",
+		InputSchema: llm.SchemaFor[clickInput](),
+		Run:         b.clickRun,
+	}
+}
+
+func (b *BrowseTools) clickRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input clickInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Selector == "" {
+		return llm.ErrorfToolOut("selector is required")
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var iframe *cdp.Node
+	if input.Frame != "" {
+		if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+
+	var node *cdp.Node
+	if err := chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		node, err = queryNode(ctx, input.Selector, input.SelectorType, iframe)
+		return err
+	})); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	if !input.WaitForNavigation {
+		if err := chromedp.Run(timeoutCtx, chromedp.MouseClickNode(node)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		return b.actionToolOut("done")
+	}
+
+	if err := chromedp.Run(timeoutCtx, clickAndWaitForNavigation(node)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	return b.actionToolOut("done; navigation finished")
+}
+
+// clickAndWaitForNavigation clicks node, then waits for the page to finish loading
+// (or for a same-document navigation, such as a history.pushState route change)
+func clickAndWaitForNavigation(node *cdp.Node) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		listenCtx, stop := context.WithCancel(ctx)
+		defer stop()
+		loaded := make(chan struct{}, 1)
+		chromedp.ListenTarget(listenCtx, func(ev any) {
+			switch ev.(type) {
+			case *page.EventLoadEventFired, *page.EventNavigatedWithinDocument:
+				select {
+				case loaded <- struct{}{}:
+				default:
+				}
+			}
+		})
+
+		if err := chromedp.MouseClickNode(node).Do(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-loaded:
+		case <-ctx.Done():
+			return fmt.Errorf("click did not lead to a finished navigation: %w", ctx.Err())
+		}
+		return chromedp.WaitReady("body").Do(ctx)
+	})
+}
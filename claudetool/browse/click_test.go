@@ -0,0 +1,57 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClickRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{`{`, `{}`, `{"selector": "a", "wait_for_navigation": "yes"}`} {
+		if out := tools.clickRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("clickRun(%s) succeeded, want error", input)
+		}
+	}
+}
+
+func TestClickWaitForNavigation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	// Chrome blocks page-initiated navigation to data: URLs, so go to about:blank
+	html := `<a id="go" href="about:blank" onclick="event.preventDefault(); setTimeout(() => location.href = this.href, 300)">go</a>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.NewNavigateTool().Run(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	toolOut = tools.clickRun(ctx, []byte(`{"selector": "#go", "wait_for_navigation": true}`))
+	if toolOut.Error != nil {
+		t.Fatalf("clickRun error: %v", toolOut.Error)
+	}
+	toolOut = tools.evalRun(ctx, []byte(`{"expression": "location.href"}`))
+	if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, "about:blank") {
+		t.Errorf("Expected to be on about:blank, got %v %v", toolOut.LLMContent, toolOut.Error)
+	}
+}
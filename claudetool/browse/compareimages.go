@@ -0,0 +1,68 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/imageutil"
+)
+
+// CompareImagesTool definition
+type compareImagesInput struct {
+	Before string `json:"before" description:"Path to the first image"`
+	After  string `json:"after" description:"Path to the second image"`
+}
+
+// NewCompareImagesTool creates a tool for comparing two image files pixel by pixel
+func (b *BrowseTools) NewCompareImagesTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "compare_images",
+		Description: `Compare two image files, such as screenshots of a page before and after a change, and report how similar they are,
+where they differ, and the path of a diff image with the differing pixels in red. Use read_image on the diff to see what changed.
+Images of different sizes are aligned at the top left.`,
+		InputSchema: llm.SchemaFor[compareImagesInput](),
+		Run:         b.compareImagesRun,
+	}
+}
+
+func (b *BrowseTools) compareImagesRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input compareImagesInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Before == "" || input.After == "" {
+		return llm.ErrorfToolOut("before and after are required")
+	}
+	before, err := os.ReadFile(input.Before)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to read image file: %w", err)
+	}
+	after, err := os.ReadFile(input.After)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to read image file: %w", err)
+	}
+
+	diff, err := imageutil.Diff(before, after)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	id, err := b.saveOptimizedScreenshot(diff.Image, "png")
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	display := map[string]any{}
+	if err := screenshotDisplay(id, "png", diff.Image, display); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	text := fmt.Sprintf("The images are identical (similarity 100%%). Diff image saved as %s", display["path"])
+	if diff.Mismatched > 0 {
+		r := diff.Bounds
+		text = fmt.Sprintf("%d pixels differ (similarity %.2f%%), all within x %d-%d, y %d-%d. Diff image saved as %s",
+			diff.Mismatched, 100*(1-diff.Score), r.Min.X, r.Max.X, r.Min.Y, r.Max.Y, display["path"])
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent(text), Display: display}
+}
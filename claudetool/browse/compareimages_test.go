@@ -0,0 +1,73 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareImagesTool(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	dir := t.TempDir()
+	writePNG := func(name string, img image.Image) string {
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := png.Encode(f, img); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	before := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	beforePath := writePNG("before.png", before)
+	after := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for y := 10; y < 20; y++ {
+		for x := 30; x < 40; x++ {
+			after.Set(x, y, color.Black)
+		}
+	}
+	afterPath := writePNG("after.png", after)
+
+	input, _ := json.Marshal(compareImagesInput{Before: beforePath, After: afterPath})
+	out := tools.compareImagesRun(ctx, input)
+	if out.Error != nil {
+		t.Fatalf("compareImagesRun error: %v", out.Error)
+	}
+	if text := out.LLMContent[0].Text; !strings.Contains(text, "100 pixels differ (similarity 99.00%), all within x 30-40, y 10-20") {
+		t.Errorf("Unexpected comparison: %s", text)
+	}
+	path, _ := out.Display.(map[string]any)["path"].(string)
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected a diff image at %q: %v", path, err)
+	}
+
+	input, _ = json.Marshal(compareImagesInput{Before: beforePath, After: beforePath})
+	if out := tools.compareImagesRun(ctx, input); out.Error != nil || !strings.Contains(out.LLMContent[0].Text, "identical") {
+		t.Errorf("Expected identical images, got %+v", out)
+	}
+
+	for _, input := range []string{
+		`{`,
+		`{"before": "` + beforePath + `"}`,
+		`{"before": "` + beforePath + `", "after": "/nonexistent.png"}`,
+		`{"before": "` + beforePath + `", "after": "compareimages.go"}`,
+	} {
+		if out := tools.compareImagesRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("Expected an error for %s", input)
+		}
+	}
+}
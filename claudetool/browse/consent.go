@@ -0,0 +1,101 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+)
+
+// consentWaitMillis is how long to wait for a consent banner to appear after navigation
+const consentWaitMillis = 2000
+
+// dismissConsentJS clicks the reject (or accept) button of a cookie/consent banner.
+// It first tries the buttons of common consent management platforms, then buttons whose
+// text matches inside a visible container that looks like a consent banner, searching
+// open shadow roots too. Banners are often injected after load, so it retries until
+// waitMillis has passed. It returns the clicked button's text, or null.
+const dismissConsentJS = `async function(prefer, waitMillis) {
+	const known = {
+		reject: ['#onetrust-reject-all-handler', '#CybotCookiebotDialogBodyButtonDecline', '#didomi-notice-disagree-button',
+			'[data-testid="uc-deny-all-button"]', '.qc-cmp2-summary-buttons button[mode="secondary"]', '#truste-consent-required',
+			'.cc-deny', '.fc-cta-do-not-consent', '.sp_choice_type_13'],
+		accept: ['#onetrust-accept-btn-handler', '#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll',
+			'#CybotCookiebotDialogBodyButtonAccept', '#didomi-notice-agree-button', '[data-testid="uc-accept-all-button"]',
+			'.qc-cmp2-summary-buttons button[mode="primary"]', '#truste-consent-button', '.cc-allow', '.cc-dismiss',
+			'.fc-cta-consent', '.sp_choice_type_11', '#L2AGLb'],
+	};
+	const patterns = {
+		reject: /^(reject|reject all|reject all cookies|decline|decline all|deny|deny all|refuse|refuse all|disagree|do not consent|only necessary|necessary only|only essential|essential only|(use |accept |allow )?(only )?(strictly )?(necessary|essential|required) cookies( only)?|continue without accepting|alle ablehnen|ablehnen|tout refuser|refuser|rechazar( todo)?|rifiuta( tutto)?)$/i,
+		accept: /^(accept|accept all|accept all cookies|accept cookies|accept & close|accept and close|allow|allow all|allow all cookies|allow cookies|agree|i agree|agree and close|i accept|ok|okay|got it|understood|alle akzeptieren|akzeptieren|tout accepter|accepter|aceptar( todo)?|accetta( tutto)?)$/i,
+	};
+	const order = prefer === 'accept' ? ['accept', 'reject'] : ['reject', 'accept'];
+	const consentish = /cookie|consent|gdpr|privacy|cmp|tracking/i;
+
+	const visible = (el) => {
+		const r = el.getBoundingClientRect();
+		const s = getComputedStyle(el);
+		return r.width > 0 && r.height > 0 && s.visibility !== 'hidden' && s.display !== 'none';
+	};
+	const text = (el) => (el.innerText || el.value || el.getAttribute('aria-label') || '').replace(/\s+/g, ' ').trim();
+	const roots = () => {
+		const out = [document];
+		for (let i = 0; i < out.length; i++) {
+			for (const el of out[i].querySelectorAll('*')) if (el.shadowRoot) out.push(el.shadowRoot);
+		}
+		return out;
+	};
+	const inConsentContainer = (el) => {
+		for (let n = el; n; n = n.parentElement || n.getRootNode().host) {
+			if (n.nodeType !== 1) continue;
+			const label = n.id + ' ' + (typeof n.className === 'string' ? n.className : '') + ' ' + (n.getAttribute('aria-label') || '');
+			if (consentish.test(label)) return true;
+			if ((n.getAttribute('role') === 'dialog' || n.tagName === 'DIALOG') && consentish.test(n.innerText || '')) return true;
+		}
+		return false;
+	};
+
+	const find = () => {
+		const all = roots();
+		for (const kind of order) {
+			for (const root of all) {
+				for (const sel of known[kind]) {
+					const el = root.querySelector(sel);
+					if (el && visible(el)) return el;
+				}
+			}
+		}
+		for (const kind of order) {
+			for (const root of all) {
+				for (const el of root.querySelectorAll('button, a, [role=button], input[type=button], input[type=submit]')) {
+					if (patterns[kind].test(text(el)) && visible(el) && inConsentContainer(el)) return el;
+				}
+			}
+		}
+		return null;
+	};
+
+	const deadline = Date.now() + waitMillis;
+	for (;;) {
+		const el = find();
+		if (el) {
+			const t = text(el) || el.tagName.toLowerCase();
+			el.click();
+			return t;
+		}
+		if (Date.now() >= deadline) return null;
+		await new Promise(resolve => setTimeout(resolve, 250));
+	}
+}`
+
+// dismissConsentBanner clicks the button preferred by prefer ("reject" or "accept") on a
+// cookie/consent banner in the current page, falling back to the other. It returns the
+// clicked button's text, or "" if no banner was found.
+func dismissConsentBanner(ctx context.Context, prefer string) (string, error) {
+	var clicked *string
+	if err := callWithArgs(dismissConsentJS, []any{prefer, consentWaitMillis}, true, &clicked).Do(ctx); err != nil {
+		return "", fmt.Errorf("failed to dismiss consent banner: %w", err)
+	}
+	if clicked == nil {
+		return "", nil
+	}
+	return *clicked, nil
+}
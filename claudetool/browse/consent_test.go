@@ -0,0 +1,58 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNavigateInvalidDismissConsent(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	out := tools.navigateRun(ctx, []byte(`{"url": "about:blank", "dismiss_consent": "ignore"}`))
+	if out.Error == nil || !strings.Contains(out.Error.Error(), "unsupported dismiss_consent") {
+		t.Errorf("Expected unsupported dismiss_consent error, got %v", out.Error)
+	}
+}
+
+func TestNavigateDismissConsent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	// The banner appears after load, as injected consent scripts do
+	html := `<p>content</p><script>setTimeout(() => document.body.insertAdjacentHTML('beforeend',` +
+		` '<div class="cookie-banner"><button onclick="document.title=this.innerText">Accept all</button>` +
+		`<button onclick="document.title=this.innerText">Reject all</button></div>'), 300)</script>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html, DismissConsent: "reject"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+	if text := toolOut.LLMContent[0].Text; !strings.Contains(text, `clicking "Reject all"`) {
+		t.Errorf("Expected the reject button to be clicked, got %q", text)
+	}
+
+	navInput, _ = json.Marshal(navigateInput{URL: "data:text/html,<p>no banner</p>", DismissConsent: "accept"})
+	toolOut = tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, "No consent banner found") {
+		t.Errorf("Expected no banner, got %v %v", toolOut.LLMContent, toolOut.Error)
+	}
+}
@@ -0,0 +1,222 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/imageutil"
+)
+
+// maxCrawlErrorsPerPage bounds the console errors reported for each crawled page
+const maxCrawlErrorsPerPage = 10
+
+// crawledPage is a page visited by browser_crawl
+type crawledPage struct {
+	URL    string `json:"url"`
+	Depth  int    `json:"depth"`
+	Status int64  `json:"status,omitempty"`
+	Title  string `json:"title,omitempty"`
+	// Links is the number of new same-origin links found on the page
+	Links         int      `json:"links,omitempty"`
+	ConsoleErrors []string `json:"console_errors,omitempty"`
+	Error         string   `json:"error,omitempty"`
+	// Screenshot is the path of the page's screenshot, if requested
+	Screenshot string `json:"screenshot,omitempty"`
+}
+
+// failed reports whether the page failed to load, returned an error status, or logged errors
+func (p *crawledPage) failed() bool {
+	return p.Error != "" || p.Status >= 400 || len(p.ConsoleErrors) > 0
+}
+
+// consoleArgsText joins console call arguments the way the console displays them
+func consoleArgsText(args []*runtime.RemoteObject) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		var s string
+		if arg.Value != nil && json.Unmarshal(arg.Value, &s) == nil {
+			parts[i] = s
+		} else if arg.Value != nil {
+			parts[i] = string(arg.Value)
+		} else {
+			parts[i] = arg.Description
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// crawlKey normalizes a URL for deduplication by dropping its fragment
+func crawlKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Fragment = ""
+	return u.String()
+}
+
+// CrawlTool definition
+type crawlInput struct {
+	URL         string `json:"url,omitempty" description:"Where to start (default: the current page)"`
+	MaxDepth    *int   `json:"max_depth,omitempty" description:"How many links away from the start to go (default: 2)"`
+	MaxPages    int    `json:"max_pages,omitempty" description:"Maximum number of pages to visit (default: 20)"`
+	Screenshots bool   `json:"screenshots,omitempty" description:"Save a screenshot of each page loaded and report its path (default: false)"`
+	Timeout     string `json:"timeout,omitempty" description:"Timeout for loading each page as a Go duration string (default: 15s)"`
+}
+
+// NewCrawlTool creates a tool for smoke-testing a small site
+func (b *BrowseTools) NewCrawlTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_crawl",
+		Description: `Visit a page and the same-origin pages it links to, breadth first, reporting each page's HTTP status, title, and console errors.
+Useful for smoke-testing a small site after deploying it. The browser is left on the last page visited.`,
+		InputSchema: llm.SchemaFor[crawlInput](),
+		Run:         b.crawlRun,
+	}
+}
+
+func (b *BrowseTools) crawlRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input crawlInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	maxDepth := 2
+	if input.MaxDepth != nil {
+		maxDepth = *input.MaxDepth
+	}
+	if maxDepth < 0 || input.MaxPages < 0 {
+		return llm.ErrorfToolOut("max_depth and max_pages must not be negative")
+	}
+	if input.MaxPages == 0 {
+		input.MaxPages = 20
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	if input.URL == "" {
+		if err := chromedp.Run(browserCtx, chromedp.Location(&input.URL)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+
+	// Collect errors logged by the page being visited
+	listenCtx, stopListening := context.WithCancel(browserCtx)
+	defer stopListening()
+	var errorsMutex sync.Mutex
+	var pageErrors []string
+	chromedp.ListenTarget(listenCtx, func(ev any) {
+		var msg string
+		switch e := ev.(type) {
+		case *runtime.EventConsoleAPICalled:
+			if e.Type != runtime.APITypeError {
+				return
+			}
+			msg = consoleArgsText(e.Args)
+		case *runtime.EventExceptionThrown:
+			msg = e.ExceptionDetails.Text
+			if e.ExceptionDetails.Exception != nil {
+				msg += " " + e.ExceptionDetails.Exception.Description
+			}
+		default:
+			return
+		}
+		errorsMutex.Lock()
+		pageErrors = append(pageErrors, msg)
+		errorsMutex.Unlock()
+	})
+
+	var pages, captured []*crawledPage
+	var captures [][]byte
+	seen := map[string]bool{crawlKey(input.URL): true}
+	queue := []*crawledPage{{URL: input.URL}}
+	for len(queue) > 0 && len(pages) < input.MaxPages && ctx.Err() == nil {
+		p := queue[0]
+		queue = queue[1:]
+		pages = append(pages, p)
+		if err := b.allowNavigation(ctx, p.URL); err != nil {
+			p.Error = err.Error()
+			continue
+		}
+
+		errorsMutex.Lock()
+		pageErrors = nil
+		errorsMutex.Unlock()
+
+		var links []pageLink
+		var capture []byte
+		pageCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+		resp, err := chromedp.RunResponse(pageCtx, chromedp.Navigate(p.URL))
+		if err == nil {
+			err = chromedp.Run(pageCtx,
+				chromedp.WaitReady("body"),
+				chromedp.Title(&p.Title),
+				chromedp.Evaluate(fmt.Sprintf("(%s)(true)", extractLinksJS), &links))
+		}
+		if err == nil && input.Screenshots {
+			err = chromedp.Run(pageCtx, chromedp.CaptureScreenshot(&capture))
+		}
+		cancel()
+		if resp != nil {
+			p.Status = resp.Status
+		}
+		if err != nil {
+			p.Error = err.Error()
+		} else if capture != nil {
+			captured = append(captured, p)
+			captures = append(captures, capture)
+		}
+
+		errorsMutex.Lock()
+		p.ConsoleErrors = pageErrors[:min(len(pageErrors), maxCrawlErrorsPerPage)]
+		errorsMutex.Unlock()
+
+		if p.Depth == maxDepth {
+			continue
+		}
+		for _, link := range links {
+			key := crawlKey(link.Href)
+			if seen[key] || !strings.HasPrefix(key, "http") {
+				continue
+			}
+			seen[key] = true
+			p.Links++
+			queue = append(queue, &crawledPage{URL: key, Depth: p.Depth + 1})
+		}
+	}
+
+	// Optimizing is slow, so it is done for all the screenshots at once rather than while crawling
+	optimized, err := imageutil.OptimizePNGs(captures)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to optimize screenshots: %w", err)
+	}
+	for i, data := range optimized {
+		id := b.SaveScreenshot(data, "png")
+		if id == "" {
+			return llm.ErrorfToolOut("failed to save screenshot")
+		}
+		captured[i].Screenshot = GetScreenshotPath(id, "png")
+	}
+
+	failed := 0
+	for _, p := range pages {
+		if p.failed() {
+			failed++
+		}
+	}
+	data, err := json.Marshal(pages)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal crawl results: %w", err)
+	}
+	summary := fmt.Sprintf("Crawled %d pages (%d more found but not visited); %d had errors", len(pages), len(queue), failed)
+	return jsonToolOut("crawl", summary, data)
+}
@@ -0,0 +1,94 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+)
+
+func TestConsoleArgsText(t *testing.T) {
+	args := []*runtime.RemoteObject{
+		{Type: "string", Value: []byte(`"failed:"`)},
+		{Type: "number", Value: []byte(`42`)},
+		{Type: "object", Description: "Error: boom"},
+	}
+	if got, want := consoleArgsText(args), "failed: 42 Error: boom"; got != want {
+		t.Errorf("consoleArgsText() = %q, want %q", got, want)
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	pages := map[string]string{
+		"/":  `<title>Home</title><a href="/a">a</a><a href="/a#x">a again</a><a href="/missing">b</a><a href="https://example.com/">external</a>`,
+		"/a": `<title>A</title><script>console.error('broken widget')</script><a href="/deep">deep</a>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		html, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(html))
+	}))
+	defer server.Close()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	if out := tools.crawlRun(ctx, []byte(`{"max_depth": -1}`)); out.Error == nil {
+		t.Error("expected an error for negative max_depth")
+	}
+
+	navInput, _ := json.Marshal(navigateInput{URL: "about:blank"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.crawlRun(ctx, []byte(`{"url": "`+server.URL+`/", "max_depth": 1}`))
+	if out.Error != nil {
+		t.Fatalf("crawlRun error: %v", out.Error)
+	}
+	text := out.LLMContent[0].Text
+	if !strings.Contains(text, "Crawled 3 pages (1 more found but not visited); 2 had errors") {
+		t.Errorf("unexpected summary: %s", text)
+	}
+	for _, want := range []string{`"title":"Home"`, `"status":404`, `"console_errors":["broken widget"]`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %s in %s", want, text)
+		}
+	}
+	if strings.Contains(text, "example.com") {
+		t.Errorf("crawled another origin: %s", text)
+	}
+	if strings.Contains(text, `"screenshot"`) {
+		t.Errorf("expected no screenshots unless requested: %s", text)
+	}
+
+	out = tools.crawlRun(ctx, []byte(`{"url": "`+server.URL+`/", "max_depth": 0, "screenshots": true}`))
+	if out.Error != nil {
+		t.Fatalf("crawlRun error: %v", out.Error)
+	}
+	if text := out.LLMContent[0].Text; !strings.Contains(text, `"screenshot":"`+ScreenshotDir) {
+		t.Errorf("expected a screenshot path in %s", text)
+	}
+}
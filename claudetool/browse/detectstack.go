@@ -0,0 +1,134 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// detectedLibrary is a framework or library found by browser_detect_stack
+type detectedLibrary struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Evidence says what gave it away, e.g. a global or a script URL
+	Evidence string `json:"evidence"`
+}
+
+// detectStackJS finds frameworks from runtime globals, DOM markers left by their renderers,
+// and the file names of loaded scripts and stylesheets. Earlier, more reliable evidence wins.
+const detectStackJS = `(() => {
+	const found = new Map();
+	const add = (name, version, evidence) => {
+		const prev = found.get(name);
+		if (!prev) found.set(name, {name, version: version || '', evidence});
+		else if (!prev.version && version) found.set(name, {name, version, evidence});
+	};
+
+	if (window.React) add('React', window.React.version, 'window.React');
+	const hook = window.__REACT_DEVTOOLS_GLOBAL_HOOK__;
+	if (hook && hook.renderers) for (const r of hook.renderers.values()) add('React', r.version, 'React DevTools hook');
+	if (window.next) add('Next.js', window.next.version, 'window.next');
+	if (window.__NEXT_DATA__) add('Next.js', '', 'window.__NEXT_DATA__');
+	if (window.Vue) add('Vue', window.Vue.version, 'window.Vue');
+	if (window.angular && window.angular.version) add('AngularJS', window.angular.version.full, 'window.angular');
+	if (window.jQuery && window.jQuery.fn) add('jQuery', window.jQuery.fn.jquery, 'window.jQuery');
+
+	const ng = document.querySelector('[ng-version]');
+	if (ng) add('Angular', ng.getAttribute('ng-version'), 'ng-version attribute');
+	for (const el of Array.from(document.querySelectorAll('body, body *')).slice(0, 2000)) {
+		if (el.__vue_app__) add('Vue', el.__vue_app__.version, 'Vue app mounted on <' + el.tagName.toLowerCase() + '>');
+		if (el.__vue__) add('Vue', el.__vue__.$root.constructor.version, 'Vue instance on <' + el.tagName.toLowerCase() + '>');
+		if (el._reactRootContainer || Object.keys(el).some(k => k.startsWith('__reactFiber') || k.startsWith('__reactContainer'))) {
+			add('React', '', 'React root on <' + el.tagName.toLowerCase() + '>');
+		}
+	}
+
+	const fingerprints = [
+		['Next.js', /\/_next\//],
+		['React', /\breact(?:-dom)?(?:@|[.-]v?)(\d+\.\d+\.\d+)/i],
+		['Vue', /\bvue(?:@|[.-]v?)(\d+\.\d+\.\d+)/i],
+		['Angular', /@angular\/core@(\d+\.\d+\.\d+)/i],
+		['AngularJS', /\bangular(?:js)?(?:@|[.-]v?)(1\.\d+\.\d+)/i],
+		['jQuery', /\bjquery(?:@|[.-]v?)(\d+\.\d+\.\d+)/i],
+		['Tailwind CSS', /\btailwind(?:css)?(?:@|[.-]v?)(\d+\.\d+\.\d+)/i],
+	];
+	const assets = Array.from(document.querySelectorAll('script[src], link[rel=stylesheet][href]'), el => el.src || el.href);
+	for (const url of assets) {
+		for (const [name, re] of fingerprints) {
+			const m = url.match(re);
+			if (m) add(name, m[1], url);
+		}
+	}
+
+	let tailwind = '';
+	for (const style of document.querySelectorAll('style')) {
+		const m = style.textContent.match(/tailwindcss v(\d+\.\d+\.\d+)/);
+		if (m) { tailwind = m[1]; break; }
+	}
+	if (tailwind) add('Tailwind CSS', tailwind, 'tailwindcss banner in a <style>');
+	outer: for (const sheet of document.styleSheets) {
+		let rules;
+		try { rules = sheet.cssRules; } catch (e) { continue; }
+		for (const rule of rules) {
+			if (rule.cssText.includes('--tw-')) {
+				add('Tailwind CSS', '', '--tw- custom properties in ' + (sheet.href || 'an inline stylesheet'));
+				break outer;
+			}
+		}
+	}
+
+	return Array.from(found.values());
+})()`
+
+// DetectStackTool definition
+type detectStackInput struct {
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewDetectStackTool creates a tool for identifying the frontend frameworks a page uses
+func (b *BrowseTools) NewDetectStackTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_detect_stack",
+		Description: `Detect the frontend frameworks and libraries the current page uses (React, Next.js, Vue, Angular, AngularJS, jQuery, Tailwind CSS)
+and their versions where known, from runtime globals, DOM markers, and script and stylesheet URLs.`,
+		InputSchema: llm.SchemaFor[detectStackInput](),
+		Run:         b.detectStackRun,
+	}
+}
+
+func (b *BrowseTools) detectStackRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input detectStackInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var libs []detectedLibrary
+	if err := chromedp.Run(timeoutCtx, chromedp.Evaluate(detectStackJS, &libs)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if len(libs) == 0 {
+		return llm.ToolOut{LLMContent: llm.TextContent("No known frameworks or libraries detected")}
+	}
+
+	names := make([]string, len(libs))
+	for i, lib := range libs {
+		names[i] = strings.TrimSpace(lib.Name + " " + lib.Version)
+	}
+	data, err := json.Marshal(libs)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal detected libraries: %w", err)
+	}
+	return jsonToolOut("stack", fmt.Sprintf("Detected %s", strings.Join(names, ", ")), data)
+}
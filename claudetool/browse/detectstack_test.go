@@ -0,0 +1,50 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectStack(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	html := `<html><head><style>/*! tailwindcss v3.4.1 */ .p-4 { --tw-ring-color: red; padding: 1rem }</style></head>
+<body><app-root ng-version="17.2.0"></app-root>
+<script>window.jQuery = {fn: {jquery: "3.7.1"}}; window.__NEXT_DATA__ = {};</script></body></html>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + url.PathEscape(html)})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.detectStackRun(ctx, []byte(`{}`))
+	if out.Error != nil {
+		t.Fatalf("detectStackRun error: %v", out.Error)
+	}
+	text := out.LLMContent[0].Text
+	for _, want := range []string{"Angular 17.2.0", "jQuery 3.7.1", "Next.js", "Tailwind CSS 3.4.1"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in %s", want, text)
+		}
+	}
+	if strings.Contains(text, "React") || strings.Contains(text, "Vue") {
+		t.Errorf("Unexpected frameworks in %s", text)
+	}
+}
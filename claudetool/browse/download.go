@@ -0,0 +1,79 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"shelley.exe.dev/llm"
+)
+
+// maxInlineDownloadSize is the largest text download returned inline by browser_read_download
+const maxInlineDownloadSize = 64 << 10
+
+// ReadDownloadTool definition
+type readDownloadInput struct {
+	Path string `json:"path" description:"Path or file name of the download"`
+}
+
+// NewReadDownloadTool creates a tool for reading a file the browser downloaded
+func (b *BrowseTools) NewReadDownloadTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_read_download",
+		Description: `Read a file the browser downloaded, given the path or file name reported when the download completed.
+Images are returned as images and text files up to 64KB inline; other files are only described.`,
+		InputSchema: llm.SchemaFor[readDownloadInput](),
+		Run:         b.readDownloadRun,
+	}
+}
+
+// downloadPath resolves name against the download directory and returns an error if it is outside it
+func (b *BrowseTools) downloadPath(name string) (string, error) {
+	dir, err := filepath.Abs(b.downloadDir())
+	if err != nil {
+		return "", err
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	rel, err := filepath.Rel(dir, filepath.Clean(path))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is not a download; downloads are saved in %s", name, dir)
+	}
+	return filepath.Join(dir, rel), nil
+}
+
+func (b *BrowseTools) readDownloadRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input readDownloadInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Path == "" {
+		return llm.ErrorfToolOut("path is required")
+	}
+	path, err := b.downloadPath(input.Path)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to read download: %w", err)
+	}
+
+	contentType := http.DetectContentType(data)
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return b.imageToolOut(path, data, 1)
+	case !utf8.Valid(data) || strings.ContainsRune(string(data), 0):
+		return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("%s is a binary file (%s, %d bytes) and cannot be shown", path, contentType, len(data)))}
+	case len(data) > maxInlineDownloadSize:
+		return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("%s is a text file of %d bytes, too large to show inline; read parts of it from disk", path, len(data)))}
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("Download %s (%d bytes):\n<download>%s</download>", path, len(data), data))}
+}
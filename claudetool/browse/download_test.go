@@ -0,0 +1,68 @@
+package browse
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestReadDownload(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	tools.sessionID = "test-" + uuid.New().String()[:8]
+	dir := tools.downloadDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"report.csv":  []byte("name,total\nwidgets,3\n"),
+		"chart.png":   img.Bytes(),
+		"archive.zip": {'P', 'K', 3, 4, 0, 0, 0xff},
+		"big.txt":     bytes.Repeat([]byte("a"), maxInlineDownloadSize+1),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := tools.readDownloadRun(ctx, []byte(`{"path": "report.csv"}`))
+	if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, "<download>name,total\nwidgets,3\n</download>") {
+		t.Errorf("text download: got %v %v", out.LLMContent, out.Error)
+	}
+
+	input := `{"path": "` + filepath.Join(dir, "chart.png") + `"}`
+	out = tools.readDownloadRun(ctx, []byte(input))
+	if out.Error != nil || len(out.LLMContent) != 2 || out.LLMContent[1].MediaType != "image/png" {
+		t.Errorf("image download: got %v %v", out.LLMContent, out.Error)
+	}
+
+	for name, want := range map[string]string{"archive.zip": "binary file", "big.txt": "too large"} {
+		out = tools.readDownloadRun(ctx, []byte(`{"path": "`+name+`"}`))
+		if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, want) {
+			t.Errorf("%s: got %v %v, want %q", name, out.LLMContent, out.Error, want)
+		}
+	}
+
+	for _, input := range []string{`{`, `{}`, `{"path": "../../etc/passwd"}`, `{"path": "/etc/passwd"}`, `{"path": "missing.txt"}`} {
+		if out := tools.readDownloadRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("readDownloadRun(%s) succeeded, want error", input)
+		}
+	}
+}
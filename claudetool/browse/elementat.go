@@ -0,0 +1,160 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// stableSelectorJS is a JavaScript function expression returning a CSS selector that uniquely
+// matches el within its document or shadow root. It prefers test IDs, IDs, and other attributes
+// that survive re-renders, and falls back to a :nth-of-type path from the nearest such ancestor.
+const stableSelectorJS = `(el) => {
+	const root = el.getRootNode();
+	const unique = (sel) => { try { return root.querySelectorAll(sel).length === 1; } catch { return false; } };
+	// IDs with long digit runs or hex hashes are usually generated per render
+	const stableID = (id) => id && !/\d{3,}|[0-9a-f]{8,}|^:|^ember|^react/i.test(id);
+	const own = (el) => {
+		const tag = el.tagName.toLowerCase();
+		for (const attr of ['data-testid', 'data-test', 'data-test-id', 'data-cy', 'data-qa']) {
+			const v = el.getAttribute(attr);
+			if (v) { const sel = '[' + attr + '=' + JSON.stringify(v) + ']'; if (unique(sel)) return sel; }
+		}
+		if (stableID(el.id) && unique('#' + CSS.escape(el.id))) return '#' + CSS.escape(el.id);
+		for (const attr of ['name', 'aria-label', 'placeholder', 'title', 'alt', 'href']) {
+			const v = el.getAttribute(attr);
+			if (v && v.length < 100) { const sel = tag + '[' + attr + '=' + JSON.stringify(v) + ']'; if (unique(sel)) return sel; }
+		}
+		return null;
+	};
+	const parts = [];
+	for (let n = el; n && n.nodeType === 1; n = n.parentElement) {
+		const sel = own(n);
+		if (sel) { parts.unshift(sel); break; }
+		const tag = n.tagName.toLowerCase();
+		const siblings = n.parentElement ? Array.from(n.parentElement.children).filter(c => c.tagName === n.tagName) : [n];
+		parts.unshift(siblings.length > 1 ? tag + ':nth-of-type(' + (siblings.indexOf(n) + 1) + ')' : tag);
+		if (tag === 'html') break;
+	}
+	return parts.join(' > ');
+}`
+
+// elementAtJS finds the deepest element at viewport (or page) coordinates x,y, descending into
+// open shadow roots and same-origin iframes
+const elementAtJS = `function(x, y, page) {
+	const stableSelector = ` + stableSelectorJS + `;
+	if (page) {
+		// elementFromPoint only sees the viewport, so scroll the point into it first
+		if (x < scrollX || y < scrollY || x >= scrollX + innerWidth || y >= scrollY + innerHeight) {
+			scrollTo(Math.max(0, x - innerWidth / 2), Math.max(0, y - innerHeight / 2));
+		}
+		x -= scrollX;
+		y -= scrollY;
+	}
+
+	const frames = [];
+	const shadowHosts = [];
+	let doc = document, el = null, fx = x, fy = y;
+	for (;;) {
+		el = doc.elementFromPoint(fx, fy);
+		if (!el) break;
+		while (el.shadowRoot) {
+			const inner = el.shadowRoot.elementFromPoint(fx, fy);
+			if (!inner || inner === el) break;
+			shadowHosts.push(stableSelector(el));
+			el = inner;
+		}
+		if (el.tagName !== 'IFRAME' && el.tagName !== 'FRAME') break;
+		let inner;
+		try { inner = el.contentDocument; } catch { inner = null; }
+		if (!inner) break; // cross-origin
+		const r = el.getBoundingClientRect();
+		frames.push(el.name || el.src);
+		fx -= r.left + el.clientLeft;
+		fy -= r.top + el.clientTop;
+		doc = inner;
+	}
+	if (!el) return null;
+
+	const r = el.getBoundingClientRect();
+	return {
+		selector: stableSelector(el),
+		tag: el.tagName.toLowerCase(),
+		text: (el.innerText || el.value || '').replace(/\s+/g, ' ').trim().slice(0, 100),
+		box: {x: r.left, y: r.top, width: r.width, height: r.height},
+		frames,
+		shadow_hosts: shadowHosts,
+	};
+}`
+
+// elementAtResult is the element found by elementAtJS
+type elementAtResult struct {
+	Selector string `json:"selector"`
+	Tag      string `json:"tag"`
+	Text     string `json:"text,omitempty"`
+	Box      struct {
+		X      float64 `json:"x"`
+		Y      float64 `json:"y"`
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+	} `json:"box"`
+	// The iframes (as values for the frame parameter) and the selectors of the shadow hosts
+	// containing the element, outermost first
+	Frames      []string `json:"frames,omitempty"`
+	ShadowHosts []string `json:"shadow_hosts,omitempty"`
+}
+
+// ElementAtTool definition
+type elementAtInput struct {
+	X       float64 `json:"x" description:"X coordinate in CSS pixels"`
+	Y       float64 `json:"y" description:"Y coordinate in CSS pixels"`
+	Page    bool    `json:"page,omitempty" description:"Coordinates are relative to the top of the page (as in a full-page screenshot) rather than the viewport; scrolls if needed (default: false)"`
+	Timeout string  `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewElementAtTool creates a tool for finding the element at a point
+func (b *BrowseTools) NewElementAtTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_element_at",
+		Description: `Find the deepest element at x,y (e.g. a spot in a screenshot) and a stable CSS selector for it, usable with the other browser tools.
+Descends into open shadow roots and same-origin iframes; the selector is relative to the innermost of them.
+For an element in an iframe, pass the reported frame as the frame parameter of the other tools.`,
+		InputSchema: llm.SchemaFor[elementAtInput](),
+		Run:         b.elementAtRun,
+	}
+}
+
+func (b *BrowseTools) elementAtRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input elementAtInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.X < 0 || input.Y < 0 {
+		return llm.ErrorfToolOut("coordinates must not be negative")
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var result *elementAtResult
+	if err := chromedp.Run(timeoutCtx, callWithArgs(elementAtJS, []any{input.X, input.Y, input.Page}, false, &result)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if result == nil {
+		return llm.ErrorfToolOut("no element at %g,%g (outside the viewport?)", input.X, input.Y)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal element: %w", err)
+	}
+	return jsonToolOut("element", fmt.Sprintf("<%s> at %g,%g: %s", result.Tag, input.X, input.Y, result.Selector), data)
+}
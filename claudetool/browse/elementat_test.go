@@ -0,0 +1,54 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestElementAt(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{`{`, `{"x": -1, "y": 0}`} {
+		if out := tools.elementAtRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("elementAtRun(%s) succeeded, want error", input)
+		}
+	}
+
+	html := `<body style="margin:0">` +
+		`<div style="height:50px"><button data-testid="save" style="width:100px;height:40px">Save</button></div>` +
+		`<ul><li>one</li><li style="height:30px">two</li></ul>` +
+		`<div id="r1234567" style="position:absolute;top:3000px"><span>deep</span></div></body>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	for _, tc := range []struct{ input, want string }{
+		{`{"x": 20, "y": 20}`, `[data-testid=\"save\"]`},
+		{`{"x": 60, "y": 90}`, `li:nth-of-type(2)`},
+		// Generated-looking IDs are skipped in favor of a structural path
+		{`{"x": 5, "y": 3005, "page": true}`, `body > div:nth-of-type(2) > span`},
+	} {
+		out := tools.elementAtRun(ctx, []byte(tc.input))
+		if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, tc.want) {
+			t.Errorf("elementAtRun(%s): expected %s, got %v %v", tc.input, tc.want, out.LLMContent, out.Error)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// elementInfoJS reports an element's (this) geometry and everything that commonly keeps
+// a click from reaching it. Coordinates are relative to the element's frame viewport.
+const elementInfoJS = `function() {
+	const describe = (el) => el.tagName.toLowerCase() + (el.id ? '#' + el.id : '') +
+		(typeof el.className === 'string' && el.className.trim() ? '.' + el.className.trim().split(/\s+/).join('.') : '');
+	const r = this.getBoundingClientRect();
+	const s = getComputedStyle(this);
+	const vw = window.innerWidth, vh = window.innerHeight;
+	const problems = [];
+
+	for (let el = this; el; el = el.parentElement || el.getRootNode().host) {
+		const cs = getComputedStyle(el);
+		if (cs.display === 'none') { problems.push('hidden by display:none on ' + describe(el)); break; }
+		if (cs.opacity === '0') { problems.push('transparent: opacity 0 on ' + describe(el)); break; }
+	}
+	if (s.visibility !== 'visible') problems.push('visibility: ' + s.visibility);
+	if (r.width === 0 || r.height === 0) problems.push('zero size');
+
+	const inViewport = r.bottom > 0 && r.right > 0 && r.top < vh && r.left < vw;
+	const fullyInViewport = r.top >= 0 && r.left >= 0 && r.bottom <= vh && r.right <= vw;
+	if (!inViewport) problems.push('outside the viewport; scroll it into view first');
+
+	let coveredBy = null;
+	if (inViewport && r.width > 0 && r.height > 0) {
+		const x = Math.min(Math.max(r.left + r.width / 2, 0), vw - 1);
+		const y = Math.min(Math.max(r.top + r.height / 2, 0), vh - 1);
+		const hit = this.getRootNode().elementFromPoint(x, y);
+		if (hit && hit !== this && !this.contains(hit)) {
+			if (hit.contains(this)) {
+				problems.push('does not receive the pointer at its center (pointer-events: ' + s.pointerEvents + ')');
+			} else {
+				coveredBy = describe(hit);
+				problems.push('covered at its center by ' + coveredBy);
+			}
+		}
+	}
+	if (s.pointerEvents === 'none') problems.push('pointer-events: none');
+
+	const disabled = this.disabled === true || !!this.closest('fieldset:disabled') || this.getAttribute('aria-disabled') === 'true';
+	if (disabled) problems.push('disabled');
+	if (this.closest('[inert]')) problems.push('inside an inert element');
+
+	return {
+		element: describe(this),
+		text: (this.innerText || this.value || '').replace(/\s+/g, ' ').trim().slice(0, 100),
+		box: {x: r.left, y: r.top, width: r.width, height: r.height},
+		page_box: {x: r.left + window.scrollX, y: r.top + window.scrollY, width: r.width, height: r.height},
+		in_viewport: inViewport,
+		needs_scroll: !fullyInViewport,
+		covered_by: coveredBy,
+		disabled,
+		display: s.display,
+		visibility: s.visibility,
+		opacity: s.opacity,
+		pointer_events: s.pointerEvents,
+		position: s.position,
+		z_index: s.zIndex,
+		problems,
+	};
+}`
+
+// ElementInfoTool definition
+type elementInfoInput struct {
+	Selector string `json:"selector" description:"The element to inspect, as a CSS selector or XPath expression (see selector_type)"`
+	selectorTypeInput
+	frameInput
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewElementInfoTool creates a tool for diagnosing an element's geometry and clickability
+func (b *BrowseTools) NewElementInfoTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_element_info",
+		Description: `Report an element's bounding box, visibility, stacking (z-index, and what covers its center), disabled state,
+and whether it must be scrolled into view, with a list of problems that would stop a click from reaching it.
+Use when a click or typing "did nothing".`,
+		InputSchema: llm.SchemaFor[elementInfoInput](),
+		Run:         b.elementInfoRun,
+	}
+}
+
+func (b *BrowseTools) elementInfoRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input elementInfoInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Selector == "" {
+		return llm.ErrorfToolOut("selector is required")
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var iframe *cdp.Node
+	if input.Frame != "" {
+		if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+
+	var info json.RawMessage
+	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		node, err := queryNode(ctx, input.Selector, input.SelectorType, iframe)
+		if err != nil {
+			return err
+		}
+		return callFunctionOnNode(ctx, node, elementInfoJS, &info)
+	}))
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	var summary struct {
+		Element  string   `json:"element"`
+		Problems []string `json:"problems"`
+	}
+	if err := json.Unmarshal(info, &summary); err != nil {
+		return llm.ErrorfToolOut("failed to parse element info: %w", err)
+	}
+	msg := fmt.Sprintf("%s looks visible and clickable", summary.Element)
+	if len(summary.Problems) > 0 {
+		msg = fmt.Sprintf("%s: %s", summary.Element, strings.Join(summary.Problems, "; "))
+	}
+	return jsonToolOut("element_info", msg, info)
+}
@@ -0,0 +1,54 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestElementInfo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{`{`, `{}`} {
+		if out := tools.elementInfoRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("elementInfoRun(%s) succeeded, want error", input)
+		}
+	}
+
+	html := `<button id="ok">ok</button><button id="off" disabled>off</button>` +
+		`<button id="under" style="position:absolute;top:100px">under</button>` +
+		`<div class="overlay" style="position:absolute;top:90px;left:0;width:300px;height:50px;z-index:5"></div>` +
+		`<button id="far" style="position:absolute;top:5000px">far</button>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	for _, tc := range []struct{ selector, want string }{
+		{"#ok", "button#ok looks visible and clickable"},
+		{"#off", "button#off: disabled"},
+		{"#under", "covered at its center by div.overlay"},
+		{"#far", "outside the viewport"},
+	} {
+		out := tools.elementInfoRun(ctx, []byte(`{"selector": "`+tc.selector+`"}`))
+		if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, tc.want) {
+			t.Errorf("%s: expected %q, got %v %v", tc.selector, tc.want, out.LLMContent, out.Error)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package browse
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// extensionFlags returns the Chrome flags that load the unpacked extensions in dirs
+func extensionFlags(dirs []string) ([]chromedp.ExecAllocatorOption, error) {
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+	paths := make([]string, len(dirs))
+	for i, dir := range dirs {
+		ext, err := readExtension(dir)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = ext.Dir
+	}
+	list := strings.Join(paths, ",")
+	return []chromedp.ExecAllocatorOption{
+		chromedp.Flag("disable-extensions", false),
+		chromedp.Flag("load-extension", list),
+		chromedp.Flag("disable-extensions-except", list),
+		// Old headless mode can't run extensions
+		chromedp.Flag("headless", "new"),
+		// Chrome 137+ ignores --load-extension unless this feature is off
+		chromedp.Flag("disable-features", disabledFeatures+",DisableLoadExtensionCommandLineSwitch"),
+	}, nil
+}
+
+// extensionInfo describes an unpacked extension from its manifest
+type extensionInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Dir     string `json:"dir"`
+	// Popup and Options are the extension's popup and options pages, if any
+	Popup   string `json:"popup,omitempty"`
+	Options string `json:"options,omitempty"`
+}
+
+// readExtension reads the manifest of the unpacked extension in dir
+func readExtension(dir string) (*extensionInfo, error) {
+	// Chrome derives the ID of an unpacked extension from its resolved path
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return nil, fmt.Errorf("extension: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return nil, fmt.Errorf("extension: %w", err)
+	}
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Key     string `json:"key"`
+		Action  struct {
+			DefaultPopup string `json:"default_popup"`
+		} `json:"action"`
+		BrowserAction struct {
+			DefaultPopup string `json:"default_popup"`
+		} `json:"browser_action"`
+		OptionsPage string `json:"options_page"`
+		OptionsUI   struct {
+			Page string `json:"page"`
+		} `json:"options_ui"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of extension %s: %w", dir, err)
+	}
+
+	// A manifest key pins the ID to that of the published extension
+	idSource := []byte(dir)
+	if manifest.Key != "" {
+		if idSource, err = base64.StdEncoding.DecodeString(manifest.Key); err != nil {
+			return nil, fmt.Errorf("invalid key in the manifest of extension %s: %w", dir, err)
+		}
+	}
+	return &extensionInfo{
+		ID:      extensionID(idSource),
+		Name:    manifest.Name,
+		Version: manifest.Version,
+		Dir:     dir,
+		Popup:   cmp.Or(manifest.Action.DefaultPopup, manifest.BrowserAction.DefaultPopup),
+		Options: cmp.Or(manifest.OptionsUI.Page, manifest.OptionsPage),
+	}, nil
+}
+
+// extensionID computes an extension ID: the first 128 bits of the SHA-256 of src, in hex written with the letters a-p
+func extensionID(src []byte) string {
+	sum := sha256.Sum256(src)
+	id := []byte(hex.EncodeToString(sum[:16]))
+	for i, c := range id {
+		if c <= '9' {
+			id[i] = 'a' + c - '0'
+		} else {
+			id[i] = 'k' + c - 'a'
+		}
+	}
+	return string(id)
+}
+
+// ExtensionsTool definition
+type extensionsInput struct {
+	Action    string `json:"action,omitempty" enum:"list,open" description:"list the extensions or open an extension page (default: list)"`
+	Extension string `json:"extension,omitempty" description:"ID or name of the extension to open; optional if only one is loaded"`
+	Page      string `json:"page,omitempty" description:"Path of the page within the extension, e.g. options.html (default: the popup, else the options page)"`
+	Timeout   string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewExtensionsTool creates a tool for listing the loaded extensions and opening their pages
+func (b *BrowseTools) NewExtensionsTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_extensions",
+		Description: `List the unpacked extensions the browser was started with, or open one of an extension's pages (its popup by default) in the current tab,
+so it can be tested with the other browser tools. A popup opened as a tab acts on that tab rather than the page it was opened over.`,
+		InputSchema: llm.SchemaFor[extensionsInput](),
+		Run:         b.extensionsRun,
+	}
+}
+
+func (b *BrowseTools) extensionsRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input extensionsInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	b.mux.Lock()
+	dirs := b.launch.Extensions
+	b.mux.Unlock()
+	if len(dirs) == 0 {
+		return llm.ErrorfToolOut("no extensions are loaded")
+	}
+	var exts []*extensionInfo
+	for _, dir := range dirs {
+		ext, err := readExtension(dir)
+		if err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		exts = append(exts, ext)
+	}
+
+	switch input.Action {
+	case "", "list":
+		data, err := json.Marshal(exts)
+		if err != nil {
+			return llm.ErrorfToolOut("failed to marshal extensions: %w", err)
+		}
+		return jsonToolOut("extensions", fmt.Sprintf("%d extensions", len(exts)), data)
+	case "open":
+	default:
+		return llm.ErrorfToolOut("unsupported action %q: must be list or open", input.Action)
+	}
+
+	var ext *extensionInfo
+	for _, e := range exts {
+		if input.Extension == "" && len(exts) == 1 || e.ID == input.Extension || strings.EqualFold(e.Name, input.Extension) {
+			ext = e
+			break
+		}
+	}
+	if ext == nil {
+		if input.Extension == "" {
+			return llm.ErrorfToolOut("extension is required when %d extensions are loaded", len(exts))
+		}
+		return llm.ErrorfToolOut("no extension %q is loaded", input.Extension)
+	}
+	page := cmp.Or(input.Page, ext.Popup, ext.Options)
+	if page == "" {
+		return llm.ErrorfToolOut("extension %s has no popup or options page; give a page", ext.Name)
+	}
+	pageURL := fmt.Sprintf("chrome-extension://%s/%s", ext.ID, strings.TrimPrefix(page, "/"))
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	if err := chromedp.Run(timeoutCtx, chromedp.Navigate(pageURL), chromedp.WaitReady("body")); err != nil {
+		return llm.ErrorfToolOut("failed to open %s: %w", pageURL, err)
+	}
+	return b.actionToolOut(fmt.Sprintf("Opened %s of %s", pageURL, ext.Name))
+}
@@ -0,0 +1,101 @@
+package browse
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeExtension creates an unpacked extension with a popup in a temporary directory
+func writeExtension(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"manifest.json": `{"manifest_version": 3, "name": "Greeter", "version": "1.0", "action": {"default_popup": "popup.html"}}`,
+		"popup.html":    `<title>Greeter popup</title><p>hello</p>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadExtension(t *testing.T) {
+	dir := writeExtension(t)
+	ext, err := readExtension(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ext.Name != "Greeter" || ext.Popup != "popup.html" || !regexp.MustCompile(`^[a-p]{32}$`).MatchString(ext.ID) {
+		t.Errorf("readExtension() = %+v", ext)
+	}
+
+	// The ID follows the resolved path, so a symlink to the extension gives the same one
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal(err)
+	}
+	if linked, err := readExtension(link); err != nil || linked.ID != ext.ID {
+		t.Errorf("readExtension(symlink) = %+v, %v; want ID %s", linked, err, ext.ID)
+	}
+
+	if _, err := extensionFlags([]string{t.TempDir()}); err == nil {
+		t.Error("Expected an error for a directory without a manifest")
+	}
+}
+
+func TestExtensionsRunErrorPaths(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	if out := tools.extensionsRun(context.Background(), []byte(`{}`)); out.Error == nil {
+		t.Error("Expected an error without extensions")
+	}
+
+	tools.SetLaunchOptions(LaunchOptions{Extensions: []string{writeExtension(t)}})
+	for _, input := range []string{
+		`{`,
+		`{"action": "install"}`,
+		`{"action": "open", "extension": "Missing"}`,
+	} {
+		if out := tools.extensionsRun(context.Background(), []byte(input)); out.Error == nil {
+			t.Errorf("Expected an error for %s", input)
+		}
+	}
+	if out := tools.extensionsRun(context.Background(), []byte(`{"action": "list"}`)); out.Error != nil || !strings.Contains(out.LLMContent[0].Text, "Greeter") {
+		t.Errorf("Expected the extension listed, got %v %v", out.LLMContent, out.Error)
+	}
+}
+
+func TestOpenExtensionPopup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	tools.SetLaunchOptions(LaunchOptions{Extensions: []string{writeExtension(t)}})
+
+	out := tools.extensionsRun(ctx, []byte(`{"action": "open"}`))
+	if out.Error != nil {
+		if strings.Contains(out.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("extensionsRun error: %v", out.Error)
+	}
+	if text := tools.evalRun(ctx, []byte(`{"expression": "document.title"}`)).LLMContent[0].Text; !strings.Contains(text, "Greeter popup") {
+		t.Errorf("Expected the popup page, got %s", text)
+	}
+}
@@ -0,0 +1,47 @@
+package browse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chromedp/chromedp"
+)
+
+// FakeMedia makes getUserMedia succeed without a prompt, returning fake camera and
+// microphone streams, so video chat and camera capture pages can be tested.
+type FakeMedia struct {
+	// VideoFile is a .y4m or .mjpeg file played as the camera; empty uses Chrome's test pattern
+	VideoFile string
+	// AudioFile is a .wav file played as the microphone; empty uses a beep
+	AudioFile string
+}
+
+// flags returns the Chrome flags for m, or none if m is nil
+func (m *FakeMedia) flags() ([]chromedp.ExecAllocatorOption, error) {
+	if m == nil {
+		return nil, nil
+	}
+	opts := []chromedp.ExecAllocatorOption{
+		chromedp.Flag("use-fake-device-for-media-stream", true),
+		chromedp.Flag("use-fake-ui-for-media-stream", true),
+	}
+	for _, f := range []struct{ flag, path string }{
+		{"use-file-for-fake-video-capture", m.VideoFile},
+		{"use-file-for-fake-audio-capture", m.AudioFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+		// Chrome only reports a missing file when a page opens the device
+		path, err := filepath.Abs(f.path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("fake media file: %w", err)
+		}
+		opts = append(opts, chromedp.Flag(f.flag, path))
+	}
+	return opts, nil
+}
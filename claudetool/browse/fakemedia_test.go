@@ -0,0 +1,66 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakeMediaFlags(t *testing.T) {
+	var none *FakeMedia
+	if opts, err := none.flags(); err != nil || len(opts) != 0 {
+		t.Errorf("nil flags() = %d options, %v; want none", len(opts), err)
+	}
+
+	video := filepath.Join(t.TempDir(), "camera.y4m")
+	if err := os.WriteFile(video, []byte("YUV4MPEG2 "), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if opts, err := (&FakeMedia{VideoFile: video}).flags(); err != nil || len(opts) != 3 {
+		t.Errorf("flags() = %d options, %v; want 3", len(opts), err)
+	}
+	if _, err := (&FakeMedia{AudioFile: filepath.Join(t.TempDir(), "missing.wav")}).flags(); err == nil {
+		t.Error("flags() with a missing file succeeded, want error")
+	}
+}
+
+func TestFakeMedia(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	tools.SetLaunchOptions(LaunchOptions{FakeMedia: &FakeMedia{}})
+
+	// getUserMedia needs a secure context, which localhost is
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<p>call</p>"))
+	}))
+	defer server.Close()
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.evalRun(ctx, []byte(`{"expression": "navigator.mediaDevices.getUserMedia({video: true, audio: true}).then(s => s.getTracks().map(t => t.kind).sort().join())", "await": true}`))
+	if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, "audio,video") {
+		t.Errorf("Expected fake audio and video tracks, got %v %v", out.LLMContent, out.Error)
+	}
+}
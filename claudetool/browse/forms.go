@@ -0,0 +1,356 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// listFormsJS describes every form on the page, plus a pseudo-form (index -1)
+// for fields that don't belong to any form. Password values are never reported.
+const listFormsJS = `(() => {
+	const describeField = (el) => {
+		const tag = el.tagName.toLowerCase();
+		const type = tag === 'input' ? (el.getAttribute('type') || 'text').toLowerCase() : tag;
+		let selector = '';
+		if (el.id) {
+			selector = '#' + CSS.escape(el.id);
+		} else if (el.name) {
+			selector = tag + '[name="' + el.name.replace(/["\\]/g, '\\$&') + '"]';
+			if (type === 'radio' || type === 'checkbox') {
+				selector += '[value="' + el.value.replace(/["\\]/g, '\\$&') + '"]';
+			}
+		}
+		const label = (el.labels && el.labels.length ? el.labels[0].innerText : '') ||
+			el.getAttribute('aria-label') || el.placeholder || el.title || '';
+		const field = {
+			selector,
+			tag,
+			type,
+			name: el.name || '',
+			id: el.id || '',
+			label: label.replace(/\s+/g, ' ').trim(),
+			required: !!el.required,
+			disabled: !!el.disabled,
+		};
+		if (type === 'checkbox' || type === 'radio') {
+			field.value = el.value;
+			field.checked = el.checked;
+		} else if (type !== 'password') {
+			field.value = el.value;
+		}
+		if (tag === 'select') {
+			field.multiple = el.multiple;
+			field.options = Array.from(el.options).map(o => ({value: o.value, label: o.label, selected: o.selected}));
+		}
+		return field;
+	};
+	const isField = (el) => !['submit', 'button', 'reset', 'image'].includes((el.type || '').toLowerCase()) && el.tagName !== 'FIELDSET' && el.tagName !== 'OUTPUT' && el.tagName !== 'OBJECT';
+	const forms = Array.from(document.forms).map((f, i) => ({
+		index: i,
+		id: f.id || '',
+		name: f.getAttribute('name') || '',
+		action: f.action,
+		method: (f.method || 'get').toLowerCase(),
+		fields: Array.from(f.elements).filter(isField).map(describeField),
+	}));
+	const orphans = Array.from(document.querySelectorAll('input, select, textarea')).filter(el => !el.form && isField(el));
+	if (orphans.length) {
+		forms.push({index: -1, id: '', name: '', action: '', method: '', fields: orphans.map(describeField)});
+	}
+	return forms;
+})()`
+
+// pageForm is a form reported by browser_list_forms
+type pageForm struct {
+	Index  int         `json:"index"`
+	ID     string      `json:"id,omitempty"`
+	Name   string      `json:"name,omitempty"`
+	Action string      `json:"action,omitempty"`
+	Method string      `json:"method,omitempty"`
+	Fields []formField `json:"fields"`
+}
+
+// formField is a single form control reported by browser_list_forms
+type formField struct {
+	Selector string        `json:"selector,omitempty"`
+	Tag      string        `json:"tag"`
+	Type     string        `json:"type"`
+	Name     string        `json:"name,omitempty"`
+	ID       string        `json:"id,omitempty"`
+	Label    string        `json:"label,omitempty"`
+	Value    *string       `json:"value,omitempty"`
+	Checked  *bool         `json:"checked,omitempty"`
+	Required bool          `json:"required,omitempty"`
+	Disabled bool          `json:"disabled,omitempty"`
+	Multiple bool          `json:"multiple,omitempty"`
+	Options  []fieldOption `json:"options,omitempty"`
+}
+
+// fieldOption is an option of a select field
+type fieldOption struct {
+	Value    string `json:"value"`
+	Label    string `json:"label"`
+	Selected bool   `json:"selected,omitempty"`
+}
+
+// ListFormsTool definition
+type listFormsInput struct {
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewListFormsTool creates a tool for describing the forms on the current page
+func (b *BrowseTools) NewListFormsTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_list_forms",
+		Description: `List the forms on the current page and their fields (selector, type, name, label, current value, required, select options) as JSON.
+Fields outside any form are reported under a form with index -1. Password values are omitted.`,
+		InputSchema: llm.SchemaFor[listFormsInput](),
+		Run:         b.listFormsRun,
+	}
+}
+
+func (b *BrowseTools) listFormsRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input listFormsInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	forms := []pageForm{}
+	if err := chromedp.Run(timeoutCtx, chromedp.Evaluate(listFormsJS, &forms)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	data, err := json.Marshal(forms)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal forms: %w", err)
+	}
+	return jsonToolOut("forms", fmt.Sprintf("Found %d forms", len(forms)), data)
+}
+
+// FillFormTool definition
+type fillFormInput struct {
+	Fields []fillField `json:"fields" description:"Fields to fill, in order"`
+	frameInput
+	Submit  bool   `json:"submit,omitempty" description:"Submit the form containing the last field after filling (default: false)"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// fillField identifies a field by name or selector and the value to give it
+type fillField struct {
+	Name     string `json:"name,omitempty" description:"Field name attribute (use this or selector)"`
+	Selector string `json:"selector,omitempty" description:"Field selector (use this or name), as a CSS selector or XPath expression (see selector_type)"`
+	selectorTypeInput
+	Value any `json:"value" description:"Value to enter: a string, a boolean for checkboxes, or an array of strings for multi-selects"`
+}
+
+// NewFillFormTool creates a tool for filling in several form fields at once
+func (b *BrowseTools) NewFillFormTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_fill_form",
+		Description: `Fill in form fields in order using real keyboard and mouse input, then optionally submit.
+Text fields are cleared and typed into; checkboxes take true/false; radio groups (by name) take the value of the option to pick;
+selects take an option value or label (or an array for multi-selects); file inputs take a file path.
+Use browser_list_forms first to discover field names and selectors.`,
+		InputSchema: llm.SchemaFor[fillFormInput](),
+		Run:         b.fillFormRun,
+	}
+}
+
+func (b *BrowseTools) fillFormRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input fillFormInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if len(input.Fields) == 0 {
+		return llm.ErrorfToolOut("fields must not be empty")
+	}
+	for i, f := range input.Fields {
+		if (f.Name == "") == (f.Selector == "") {
+			return llm.ErrorfToolOut("field %d: exactly one of name or selector is required", i)
+		}
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var iframe *cdp.Node
+	if input.Frame != "" {
+		if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+
+	var filled []string
+	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var last *cdp.Node
+		for _, f := range input.Fields {
+			node, err := fillOneField(ctx, f, iframe)
+			if err != nil {
+				return fmt.Errorf("%s: %w", f.label(), err)
+			}
+			filled = append(filled, f.label())
+			last = node
+		}
+		if input.Submit {
+			return callFunctionOnNode(ctx, last, `function() {
+				if (!this.form) throw new Error('the last field is not inside a form');
+				this.form.requestSubmit();
+			}`, nil)
+		}
+		return nil
+	}))
+	if err != nil {
+		return llm.ErrorfToolOut("filled %d of %d fields: %w", len(filled), len(input.Fields), err)
+	}
+
+	msg := fmt.Sprintf("Filled %d fields: %s", len(filled), strings.Join(filled, ", "))
+	if input.Submit {
+		msg += "\nSubmitted the form."
+	}
+	return b.actionToolOut(msg)
+}
+
+// label describes the field for messages
+func (f fillField) label() string {
+	if f.Name != "" {
+		return "name=" + f.Name
+	}
+	return f.Selector
+}
+
+// fillOneField locates a field and sets its value according to its type, returning its node
+func fillOneField(ctx context.Context, f fillField, iframe *cdp.Node) (*cdp.Node, error) {
+	sel, selectorType := f.Selector, f.SelectorType
+	if f.Name != "" {
+		sel, selectorType = "[name="+cssString(f.Name)+"]", selectorTypeCSS
+	}
+	node, err := queryNode(ctx, sel, selectorType, iframe)
+	if err != nil {
+		return nil, err
+	}
+
+	var kind struct {
+		Tag     string `json:"tag"`
+		Type    string `json:"type"`
+		Checked bool   `json:"checked"`
+	}
+	if err := callFunctionOnNode(ctx, node, `function() {
+		return {tag: this.tagName.toLowerCase(), type: (this.type || '').toLowerCase(), checked: !!this.checked};
+	}`, &kind); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case kind.Type == "checkbox":
+		want, err := boolValue(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		if want != kind.Checked {
+			return node, chromedp.MouseClickNode(node).Do(ctx)
+		}
+		return node, nil
+	case kind.Type == "radio":
+		value, ok := f.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("radio value must be a string, got %T", f.Value)
+		}
+		if f.Name != "" {
+			// Pick the radio in the group with the requested value
+			node, err = queryNode(ctx, "input[type=radio][name="+cssString(f.Name)+"][value="+cssString(value)+"]", selectorTypeCSS, iframe)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return node, chromedp.MouseClickNode(node).Do(ctx)
+	case kind.Tag == "select":
+		return node, selectOptions(ctx, node, f.Value)
+	case kind.Type == "file":
+		path, ok := f.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("file value must be a path string, got %T", f.Value)
+		}
+		return node, chromedp.SendKeys([]cdp.NodeID{node.NodeID}, path, chromedp.ByNodeID).Do(ctx)
+	default:
+		text, ok := f.Value.(string)
+		if !ok {
+			text = fmt.Sprint(f.Value)
+		}
+		// Clear the current value, then type the new one so the page sees real key events
+		if err := callFunctionOnNode(ctx, node, `function() {
+			if (this.isContentEditable) { this.textContent = ''; } else { this.value = ''; }
+		}`, nil); err != nil {
+			return nil, err
+		}
+		return node, chromedp.KeyEventNode(node, text).Do(ctx)
+	}
+}
+
+// queryNode returns the first node matching sel, waiting for it to be ready
+func queryNode(ctx context.Context, sel, selectorType string, iframe *cdp.Node) (*cdp.Node, error) {
+	opts, err := selectorQuery(sel, selectorType, iframe)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*cdp.Node
+	if err := chromedp.Nodes(sel, &nodes, opts...).Do(ctx); err != nil {
+		return nil, err
+	}
+	return nodes[0], nil
+}
+
+// selectOptions selects the options of a select element matching value (an option value or label,
+// or an array of them for multi-selects) and fires input and change events
+func selectOptions(ctx context.Context, node *cdp.Node, value any) error {
+	var wanted []any
+	switch v := value.(type) {
+	case string:
+		wanted = []any{v}
+	case []any:
+		for _, item := range v {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("select values must be strings, got %T", item)
+			}
+		}
+		wanted = v
+	default:
+		return fmt.Errorf("select value must be a string or array of strings, got %T", value)
+	}
+	_, err := chooseOptions(ctx, node, "", wanted)
+	return err
+}
+
+// boolValue interprets a checkbox value
+func boolValue(v any) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "true", "on", "yes", "checked":
+			return true, nil
+		case "false", "off", "no", "unchecked", "":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("checkbox value must be true or false, got %v", v)
+}
@@ -0,0 +1,62 @@
+package browse
+
+import (
+	"context"
+	"testing"
+)
+
+func TestListFormsRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	toolOut := tools.listFormsRun(ctx, []byte(`{"timeout": 5}`))
+	if toolOut.Error == nil {
+		t.Error("Expected error for invalid JSON input")
+	}
+}
+
+func TestFillFormRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, in := range []string{
+		`{"fields": "not-an-array"}`,
+		`{"fields": []}`,
+		`{"fields": [{"value": "x"}]}`,
+		`{"fields": [{"name": "q", "selector": "#q", "value": "x"}]}`,
+	} {
+		if toolOut := tools.fillFormRun(ctx, []byte(in)); toolOut.Error == nil {
+			t.Errorf("Expected error for %s", in)
+		}
+	}
+}
+
+func TestBoolValue(t *testing.T) {
+	tests := []struct {
+		in   any
+		want bool
+	}{
+		{true, true},
+		{false, false},
+		{"on", true},
+		{"False", false},
+	}
+	for _, tt := range tests {
+		got, err := boolValue(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("boolValue(%v) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := boolValue(1.0); err == nil {
+		t.Error("Expected error for numeric checkbox value")
+	}
+	if _, err := boolValue("maybe"); err == nil {
+		t.Error("Expected error for unrecognized checkbox value")
+	}
+}
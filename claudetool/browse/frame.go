@@ -0,0 +1,139 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// frameInput is the frame parameter shared by tools that can target an iframe, embedded in their inputs
+type frameInput struct {
+	Frame string `json:"frame,omitempty" description:"Run inside an iframe instead of the top-level page: a 0-based index of the page's iframes in document order, the iframe's name attribute, or a substring of its URL"`
+}
+
+// handleExecutionContextCreated records the default JavaScript context of each frame
+func (b *BrowseTools) handleExecutionContextCreated(e *runtime.EventExecutionContextCreated) {
+	var aux struct {
+		FrameID   cdp.FrameID `json:"frameId"`
+		IsDefault bool        `json:"isDefault"`
+	}
+	if err := json.Unmarshal(e.Context.AuxData, &aux); err != nil || !aux.IsDefault || aux.FrameID == "" {
+		return
+	}
+	b.frameContextsMutex.Lock()
+	defer b.frameContextsMutex.Unlock()
+	b.frameContexts[aux.FrameID] = e.Context.ID
+}
+
+// handleExecutionContextDestroyed forgets a destroyed frame context
+func (b *BrowseTools) handleExecutionContextDestroyed(e *runtime.EventExecutionContextDestroyed) {
+	b.frameContextsMutex.Lock()
+	defer b.frameContextsMutex.Unlock()
+	for frameID, id := range b.frameContexts {
+		if id == e.ExecutionContextID {
+			delete(b.frameContexts, frameID)
+		}
+	}
+}
+
+// handleExecutionContextsCleared forgets all frame contexts, e.g. on navigation
+func (b *BrowseTools) handleExecutionContextsCleared() {
+	b.frameContextsMutex.Lock()
+	defer b.frameContextsMutex.Unlock()
+	clear(b.frameContexts)
+}
+
+// frameContext returns the default JavaScript context of the frame owned by the iframe node
+func (b *BrowseTools) frameContext(iframe *cdp.Node) (runtime.ExecutionContextID, error) {
+	b.frameContextsMutex.Lock()
+	defer b.frameContextsMutex.Unlock()
+	id, ok := b.frameContexts[iframe.FrameID]
+	if !ok {
+		return 0, fmt.Errorf("iframe %s has no JavaScript context (is it still loading?)", describeFrame(iframe))
+	}
+	return id, nil
+}
+
+// findFrame resolves a frame spec to its iframe element node.
+// spec is an index into all iframes (including nested ones) in document order,
+// an iframe name attribute, or a substring of the iframe's URL.
+func findFrame(spec string, res **cdp.Node) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		var frames []*cdp.Node
+		var collect func(from *cdp.Node) error
+		collect = func(from *cdp.Node) error {
+			var nodes []*cdp.Node
+			opts := []chromedp.QueryOption{chromedp.ByQueryAll, chromedp.AtLeast(0)}
+			if from != nil {
+				opts = append(opts, chromedp.FromNode(from))
+			}
+			if err := chromedp.Nodes("iframe, frame", &nodes, opts...).Do(ctx); err != nil {
+				return err
+			}
+			for _, n := range nodes {
+				frames = append(frames, n)
+				if n.ContentDocument != nil {
+					if err := collect(n); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		if err := collect(nil); err != nil {
+			return fmt.Errorf("failed to list iframes: %w", err)
+		}
+
+		if i, err := strconv.Atoi(spec); err == nil {
+			if i < 0 || i >= len(frames) {
+				return fmt.Errorf("frame index %d out of range: page has %d iframe(s)", i, len(frames))
+			}
+			*res = frames[i]
+			return nil
+		}
+		for _, f := range frames {
+			if f.AttributeValue("name") == spec {
+				*res = f
+				return nil
+			}
+		}
+		for _, f := range frames {
+			if strings.Contains(frameURL(f), spec) {
+				*res = f
+				return nil
+			}
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "no iframe matches %q; available frames:", spec)
+		for i, f := range frames {
+			fmt.Fprintf(&sb, "\n  %d: %s", i, describeFrame(f))
+		}
+		if len(frames) == 0 {
+			sb.WriteString(" none")
+		}
+		return fmt.Errorf("%s", sb.String())
+	}
+}
+
+// frameURL returns the URL loaded in an iframe, falling back to its src attribute
+func frameURL(iframe *cdp.Node) string {
+	if iframe.ContentDocument != nil && iframe.ContentDocument.DocumentURL != "" {
+		return iframe.ContentDocument.DocumentURL
+	}
+	return iframe.AttributeValue("src")
+}
+
+// describeFrame returns a short human-readable description of an iframe
+func describeFrame(iframe *cdp.Node) string {
+	if name := iframe.AttributeValue("name"); name != "" {
+		return fmt.Sprintf("name=%q url=%q", name, frameURL(iframe))
+	}
+	return fmt.Sprintf("url=%q", frameURL(iframe))
+}
@@ -0,0 +1,67 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+func TestFrameSchemas(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, tool := range []*Tool{tools.NewEvalTool(), tools.NewScreenshotTool()} {
+		var schema struct {
+			Properties map[string]any `json:"properties"`
+		}
+		if err := json.Unmarshal(tool.InputSchema, &schema); err != nil {
+			t.Fatalf("%s: invalid schema: %v", tool.Name, err)
+		}
+		if _, ok := schema.Properties["frame"]; !ok {
+			t.Errorf("%s: schema missing frame property", tool.Name)
+		}
+	}
+}
+
+func TestFrameContextTracking(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	iframe := &cdp.Node{FrameID: "frame-1", Attributes: []string{"name", "checkout", "src", "https://pay.example/widget"}}
+
+	if _, err := tools.frameContext(iframe); err == nil {
+		t.Fatal("expected error before any context is created")
+	}
+
+	// Isolated worlds are not the frame's default context
+	tools.handleExecutionContextCreated(&runtime.EventExecutionContextCreated{Context: &runtime.ExecutionContextDescription{
+		ID: 7, AuxData: jsontext.Value(`{"frameId":"frame-1","isDefault":false}`),
+	}})
+	if _, err := tools.frameContext(iframe); err == nil {
+		t.Fatal("expected error for non-default context")
+	}
+
+	tools.handleExecutionContextCreated(&runtime.EventExecutionContextCreated{Context: &runtime.ExecutionContextDescription{
+		ID: 3, AuxData: jsontext.Value(`{"frameId":"frame-1","isDefault":true}`),
+	}})
+	id, err := tools.frameContext(iframe)
+	if err != nil || id != 3 {
+		t.Fatalf("frameContext() = %v, %v; want 3", id, err)
+	}
+
+	tools.handleExecutionContextDestroyed(&runtime.EventExecutionContextDestroyed{ExecutionContextID: 3})
+	if _, err := tools.frameContext(iframe); err == nil {
+		t.Fatal("expected error after context destroyed")
+	}
+
+	if got, want := describeFrame(iframe), `name="checkout" url="https://pay.example/widget"`; got != want {
+		t.Errorf("describeFrame() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// BrowserHealth describes the state of the shared browser
+type BrowserHealth struct {
+	// Running reports whether a browser is currently started.
+	// The remaining fields are only set when it is.
+	Running bool `json:"running"`
+	// Version is the browser product, e.g. "HeadlessChrome/120.0.6099.109".
+	Version string `json:"version,omitempty"`
+	// Targets is the number of open targets (pages, iframes, workers).
+	Targets int `json:"targets,omitempty"`
+	// JSHeapUsedBytes and JSHeapTotalBytes describe the current page's JavaScript heap.
+	JSHeapUsedBytes  int64 `json:"js_heap_used_bytes,omitempty"`
+	JSHeapTotalBytes int64 `json:"js_heap_total_bytes,omitempty"`
+	// IdleShutdownIn is how long until the browser is shut down if it stays idle.
+	IdleShutdownIn time.Duration `json:"idle_shutdown_in,omitempty"`
+}
+
+// Health reports on the shared browser without starting it or resetting its idle timer.
+func (b *BrowseTools) Health(ctx context.Context) (BrowserHealth, error) {
+	b.mux.Lock()
+	browserCtx := b.browserCtx
+	idleDeadline := b.idleDeadline
+	b.mux.Unlock()
+
+	if browserCtx == nil || browserCtx.Err() != nil {
+		return BrowserHealth{}, nil
+	}
+
+	h := BrowserHealth{Running: true, IdleShutdownIn: time.Until(idleDeadline).Round(time.Second)}
+	// Bound the checks by both the caller's context and the browser's lifetime
+	runCtx, cancel := context.WithCancel(browserCtx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	err := chromedp.Run(runCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		_, h.Version, _, _, _, err = browser.GetVersion().Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get browser version: %w", err)
+		}
+		targets, err := target.GetTargets().Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list targets: %w", err)
+		}
+		h.Targets = len(targets)
+		used, total, _, _, err := runtime.GetHeapUsage().Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get heap usage: %w", err)
+		}
+		h.JSHeapUsedBytes, h.JSHeapTotalBytes = int64(used), int64(total)
+		return nil
+	}))
+	if err != nil {
+		return BrowserHealth{}, err
+	}
+	return h, nil
+}
+
+// HealthTool definition
+type healthInput struct {
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewHealthTool creates a tool for diagnosing the browser
+func (b *BrowseTools) NewHealthTool() *llm.Tool {
+	return &llm.Tool{
+		Name:        "browser_health",
+		Description: "Report whether the browser is running, its version, open target count, JavaScript heap use, and time until idle shutdown. Does not start the browser.",
+		InputSchema: llm.SchemaFor[healthInput](),
+		Run:         b.healthRun,
+	}
+}
+
+func (b *BrowseTools) healthRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input healthInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	h, err := b.Health(timeoutCtx)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if !h.Running {
+		return llm.ToolOut{LLMContent: llm.TextContent("Browser is not running; it will start on the next browser tool call.")}
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf(
+		"Browser is running.\nVersion: %s\nOpen targets: %d\nJS heap: %.1f MB used of %.1f MB\nIdle shutdown in: %s",
+		h.Version, h.Targets, float64(h.JSHeapUsedBytes)/1e6, float64(h.JSHeapTotalBytes)/1e6, h.IdleShutdownIn))}
+}
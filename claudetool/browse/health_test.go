@@ -0,0 +1,69 @@
+package browse
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHealthNotRunning(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	h, err := tools.Health(ctx)
+	if err != nil {
+		t.Fatalf("Health() error: %v", err)
+	}
+	if h.Running {
+		t.Error("Expected browser not to be running before first use")
+	}
+
+	toolOut := tools.healthRun(ctx, []byte(`{}`))
+	if toolOut.Error != nil {
+		t.Fatalf("healthRun error: %v", toolOut.Error)
+	}
+	if !strings.Contains(toolOut.LLMContent[0].Text, "not running") {
+		t.Errorf("Expected not running message, got: %s", toolOut.LLMContent[0].Text)
+	}
+
+	// Checking health must not start the browser
+	if h, _ := tools.Health(ctx); h.Running {
+		t.Error("Health() started the browser")
+	}
+}
+
+func TestHealthRunning(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, time.Hour)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	if _, err := tools.GetBrowserContext(); err != nil {
+		if strings.Contains(err.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Failed to get browser context: %v", err)
+	}
+
+	h, err := tools.Health(ctx)
+	if err != nil {
+		t.Fatalf("Health() error: %v", err)
+	}
+	if !h.Running || h.Version == "" || h.Targets == 0 {
+		t.Errorf("Unexpected health: %+v", h)
+	}
+	if h.IdleShutdownIn <= 0 || h.IdleShutdownIn > time.Hour {
+		t.Errorf("IdleShutdownIn = %v, want within (0, 1h]", h.IdleShutdownIn)
+	}
+}
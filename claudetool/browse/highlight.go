@@ -0,0 +1,29 @@
+package browse
+
+// highlightAttr marks the overlays added by highlightJS so unhighlightJS can remove them
+const highlightAttr = "data-shelley-highlight"
+
+// maxHighlights bounds the elements highlightJS outlines
+const maxHighlights = 50
+
+// highlightJS outlines the elements matching a CSS selector with overlays that do not affect
+// layout or receive events, returning how many it outlined
+const highlightJS = `function(sel, max) {
+	const els = Array.from(document.querySelectorAll(sel)).slice(0, max);
+	for (const el of els) {
+		const r = el.getBoundingClientRect();
+		const o = document.createElement('div');
+		o.setAttribute('` + highlightAttr + `', '');
+		o.style.cssText = 'position:absolute;box-sizing:border-box;pointer-events:none;z-index:2147483647;' +
+			'border:3px solid #ff0040;background:rgba(255,0,64,0.12);border-radius:2px;' +
+			'left:' + (r.left + scrollX - 3) + 'px;top:' + (r.top + scrollY - 3) + 'px;' +
+			'width:' + (r.width + 6) + 'px;height:' + (r.height + 6) + 'px';
+		document.documentElement.appendChild(o);
+	}
+	return els.length;
+}`
+
+// unhighlightJS removes the overlays added by highlightJS
+const unhighlightJS = `function() {
+	for (const o of document.querySelectorAll('[` + highlightAttr + `]')) o.remove();
+}`
@@ -0,0 +1,47 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScreenshotHighlight(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<h1>Title</h1><p>one</p><p>two</p>"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.screenshotRun(ctx, []byte(`{"highlight_selector": "p"}`))
+	if out.Error != nil {
+		t.Fatalf("screenshotRun error: %v", out.Error)
+	}
+	if out := tools.screenshotRun(ctx, []byte(`{"highlight_selector": "table"}`)); out.Error == nil || !strings.Contains(out.Error.Error(), "matched no elements") {
+		t.Errorf("expected an error for a selector matching nothing, got %v", out.Error)
+	}
+
+	// The overlays are removed after the capture
+	evalInput, _ := json.Marshal(evalInput{Expression: "document.querySelectorAll('[" + highlightAttr + "]').length"})
+	out = tools.evalRun(ctx, evalInput)
+	if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, "<javascript_result>0</javascript_result>") {
+		t.Errorf("expected no overlays left, got %v %v", out.LLMContent, out.Error)
+	}
+}
@@ -0,0 +1,97 @@
+package browse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/imageutil"
+)
+
+// imageInfo describes an image file and what reading it would cost
+type imageInfo struct {
+	Path   string `json:"path"`
+	Format string `json:"format"`
+	Bytes  int    `json:"bytes"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	// Frames is the number of frames of an animated GIF, of which read_image sends one by default
+	Frames int `json:"frames,omitempty"`
+	// EstimatedTokens is the cost of sending the image at full size; providers may downscale it to their limits
+	EstimatedTokens int `json:"estimated_tokens"`
+}
+
+// ImageInfoTool definition
+type imageInfoInput struct {
+	Path string `json:"path" description:"Path to the image file"`
+}
+
+// NewImageInfoTool creates a tool for describing an image file without reading its pixels
+func (b *BrowseTools) NewImageInfoTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "image_info",
+		Description: `Report an image file's format, byte size, dimensions, and the estimated input tokens read_image would cost, without sending the image.
+Use it to decide whether to read an image as is, crop it, or take a smaller screenshot instead.`,
+		InputSchema: llm.SchemaFor[imageInfoInput](),
+		Run:         b.imageInfoRun,
+	}
+}
+
+func (b *BrowseTools) imageInfoRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input imageInfoInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Path == "" {
+		return llm.ErrorfToolOut("path is required")
+	}
+	data, err := os.ReadFile(input.Path)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to read image file: %w", err)
+	}
+	info, err := b.describeImage(input.Path, data)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	out, err := json.Marshal(info)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal image info: %w", err)
+	}
+	return jsonToolOut("image_info", fmt.Sprintf("%dx%d %s, %d bytes, about %d tokens to read",
+		info.Width, info.Height, info.Format, info.Bytes, info.EstimatedTokens), out)
+}
+
+// describeImage reads the header of an image and works out the size read_image would send it at
+func (b *BrowseTools) describeImage(path string, data []byte) (*imageInfo, error) {
+	info := &imageInfo{Path: path, Bytes: len(data)}
+	decodable := data
+	if imageutil.IsHEIC(data) {
+		var err error
+		if decodable, err = imageutil.ConvertHEICToPNG(data); err != nil {
+			return nil, fmt.Errorf("failed to convert HEIC image: %w", err)
+		}
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(decodable))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image %s: %w", path, err)
+	}
+	info.Format, info.Width, info.Height = format, config.Width, config.Height
+	if imageutil.IsHEIC(data) {
+		info.Format = "heic"
+	}
+
+	if imageutil.IsGIF(data) {
+		if _, total, err := imageutil.GIFFrames(data, 1); err != nil {
+			return nil, err
+		} else if total > 1 {
+			info.Frames = total
+		}
+	}
+
+	info.EstimatedTokens = imageutil.EstimateTokens(info.Width, info.Height)
+	return info, nil
+}
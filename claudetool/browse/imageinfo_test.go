@@ -0,0 +1,53 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImageInfoTool(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	path := filepath.Join(t.TempDir(), "wide.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewGray(image.Rect(0, 0, 3000, 1000))); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	input, _ := json.Marshal(imageInfoInput{Path: path})
+	out := tools.imageInfoRun(ctx, input)
+	if out.Error != nil {
+		t.Fatalf("imageInfoRun error: %v", out.Error)
+	}
+	text := out.LLMContent[0].Text
+	for _, want := range []string{`"width":3000`, `"height":1000`, `"format":"png"`, `"estimated_tokens":4000`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %s in %s", want, text)
+		}
+	}
+
+	for _, input := range []string{
+		`{`,
+		`{}`,
+		`{"path": "/nonexistent/image.png"}`,
+		`{"path": "imageinfo.go"}`,
+	} {
+		if out := tools.imageInfoRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("Expected an error for %s", input)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// AddInitScript registers JavaScript to run in every new document (including iframes)
+// before the page's own scripts, on every page including after navigations and browser restarts.
+func (b *BrowseTools) AddInitScript(source string) error {
+	if source == "" {
+		return fmt.Errorf("script must not be empty")
+	}
+
+	b.initScriptsMutex.Lock()
+	b.initScripts = append(b.initScripts, source)
+	b.initScriptsMutex.Unlock()
+
+	b.mux.Lock()
+	browserCtx := b.browserCtx
+	b.mux.Unlock()
+	if browserCtx == nil {
+		// Added when the browser starts
+		return nil
+	}
+	return chromedp.Run(browserCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		_, err := page.AddScriptToEvaluateOnNewDocument(source).Do(ctx)
+		return err
+	}))
+}
+
+// addInitScripts adds every registered init script to a newly started browser
+func (b *BrowseTools) addInitScripts(ctx context.Context) error {
+	b.initScriptsMutex.Lock()
+	defer b.initScriptsMutex.Unlock()
+	for i, source := range b.initScripts {
+		if _, err := page.AddScriptToEvaluateOnNewDocument(source).Do(ctx); err != nil {
+			return fmt.Errorf("failed to add init script %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// AddInitScriptTool definition
+type addInitScriptInput struct {
+	Script  string `json:"script" description:"JavaScript source to run at the start of each document"`
+	RunNow  bool   `json:"run_now,omitempty" description:"Also run the script in the current page's main frame, which has already loaded (default: false)"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewAddInitScriptTool creates a tool for injecting JavaScript into every new document
+func (b *BrowseTools) NewAddInitScriptTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_add_init_script",
+		Description: `Add JavaScript that runs in every new document (including iframes) before the page's own scripts,
+so instrumentation such as error hooks, fetch patching, or test helpers survives navigations and reloads.
+Scripts accumulate and stay active for the rest of the session.`,
+		InputSchema: llm.SchemaFor[addInitScriptInput](),
+		Run:         b.addInitScriptRun,
+	}
+}
+
+func (b *BrowseTools) addInitScriptRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input addInitScriptInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Script == "" {
+		return llm.ErrorfToolOut("script is required")
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if err := b.AddInitScript(input.Script); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if !input.RunNow {
+		return llm.ToolOut{LLMContent: llm.TextContent("Added init script; it runs from the next navigation or reload")}
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	if err := chromedp.Run(timeoutCtx, chromedp.Evaluate(input.Script, nil)); err != nil {
+		return llm.ErrorfToolOut("added init script, but failed to run it now: %w", err)
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent("Added init script and ran it in the current page")}
+}
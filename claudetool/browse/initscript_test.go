@@ -0,0 +1,59 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddInitScriptRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	if err := tools.AddInitScript(""); err == nil {
+		t.Error("AddInitScript with empty script succeeded, want error")
+	}
+	for _, input := range []string{`{`, `{}`, `{"script": ""}`} {
+		if out := tools.addInitScriptRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("addInitScriptRun(%s) succeeded, want error", input)
+		}
+	}
+}
+
+func TestAddInitScriptPersistsAcrossNavigations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	toolOut := tools.addInitScriptRun(ctx, []byte(`{"script": "window.injected = (window.injected || 0) + 1", "run_now": true}`))
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("addInitScriptRun error: %v", toolOut.Error)
+	}
+
+	for i := range 2 {
+		navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<p>page</p>"})
+		if toolOut := tools.NewNavigateTool().Run(ctx, navInput); toolOut.Error != nil {
+			t.Fatalf("Navigation %d error: %v", i, toolOut.Error)
+		}
+		toolOut = tools.evalRun(ctx, []byte(`{"expression": "window.injected"}`))
+		if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, "<javascript_result>1</javascript_result>") {
+			t.Errorf("Navigation %d: expected the script to run once, got %v %v", i, toolOut.LLMContent, toolOut.Error)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/input"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+	"shelley.exe.dev/llm"
+)
+
+// keyAliases maps common alternative key names to DOM key names
+var keyAliases = map[string]string{
+	"esc":      "Escape",
+	"return":   "Enter",
+	"space":    " ",
+	"spacebar": " ",
+	"up":       "ArrowUp",
+	"down":     "ArrowDown",
+	"left":     "ArrowLeft",
+	"right":    "ArrowRight",
+	"del":      "Delete",
+	"ins":      "Insert",
+	"pgup":     "PageUp",
+	"pgdn":     "PageDown",
+	"plus":     "+",
+}
+
+// namedKeys maps lowercase DOM key names, e.g. "arrowdown", to their kb rune.
+// Where keys share a name (Enter and NumpadEnter), the one whose code is the name wins.
+var namedKeys = func() map[string]rune {
+	m := make(map[string]rune)
+	for r, k := range kb.Keys {
+		if utf8.RuneCountInString(k.Key) < 2 {
+			continue
+		}
+		name := strings.ToLower(k.Key)
+		if _, ok := m[name]; !ok || k.Code == k.Key {
+			m[name] = r
+		}
+	}
+	return m
+}()
+
+// modifierKeys are the kb runes of the modifier keys themselves
+var modifierKeys = map[input.Modifier]rune{
+	input.ModifierAlt:   []rune(kb.Alt)[0],
+	input.ModifierCtrl:  []rune(kb.Control)[0],
+	input.ModifierMeta:  []rune(kb.Meta)[0],
+	input.ModifierShift: []rune(kb.Shift)[0],
+}
+
+// chordKeyEvents returns the key events for a chord such as "Ctrl+K", "Escape", or "Cmd+Shift+Enter":
+// the modifiers are pressed in order, the key is pressed and released, then the modifiers are released.
+func chordKeyEvents(chord string) ([]*input.DispatchKeyEventParams, error) {
+	names := strings.Split(chord, "+")
+	keyName := names[len(names)-1]
+	names = names[:len(names)-1]
+	if chord == "+" || strings.HasSuffix(chord, "++") {
+		keyName, names = "+", names[:len(names)-1]
+	}
+	if keyName == "" {
+		return nil, fmt.Errorf("invalid key chord %q", chord)
+	}
+
+	var mods input.Modifier
+	var held []input.Modifier
+	for _, name := range names {
+		mod, err := parseModifiers([]string{name})
+		if err != nil {
+			return nil, fmt.Errorf("invalid key chord %q: %w", chord, err)
+		}
+		mods |= mod
+		held = append(held, mod)
+	}
+
+	if alias, ok := keyAliases[strings.ToLower(keyName)]; ok {
+		keyName = alias
+	}
+	var key rune
+	if utf8.RuneCountInString(keyName) == 1 {
+		// Shortcuts name letters in upper case ("Ctrl+K") but mean the unshifted key
+		key, _ = utf8.DecodeRuneInString(keyName)
+		key = unicode.ToLower(key)
+		if mods&input.ModifierShift != 0 {
+			key = unicode.ToUpper(key)
+		}
+	} else {
+		var ok bool
+		if key, ok = namedKeys[strings.ToLower(keyName)]; !ok {
+			return nil, fmt.Errorf("unknown key %q in chord %q", keyName, chord)
+		}
+	}
+
+	var events []*input.DispatchKeyEventParams
+	var down input.Modifier
+	for _, mod := range held {
+		down |= mod
+		e := kb.Encode(modifierKeys[mod])[0]
+		e.Modifiers = down
+		events = append(events, e)
+	}
+	for _, e := range kb.Encode(key) {
+		// With Ctrl, Alt, or Meta held, a key triggers a shortcut instead of typing text
+		if e.Type == input.KeyChar && mods&^input.ModifierShift != 0 {
+			continue
+		}
+		e.Modifiers |= mods
+		events = append(events, e)
+	}
+	for i := len(held) - 1; i >= 0; i-- {
+		down &^= held[i]
+		e := kb.Encode(modifierKeys[held[i]])[1]
+		e.Modifiers = down
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// PressKeysTool definition
+type pressKeysInput struct {
+	Keys     []string `json:"keys" description:"Keys or chords to press in order; modifiers are ctrl, alt, shift, and meta (cmd), joined to the key with +"`
+	Selector string   `json:"selector,omitempty" description:"Element to focus first (default: keep the current focus), as a CSS selector or XPath expression (see selector_type)"`
+	selectorTypeInput
+	frameInput
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewPressKeysTool creates a tool for sending keyboard shortcuts
+func (b *BrowseTools) NewPressKeysTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_press_keys",
+		Description: `Press keys and shortcuts as trusted keyboard events, which pages handle like real typing (unlike KeyboardEvents created in browser_eval).
+Each entry is a key or chord, pressed in order: "Escape", "ArrowDown", "Ctrl+K", "Cmd+Enter", "Shift+Tab", "a".
+Keys go to the focused element, or to selector after focusing it.`,
+		InputSchema: llm.SchemaFor[pressKeysInput](),
+		Run:         b.pressKeysRun,
+	}
+}
+
+func (b *BrowseTools) pressKeysRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input pressKeysInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if len(input.Keys) == 0 {
+		return llm.ErrorfToolOut("keys is required")
+	}
+	var actions []chromedp.Action
+	for _, chord := range input.Keys {
+		events, err := chordKeyEvents(chord)
+		if err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		for _, e := range events {
+			actions = append(actions, e)
+		}
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	if input.Selector != "" {
+		var iframe *cdp.Node
+		if input.Frame != "" {
+			if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+				return llm.ErrorToolOut(err)
+			}
+		}
+		if err := chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+			node, err := queryNode(ctx, input.Selector, input.SelectorType, iframe)
+			if err != nil {
+				return err
+			}
+			return dom.Focus().WithNodeID(node.NodeID).Do(ctx)
+		})); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+
+	if err := chromedp.Run(timeoutCtx, actions...); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	return b.actionToolOut("done")
+}
@@ -0,0 +1,98 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/input"
+)
+
+func TestChordKeyEvents(t *testing.T) {
+	describe := func(events []*input.DispatchKeyEventParams) string {
+		parts := make([]string, len(events))
+		for i, e := range events {
+			parts[i] = string(e.Type) + ":" + e.Key
+			if e.Modifiers != 0 {
+				parts[i] += ":" + strconv.Itoa(int(e.Modifiers))
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+
+	tests := []struct {
+		chord, want, wantErr string
+	}{
+		{"Escape", "keyDown:Escape keyUp:Escape", ""},
+		{"esc", "keyDown:Escape keyUp:Escape", ""},
+		{"ArrowDown", "keyDown:ArrowDown keyUp:ArrowDown", ""},
+		{"Enter", "keyDown:Enter char:Enter keyUp:Enter", ""},
+		{"a", "keyDown:a char:a keyUp:a", ""},
+		// Ctrl suppresses the typed character, and the letter is unshifted
+		{"Ctrl+K", "keyDown:Control:2 keyDown:k:2 keyUp:k:2 keyUp:Control", ""},
+		{"Shift+a", "keyDown:Shift:8 keyDown:A:8 char:A:8 keyUp:A:8 keyUp:Shift", ""},
+		{"Cmd+Enter", "keyDown:Meta:4 keyDown:Enter:4 keyUp:Enter:4 keyUp:Meta", ""},
+		// + is a shifted key
+		{"Ctrl++", "keyDown:Control:2 keyDown:+:10 keyUp:+:10 keyUp:Control", ""},
+		{"Ctrl+", "", "invalid key chord"},
+		{"Hyper+K", "", "unsupported modifier"},
+		{"Ctrl+Banana", "", "unknown key"},
+	}
+	for _, tt := range tests {
+		events, err := chordKeyEvents(tt.chord)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("chordKeyEvents(%q) error = %v, want %q", tt.chord, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("chordKeyEvents(%q) error: %v", tt.chord, err)
+			continue
+		}
+		if got := describe(events); got != tt.want {
+			t.Errorf("chordKeyEvents(%q) = %s, want %s", tt.chord, got, tt.want)
+		}
+	}
+}
+
+func TestPressKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	if out := tools.pressKeysRun(ctx, []byte(`{"keys": []}`)); out.Error == nil {
+		t.Error("pressKeysRun with no keys succeeded, want error")
+	}
+
+	html := `<input id="q"><script>document.addEventListener('keydown', e => {` +
+		` if (e.ctrlKey && e.key === 'k') document.title = 'palette:' + e.isTrusted + ':' + document.activeElement.id; })</script>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	toolOut = tools.pressKeysRun(ctx, []byte(`{"keys": ["Ctrl+K"], "selector": "#q"}`))
+	if toolOut.Error != nil {
+		t.Fatalf("pressKeysRun error: %v", toolOut.Error)
+	}
+	toolOut = tools.evalRun(ctx, []byte(`{"expression": "document.title + ':' + document.getElementById('q').value"}`))
+	if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, `"palette:true:q:"`) {
+		t.Errorf("Expected a trusted Ctrl+K in the focused input without typing text, got %v %v", toolOut.LLMContent, toolOut.Error)
+	}
+}
@@ -0,0 +1,42 @@
+package browse
+
+import (
+	"slices"
+
+	"github.com/chromedp/chromedp"
+)
+
+// LaunchOptions are Chrome command-line settings for launched browsers
+type LaunchOptions struct {
+	// FakeMedia replaces the camera and microphone; nil uses real ones
+	FakeMedia *FakeMedia
+	// Extensions are directories of unpacked extensions to load
+	Extensions []string
+	// WebRTC limits what WebRTC reveals about the host's network
+	WebRTC WebRTCPolicy
+}
+
+// flags returns the Chrome flags for o
+func (o LaunchOptions) flags() ([]chromedp.ExecAllocatorOption, error) {
+	mediaOpts, err := o.FakeMedia.flags()
+	if err != nil {
+		return nil, err
+	}
+	extensionOpts, err := extensionFlags(o.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	webRTCOpts, err := o.WebRTC.flags()
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(mediaOpts, extensionOpts, webRTCOpts), nil
+}
+
+// SetLaunchOptions sets the options of browsers launched from now on.
+// Browsers taken from a pool are launched with the pool's options instead.
+func (b *BrowseTools) SetLaunchOptions(o LaunchOptions) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.launch = o
+}
@@ -0,0 +1,76 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// pageLink is a single anchor reported by browser_extract_links
+type pageLink struct {
+	Href    string `json:"href"`
+	Text    string `json:"text"`
+	Rel     string `json:"rel,omitempty"`
+	Visible bool   `json:"visible"`
+}
+
+// extractLinksJS collects all anchors with an href, resolved to absolute URLs.
+// It is a function expression taking the same-origin filter flag.
+const extractLinksJS = `(sameOrigin) => Array.from(document.querySelectorAll('a[href], area[href]'))
+	.filter(a => !sameOrigin || a.origin === location.origin)
+	.map(a => {
+		const r = a.getBoundingClientRect();
+		const s = getComputedStyle(a);
+		return {
+			href: a.href,
+			text: (a.innerText || a.getAttribute('aria-label') || a.title || '').replace(/\s+/g, ' ').trim().slice(0, 200),
+			rel: a.rel,
+			visible: r.width > 0 && r.height > 0 && s.visibility !== 'hidden' && s.display !== 'none',
+		};
+	})`
+
+// ExtractLinksTool definition
+type extractLinksInput struct {
+	SameOrigin bool   `json:"same_origin,omitempty" description:"Only include links to the current page's origin (default: false)"`
+	Timeout    string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewExtractLinksTool creates a tool for listing the links on the current page
+func (b *BrowseTools) NewExtractLinksTool() *llm.Tool {
+	return &llm.Tool{
+		Name:        "browser_extract_links",
+		Description: "List all links on the current page as JSON (absolute href, text, rel, visibility)",
+		InputSchema: llm.SchemaFor[extractLinksInput](),
+		Run:         b.extractLinksRun,
+	}
+}
+
+func (b *BrowseTools) extractLinksRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input extractLinksInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	links := []pageLink{}
+	expr := fmt.Sprintf("(%s)(%t)", extractLinksJS, input.SameOrigin)
+	if err := chromedp.Run(timeoutCtx, chromedp.Evaluate(expr, &links)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	data, err := json.Marshal(links)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal links: %w", err)
+	}
+	return jsonToolOut("links", fmt.Sprintf("Found %d links", len(links)), data)
+}
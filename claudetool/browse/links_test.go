@@ -0,0 +1,19 @@
+package browse
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExtractLinksRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	toolOut := tools.extractLinksRun(ctx, []byte(`{"same_origin": "yes"}`))
+	if toolOut.Error == nil {
+		t.Error("Expected error for invalid JSON input")
+	}
+}
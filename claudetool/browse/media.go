@@ -0,0 +1,58 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// EmulateMediaTool definition
+type emulateMediaInput struct {
+	Media   string `json:"media" enum:"print,screen" description:"Media type to emulate; screen restores normal rendering"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewEmulateMediaTool creates a tool for emulating a CSS media type
+func (b *BrowseTools) NewEmulateMediaTool() *llm.Tool {
+	return &llm.Tool{
+		Name:        "browser_emulate_media",
+		Description: "Emulate a CSS media type so @media print styles apply, e.g. to preview or screenshot the print layout without exporting a PDF. Persists until changed back to screen.",
+		InputSchema: llm.SchemaFor[emulateMediaInput](),
+		Run:         b.emulateMediaRun,
+	}
+}
+
+func (b *BrowseTools) emulateMediaRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input emulateMediaInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	// An empty media type disables emulation
+	var media string
+	switch input.Media {
+	case "print":
+		media = "print"
+	case "screen":
+	default:
+		return llm.ErrorfToolOut("unsupported media %q: must be print or screen", input.Media)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	if err := chromedp.Run(timeoutCtx, emulation.SetEmulatedMedia().WithMedia(media)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("Emulating %s media", input.Media))}
+}
@@ -0,0 +1,32 @@
+package browse
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEmulateMediaRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"invalid json", `{`, "invalid input"},
+		{"missing media", `{}`, "unsupported media"},
+		{"unknown media", `{"media": "braille"}`, "unsupported media"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := tools.emulateMediaRun(ctx, []byte(tc.input))
+			if out.Error == nil || !strings.Contains(out.Error.Error(), tc.want) {
+				t.Errorf("emulateMediaRun(%s) error = %v, want %q", tc.input, out.Error, tc.want)
+			}
+		})
+	}
+}
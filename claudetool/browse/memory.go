@@ -0,0 +1,232 @@
+package browse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/chromedp/cdproto/heapprofiler"
+	"github.com/chromedp/cdproto/performance"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/google/uuid"
+	"shelley.exe.dev/llm"
+)
+
+// HeapSnapshotDir is the directory where JavaScript heap snapshots are stored
+const HeapSnapshotDir = "/tmp/shelley-heap-snapshots"
+
+// memoryMetrics are the Performance.getMetrics values compared by browser_memory, in report order
+var memoryMetrics = []string{"JSHeapUsedSize", "JSHeapTotalSize", "Nodes", "JSEventListeners", "Documents", "Frames"}
+
+// memorySample is a reading of memoryMetrics
+type memorySample struct {
+	Taken  time.Time
+	Values map[string]float64
+}
+
+// sampleMemory reads memoryMetrics, first collecting garbage if gc is set so only live objects count
+func sampleMemory(ctx context.Context, gc bool) (*memorySample, error) {
+	var metrics []*performance.Metric
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if gc {
+			if err := heapprofiler.CollectGarbage().Do(ctx); err != nil {
+				return fmt.Errorf("failed to collect garbage: %w", err)
+			}
+		}
+		if err := performance.Enable().Do(ctx); err != nil {
+			return err
+		}
+		var err error
+		metrics, err = performance.GetMetrics().Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, err
+	}
+	sample := &memorySample{Taken: time.Now(), Values: make(map[string]float64)}
+	for _, m := range metrics {
+		if slices.Contains(memoryMetrics, m.Name) {
+			sample.Values[m.Name] = m.Value
+		}
+	}
+	return sample, nil
+}
+
+// memoryChange is a metric's value at a mark and now
+type memoryChange struct {
+	Metric string  `json:"metric"`
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Change float64 `json:"change"`
+}
+
+// compareMemory lists how each metric changed from before to after
+func compareMemory(before, after *memorySample) []memoryChange {
+	changes := make([]memoryChange, 0, len(memoryMetrics))
+	for _, name := range memoryMetrics {
+		changes = append(changes, memoryChange{
+			Metric: name,
+			Before: before.Values[name],
+			After:  after.Values[name],
+			Change: after.Values[name] - before.Values[name],
+		})
+	}
+	return changes
+}
+
+// MemoryTool definition
+type memoryInput struct {
+	Action  string `json:"action" enum:"mark,compare" description:"mark to record the current usage, compare to report the change since a mark"`
+	Name    string `json:"name,omitempty" description:"Name of the mark (default: \"default\")"`
+	GC      *bool  `json:"gc,omitempty" description:"Collect garbage before measuring (default: true)"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewMemoryTool creates a tool for measuring memory growth between two points
+func (b *BrowseTools) NewMemoryTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_memory",
+		Description: `Measure the page's memory: JS heap used and total (bytes), DOM nodes, event listeners, documents, and frames.
+Use action mark to record them under a name, repeat the suspected leaking interaction, then use action compare to see the growth since the mark.
+Garbage is collected before each reading so only reachable objects count.`,
+		InputSchema: llm.SchemaFor[memoryInput](),
+		Run:         b.memoryRun,
+	}
+}
+
+func (b *BrowseTools) memoryRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input memoryInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Action != "mark" && input.Action != "compare" {
+		return llm.ErrorfToolOut("unsupported action %q: must be mark or compare", input.Action)
+	}
+	if input.Name == "" {
+		input.Name = "default"
+	}
+	b.memoryMarksMutex.Lock()
+	mark, marked := b.memoryMarks[input.Name]
+	b.memoryMarksMutex.Unlock()
+	if input.Action == "compare" && !marked {
+		return llm.ErrorfToolOut("no memory mark %q; mark it first", input.Name)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	sample, err := sampleMemory(timeoutCtx, input.GC == nil || *input.GC)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	if input.Action == "mark" {
+		b.memoryMarksMutex.Lock()
+		b.memoryMarks[input.Name] = sample
+		b.memoryMarksMutex.Unlock()
+		return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("Marked %q: %.0f bytes of JS heap used, %.0f DOM nodes, %.0f event listeners",
+			input.Name, sample.Values["JSHeapUsedSize"], sample.Values["Nodes"], sample.Values["JSEventListeners"]))}
+	}
+
+	changes := compareMemory(mark, sample)
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal memory changes: %w", err)
+	}
+	summary := fmt.Sprintf("Since %q (%s ago): JS heap used %+.0f bytes, DOM nodes %+.0f, event listeners %+.0f",
+		input.Name, sample.Taken.Sub(mark.Taken).Round(time.Second), changes[0].Change, changes[2].Change, changes[3].Change)
+	return jsonToolOut("memory", summary, data)
+}
+
+// HeapSnapshotTool definition
+type heapSnapshotInput struct {
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 60s)"`
+}
+
+// NewHeapSnapshotTool creates a tool for saving a JavaScript heap snapshot
+func (b *BrowseTools) NewHeapSnapshotTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_heap_snapshot",
+		Description: `Save a snapshot of the page's JavaScript heap to a .heapsnapshot file, which can be loaded in the DevTools Memory panel
+or compared with another snapshot to find what is leaking. Snapshots of large pages take a while and can be hundreds of MB.`,
+		InputSchema: llm.SchemaFor[heapSnapshotInput](),
+		Run:         b.heapSnapshotRun,
+	}
+}
+
+func (b *BrowseTools) heapSnapshotRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input heapSnapshotInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	timeout := 60 * time.Second
+	if input.Timeout != "" {
+		timeout = parseTimeout(input.Timeout)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, timeout)
+	defer cancel()
+
+	filePath := filepath.Join(HeapSnapshotDir, fmt.Sprintf("heap_%s.heapsnapshot", uuid.New().String()[:8]))
+	f, err := os.Create(filePath)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to create heap snapshot file: %w", err)
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+
+	// Chunks arrive as events before the command returns
+	var writeErr error
+	listenCtx, stopListening := context.WithCancel(timeoutCtx)
+	defer stopListening()
+	chromedp.ListenTarget(listenCtx, func(ev any) {
+		if e, ok := ev.(*heapprofiler.EventAddHeapSnapshotChunk); ok && writeErr == nil {
+			_, writeErr = w.WriteString(e.Chunk)
+		}
+	})
+
+	var usedSize, totalSize float64
+	err = chromedp.Run(timeoutCtx,
+		heapprofiler.Enable(),
+		heapprofiler.TakeHeapSnapshot().WithReportProgress(false),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			usedSize, totalSize, _, _, err = runtime.GetHeapUsage().Do(ctx)
+			return err
+		}),
+		heapprofiler.Disable(),
+	)
+	stopListening()
+	if err == nil {
+		err = writeErr
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		os.Remove(filePath)
+		return llm.ErrorfToolOut("failed to take heap snapshot: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("Heap snapshot (%d bytes) written to: %s\nJS heap: %.0f bytes used of %.0f allocated",
+		info.Size(), filePath, usedSize, totalSize))}
+}
@@ -0,0 +1,99 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareMemory(t *testing.T) {
+	before := &memorySample{Values: map[string]float64{"JSHeapUsedSize": 1000, "Nodes": 50}}
+	after := &memorySample{Values: map[string]float64{"JSHeapUsedSize": 4000, "Nodes": 40, "JSEventListeners": 3}}
+	changes := compareMemory(before, after)
+	if len(changes) != len(memoryMetrics) {
+		t.Fatalf("Expected %d changes, got %d", len(memoryMetrics), len(changes))
+	}
+	got := map[string]float64{}
+	for _, c := range changes {
+		got[c.Metric] = c.Change
+	}
+	if got["JSHeapUsedSize"] != 3000 || got["Nodes"] != -10 || got["JSEventListeners"] != 3 || got["Frames"] != 0 {
+		t.Errorf("compareMemory() = %+v", changes)
+	}
+}
+
+func TestMemoryRunErrorPaths(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{
+		`{`,
+		`{"action": "reset"}`,
+		`{"action": "compare", "name": "never"}`,
+	} {
+		if out := tools.memoryRun(context.Background(), []byte(input)); out.Error == nil {
+			t.Errorf("Expected an error for %s", input)
+		}
+	}
+}
+
+func TestMemoryAndHeapSnapshot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<body></body>"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	if out := tools.memoryRun(ctx, []byte(`{"action": "mark"}`)); out.Error != nil {
+		t.Fatalf("memoryRun(mark) error: %v", out.Error)
+	}
+	leak := `window.leak = []; for (let i = 0; i < 1000; i++) { const d = document.createElement('div'); window.leak.push(d); document.body.append(d); }`
+	if out := tools.evalRun(ctx, []byte(`{"expression": "`+leak+`"}`)); out.Error != nil {
+		t.Fatalf("evalRun error: %v", out.Error)
+	}
+	out := tools.memoryRun(ctx, []byte(`{"action": "compare"}`))
+	if out.Error != nil {
+		t.Fatalf("memoryRun(compare) error: %v", out.Error)
+	}
+	if text := out.LLMContent[0].Text; !strings.Contains(text, "DOM nodes +10") {
+		t.Errorf("Expected about 1000 more DOM nodes, got %s", text)
+	}
+
+	out = tools.heapSnapshotRun(ctx, []byte(`{}`))
+	if out.Error != nil {
+		t.Fatalf("heapSnapshotRun error: %v", out.Error)
+	}
+	text := out.LLMContent[0].Text
+	path := strings.TrimSpace(strings.SplitN(strings.SplitN(text, "written to: ", 2)[1], "\n", 2)[0])
+	t.Cleanup(func() { os.Remove(path) })
+	var snapshot struct {
+		Snapshot json.RawMessage `json:"snapshot"`
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil || snapshot.Snapshot == nil {
+		t.Errorf("Expected a heap snapshot in %s: %v", path, err)
+	}
+}
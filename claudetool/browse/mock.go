@@ -0,0 +1,203 @@
+package browse
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// mockRule is a canned response served by browser_mock_response instead of the network
+type mockRule struct {
+	Pattern string            `json:"url_pattern,omitempty" description:"Regular expression matched against the request URL, e.g. /api/orders$"`
+	Method  string            `json:"method,omitempty" description:"HTTP method to mock, e.g. POST (default: any)"`
+	Status  int               `json:"status,omitempty" description:"HTTP status code (default: 200)"`
+	Headers map[string]string `json:"headers,omitempty" description:"Response headers; Content-Type defaults to application/json for a JSON body and text/plain otherwise"`
+	Body    string            `json:"body,omitempty" description:"Response body"`
+	Delay   string            `json:"delay,omitempty" description:"How long to wait before responding, as a Go duration string (default: 0s)"`
+	re      *regexp.Regexp
+	delay   time.Duration
+}
+
+// matches reports whether r applies to a request
+func (r *mockRule) matches(method, url string) bool {
+	return (r.Method == "" || strings.EqualFold(r.Method, method)) && r.re.MatchString(url)
+}
+
+// fulfill answers the paused request with r's response after r's delay
+func (r *mockRule) fulfill(ctx context.Context, id fetch.RequestID) error {
+	if r.delay > 0 {
+		t := time.NewTimer(r.delay)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	headers := []*fetch.HeaderEntry{}
+	has := func(name string) bool {
+		for k := range r.Headers {
+			if strings.EqualFold(k, name) {
+				return true
+			}
+		}
+		return false
+	}
+	if !has("Content-Type") {
+		contentType := "text/plain; charset=utf-8"
+		if json.Valid([]byte(r.Body)) {
+			contentType = "application/json"
+		}
+		headers = append(headers, &fetch.HeaderEntry{Name: "Content-Type", Value: contentType})
+	}
+	// Mocked APIs are usually on another origin than the page calling them
+	if !has("Access-Control-Allow-Origin") {
+		headers = append(headers, &fetch.HeaderEntry{Name: "Access-Control-Allow-Origin", Value: "*"})
+	}
+	for k, v := range r.Headers {
+		headers = append(headers, &fetch.HeaderEntry{Name: k, Value: v})
+	}
+	return fetch.FulfillRequest(id, int64(r.Status)).
+		WithResponseHeaders(headers).
+		WithBody(base64.StdEncoding.EncodeToString([]byte(r.Body))).
+		Do(ctx)
+}
+
+// findMock returns the most recently added rule matching a request, or nil
+func (b *BrowseTools) findMock(method, url string) *mockRule {
+	b.mocksMutex.Lock()
+	defer b.mocksMutex.Unlock()
+	for _, r := range slices.Backward(b.mocks) {
+		if r.matches(method, url) {
+			return r
+		}
+	}
+	return nil
+}
+
+// hasMocks reports whether any mocked responses are registered
+func (b *BrowseTools) hasMocks() bool {
+	b.mocksMutex.Lock()
+	defer b.mocksMutex.Unlock()
+	return len(b.mocks) > 0
+}
+
+// MockResponseTool definition
+type mockResponseInput struct {
+	Action string `json:"action,omitempty" enum:"add,remove,clear,list" description:"add a rule, remove the rules with a url_pattern and method, clear all rules, or list them (default: add)"`
+	mockRule
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewMockResponseTool creates a tool for serving canned responses to matching requests
+func (b *BrowseTools) NewMockResponseTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_mock_response",
+		Description: `Serve a canned response to every request whose URL matches a pattern, instead of sending it to the network,
+e.g. to develop a frontend against an API that doesn't exist yet or to simulate errors and slow responses.
+The newest matching rule wins. Rules persist, including across browser restarts, until removed or cleared.`,
+		InputSchema: llm.SchemaFor[mockResponseInput](),
+		Run:         b.mockResponseRun,
+	}
+}
+
+func (b *BrowseTools) mockResponseRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input mockResponseInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	rule := &input.mockRule
+
+	var msg string
+	switch input.Action {
+	case "", "add":
+		if rule.Pattern == "" {
+			return llm.ErrorfToolOut("url_pattern is required")
+		}
+		var err error
+		if rule.re, err = regexp.Compile(rule.Pattern); err != nil {
+			return llm.ErrorfToolOut("invalid url_pattern: %w", err)
+		}
+		if rule.Status == 0 {
+			rule.Status = http.StatusOK
+		}
+		if rule.Status < 100 || rule.Status > 599 {
+			return llm.ErrorfToolOut("invalid status %d", rule.Status)
+		}
+		if rule.Delay != "" {
+			if rule.delay, err = time.ParseDuration(rule.Delay); err != nil {
+				return llm.ErrorfToolOut("invalid delay: %w", err)
+			}
+		}
+		b.mocksMutex.Lock()
+		b.mocks = append(b.mocks, rule)
+		b.mocksMutex.Unlock()
+		msg = fmt.Sprintf("Mocking %s with status %d", describeMock(rule.Method, rule.Pattern), rule.Status)
+
+	case "remove":
+		b.mocksMutex.Lock()
+		n := len(b.mocks)
+		b.mocks = slices.DeleteFunc(b.mocks, func(r *mockRule) bool {
+			return r.Pattern == rule.Pattern && strings.EqualFold(r.Method, rule.Method)
+		})
+		removed := n - len(b.mocks)
+		b.mocksMutex.Unlock()
+		if removed == 0 {
+			return llm.ErrorfToolOut("no mock for %s", describeMock(rule.Method, rule.Pattern))
+		}
+		msg = fmt.Sprintf("Removed %d mocks for %s", removed, describeMock(rule.Method, rule.Pattern))
+
+	case "clear":
+		b.mocksMutex.Lock()
+		b.mocks = nil
+		b.mocksMutex.Unlock()
+		msg = "Cleared all mocks"
+
+	case "list":
+		b.mocksMutex.Lock()
+		data, err := json.Marshal(append([]*mockRule{}, b.mocks...))
+		n := len(b.mocks)
+		b.mocksMutex.Unlock()
+		if err != nil {
+			return llm.ErrorfToolOut("failed to marshal mocks: %w", err)
+		}
+		return jsonToolOut("mocks", fmt.Sprintf("%d mocks", n), data)
+
+	default:
+		return llm.ErrorfToolOut("unsupported action %q: must be add, remove, clear, or list", input.Action)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	b.mux.Lock()
+	policy := b.policy
+	b.mux.Unlock()
+	if err := chromedp.Run(timeoutCtx, b.enableInterception(policy)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent(msg)}
+}
+
+// describeMock names the requests a rule applies to for messages
+func describeMock(method, pattern string) string {
+	if method == "" {
+		return pattern + " (any method)"
+	}
+	return strings.ToUpper(method) + " " + pattern
+}
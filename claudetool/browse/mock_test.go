@@ -0,0 +1,107 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMockResponseRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{
+		`{`,
+		`{}`,
+		`{"url_pattern": "("}`,
+		`{"url_pattern": "/api", "status": 1000}`,
+		`{"url_pattern": "/api", "delay": "soon"}`,
+		`{"action": "remove", "url_pattern": "/api"}`,
+		`{"action": "replace"}`,
+	} {
+		if out := tools.mockResponseRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("mockResponseRun(%s) succeeded, want error", input)
+		}
+	}
+	if tools.hasMocks() {
+		t.Error("Invalid input registered a mock")
+	}
+}
+
+func TestFindMock(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	tools.mocks = []*mockRule{
+		{Pattern: "/api/", re: regexp.MustCompile("/api/"), Status: 500},
+		{Pattern: "/api/orders", Method: "post", re: regexp.MustCompile("/api/orders"), Status: 201},
+	}
+	if r := tools.findMock("POST", "https://example.com/api/orders"); r == nil || r.Status != 201 {
+		t.Errorf("findMock(POST) = %+v, want the newest matching rule", r)
+	}
+	if r := tools.findMock("GET", "https://example.com/api/orders"); r == nil || r.Status != 500 {
+		t.Errorf("findMock(GET) = %+v, want the any-method rule", r)
+	}
+	if r := tools.findMock("GET", "https://example.com/"); r != nil {
+		t.Errorf("findMock(/) = %+v, want nil", r)
+	}
+}
+
+func TestMockResponse(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("<p>app</p>"))
+	}))
+	defer server.Close()
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	if out := tools.mockResponseRun(ctx, []byte(`{"url_pattern": "/api/orders$", "status": 201, "body": "{\"id\": 7}"}`)); out.Error != nil {
+		t.Fatalf("mockResponseRun error: %v", out.Error)
+	}
+	fetchOrders := []byte(`{"expression": "fetch('/api/orders').then(async r => r.status + ' ' + r.headers.get('content-type') + ' ' + await r.text())", "await": true}`)
+	out := tools.evalRun(ctx, fetchOrders)
+	if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, `201 application/json {\"id\": 7}`) {
+		t.Errorf("Expected the mocked response, got %v %v", out.LLMContent, out.Error)
+	}
+
+	// Cleared mocks let requests through to the server again
+	if out := tools.mockResponseRun(ctx, []byte(`{"action": "clear"}`)); out.Error != nil {
+		t.Fatalf("mockResponseRun error: %v", out.Error)
+	}
+	out = tools.evalRun(ctx, fetchOrders)
+	if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, "404") {
+		t.Errorf("Expected the server's response after clearing mocks, got %v %v", out.LLMContent, out.Error)
+	}
+}
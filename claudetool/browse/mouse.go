@@ -0,0 +1,124 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/input"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// MouseTool definition
+type mouseInput struct {
+	Action    string   `json:"action" enum:"click,double_click,right_click,move,down,up" description:"Mouse action to perform"`
+	X         float64  `json:"x" description:"X coordinate from the left of the viewport"`
+	Y         float64  `json:"y" description:"Y coordinate from the top of the viewport"`
+	Button    string   `json:"button,omitempty" enum:"left,middle,right" description:"Mouse button for click, double_click, down, and up, or held during move (default: left; none for move)"`
+	Modifiers []string `json:"modifiers,omitempty" enum:"alt,ctrl,meta,shift" description:"Modifier keys held during the action"`
+	Timeout   string   `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewMouseTool creates a tool for mouse input at viewport coordinates
+func (b *BrowseTools) NewMouseTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_mouse",
+		Description: `Send mouse input at x,y viewport coordinates in CSS pixels (as in an unscaled screenshot of the viewport).
+Use for canvas apps, maps, and other targets without a usable selector. Drag with down, move, up.`,
+		InputSchema: llm.SchemaFor[mouseInput](),
+		Run:         b.mouseRun,
+	}
+}
+
+// mouseActions returns the actions for a mouse tool input
+func (in *mouseInput) mouseActions() ([]chromedp.Action, error) {
+	if in.X < 0 || in.Y < 0 {
+		return nil, fmt.Errorf("coordinates must not be negative")
+	}
+	modifiers, err := parseModifiers(in.Modifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	button := input.Left
+	switch in.Button {
+	case "", "left":
+		if in.Action == "right_click" {
+			button = input.Right
+		} else if in.Action == "move" && in.Button == "" {
+			button = input.None
+		}
+	case "middle":
+		button = input.Middle
+	case "right":
+		button = input.Right
+	default:
+		return nil, fmt.Errorf("unsupported button %q: must be left, middle, or right", in.Button)
+	}
+	opts := []chromedp.MouseOption{chromedp.ButtonType(button), chromedp.ButtonModifiers(modifiers)}
+
+	switch in.Action {
+	case "click", "right_click":
+		return []chromedp.Action{chromedp.MouseClickXY(in.X, in.Y, opts...)}, nil
+	case "double_click":
+		// Browsers see a double click as two clicks, the second with a click count of 2
+		return []chromedp.Action{
+			chromedp.MouseClickXY(in.X, in.Y, append(opts, chromedp.ClickCount(1))...),
+			chromedp.MouseClickXY(in.X, in.Y, append(opts, chromedp.ClickCount(2))...),
+		}, nil
+	case "move":
+		return []chromedp.Action{chromedp.MouseEvent(input.MouseMoved, in.X, in.Y, opts...)}, nil
+	case "down":
+		return []chromedp.Action{chromedp.MouseEvent(input.MousePressed, in.X, in.Y, append(opts, chromedp.ClickCount(1))...)}, nil
+	case "up":
+		return []chromedp.Action{chromedp.MouseEvent(input.MouseReleased, in.X, in.Y, append(opts, chromedp.ClickCount(1))...)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported action %q", in.Action)
+	}
+}
+
+// parseModifiers combines modifier key names (alt, ctrl, meta, shift) into a modifier mask
+func parseModifiers(names []string) (input.Modifier, error) {
+	var mods input.Modifier
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case "alt":
+			mods |= input.ModifierAlt
+		case "ctrl", "control":
+			mods |= input.ModifierCtrl
+		case "meta", "cmd", "command":
+			mods |= input.ModifierMeta
+		case "shift":
+			mods |= input.ModifierShift
+		default:
+			return 0, fmt.Errorf("unsupported modifier %q: must be alt, ctrl, meta, or shift", name)
+		}
+	}
+	return mods, nil
+}
+
+func (b *BrowseTools) mouseRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input mouseInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	actions, err := input.mouseActions()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	if err := chromedp.Run(timeoutCtx, actions...); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	return b.actionToolOut("done")
+}
@@ -0,0 +1,87 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/input"
+)
+
+func TestParseModifiers(t *testing.T) {
+	mods, err := parseModifiers([]string{"Shift", "ctrl"})
+	if err != nil || mods != input.ModifierShift|input.ModifierCtrl {
+		t.Errorf("parseModifiers = %v, %v", mods, err)
+	}
+	if _, err := parseModifiers([]string{"hyper"}); err == nil {
+		t.Error("Expected error for unknown modifier")
+	}
+}
+
+func TestMouseActions(t *testing.T) {
+	tests := []struct {
+		input   string
+		actions int
+		wantErr string
+	}{
+		{`{"action": "click", "x": 1, "y": 2}`, 1, ""},
+		{`{"action": "double_click", "x": 1, "y": 2}`, 2, ""},
+		{`{"action": "move", "x": 1, "y": 2, "button": "left"}`, 1, ""},
+		{`{"action": "right_click", "x": 1, "y": 2, "modifiers": ["shift"]}`, 1, ""},
+		{`{"action": "hover", "x": 1, "y": 2}`, 0, "unsupported action"},
+		{`{"action": "click", "x": -1, "y": 2}`, 0, "negative"},
+		{`{"action": "click", "x": 1, "y": 2, "button": "back"}`, 0, "unsupported button"},
+		{`{"action": "click", "x": 1, "y": 2, "modifiers": ["fn"]}`, 0, "unsupported modifier"},
+	}
+	for _, tt := range tests {
+		var in mouseInput
+		if err := json.Unmarshal([]byte(tt.input), &in); err != nil {
+			t.Fatal(err)
+		}
+		actions, err := in.mouseActions()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("mouseActions(%s) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || len(actions) != tt.actions {
+			t.Errorf("mouseActions(%s) = %d actions, %v; want %d", tt.input, len(actions), err, tt.actions)
+		}
+	}
+}
+
+func TestMouse(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	html := `<body style="margin:0" ondblclick="document.title='dbl:'+event.clientX+','+event.clientY+':'+event.shiftKey"></body>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.NewNavigateTool().Run(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	toolOut = tools.mouseRun(ctx, []byte(`{"action": "double_click", "x": 40, "y": 30, "modifiers": ["shift"]}`))
+	if toolOut.Error != nil {
+		t.Fatalf("mouseRun error: %v", toolOut.Error)
+	}
+	toolOut = tools.evalRun(ctx, []byte(`{"expression": "document.title"}`))
+	if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, "dbl:40,30:true") {
+		t.Errorf("Expected double click at 40,30 with shift, got %v %v", toolOut.LLMContent, toolOut.Error)
+	}
+}
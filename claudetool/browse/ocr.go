@@ -0,0 +1,99 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/imageutil"
+)
+
+// defaultOCRMinConfidence drops the low-confidence noise tesseract reports for icons and graphics
+const defaultOCRMinConfidence = 30
+
+// OCRImageTool definition
+type ocrImageInput struct {
+	Path          string   `json:"path,omitempty" description:"Path to the image file, such as a screenshot (default: capture the current viewport)"`
+	MinConfidence *float64 `json:"min_confidence,omitempty" description:"Drop lines with a lower mean confidence, from 0 to 100 (default: 30)"`
+	Words         bool     `json:"words,omitempty" description:"Include each word's bounding box, not just each line's (default: false)"`
+	Timeout       string   `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewOCRImageTool creates a tool for extracting text from images
+func (b *BrowseTools) NewOCRImageTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "ocr_image",
+		Description: `Extract text lines with bounding boxes from an image file, or from the current browser viewport if no path is given.
+Useful for canvas-rendered UIs, and much cheaper than reading the image itself. Boxes are in image pixels
+(for the viewport, CSS pixels, usable with browser_mouse). Requires tesseract.`,
+		InputSchema: llm.SchemaFor[ocrImageInput](),
+		Run:         b.ocrImageRun,
+	}
+}
+
+func (b *BrowseTools) ocrImageRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input ocrImageInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	minConfidence := float64(defaultOCRMinConfidence)
+	if input.MinConfidence != nil {
+		minConfidence = *input.MinConfidence
+	}
+
+	var data []byte
+	source := input.Path
+	if input.Path != "" {
+		var err error
+		data, err = os.ReadFile(input.Path)
+		if err != nil {
+			return llm.ErrorfToolOut("failed to read image file: %w", err)
+		}
+		if imageutil.IsHEIC(data) {
+			if data, err = imageutil.ConvertHEICToPNG(data); err != nil {
+				return llm.ErrorfToolOut("failed to convert HEIC image: %w", err)
+			}
+		}
+	} else {
+		browserCtx, err := b.GetBrowserContext()
+		if err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+		defer cancel()
+		if err := chromedp.Run(timeoutCtx, chromedp.CaptureScreenshot(&data)); err != nil {
+			return llm.ErrorfToolOut("failed to capture viewport: %w", err)
+		}
+		source = "the viewport"
+	}
+
+	ocrCtx, cancel := context.WithTimeout(ctx, parseTimeout(input.Timeout))
+	defer cancel()
+	lines, err := imageutil.OCR(ocrCtx, data)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	kept := lines[:0]
+	for _, line := range lines {
+		if line.Confidence < minConfidence {
+			continue
+		}
+		if !input.Words {
+			line.Words = nil
+		}
+		kept = append(kept, line)
+	}
+	if len(kept) == 0 {
+		return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("No text found in %s", source))}
+	}
+
+	data, err = json.Marshal(kept)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal OCR result: %w", err)
+	}
+	return jsonToolOut("ocr", fmt.Sprintf("%d lines of text in %s", len(kept), source), data)
+}
@@ -0,0 +1,61 @@
+package browse
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOCRImageRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	out := tools.ocrImageRun(ctx, []byte(`{`))
+	if out.Error == nil || !strings.Contains(out.Error.Error(), "invalid input") {
+		t.Errorf("Expected invalid input error, got %v", out.Error)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.png")
+	out = tools.ocrImageRun(ctx, []byte(`{"path": "`+missing+`"}`))
+	if out.Error == nil || !strings.Contains(out.Error.Error(), "failed to read image file") {
+		t.Errorf("Expected read error, got %v", out.Error)
+	}
+}
+
+func TestOCRImageViewport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+	if _, err := exec.LookPath("tesseract"); err != nil {
+		t.Skip("tesseract not installed")
+	}
+
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	html := `<canvas id="c" width="600" height="200"></canvas><script>` +
+		`const g = document.getElementById('c').getContext('2d'); g.font = '48px sans-serif'; g.fillText('Canvas Label', 20, 100)</script>`
+	navOut := tools.navigateRun(ctx, []byte(`{"url": "data:text/html,`+strings.ReplaceAll(html, `"`, `\"`)+`"}`))
+	if navOut.Error != nil {
+		if strings.Contains(navOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", navOut.Error)
+	}
+
+	out := tools.ocrImageRun(ctx, []byte(`{}`))
+	if out.Error != nil {
+		t.Fatalf("ocrImageRun error: %v", out.Error)
+	}
+	if text := out.LLMContent[0].Text; !strings.Contains(text, "Canvas Label") {
+		t.Errorf("Expected OCR to find the canvas text, got %s", text)
+	}
+}
@@ -0,0 +1,55 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// SetOfflineTool definition
+type setOfflineInput struct {
+	Offline *bool  `json:"offline" description:"true to go offline, false to restore the network"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewSetOfflineTool creates a tool for emulating a lost network connection
+func (b *BrowseTools) NewSetOfflineTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_set_offline",
+		Description: `Emulate being offline (or back online): page network requests fail and navigator.onLine and online/offline events follow,
+so service worker caching and offline UI can be tested. Persists until turned off or the browser restarts.`,
+		InputSchema: llm.SchemaFor[setOfflineInput](),
+		Run:         b.setOfflineRun,
+	}
+}
+
+func (b *BrowseTools) setOfflineRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input setOfflineInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Offline == nil {
+		return llm.ErrorfToolOut("offline is required")
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	// Throughput -1 disables throttling
+	if err := chromedp.Run(timeoutCtx, network.EmulateNetworkConditions(*input.Offline, 0, -1, -1)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	if *input.Offline {
+		return llm.ToolOut{LLMContent: llm.TextContent("Browser is offline")}
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent("Browser is online")}
+}
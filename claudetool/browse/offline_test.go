@@ -0,0 +1,58 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetOfflineRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{`{`, `{}`, `{"offline": "yes"}`} {
+		if out := tools.setOfflineRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("setOfflineRun(%s) succeeded, want error", input)
+		}
+	}
+}
+
+func TestSetOffline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<p>page</p>"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	for _, offline := range []bool{true, false} {
+		input, _ := json.Marshal(setOfflineInput{Offline: &offline})
+		if out := tools.setOfflineRun(ctx, input); out.Error != nil {
+			t.Fatalf("setOfflineRun error: %v", out.Error)
+		}
+		out := tools.evalRun(ctx, []byte(`{"expression": "navigator.onLine"}`))
+		want := "<javascript_result>" + map[bool]string{true: "false", false: "true"}[offline] + "</javascript_result>"
+		if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, want) {
+			t.Errorf("offline=%v: expected %s, got %v %v", offline, want, out.LLMContent, out.Error)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// pageInfo is the result of browser_page_info
+type pageInfo struct {
+	URL            string  `json:"url"`
+	Title          string  `json:"title"`
+	ReadyState     string  `json:"ready_state"`
+	ViewportWidth  int     `json:"viewport_width"`
+	ViewportHeight int     `json:"viewport_height"`
+	ScrollX        float64 `json:"scroll_x"`
+	ScrollY        float64 `json:"scroll_y"`
+	ScrollWidth    int     `json:"scroll_width"`
+	ScrollHeight   int     `json:"scroll_height"`
+	FrameCount     int     `json:"frame_count"`
+}
+
+// pageInfoJS collects pageInfo in a single evaluation
+const pageInfoJS = `(() => {
+	const el = document.scrollingElement || document.documentElement;
+	return {
+		url: location.href,
+		title: document.title,
+		ready_state: document.readyState,
+		viewport_width: window.innerWidth,
+		viewport_height: window.innerHeight,
+		scroll_x: window.scrollX,
+		scroll_y: window.scrollY,
+		scroll_width: el ? el.scrollWidth : 0,
+		scroll_height: el ? el.scrollHeight : 0,
+		frame_count: window.frames.length,
+	};
+})()`
+
+// PageInfoTool definition
+type pageInfoInput struct {
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewPageInfoTool creates a tool for summarizing the current page
+func (b *BrowseTools) NewPageInfoTool() *llm.Tool {
+	return &llm.Tool{
+		Name:        "browser_page_info",
+		Description: "Get the current page's URL, title, ready state, viewport size, scroll position and size, and frame count in one call",
+		InputSchema: llm.SchemaFor[pageInfoInput](),
+		Run:         b.pageInfoRun,
+	}
+}
+
+func (b *BrowseTools) pageInfoRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input pageInfoInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var info pageInfo
+	if err := chromedp.Run(timeoutCtx, chromedp.Evaluate(pageInfoJS, &info)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal page info: %w", err)
+	}
+	return jsonToolOut("page_info", "Current page", data)
+}
@@ -0,0 +1,63 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPageInfoRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	toolOut := tools.pageInfoRun(ctx, []byte(`{"timeout": 5}`))
+	if toolOut.Error == nil {
+		t.Error("Expected error for invalid JSON input")
+	}
+}
+
+func TestPageInfo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	html := `<title>Info</title><iframe srcdoc="x"></iframe><div style="height:5000px"></div>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.NewNavigateTool().Run(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	toolOut = tools.pageInfoRun(ctx, []byte(`{}`))
+	if toolOut.Error != nil {
+		t.Fatalf("pageInfoRun error: %v", toolOut.Error)
+	}
+	text := toolOut.LLMContent[0].Text
+	start, end := strings.Index(text, "<page_info>"), strings.Index(text, "</page_info>")
+	if start < 0 || end < 0 {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	var info pageInfo
+	if err := json.Unmarshal([]byte(text[start+len("<page_info>"):end]), &info); err != nil {
+		t.Fatalf("Failed to parse page info: %v", err)
+	}
+	if info.Title != "Info" || info.FrameCount != 1 || info.ViewportWidth != 1280 || info.ScrollHeight < 5000 {
+		t.Errorf("Unexpected page info: %+v", info)
+	}
+}
@@ -0,0 +1,208 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// pageState is a snapshot saved by browser_save_state
+type pageState struct {
+	URL     string            `json:"url"`
+	Origin  string            `json:"origin"`
+	Local   map[string]string `json:"local"`
+	Session map[string]string `json:"session"`
+	ScrollX float64           `json:"scroll_x"`
+	ScrollY float64           `json:"scroll_y"`
+	Cookies []*network.Cookie `json:"-"`
+}
+
+// describe summarizes what the snapshot holds
+func (s *pageState) describe() string {
+	return fmt.Sprintf("%s with %d cookies, %d localStorage and %d sessionStorage items, scrolled to %g,%g",
+		s.URL, len(s.Cookies), len(s.Local), len(s.Session), s.ScrollX, s.ScrollY)
+}
+
+// savePageStateJS reads the current page's URL, origin, storage, and scroll position
+const savePageStateJS = `function() {
+	const read = (s) => { const o = {}; for (let i = 0; i < s.length; i++) { const k = s.key(i); o[k] = s.getItem(k); } return o; };
+	return {url: location.href, origin: location.origin, local: read(localStorage), session: read(sessionStorage), scroll_x: scrollX, scroll_y: scrollY};
+}`
+
+// restoreStorageScript returns a script for new documents that replaces the storage of state's origin
+func restoreStorageScript(state *pageState) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	return `(() => {
+	const state = ` + string(data) + `;
+	if (location.origin !== state.origin) return;
+	for (const [s, items] of [[localStorage, state.local], [sessionStorage, state.session]]) {
+		s.clear();
+		for (const [k, v] of Object.entries(items)) s.setItem(k, v);
+	}
+})()`, nil
+}
+
+// cookieParam converts a saved cookie into the parameters that recreate it
+func cookieParam(c *network.Cookie) *network.CookieParam {
+	p := &network.CookieParam{
+		Name:         c.Name,
+		Value:        c.Value,
+		Domain:       c.Domain,
+		Path:         c.Path,
+		Secure:       c.Secure,
+		HTTPOnly:     c.HTTPOnly,
+		SameSite:     c.SameSite,
+		Priority:     c.Priority,
+		SourceScheme: c.SourceScheme,
+		SourcePort:   c.SourcePort,
+		PartitionKey: c.PartitionKey,
+	}
+	if !c.Session {
+		expires := cdp.TimeSinceEpoch(time.Unix(0, int64(c.Expires*float64(time.Second))))
+		p.Expires = &expires
+	}
+	return p
+}
+
+// SaveStateTool and RestoreStateTool definition
+type pageStateInput struct {
+	Name    string `json:"name" description:"Name of the snapshot"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewSaveStateTool creates a tool for snapshotting the page's URL, cookies, storage, and scroll position
+func (b *BrowseTools) NewSaveStateTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_save_state",
+		Description: `Save the current URL, all cookies, the page origin's localStorage and sessionStorage, and the scroll position under a name,
+to come back to with browser_restore_state, e.g. to try several flows from the same point without repeating the setup. IndexedDB and in-memory page state are not saved.`,
+		InputSchema: llm.SchemaFor[pageStateInput](),
+		Run:         b.saveStateRun,
+	}
+}
+
+// NewRestoreStateTool creates a tool for restoring a snapshot saved by browser_save_state
+func (b *BrowseTools) NewRestoreStateTool() *llm.Tool {
+	return &llm.Tool{
+		Name:        "browser_restore_state",
+		Description: `Restore a snapshot saved by browser_save_state: replace all cookies and the origin's storage with the saved ones, load the saved URL, and scroll to the saved position.`,
+		InputSchema: llm.SchemaFor[pageStateInput](),
+		Run:         b.restoreStateRun,
+	}
+}
+
+func (b *BrowseTools) saveStateRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input pageStateInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Name == "" {
+		return llm.ErrorfToolOut("name is required")
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var state pageState
+	err = chromedp.Run(timeoutCtx,
+		callWithArgs(savePageStateJS, nil, false, &state),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			state.Cookies, err = storage.GetCookies().Do(ctx)
+			return err
+		}))
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	b.statesMutex.Lock()
+	b.states[input.Name] = &state
+	b.statesMutex.Unlock()
+	return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("Saved state %q: %s", input.Name, state.describe()))}
+}
+
+func (b *BrowseTools) restoreStateRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input pageStateInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	b.statesMutex.Lock()
+	state, ok := b.states[input.Name]
+	names := make([]string, 0, len(b.states))
+	for name := range b.states {
+		names = append(names, name)
+	}
+	b.statesMutex.Unlock()
+	if !ok {
+		slices.Sort(names)
+		return llm.ErrorfToolOut("no saved state %q; saved states: [%s]", input.Name, strings.Join(names, ", "))
+	}
+	if err := b.allowNavigation(ctx, state.URL); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	script, err := restoreStorageScript(state)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to encode storage: %w", err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	cookies := make([]*network.CookieParam, len(state.Cookies))
+	for i, c := range state.Cookies {
+		cookies[i] = cookieParam(c)
+	}
+	var scriptID page.ScriptIdentifier
+	err = chromedp.Run(timeoutCtx,
+		storage.ClearCookies(),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			if len(cookies) == 0 {
+				return nil
+			}
+			return storage.SetCookies(cookies).Do(ctx)
+		}),
+		// Storage is written as the document is created, before the page's own scripts read it
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			scriptID, err = page.AddScriptToEvaluateOnNewDocument(script).Do(ctx)
+			return err
+		}))
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	err = chromedp.Run(timeoutCtx,
+		chromedp.Navigate(state.URL),
+		chromedp.WaitReady("body"),
+		callWithArgs(`function(x, y) { scrollTo(x, y); }`, []any{state.ScrollX, state.ScrollY}, false, nil),
+	)
+	if rmErr := chromedp.Run(timeoutCtx, page.RemoveScriptToEvaluateOnNewDocument(scriptID)); err == nil {
+		err = rmErr
+	}
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	return b.actionToolOut(fmt.Sprintf("Restored state %q: %s", input.Name, state.describe()))
+}
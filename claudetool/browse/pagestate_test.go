@@ -0,0 +1,89 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPageStateRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	if out := tools.saveStateRun(ctx, []byte(`{}`)); out.Error == nil || !strings.Contains(out.Error.Error(), "name is required") {
+		t.Errorf("expected name to be required, got %v", out.Error)
+	}
+	if out := tools.restoreStateRun(ctx, []byte(`{"name": "missing"}`)); out.Error == nil || !strings.Contains(out.Error.Error(), `no saved state "missing"`) {
+		t.Errorf("expected an unknown state error, got %v", out.Error)
+	}
+}
+
+func TestPageStateSaveRestore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		// The page records what storage held before any later script could change it
+		w.Write([]byte(`<script>window.initial = localStorage.getItem('step')</script><div style="height:5000px">tall</div>`))
+	}))
+	defer server.Close()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL + "/checkout"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	eval := func(expr string) string {
+		t.Helper()
+		input, _ := json.Marshal(evalInput{Expression: expr})
+		out := tools.evalRun(ctx, input)
+		if out.Error != nil {
+			t.Fatalf("evalRun(%s) error: %v", expr, out.Error)
+		}
+		return out.LLMContent[0].Text
+	}
+	eval(`localStorage.setItem('step', 'address'); document.cookie = 'cart=42'; scrollTo(0, 1200)`)
+
+	out := tools.saveStateRun(ctx, []byte(`{"name": "checkout"}`))
+	if out.Error != nil {
+		t.Fatalf("saveStateRun error: %v", out.Error)
+	}
+	if !strings.Contains(out.LLMContent[0].Text, "1 cookies, 1 localStorage") {
+		t.Errorf("unexpected save output: %s", out.LLMContent[0].Text)
+	}
+
+	eval(`localStorage.setItem('step', 'payment'); localStorage.setItem('extra', 'x'); document.cookie = 'cart=99'`)
+	navInput, _ = json.Marshal(navigateInput{URL: server.URL + "/elsewhere"})
+	if out := tools.navigateRun(ctx, navInput); out.Error != nil {
+		t.Fatalf("Navigation error: %v", out.Error)
+	}
+
+	if out := tools.restoreStateRun(ctx, []byte(`{"name": "checkout"}`)); out.Error != nil {
+		t.Fatalf("restoreStateRun error: %v", out.Error)
+	}
+	got := eval(`[location.pathname, window.initial, localStorage.getItem('extra'), document.cookie, scrollY].join('|')`)
+	if !strings.Contains(got, `"/checkout|address||cart=42|1200"`) {
+		t.Errorf("unexpected restored state: %s", got)
+	}
+}
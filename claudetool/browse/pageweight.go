@@ -0,0 +1,193 @@
+package browse
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// pageWeightJS returns the page's navigation and resource timing entries with their sizes
+const pageWeightJS = `(() => performance.getEntriesByType('navigation').concat(performance.getEntriesByType('resource')).map(e => ({
+	url: e.name,
+	initiator: e.initiatorType,
+	transferred: e.transferSize,
+	encoded: e.encodedBodySize,
+	decoded: e.decodedBodySize,
+})))()`
+
+// minCompressibleSize is the size above which an uncompressed text resource is reported
+const minCompressibleSize = 1024
+
+// loadedResource is a resource timing entry
+type loadedResource struct {
+	URL       string `json:"url"`
+	Type      string `json:"type"`
+	Initiator string `json:"initiator,omitempty"`
+	// Transferred includes headers and is 0 when served from cache
+	Transferred int64 `json:"transferred"`
+	Encoded     int64 `json:"encoded"`
+	Decoded     int64 `json:"decoded"`
+}
+
+// resourceType classifies a resource by file extension, then by what requested it
+func (r *loadedResource) resourceType() string {
+	ext := ""
+	if u, err := url.Parse(r.URL); err == nil {
+		ext = strings.ToLower(path.Ext(u.Path))
+	}
+	switch {
+	case r.Initiator == "navigation" || r.Initiator == "iframe" || ext == ".html":
+		return "document"
+	case ext == ".js" || ext == ".mjs" || r.Initiator == "script" && ext == "":
+		return "js"
+	case ext == ".css":
+		return "css"
+	case slices.Contains([]string{".woff2", ".woff", ".ttf", ".otf", ".eot"}, ext):
+		return "font"
+	case slices.Contains([]string{".png", ".jpg", ".jpeg", ".gif", ".webp", ".avif", ".svg", ".ico"}, ext) || r.Initiator == "img":
+		return "image"
+	case slices.Contains([]string{".mp4", ".webm", ".mp3", ".ogg", ".wav"}, ext) || r.Initiator == "video" || r.Initiator == "audio":
+		return "media"
+	case r.Initiator == "fetch" || r.Initiator == "xmlhttprequest" || r.Initiator == "beacon":
+		return "fetch"
+	}
+	return "other"
+}
+
+// uncompressed reports whether r is a text resource sent without compression
+func (r *loadedResource) uncompressed() bool {
+	switch r.Type {
+	case "document", "js", "css", "fetch":
+		return r.Encoded >= minCompressibleSize && r.Encoded == r.Decoded
+	case "image":
+		return strings.HasSuffix(strings.ToLower(r.URL), ".svg") && r.Encoded >= minCompressibleSize && r.Encoded == r.Decoded
+	}
+	return false
+}
+
+// resourceTypeWeight totals the resources of one type
+type resourceTypeWeight struct {
+	Type        string `json:"type"`
+	Requests    int    `json:"requests"`
+	Transferred int64  `json:"transferred"`
+	Decoded     int64  `json:"decoded"`
+}
+
+// pageWeightReport is the result of browser_page_weight
+type pageWeightReport struct {
+	Requests    int                   `json:"requests"`
+	Transferred int64                 `json:"transferred"`
+	Decoded     int64                 `json:"decoded"`
+	Cached      int                   `json:"cached"`
+	ByType      []*resourceTypeWeight `json:"by_type"`
+	Largest     []*loadedResource     `json:"largest"`
+	// Uncompressed lists text resources over 1KB sent without gzip, brotli, or zstd
+	Uncompressed []string `json:"uncompressed"`
+	// UnknownSize counts cross-origin resources whose sizes are hidden for lack of Timing-Allow-Origin
+	UnknownSize int `json:"unknown_size,omitempty"`
+}
+
+// weighPage builds the report from timing entries, listing up to largest of the biggest resources
+func weighPage(resources []*loadedResource, largest int) *pageWeightReport {
+	report := &pageWeightReport{Largest: []*loadedResource{}, Uncompressed: []string{}}
+	byType := map[string]*resourceTypeWeight{}
+	for _, r := range resources {
+		r.Type = r.resourceType()
+		report.Requests++
+		report.Transferred += r.Transferred
+		report.Decoded += r.Decoded
+		switch {
+		case r.Transferred == 0 && r.Decoded > 0:
+			report.Cached++
+		case r.Transferred == 0 && r.Decoded == 0 && r.Initiator != "navigation":
+			report.UnknownSize++
+		}
+		t, ok := byType[r.Type]
+		if !ok {
+			t = &resourceTypeWeight{Type: r.Type}
+			byType[r.Type] = t
+			report.ByType = append(report.ByType, t)
+		}
+		t.Requests++
+		t.Transferred += r.Transferred
+		t.Decoded += r.Decoded
+		if r.uncompressed() {
+			report.Uncompressed = append(report.Uncompressed, r.URL)
+		}
+	}
+	slices.SortStableFunc(report.ByType, func(a, b *resourceTypeWeight) int {
+		return cmp.Or(cmp.Compare(b.Transferred, a.Transferred), cmp.Compare(b.Decoded, a.Decoded))
+	})
+
+	sorted := slices.Clone(resources)
+	slices.SortStableFunc(sorted, func(a, b *loadedResource) int {
+		return cmp.Or(cmp.Compare(b.Transferred, a.Transferred), cmp.Compare(b.Decoded, a.Decoded))
+	})
+	for _, r := range sorted[:min(largest, len(sorted))] {
+		if r.Decoded > 0 || r.Transferred > 0 {
+			report.Largest = append(report.Largest, r)
+		}
+	}
+	return report
+}
+
+// PageWeightTool definition
+type pageWeightInput struct {
+	Largest int    `json:"largest,omitempty" description:"Number of largest resources to list (default: 10)"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewPageWeightTool creates a tool for summarizing what the current page downloaded
+func (b *BrowseTools) NewPageWeightTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_page_weight",
+		Description: `Summarize the bytes the current page loaded: totals, bytes by resource type (document, js, css, image, font, media, fetch),
+the largest resources, and text resources sent uncompressed. Sizes are in bytes; "transferred" is 0 for cached resources,
+so navigate with a fresh browser for a cold-load measurement. Cross-origin sizes are hidden unless the server sends Timing-Allow-Origin.`,
+		InputSchema: llm.SchemaFor[pageWeightInput](),
+		Run:         b.pageWeightRun,
+	}
+}
+
+func (b *BrowseTools) pageWeightRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input pageWeightInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Largest < 0 {
+		return llm.ErrorfToolOut("largest must not be negative")
+	}
+	if input.Largest == 0 {
+		input.Largest = 10
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var resources []*loadedResource
+	if err := chromedp.Run(timeoutCtx, chromedp.Evaluate(pageWeightJS, &resources)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	report := weighPage(resources, input.Largest)
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal report: %w", err)
+	}
+	summary := fmt.Sprintf("%d requests, %d bytes transferred (%d decoded), %d from cache, %d uncompressed text resources",
+		report.Requests, report.Transferred, report.Decoded, report.Cached, len(report.Uncompressed))
+	return jsonToolOut("page_weight", summary, data)
+}
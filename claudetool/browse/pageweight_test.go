@@ -0,0 +1,83 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWeighPage(t *testing.T) {
+	resources := []*loadedResource{
+		{URL: "https://example.com/", Initiator: "navigation", Transferred: 3000, Encoded: 2700, Decoded: 9000},
+		{URL: "https://example.com/app.js", Initiator: "script", Transferred: 50300, Encoded: 50000, Decoded: 50000},
+		{URL: "https://example.com/site.css?v=2", Initiator: "link", Transferred: 0, Encoded: 4000, Decoded: 16000},
+		{URL: "https://example.com/logo.png", Initiator: "img", Transferred: 20300, Encoded: 20000, Decoded: 20000},
+		{URL: "https://fonts.example.net/inter.woff2", Initiator: "css"},
+	}
+	report := weighPage(resources, 2)
+
+	if report.Requests != 5 || report.Transferred != 73600 || report.Cached != 1 || report.UnknownSize != 1 {
+		t.Errorf("totals = %+v", report)
+	}
+	var types []string
+	for _, w := range report.ByType {
+		types = append(types, w.Type)
+	}
+	if got := strings.Join(types, ","); got != "js,image,document,css,font" {
+		t.Errorf("by_type order = %s", got)
+	}
+	if len(report.Largest) != 2 || report.Largest[0].URL != "https://example.com/app.js" || report.Largest[1].Type != "image" {
+		t.Errorf("largest = %+v", report.Largest)
+	}
+	if len(report.Uncompressed) != 1 || report.Uncompressed[0] != "https://example.com/app.js" {
+		t.Errorf("uncompressed = %v", report.Uncompressed)
+	}
+}
+
+func TestPageWeight(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	script := "var x = '" + strings.Repeat("a", 5000) + "';"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Timing-Allow-Origin", "*")
+		if r.URL.Path == "/app.js" {
+			w.Header().Set("Content-Type", "text/javascript")
+			w.Write([]byte(script))
+			return
+		}
+		w.Write([]byte(`<script src="/app.js"></script>`))
+	}))
+	defer server.Close()
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.pageWeightRun(ctx, []byte(`{}`))
+	if out.Error != nil {
+		t.Fatalf("pageWeightRun error: %v", out.Error)
+	}
+	text := out.LLMContent[0].Text
+	if !strings.Contains(text, "2 requests") || !strings.Contains(text, "1 uncompressed") {
+		t.Errorf("Unexpected page weight: %s", text)
+	}
+}
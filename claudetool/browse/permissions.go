@@ -0,0 +1,102 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// permissionNames maps the tool's permission names to Permissions API names
+var permissionNames = map[string][]string{
+	"notifications": {"notifications"},
+	"clipboard":     {"clipboard-read", "clipboard-write"},
+	"camera":        {"camera"},
+	"microphone":    {"microphone"},
+	"geolocation":   {"geolocation"},
+}
+
+// SetPermissionsTool definition
+type setPermissionsInput struct {
+	Permissions []string `json:"permissions" enum:"notifications,clipboard,camera,microphone,geolocation" description:"Permissions to set"`
+	Setting     string   `json:"setting" enum:"granted,denied,prompt" description:"What the page gets when it asks"`
+	Origin      string   `json:"origin,omitempty" description:"Origin to set them for, e.g. https://example.com (default: the current page's origin)"`
+	Timeout     string   `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewSetPermissionsTool creates a tool for granting or denying page permissions
+func (b *BrowseTools) NewSetPermissionsTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_set_permissions",
+		Description: `Grant or deny permissions for an origin, so pages asking for them get an answer instead of a permission prompt that automation cannot click.
+"prompt" restores the default of asking. Settings persist until changed or the browser restarts.`,
+		InputSchema: llm.SchemaFor[setPermissionsInput](),
+		Run:         b.setPermissionsRun,
+	}
+}
+
+func (b *BrowseTools) setPermissionsRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input setPermissionsInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if len(input.Permissions) == 0 {
+		return llm.ErrorfToolOut("permissions is required")
+	}
+	var names []string
+	for _, p := range input.Permissions {
+		apiNames, ok := permissionNames[p]
+		if !ok {
+			return llm.ErrorfToolOut("unknown permission %q", p)
+		}
+		names = append(names, apiNames...)
+	}
+	setting := browser.PermissionSetting(input.Setting)
+	switch setting {
+	case browser.PermissionSettingGranted, browser.PermissionSettingDenied, browser.PermissionSettingPrompt:
+	default:
+		return llm.ErrorfToolOut("setting must be granted, denied, or prompt, not %q", input.Setting)
+	}
+	if input.Origin != "" {
+		u, err := url.Parse(input.Origin)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return llm.ErrorfToolOut("invalid origin %q", input.Origin)
+		}
+		input.Origin = u.Scheme + "://" + u.Host
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	if input.Origin == "" {
+		if err := chromedp.Run(timeoutCtx, chromedp.Evaluate("location.origin", &input.Origin)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		if input.Origin == "null" {
+			return llm.ErrorfToolOut("the current page has no origin; give one")
+		}
+	}
+
+	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		for _, name := range names {
+			if err := browser.SetPermission(&browser.PermissionDescriptor{Name: name}, setting).WithOrigin(input.Origin).Do(ctx); err != nil {
+				return fmt.Errorf("failed to set %s: %w", name, err)
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("Set %s to %s for %s", strings.Join(input.Permissions, ", "), setting, input.Origin))}
+}
@@ -0,0 +1,71 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetPermissionsRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{
+		`{`,
+		`{"setting": "granted"}`,
+		`{"permissions": ["teleport"], "setting": "granted"}`,
+		`{"permissions": ["camera"], "setting": "maybe"}`,
+		`{"permissions": ["camera"], "setting": "granted", "origin": "example.com"}`,
+	} {
+		if out := tools.setPermissionsRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("setPermissionsRun(%s) succeeded, want error", input)
+		}
+	}
+}
+
+func TestSetPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<p>page</p>"))
+	}))
+	defer server.Close()
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	for _, setting := range []string{"granted", "denied"} {
+		input, _ := json.Marshal(setPermissionsInput{Permissions: []string{"notifications", "geolocation"}, Setting: setting})
+		if out := tools.setPermissionsRun(ctx, input); out.Error != nil {
+			t.Fatalf("setPermissionsRun error: %v", out.Error)
+		}
+		out := tools.evalRun(ctx, []byte(`{"expression": "navigator.permissions.query({name: 'geolocation'}).then(s => s.state + ',' + Notification.permission)", "await": true}`))
+		want := setting + "," + setting
+		if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, want) {
+			t.Errorf("setting=%s: expected %s, got %v %v", setting, want, out.LLMContent, out.Error)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+// NavigationPolicy restricts which URLs the browser may load as documents.
+// It is enforced on browser_navigate, on redirects and iframe loads (via request
+// interception, in every page and worker), and on pages opened with window.open (which are closed).
+// With BlockPrivateNetworks, a page that opens a WebSocket to an internal address is navigated away.
+//
+// Host patterns use path.Match syntax against the URL's hostname, e.g. "*.example.com".
+// URLs without a host (about:blank, data:) are only subject to the scheme check.
+type NavigationPolicy struct {
+	// Allow lists host patterns that may be loaded; empty allows any host not denied.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists host patterns that may not be loaded. Deny takes precedence over Allow.
+	Deny []string `json:"deny,omitempty"`
+	// Schemes lists allowed URL schemes, e.g. ["http", "https"]; empty allows any.
+	Schemes []string `json:"schemes,omitempty"`
+	// DenyPorts lists ports that may not be loaded. Scheme default ports count, so 80 blocks plain http URLs.
+	DenyPorts []int `json:"deny_ports,omitempty"`
+	// BlockPrivateNetworks blocks documents and subresources whose host resolves to a
+	// loopback, private, link-local (including cloud metadata), or otherwise internal address.
+	// Chrome resolves hosts itself, so this does not stop DNS rebinding. WebSocket handshakes can't be
+	// intercepted, so a page that opens one to such an address is replaced with about:blank instead,
+	// which closes the socket, but not before the handshake may have been sent.
+	BlockPrivateNetworks bool `json:"block_private_networks,omitempty"`
+	// AllowPrivate lists exceptions to BlockPrivateNetworks, as host patterns or CIDR prefixes.
+	AllowPrivate []string `json:"allow_private,omitempty"`
+}
+
+// internalPrefixes are address ranges blocked by BlockPrivateNetworks beyond those
+// covered by netip.Addr's IsLoopback, IsPrivate, IsLinkLocalUnicast, and IsUnspecified.
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT, also used by some metadata services
+}
+
+// DefaultNavigationPolicy blocks port 80, where the main server runs
+func DefaultNavigationPolicy() *NavigationPolicy {
+	return &NavigationPolicy{DenyPorts: []int{80}}
+}
+
+// LoadNavigationPolicy reads a JSON NavigationPolicy from a file
+func LoadNavigationPolicy(filename string) (*NavigationPolicy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var p NavigationPolicy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse navigation policy %s: %w", filename, err)
+	}
+	for _, pattern := range slices.Concat(p.Allow, p.Deny, p.AllowPrivate) {
+		if strings.Contains(pattern, "/") {
+			if _, err := netip.ParsePrefix(pattern); err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q in navigation policy: %w", pattern, err)
+			}
+		} else if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid host pattern %q in navigation policy: %w", pattern, err)
+		}
+	}
+	return &p, nil
+}
+
+// Check returns an error describing why rawURL may not be loaded as a document, or nil if it may
+func (p *NavigationPolicy) Check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if len(p.Schemes) > 0 && !slices.Contains(p.Schemes, u.Scheme) {
+		return fmt.Errorf("navigation to %s blocked: scheme %q is not allowed", rawURL, u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return nil
+	}
+
+	if port := effectivePort(u); port != 0 && slices.Contains(p.DenyPorts, port) {
+		return fmt.Errorf("navigation to %s blocked: port %d is not allowed", rawURL, port)
+	}
+	if matchHost(p.Deny, host) {
+		return fmt.Errorf("navigation to %s blocked: host %s is denied", rawURL, host)
+	}
+	if len(p.Allow) > 0 && !matchHost(p.Allow, host) {
+		return fmt.Errorf("navigation to %s blocked: host %s is not in the allow list", rawURL, host)
+	}
+	return p.checkPrivate(ctx, host)
+}
+
+// CheckSubresource returns an error if rawURL may not be fetched by a page, or nil if it may.
+// Only BlockPrivateNetworks applies to subresources.
+func (p *NavigationPolicy) CheckSubresource(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if host := u.Hostname(); host != "" {
+		return p.checkPrivate(ctx, host)
+	}
+	return nil
+}
+
+// checkPrivate returns an error if BlockPrivateNetworks is set and host resolves to an
+// internal address that AllowPrivate doesn't except. Hosts that fail to resolve are blocked.
+func (p *NavigationPolicy) checkPrivate(ctx context.Context, host string) error {
+	if !p.BlockPrivateNetworks || matchHost(p.AllowPrivate, host) {
+		return nil
+	}
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		addrs, err = net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return fmt.Errorf("request to %s blocked: failed to resolve host: %w", host, err)
+		}
+	}
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		if isInternalAddr(addr) && !p.allowedPrivateAddr(addr) {
+			return fmt.Errorf("request to %s blocked: %s is a private network address", host, addr)
+		}
+	}
+	return nil
+}
+
+// isInternalAddr reports whether addr is loopback, private, link-local, or otherwise not publicly routable
+func isInternalAddr(addr netip.Addr) bool {
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedPrivateAddr reports whether addr falls in one of the AllowPrivate CIDR prefixes
+func (p *NavigationPolicy) allowedPrivateAddr(addr netip.Addr) bool {
+	for _, entry := range p.AllowPrivate {
+		if prefix, err := netip.ParsePrefix(entry); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// effectivePort returns u's explicit port, or the scheme's default, or 0 if unknown
+func effectivePort(u *url.URL) int {
+	if port := u.Port(); port != "" {
+		n, _ := strconv.Atoi(port)
+		return n
+	}
+	switch u.Scheme {
+	case "http", "ws":
+		return 80
+	case "https", "wss":
+		return 443
+	}
+	return 0
+}
+
+// matchHost reports whether host matches any of patterns, case-insensitively
+func matchHost(patterns []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNavigationPolicy replaces the navigation policy; nil disables all checks.
+// It takes effect the next time the browser starts.
+func (b *BrowseTools) SetNavigationPolicy(p *NavigationPolicy) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.policy = p
+}
+
+// interceptRequests pauses requests in browserCtx to serve mocked responses and to check
+// redirects and iframe loads against the navigation policy p (and subresources too with
+// BlockPrivateNetworks), and closes popups opened to URLs p disallows. p may be nil.
+// With BlockPrivateNetworks, it also navigates the page away from WebSockets p disallows.
+//
+// Requests are intercepted browser-wide rather than in the page, so those of popups and
+// workers are paused too, before they go out.
+func (b *BrowseTools) interceptRequests(browserCtx context.Context, p *NavigationPolicy) error {
+	c := chromedp.FromContext(browserCtx)
+
+	chromedp.ListenBrowser(browserCtx, func(ev any) {
+		e, ok := ev.(*fetch.EventRequestPaused)
+		if !ok {
+			return
+		}
+		go func() {
+			ctx := cdp.WithExecutor(browserCtx, c.Browser)
+			var err error
+			if rule := b.findMock(e.Request.Method, e.Request.URL); rule != nil {
+				err = rule.fulfill(ctx, e.RequestID)
+			} else if policyErr := p.checkPaused(ctx, e); policyErr != nil {
+				log.Printf("Blocked request: %v", policyErr)
+				err = fetch.FailRequest(e.RequestID, network.ErrorReasonBlockedByClient).Do(ctx)
+			} else {
+				err = fetch.ContinueRequest(e.RequestID).Do(ctx)
+			}
+			if err != nil && browserCtx.Err() == nil {
+				log.Printf("Failed to resolve paused request: %v", err)
+			}
+		}()
+	})
+
+	if p != nil {
+		chromedp.ListenBrowser(browserCtx, func(ev any) {
+			var info *target.Info
+			switch e := ev.(type) {
+			case *target.EventTargetCreated:
+				info = e.TargetInfo
+			case *target.EventTargetInfoChanged:
+				info = e.TargetInfo
+			default:
+				return
+			}
+			if info.Type != "page" || info.OpenerID == "" {
+				return
+			}
+			go func() {
+				if policyErr := p.Check(browserCtx, info.URL); policyErr != nil {
+					log.Printf("Closing popup: %v", policyErr)
+					ctx := cdp.WithExecutor(browserCtx, c.Browser)
+					if err := target.CloseTarget(info.TargetID).Do(ctx); err != nil && browserCtx.Err() == nil {
+						log.Printf("Failed to close popup: %v", err)
+					}
+				}
+			}()
+		})
+	}
+
+	if p != nil && p.BlockPrivateNetworks {
+		// The Fetch domain never pauses WebSocket handshakes, so unload the page that opened the socket instead
+		chromedp.ListenTarget(browserCtx, func(ev any) {
+			e, ok := ev.(*network.EventWebSocketCreated)
+			if !ok {
+				return
+			}
+			go func() {
+				policyErr := p.CheckSubresource(browserCtx, e.URL)
+				if policyErr == nil {
+					return
+				}
+				log.Printf("Blocked WebSocket, leaving the page: %v", policyErr)
+				if err := chromedp.Run(browserCtx, chromedp.Navigate("about:blank")); err != nil && browserCtx.Err() == nil {
+					log.Printf("Failed to leave the page: %v", err)
+				}
+			}()
+		})
+	}
+
+	return chromedp.Run(browserCtx, b.enableInterception(p))
+}
+
+// checkPaused checks a paused request against the policy; a nil policy allows everything
+func (p *NavigationPolicy) checkPaused(ctx context.Context, e *fetch.EventRequestPaused) error {
+	switch {
+	case p == nil:
+		return nil
+	case e.ResourceType == network.ResourceTypeDocument:
+		return p.Check(ctx, e.Request.URL)
+	}
+	return p.CheckSubresource(ctx, e.Request.URL)
+}
+
+// enableInterception pauses the requests, of all the browser's targets, that the policy p or a mocked response
+// needs to see, or stops pausing requests if none do
+func (b *BrowseTools) enableInterception(p *NavigationPolicy) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		ctx = cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser)
+		var pattern *fetch.RequestPattern
+		switch {
+		case b.hasMocks() || p != nil && p.BlockPrivateNetworks:
+			pattern = &fetch.RequestPattern{URLPattern: "*"}
+		case p != nil:
+			pattern = &fetch.RequestPattern{ResourceType: network.ResourceTypeDocument}
+		default:
+			return fetch.Disable().Do(ctx)
+		}
+		return fetch.Enable().WithPatterns([]*fetch.RequestPattern{pattern}).Do(ctx)
+	})
+}
@@ -0,0 +1,197 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultNavigationPolicy(t *testing.T) {
+	tests := []struct {
+		url     string
+		blocked bool
+		name    string
+	}{
+		{"http://example.com:80", true, "http with explicit port 80"},
+		{"http://example.com", true, "http without explicit port"},
+		{"https://example.com:80", true, "https with explicit port 80"},
+		{"http://example.com:8080", false, "http with different port"},
+		{"https://example.com", false, "https without explicit port"},
+		{"https://example.com:443", false, "https with standard port"},
+		{"invalid-url", false, "invalid URL"},
+		{"ftp://example.com:80", true, "ftp with port 80"},
+		{"about:blank", false, "about:blank"},
+	}
+
+	p := DefaultNavigationPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Check(context.Background(), tt.url)
+			if (err != nil) != tt.blocked {
+				t.Errorf("Check(%q) = %v, want blocked=%v", tt.url, err, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestNavigationPolicyCheck(t *testing.T) {
+	p := &NavigationPolicy{
+		Allow:     []string{"*.example.com", "localhost"},
+		Deny:      []string{"admin.example.com"},
+		Schemes:   []string{"http", "https", "about"},
+		DenyPorts: []int{22},
+	}
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.example.com/path", ""},
+		{"https://WWW.Example.com", ""},
+		{"http://localhost:8000", ""},
+		{"about:blank", ""},
+		{"https://admin.example.com", "is denied"},
+		{"https://example.org", "not in the allow list"},
+		{"https://example.com", "not in the allow list"},
+		{"file:///etc/passwd", "scheme"},
+		{"http://localhost:22", "port 22"},
+	}
+	for _, tt := range tests {
+		err := p.Check(context.Background(), tt.url)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("Check(%q) = %v, want allowed", tt.url, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Check(%q) = %v, want error containing %q", tt.url, err, tt.want)
+		}
+	}
+}
+
+func TestLoadNavigationPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.json")
+	os.WriteFile(good, []byte(`{"allow": ["*.test"], "deny_ports": [80, 22]}`), 0o644)
+	p, err := LoadNavigationPolicy(good)
+	if err != nil {
+		t.Fatalf("LoadNavigationPolicy error: %v", err)
+	}
+	if len(p.Allow) != 1 || len(p.DenyPorts) != 2 {
+		t.Errorf("Unexpected policy: %+v", p)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`{"deny": ["[unclosed"]}`), 0o644)
+	if _, err := LoadNavigationPolicy(bad); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestNavigateRunPolicy(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	tools.SetNavigationPolicy(&NavigationPolicy{Deny: []string{"blocked.test"}})
+
+	out := tools.navigateRun(ctx, []byte(`{"url": "https://blocked.test/"}`))
+	if out.Error == nil || !strings.Contains(out.Error.Error(), "is denied") {
+		t.Errorf("Expected policy error, got %v", out.Error)
+	}
+}
+
+func TestNavigationPolicyPrivateNetworks(t *testing.T) {
+	ctx := context.Background()
+	p := &NavigationPolicy{
+		BlockPrivateNetworks: true,
+		AllowPrivate:         []string{"10.1.0.0/16", "devbox"},
+	}
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		{"http://127.0.0.1:8000/", true},
+		{"http://localhost:8000/", true},
+		{"http://[::1]/", true},
+		{"http://192.168.1.1/", true},
+		{"http://169.254.169.254/latest/meta-data/", true},
+		{"http://100.100.100.200/", true},
+		{"http://[fd00:ec2::254]/", true},
+		{"http://[::ffff:10.0.0.1]/", true},
+		{"http://0.0.0.0:9000/", true},
+		{"http://10.1.2.3/", false},
+		{"http://devbox/", false},
+		{"https://93.184.215.14/", false},
+		{"about:blank", false},
+	}
+	for _, tt := range tests {
+		if err := p.CheckSubresource(ctx, tt.url); (err != nil) != tt.blocked {
+			t.Errorf("CheckSubresource(%q) = %v, want blocked=%v", tt.url, err, tt.blocked)
+		}
+		if err := p.Check(ctx, tt.url); (err != nil) != tt.blocked {
+			t.Errorf("Check(%q) = %v, want blocked=%v", tt.url, err, tt.blocked)
+		}
+	}
+
+	// Subresources are not subject to the navigation allow list
+	p.Allow = []string{"example.com"}
+	if err := p.CheckSubresource(ctx, "https://93.184.215.14/"); err != nil {
+		t.Errorf("CheckSubresource applied the allow list: %v", err)
+	}
+}
+
+func TestNavigationPolicyPrivateWebSocket(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	// The page may load from localhost, but not open a WebSocket to 127.0.0.1
+	tools.SetNavigationPolicy(&NavigationPolicy{BlockPrivateNetworks: true, AllowPrivate: []string{"localhost"}})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<p>app</p>"))
+	}))
+	defer server.Close()
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+
+	navInput, _ := json.Marshal(navigateInput{URL: fmt.Sprintf("http://localhost:%d/", port)})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	openSocket, _ := json.Marshal(map[string]string{"expression": fmt.Sprintf("new WebSocket('ws://127.0.0.1:%d/ws'), 'opened'", port)})
+	if out := tools.evalRun(ctx, openSocket); out.Error != nil {
+		t.Fatalf("evalRun error: %v", out.Error)
+	}
+	for {
+		out := tools.evalRun(ctx, []byte(`{"expression": "location.href"}`))
+		if out.Error == nil && strings.Contains(out.LLMContent[0].Text, "about:blank") {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Expected the page to be left after opening a private WebSocket, got %v %v", out.LLMContent, out.Error)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package browse
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// BrowserPool keeps a number of browsers launched ahead of time, so the first
+// browser tool call in a session doesn't pay Chrome's multi-second startup.
+// Each browser is handed out once; the pool launches a replacement in the background.
+type BrowserPool struct {
+	// parent is the lifetime of launched browsers; ctx is the pool's own, ended by Close
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	ready  chan *launchedBrowser
+	launch LaunchOptions
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewBrowserPool starts launching size browsers configured by launch in the background.
+// Browsers are shut down when ctx is done; waiting ones also when Close is called.
+func NewBrowserPool(ctx context.Context, size int, launch LaunchOptions) *BrowserPool {
+	poolCtx, cancel := context.WithCancel(ctx)
+	p := &BrowserPool{
+		parent: ctx,
+		ctx:    poolCtx,
+		cancel: cancel,
+		ready:  make(chan *launchedBrowser, size),
+		launch: launch,
+	}
+	for range size {
+		p.refill()
+	}
+	return p
+}
+
+// refill launches one browser in the background and adds it to the pool
+func (p *BrowserPool) refill() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		lb, err := launchBrowser(p.parent, p.launch)
+		if err != nil {
+			log.Printf("Failed to pre-launch browser: %v", err)
+			return
+		}
+		select {
+		case p.ready <- lb:
+		case <-p.ctx.Done():
+			lb.close()
+		}
+	}()
+}
+
+// take returns a ready browser, or nil if none is ready.
+// Taking a browser triggers launching its replacement.
+func (p *BrowserPool) take() *launchedBrowser {
+	for {
+		select {
+		case lb := <-p.ready:
+			p.refill()
+			if lb.browserCtx.Err() != nil {
+				// Died while waiting, e.g. crashed
+				lb.close()
+				continue
+			}
+			return lb
+		default:
+			return nil
+		}
+	}
+}
+
+// Ready returns how many launched browsers are waiting to be handed out
+func (p *BrowserPool) Ready() int {
+	return len(p.ready)
+}
+
+// Close shuts down all browsers that haven't been handed out.
+// Browsers already handed out belong to their sessions and keep running.
+func (p *BrowserPool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	p.cancel()
+	p.wg.Wait()
+	for {
+		select {
+		case lb := <-p.ready:
+			lb.close()
+		default:
+			return
+		}
+	}
+}
+
+// close shuts down the browser
+func (lb *launchedBrowser) close() {
+	lb.browserCancel()
+	lb.allocCancel()
+}
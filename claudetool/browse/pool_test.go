@@ -0,0 +1,48 @@
+package browse
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBrowserPoolTakeEmpty(t *testing.T) {
+	p := NewBrowserPool(context.Background(), 0, LaunchOptions{})
+	defer p.Close()
+
+	if lb := p.take(); lb != nil {
+		t.Errorf("take() from empty pool = %v, want nil", lb)
+	}
+}
+
+func TestBrowserPoolSkipsDeadBrowsers(t *testing.T) {
+	p := NewBrowserPool(context.Background(), 0, LaunchOptions{})
+	defer p.Close()
+	p.ready = make(chan *launchedBrowser, 1)
+
+	allocCtx, allocCancel := context.WithCancel(context.Background())
+	browserCtx, browserCancel := context.WithCancel(allocCtx)
+	browserCancel()
+	p.ready <- &launchedBrowser{allocCtx, allocCancel, browserCtx, browserCancel}
+
+	if lb := p.take(); lb != nil {
+		t.Error("take() returned a dead browser")
+	}
+	if allocCtx.Err() == nil {
+		t.Error("Dead browser's allocator was not shut down")
+	}
+}
+
+func TestBrowserPoolClose(t *testing.T) {
+	p := NewBrowserPool(context.Background(), 0, LaunchOptions{})
+	p.Close()
+
+	// Taking after close must not launch replacements
+	if lb := p.take(); lb != nil {
+		t.Error("take() after Close returned a browser")
+	}
+	p.refill()
+	p.wg.Wait()
+	if p.Ready() != 0 {
+		t.Error("refill after Close launched a browser")
+	}
+}
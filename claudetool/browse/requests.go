@@ -0,0 +1,180 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// maxFinishedRequests is how many completed requests are kept for browser_wait_for_request
+const maxFinishedRequests = 200
+
+// finishedRequest is a network request that completed or failed
+type finishedRequest struct {
+	id       network.RequestID
+	URL      string          `json:"url"`
+	Method   string          `json:"method"`
+	Status   int64           `json:"status,omitempty"`
+	MimeType string          `json:"mime_type,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	// taken is set once browser_wait_for_request has returned the request
+	taken bool
+}
+
+// handleRequestWillBeSent starts tracking a request
+func (b *BrowseTools) handleRequestWillBeSent(e *network.EventRequestWillBeSent) {
+	b.requestsMutex.Lock()
+	defer b.requestsMutex.Unlock()
+	// A redirect reuses the request ID; the final URL wins
+	b.pendingRequests[e.RequestID] = &finishedRequest{id: e.RequestID, URL: e.Request.URL, Method: e.Request.Method}
+}
+
+// handleResponseReceived records a tracked request's status
+func (b *BrowseTools) handleResponseReceived(e *network.EventResponseReceived) {
+	b.requestsMutex.Lock()
+	defer b.requestsMutex.Unlock()
+	if r, ok := b.pendingRequests[e.RequestID]; ok {
+		r.Status = e.Response.Status
+		r.MimeType = e.Response.MimeType
+	}
+}
+
+// finishRequest moves a tracked request to the finished list, failed if errText is set
+func (b *BrowseTools) finishRequest(id network.RequestID, errText string) {
+	b.requestsMutex.Lock()
+	defer b.requestsMutex.Unlock()
+	r, ok := b.pendingRequests[id]
+	if !ok {
+		return
+	}
+	delete(b.pendingRequests, id)
+	r.Error = errText
+	b.finishedRequests = append(b.finishedRequests, r)
+	b.finishedCount++
+	if len(b.finishedRequests) > maxFinishedRequests {
+		b.finishedRequests = b.finishedRequests[len(b.finishedRequests)-maxFinishedRequests:]
+	}
+	b.requestsCond.Broadcast()
+}
+
+// takeRequest returns the oldest finished request matching urlPattern and method (if set) that
+// no earlier call returned, waiting until there is one or ctx is done. Requests finished before
+// the call are only considered if includeEarlier is set.
+func (b *BrowseTools) takeRequest(ctx context.Context, urlPattern *regexp.Regexp, method string, includeEarlier bool) *finishedRequest {
+	stop := context.AfterFunc(ctx, func() {
+		b.requestsMutex.Lock()
+		b.requestsCond.Broadcast()
+		b.requestsMutex.Unlock()
+	})
+	defer stop()
+
+	b.requestsMutex.Lock()
+	defer b.requestsMutex.Unlock()
+	// next counts finished requests, so it stays valid as old ones are dropped from the list
+	next := 0
+	if !includeEarlier {
+		next = b.finishedCount
+	}
+	for ctx.Err() == nil {
+		first := b.finishedCount - len(b.finishedRequests)
+		for _, r := range b.finishedRequests[max(next-first, 0):] {
+			if !r.taken && urlPattern.MatchString(r.URL) && (method == "" || strings.EqualFold(r.Method, method)) {
+				r.taken = true
+				return r
+			}
+		}
+		next = b.finishedCount
+		b.requestsCond.Wait()
+	}
+	return nil
+}
+
+// WaitForRequestTool definition
+type waitForRequestInput struct {
+	URLPattern string `json:"url_pattern" description:"Regular expression matched against the request URL, e.g. /api/orders"`
+	Method     string `json:"method,omitempty" description:"HTTP method the request must use, e.g. POST"`
+	Body       bool   `json:"body,omitempty" description:"Include the response body (default: false)"`
+	NewOnly    bool   `json:"new_only,omitempty" description:"Ignore requests that finished before this call (default: false)"`
+	Timeout    string `json:"timeout,omitempty" description:"How long to wait as a Go duration string (default: 15s)"`
+}
+
+// NewWaitForRequestTool creates a tool for waiting on a network request
+func (b *BrowseTools) NewWaitForRequestTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_wait_for_request",
+		Description: `Wait for a network request whose URL matches a pattern to complete, and return its method, status, and optionally its response body,
+e.g. to confirm a button click called the expected API. Requests that finished since the browser started count unless new_only is set,
+so the click may come first; each request is returned at most once.`,
+		InputSchema: llm.SchemaFor[waitForRequestInput](),
+		Run:         b.waitForRequestRun,
+	}
+}
+
+func (b *BrowseTools) waitForRequestRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input waitForRequestInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.URLPattern == "" {
+		return llm.ErrorfToolOut("url_pattern is required")
+	}
+	urlPattern, err := regexp.Compile(input.URLPattern)
+	if err != nil {
+		return llm.ErrorfToolOut("invalid url_pattern: %w", err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeout := parseTimeout(input.Timeout)
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	found := b.takeRequest(waitCtx, urlPattern, input.Method, !input.NewOnly)
+	if found == nil {
+		return llm.ErrorfToolOut("no request matching %s completed within %s", input.URLPattern, timeout)
+	}
+	r := *found
+
+	if input.Body && r.Error == "" {
+		bodyCtx, cancel := context.WithTimeout(browserCtx, 15*time.Second)
+		defer cancel()
+		var body []byte
+		err := chromedp.Run(bodyCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			body, err = network.GetResponseBody(r.id).Do(ctx)
+			return err
+		}))
+		if err != nil {
+			return llm.ErrorfToolOut("failed to get the response body of %s (it is gone once the page navigates): %w", r.URL, err)
+		}
+		switch {
+		case json.Valid(body):
+			r.Body = body
+		case utf8.Valid(body):
+			r.Body, _ = json.Marshal(string(body))
+		default:
+			r.Body, _ = json.Marshal(fmt.Sprintf("(%d bytes of binary data)", len(body)))
+		}
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal request: %w", err)
+	}
+	summary := fmt.Sprintf("%s %s returned %d", r.Method, r.URL, r.Status)
+	if r.Error != "" {
+		summary = fmt.Sprintf("%s %s failed: %s", r.Method, r.URL, r.Error)
+	}
+	return jsonToolOut("request", summary, data)
+}
@@ -0,0 +1,97 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+func TestTakeRequest(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	finish := func(id, method, url string, status int64) {
+		tools.handleRequestWillBeSent(&network.EventRequestWillBeSent{RequestID: network.RequestID(id), Request: &network.Request{URL: url, Method: method}})
+		tools.handleResponseReceived(&network.EventResponseReceived{RequestID: network.RequestID(id), Response: &network.Response{Status: status}})
+		tools.finishRequest(network.RequestID(id), "")
+	}
+	finish("1", "GET", "https://example.com/api/orders", 200)
+	finish("2", "POST", "https://example.com/api/orders", 201)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	orders := regexp.MustCompile(`/api/orders`)
+	if r := tools.takeRequest(ctx, orders, "post", true); r == nil || r.Status != 201 {
+		t.Errorf("takeRequest(POST) = %+v, want the POST", r)
+	}
+	if r := tools.takeRequest(ctx, orders, "", true); r == nil || r.Method != "GET" {
+		t.Errorf("takeRequest() = %+v, want the GET", r)
+	}
+
+	// Both are taken, so only a new request matches
+	go finish("3", "DELETE", "https://example.com/api/orders/7", 204)
+	if r := tools.takeRequest(ctx, orders, "", true); r == nil || r.Method != "DELETE" {
+		t.Errorf("takeRequest() = %+v, want the DELETE", r)
+	}
+
+	// Requests finished before the call are skipped without includeEarlier
+	finish("4", "GET", "https://example.com/api/orders", 200)
+	short, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if r := tools.takeRequest(short, orders, "", false); r != nil {
+		t.Errorf("takeRequest() = %+v, want nil for an earlier request", r)
+	}
+}
+
+func TestWaitForRequest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/orders" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":7}`))
+			return
+		}
+		w.Write([]byte(`<button onclick="fetch('/api/orders', {method: 'POST'})">Order</button>`))
+	}))
+	defer server.Close()
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+	if out := tools.evalRun(ctx, []byte(`{"expression": "document.querySelector('button').click()"}`)); out.Error != nil {
+		t.Fatalf("evalRun error: %v", out.Error)
+	}
+
+	out := tools.waitForRequestRun(ctx, []byte(`{"url_pattern": "/api/orders$", "method": "POST", "body": true}`))
+	if out.Error != nil {
+		t.Fatalf("waitForRequestRun error: %v", out.Error)
+	}
+	if text := out.LLMContent[0].Text; !strings.Contains(text, "returned 201") || !strings.Contains(text, `"body":{"id":7}`) {
+		t.Errorf("Unexpected request: %s", text)
+	}
+}
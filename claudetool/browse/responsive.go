@@ -0,0 +1,132 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/imageutil"
+)
+
+// defaultResponsiveWidths are common phone, tablet, laptop, and desktop breakpoints
+var defaultResponsiveWidths = []int{375, 768, 1280, 1920}
+
+// contactSheetHeight is the height each capture is scaled to on the contact sheet
+const contactSheetHeight = 800
+
+// ResponsiveScreenshotsTool definition
+type responsiveScreenshotsInput struct {
+	Widths   []int  `json:"widths,omitempty" description:"Viewport widths in CSS pixels (default: [375, 768, 1280, 1920])"`
+	Height   int    `json:"height,omitempty" description:"Viewport height in CSS pixels (default: 900)"`
+	FullPage bool   `json:"full_page,omitempty" description:"Capture the whole page rather than the viewport (default: false)"`
+	Timeout  string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 60s)"`
+}
+
+// NewResponsiveScreenshotsTool creates a tool for screenshotting the page at several viewport widths
+func (b *BrowseTools) NewResponsiveScreenshotsTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_responsive_screenshots",
+		Description: `Screenshot the current page at several viewport widths in one call, for reviewing responsive layouts.
+Returns each screenshot and a contact sheet of all of them side by side, then restores the viewport size.`,
+		InputSchema: llm.SchemaFor[responsiveScreenshotsInput](),
+		Run:         b.responsiveScreenshotsRun,
+	}
+}
+
+func (b *BrowseTools) responsiveScreenshotsRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input responsiveScreenshotsInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if len(input.Widths) == 0 {
+		input.Widths = defaultResponsiveWidths
+	}
+	for _, w := range input.Widths {
+		if w <= 0 {
+			return llm.ErrorfToolOut("invalid width %d: widths must be positive", w)
+		}
+	}
+	if input.Height < 0 {
+		return llm.ErrorfToolOut("height must be positive")
+	}
+	if input.Height == 0 {
+		input.Height = 900
+	}
+	timeout := 60 * time.Second
+	if input.Timeout != "" {
+		timeout = parseTimeout(input.Timeout)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, timeout)
+	defer cancel()
+
+	var original struct{ Width, Height int64 }
+	if err := chromedp.Run(timeoutCtx, chromedp.Evaluate(`({Width: innerWidth, Height: innerHeight})`, &original)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	captures := make([][]byte, len(input.Widths))
+	for i, w := range input.Widths {
+		capture := chromedp.CaptureScreenshot(&captures[i])
+		if input.FullPage {
+			capture = chromedp.FullScreenshot(&captures[i], 100)
+		}
+		if err := chromedp.Run(timeoutCtx, chromedp.EmulateViewport(int64(w), int64(input.Height)), capture); err != nil {
+			return llm.ErrorfToolOut("failed to capture at width %d: %w", w, err)
+		}
+	}
+	if err := chromedp.Run(timeoutCtx, chromedp.EmulateViewport(original.Width, original.Height)); err != nil {
+		return llm.ErrorfToolOut("failed to restore the viewport: %w", err)
+	}
+
+	sheet, err := imageutil.ContactSheet(captures, contactSheetHeight)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	optimized, err := imageutil.OptimizePNGs(append(captures, sheet))
+	if err != nil {
+		return llm.ErrorfToolOut("failed to optimize screenshots: %w", err)
+	}
+
+	var lines []string
+	var images []llm.Content
+	display := map[string]any{}
+	for i, data := range optimized {
+		id := b.SaveScreenshot(data, "png")
+		if id == "" {
+			return llm.ErrorfToolOut("failed to save screenshot")
+		}
+		path := GetScreenshotPath(id, "png")
+		if i < len(input.Widths) {
+			lines = append(lines, fmt.Sprintf("%dpx: %s", input.Widths[i], path))
+		} else {
+			lines = append(lines, "contact sheet: "+path)
+			if err := screenshotDisplay(id, "png", data, display); err != nil {
+				return llm.ErrorToolOut(err)
+			}
+		}
+
+		img, err := llm.ImageContent(data)
+		if err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		images = append(images, img)
+	}
+
+	description := fmt.Sprintf("Screenshots at %d widths, then a contact sheet of all of them (saved as):\n%s",
+		len(input.Widths), strings.Join(lines, "\n"))
+	return llm.ToolOut{
+		LLMContent: append([]llm.Content{{Type: llm.ContentTypeText, Text: description}}, images...),
+		Display:    display,
+	}
+}
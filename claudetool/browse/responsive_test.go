@@ -0,0 +1,65 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponsiveScreenshots(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{`{`, `{"widths": [375, 0]}`, `{"height": -1}`} {
+		if out := tools.responsiveScreenshotsRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("responsiveScreenshotsRun(%s) succeeded, want error", input)
+		}
+	}
+
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<h1>Hello</h1>"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+	evalInput, _ := json.Marshal(evalInput{Expression: "innerWidth"})
+	before := tools.evalRun(ctx, evalInput)
+	if before.Error != nil {
+		t.Fatalf("evalRun error: %v", before.Error)
+	}
+
+	out := tools.responsiveScreenshotsRun(ctx, []byte(`{"widths": [375, 1024], "height": 600}`))
+	if out.Error != nil {
+		t.Fatalf("responsiveScreenshotsRun error: %v", out.Error)
+	}
+	// A description, two screenshots, and the contact sheet
+	if len(out.LLMContent) != 4 {
+		t.Fatalf("expected 4 content items, got %d", len(out.LLMContent))
+	}
+	for _, c := range out.LLMContent[1:] {
+		if c.MediaType != "image/png" || c.Data == "" {
+			t.Errorf("expected a PNG image, got %q", c.MediaType)
+		}
+	}
+	if text := out.LLMContent[0].Text; !strings.Contains(text, "375px: ") || !strings.Contains(text, "contact sheet: ") {
+		t.Errorf("unexpected description: %s", text)
+	}
+
+	after := tools.evalRun(ctx, evalInput)
+	if after.Error != nil || after.LLMContent[0].Text != before.LLMContent[0].Text {
+		t.Errorf("viewport width not restored: %s before, %v %v after", before.LLMContent[0].Text, after.LLMContent, after.Error)
+	}
+}
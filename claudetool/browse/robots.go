@@ -0,0 +1,267 @@
+package browse
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotsAgent is the product token looked up in robots.txt; groups for "*" apply otherwise
+const robotsAgent = "shelley"
+
+// robotsUserAgent identifies robots.txt requests, which would otherwise carry Go's default user agent
+const robotsUserAgent = "Mozilla/5.0 (compatible; " + robotsAgent + ")"
+
+// maxRobotsSize is how much of a robots.txt file is read, as RFC 9309 allows parsers to limit
+const maxRobotsSize = 500 << 10
+
+// robotsRule is an allow or disallow line of a robots.txt group
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// robotsRules are the rules of the robots.txt group that applies to robotsAgent
+type robotsRules struct {
+	rules []robotsRule
+	// crawlDelay is the group's non-standard Crawl-delay, if any
+	crawlDelay time.Duration
+	// disallowAll is set when robots.txt could not be fetched because of a server error
+	disallowAll bool
+}
+
+// parseRobots parses robots.txt and returns the rules for agent (lower case), or for "*" if no group names it
+func parseRobots(r io.Reader, agent string) *robotsRules {
+	var own, star *robotsRules
+	var current []*robotsRules
+	inAgents := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if key == "user-agent" {
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			name := strings.ToLower(value)
+			switch {
+			case name == "*":
+				if star == nil {
+					star = &robotsRules{}
+				}
+				current = append(current, star)
+			case name != "" && strings.Contains(agent, name):
+				if own == nil {
+					own = &robotsRules{}
+				}
+				current = append(current, own)
+			}
+			continue
+		}
+		inAgents = false
+		for _, g := range current {
+			switch key {
+			case "allow", "disallow":
+				// An empty disallow allows everything, like no rule at all
+				if value != "" {
+					g.rules = append(g.rules, robotsRule{allow: key == "allow", pattern: value})
+				}
+			case "crawl-delay":
+				if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+					g.crawlDelay = time.Duration(secs * float64(time.Second))
+				}
+			}
+		}
+	}
+	switch {
+	case own != nil:
+		return own
+	case star != nil:
+		return star
+	}
+	return &robotsRules{}
+}
+
+// matchRobotsPattern reports whether path matches a robots.txt path pattern,
+// where * matches any characters and a trailing $ anchors the end
+func matchRobotsPattern(pattern, path string) bool {
+	expr := strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSuffix(pattern, "$")), `\*`, ".*")
+	if strings.HasSuffix(pattern, "$") {
+		expr += "$"
+	}
+	matched, _ := regexp.MatchString("^"+expr, path)
+	return matched
+}
+
+// allowed reports whether path (with its query) may be fetched, and the deciding rule.
+// The longest matching pattern wins, and allow wins ties.
+func (r *robotsRules) allowed(path string) (bool, *robotsRule) {
+	if r.disallowAll {
+		return false, nil
+	}
+	var best *robotsRule
+	for i, rule := range r.rules {
+		if !matchRobotsPattern(rule.pattern, path) {
+			continue
+		}
+		if best == nil || len(rule.pattern) > len(best.pattern) || len(rule.pattern) == len(best.pattern) && rule.allow {
+			best = &r.rules[i]
+		}
+	}
+	return best == nil || best.allow, best
+}
+
+// politeness enforces robots.txt and a minimum delay between page loads on each host
+type politeness struct {
+	minDelay time.Duration
+	client   *http.Client
+
+	mu       sync.Mutex
+	robots   map[string]*robotsRules // keyed by origin
+	nextLoad map[string]time.Time    // keyed by origin
+}
+
+func newPoliteness(minDelay time.Duration) *politeness {
+	return &politeness{
+		minDelay: minDelay,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		robots:   make(map[string]*robotsRules),
+		nextLoad: make(map[string]time.Time),
+	}
+}
+
+// rules returns origin's robots.txt rules, fetching them on first use.
+// policy, if not nil, must allow robots.txt and every redirect it takes.
+func (p *politeness) rules(ctx context.Context, origin string, policy *NavigationPolicy) (*robotsRules, error) {
+	p.mu.Lock()
+	rules, ok := p.robots[origin]
+	p.mu.Unlock()
+	if ok {
+		return rules, nil
+	}
+
+	robotsURL := origin + "/robots.txt"
+	client := *p.client
+	if policy != nil {
+		if err := policy.Check(ctx, robotsURL); err != nil {
+			return nil, err
+		}
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return policy.Check(req.Context(), req.URL.String())
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", robotsUserAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch robots.txt for %s: %w", origin, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 500:
+		// RFC 9309: an unreachable robots.txt means nothing may be crawled
+		rules = &robotsRules{disallowAll: true}
+	case resp.StatusCode >= 400:
+		rules = &robotsRules{}
+	default:
+		rules = parseRobots(io.LimitReader(resp.Body, maxRobotsSize), robotsAgent)
+	}
+
+	p.mu.Lock()
+	p.robots[origin] = rules
+	p.mu.Unlock()
+	return rules, nil
+}
+
+// wait returns an error if robots.txt disallows rawURL, and otherwise blocks until its host may be loaded again.
+// robots.txt is fetched subject to policy, which may be nil.
+func (p *politeness) wait(ctx context.Context, rawURL string, policy *NavigationPolicy) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil
+	}
+	origin := u.Scheme + "://" + u.Host
+	rules, err := p.rules(ctx, origin, policy)
+	if err != nil {
+		return err
+	}
+	if ok, rule := rules.allowed(u.RequestURI()); !ok {
+		if rule == nil {
+			return fmt.Errorf("navigation to %s blocked: robots.txt could not be fetched", rawURL)
+		}
+		// The rule itself stays out of the error, as robots.txt is untrusted page content
+		return fmt.Errorf("navigation to %s blocked by robots.txt", rawURL)
+	}
+
+	// Reserve the next slot for this host, then wait for it
+	p.mu.Lock()
+	now := time.Now()
+	at := now
+	if next := p.nextLoad[origin]; next.After(now) {
+		at = next
+	}
+	p.nextLoad[origin] = at.Add(max(p.minDelay, rules.crawlDelay))
+	p.mu.Unlock()
+	if !at.After(now) {
+		return nil
+	}
+	t := time.NewTimer(at.Sub(now))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetPolite makes browser_navigate and browser_crawl honor robots.txt and wait at least
+// minDelay (or the site's Crawl-delay, if longer) between page loads on the same host.
+func (b *BrowseTools) SetPolite(enabled bool, minDelay time.Duration) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.polite = nil
+	if enabled {
+		b.polite = newPoliteness(minDelay)
+	}
+}
+
+// allowNavigation returns an error if the navigation policy or, when polite, robots.txt
+// disallows loading rawURL, waiting out the host's delay if needed
+func (b *BrowseTools) allowNavigation(ctx context.Context, rawURL string) error {
+	b.mux.Lock()
+	policy, polite := b.policy, b.polite
+	b.mux.Unlock()
+	if policy != nil {
+		if err := policy.Check(ctx, rawURL); err != nil {
+			return err
+		}
+	}
+	if polite != nil {
+		return polite.wait(ctx, rawURL, policy)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRobots(t *testing.T) {
+	robots := `# comment
+User-agent: Googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /private/
+Allow: /private/ok
+Disallow: /*.pdf$
+Crawl-delay: 2
+
+User-agent: Shelley
+User-agent: OtherBot
+Disallow: /no-shelley
+`
+	rules := parseRobots(strings.NewReader(robots), robotsAgent)
+	if ok, _ := rules.allowed("/no-shelley/page"); ok {
+		t.Error("expected the shelley group to apply")
+	}
+	if ok, _ := rules.allowed("/private/x"); !ok {
+		t.Error("expected the * group not to apply when a group names shelley")
+	}
+
+	rules = parseRobots(strings.NewReader(robots), "otheragent")
+	if rules.crawlDelay != 2*time.Second {
+		t.Errorf("crawlDelay = %v, want 2s", rules.crawlDelay)
+	}
+	for path, want := range map[string]bool{
+		"/":                true,
+		"/private/":        false,
+		"/private/secret":  false,
+		"/private/ok":      true,
+		"/private/ok/more": true,
+		"/docs/a.pdf":      false,
+		"/docs/a.pdf?x=1":  true,
+		"/public?q=1":      true,
+	} {
+		if ok, _ := rules.allowed(path); ok != want {
+			t.Errorf("allowed(%q) = %v, want %v", path, ok, want)
+		}
+	}
+}
+
+func TestPolitenessWait(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != robotsUserAgent {
+			t.Errorf("robots.txt fetched with User-Agent %q, want %q", ua, robotsUserAgent)
+		}
+		w.Write([]byte("User-agent: *\nDisallow: /admin\n"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	ctx := context.Background()
+	p := newPoliteness(50 * time.Millisecond)
+	if err := p.wait(ctx, server.URL+"/admin/users", nil); err == nil || !strings.Contains(err.Error(), "blocked by robots.txt") {
+		t.Errorf("expected /admin to be disallowed, got %v", err)
+	} else if strings.Contains(err.Error(), "/admin\n") || strings.Contains(err.Error(), "Disallow") {
+		t.Errorf("error leaks the robots.txt rule: %v", err)
+	}
+	if err := p.wait(ctx, down.URL+"/", nil); err == nil || !strings.Contains(err.Error(), "could not be fetched") {
+		t.Errorf("expected a server error to disallow everything, got %v", err)
+	}
+	if err := p.wait(ctx, missing.URL+"/anything", nil); err != nil {
+		t.Errorf("expected a missing robots.txt to allow everything, got %v", err)
+	}
+
+	start := time.Now()
+	for range 3 {
+		if err := p.wait(ctx, server.URL+"/page", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("three loads of one host took %v, want at least two delays", elapsed)
+	}
+}
+
+func TestPolitenessRobotsPolicy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("robots.txt redirect followed to a denied host: %s", r.URL)
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, strings.Replace(target.URL, "127.0.0.1", "localhost", 1)+"/robots.txt", http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	policy := &NavigationPolicy{Deny: []string{"localhost"}}
+	p := newPoliteness(0)
+	err := p.wait(context.Background(), redirect.URL+"/page", policy)
+	if err == nil || !strings.Contains(err.Error(), "host localhost is denied") {
+		t.Errorf("expected the robots.txt redirect to be blocked, got %v", err)
+	}
+	if err := p.wait(context.Background(), strings.Replace(target.URL, "127.0.0.1", "localhost", 1)+"/page", policy); err == nil {
+		t.Error("expected robots.txt on a denied host not to be fetched")
+	}
+}
+
+func TestNavigateRobots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: *\nDisallow: /\n"))
+	}))
+	defer server.Close()
+
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	tools.SetPolite(true, 0)
+
+	// Blocked before the browser starts
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL + "/page"})
+	out := tools.navigateRun(context.Background(), navInput)
+	if out.Error == nil || !strings.Contains(out.Error.Error(), "blocked by robots.txt") {
+		t.Errorf("expected navigation to be blocked by robots.txt, got %v", out.Error)
+	}
+}
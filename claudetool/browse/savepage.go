@@ -0,0 +1,66 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// SavePageTool definition
+type savePageInput struct {
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 30s)"`
+}
+
+// NewSavePageTool creates a tool for archiving the current page as MHTML
+func (b *BrowseTools) NewSavePageTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_save_page",
+		Description: `Save the complete current page (DOM as rendered, styles, images, and same-origin iframes) as a single MHTML archive
+next to the screenshots, to keep a record of exactly what was seen. Chrome can open the file later.`,
+		InputSchema: llm.SchemaFor[savePageInput](),
+		Run:         b.savePageRun,
+	}
+}
+
+func (b *BrowseTools) savePageRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input savePageInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	// Pages with many resources take a while to serialize
+	if input.Timeout == "" {
+		input.Timeout = "30s"
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var data, pageURL string
+	err = chromedp.Run(timeoutCtx,
+		chromedp.Location(&pageURL),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			data, err = page.CaptureSnapshot().WithFormat(page.CaptureSnapshotFormatMhtml).Do(ctx)
+			return err
+		}),
+	)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to capture page: %w", err)
+	}
+
+	id := b.SaveScreenshot([]byte(data), "mhtml")
+	if id == "" {
+		return llm.ErrorfToolOut("failed to save page archive")
+	}
+	path := GetScreenshotPath(id, "mhtml")
+	return llm.ToolOut{LLMContent: llm.TextContent(fmt.Sprintf("Saved %s (%d bytes) as %s", pageURL, len(data), path))}
+}
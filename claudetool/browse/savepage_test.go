@@ -0,0 +1,51 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSavePage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	if out := tools.savePageRun(ctx, []byte(`{`)); out.Error == nil {
+		t.Error("savePageRun with invalid input succeeded, want error")
+	}
+
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<p>archived content</p>"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	toolOut = tools.savePageRun(ctx, []byte(`{}`))
+	if toolOut.Error != nil {
+		t.Fatalf("savePageRun error: %v", toolOut.Error)
+	}
+	path := regexp.MustCompile(`\S+\.mhtml`).FindString(toolOut.LLMContent[0].Text)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading archive from %q: %v", toolOut.LLMContent[0].Text, err)
+	}
+	if !strings.Contains(string(data), "archived content") {
+		t.Errorf("Archive does not contain the page content")
+	}
+}
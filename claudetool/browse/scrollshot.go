@@ -0,0 +1,193 @@
+package browse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/imageutil"
+)
+
+// scrollToSettleJS centers the element in the viewport, then resolves once its position has held still
+// for a few frames (or after about a second, e.g. for a carousel that never stops), outlining it if asked.
+// It returns the element's position in the viewport.
+const scrollToSettleJS = `function(outline) {
+	this.scrollIntoView({block: 'center', inline: 'center', behavior: 'instant'});
+	const win = this.ownerDocument.defaultView;
+	return new Promise(resolve => {
+		let last = '', still = 0, frames = 0;
+		const check = () => {
+			const r = this.getBoundingClientRect();
+			const now = [r.left, r.top, r.width, r.height].join();
+			still = now === last ? still + 1 : 0;
+			last = now;
+			if (still < 3 && ++frames < 60) {
+				win.requestAnimationFrame(check);
+				return;
+			}
+			if (outline) {
+				const o = this.ownerDocument.createElement('div');
+				o.setAttribute('` + highlightAttr + `', '');
+				o.style.cssText = 'position:absolute;box-sizing:border-box;pointer-events:none;z-index:2147483647;' +
+					'border:3px solid #ff0040;border-radius:2px;' +
+					'left:' + (r.left + win.scrollX - 3) + 'px;top:' + (r.top + win.scrollY - 3) + 'px;' +
+					'width:' + (r.width + 6) + 'px;height:' + (r.height + 6) + 'px';
+				this.ownerDocument.documentElement.appendChild(o);
+			}
+			resolve({x: Math.round(r.left), y: Math.round(r.top), width: Math.round(r.width), height: Math.round(r.height)});
+		};
+		win.requestAnimationFrame(check);
+	});
+}`
+
+// unoutlineJS removes the outline added by scrollToSettleJS
+const unoutlineJS = `function() {
+	for (const o of this.ownerDocument.querySelectorAll('[` + highlightAttr + `]')) o.remove();
+}`
+
+// scrollStepJS scrolls the page to y and resolves after the next frame has rendered
+const scrollStepJS = `function(y) {
+	scrollTo(scrollX, y);
+	return new Promise(resolve => requestAnimationFrame(() => requestAnimationFrame(() =>
+		resolve({y: scrollY, height: innerHeight, scrollHeight: document.documentElement.scrollHeight}))));
+}`
+
+// maxStitchCaptures bounds the viewports scrollAndStitch captures, e.g. on an infinitely scrolling page
+const maxStitchCaptures = 30
+
+// scrollAndStitch captures the page a viewport at a time from the top, so content that loads or lays out
+// as it is scrolled into view is shown, and stitches the captures into one PNG.
+// It returns the PNG and the viewport height in image pixels, and restores the scroll position.
+func scrollAndStitch(ctx context.Context) ([]byte, int, error) {
+	var start struct{ Y float64 }
+	if err := chromedp.Evaluate(`({y: scrollY})`, &start).Do(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer callWithArgs(scrollStepJS, []any{start.Y}, true, nil).Do(ctx)
+
+	var captures [][]byte
+	var step struct{ Y, Height, ScrollHeight float64 }
+	for y := 0.0; len(captures) < maxStitchCaptures; y = step.Y + step.Height {
+		last := step.Y
+		if err := callWithArgs(scrollStepJS, []any{y}, true, &step).Do(ctx); err != nil {
+			return nil, 0, fmt.Errorf("failed to scroll to %.0f: %w", y, err)
+		}
+		if len(captures) > 0 && step.Y <= last {
+			// The page didn't scroll any further
+			break
+		}
+		buf, err := page.CaptureScreenshot().WithFormat(page.CaptureScreenshotFormatPng).WithFromSurface(true).Do(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		captures = append(captures, buf)
+		if step.Y+step.Height >= step.ScrollHeight {
+			break
+		}
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(captures[0]))
+	if err != nil {
+		return nil, 0, err
+	}
+	buf, err := imageutil.StitchVertical(captures)
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf, config.Height, nil
+}
+
+// ScrollToScreenshotTool definition
+type scrollToScreenshotInput struct {
+	Selector string `json:"selector" description:"Element to scroll to, as a CSS selector or XPath expression (see selector_type)"`
+	selectorTypeInput
+	frameInput
+	Outline bool   `json:"outline,omitempty" description:"Outline the element in the screenshot (default: false)"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewScrollToScreenshotTool creates a tool for scrolling an element into view and screenshotting it in context
+func (b *BrowseTools) NewScrollToScreenshotTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_scroll_to_screenshot",
+		Description: `Scroll an element to the center of the viewport, wait for the layout to settle, and screenshot the viewport,
+showing the element with its surroundings. Leaves the page scrolled to the element.`,
+		InputSchema: llm.SchemaFor[scrollToScreenshotInput](),
+		Run:         b.scrollToScreenshotRun,
+	}
+}
+
+func (b *BrowseTools) scrollToScreenshotRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input scrollToScreenshotInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Selector == "" {
+		return llm.ErrorfToolOut("selector is required")
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var iframe *cdp.Node
+	if input.Frame != "" {
+		if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+	queryOpts, err := selectorQuery(input.Selector, input.SelectorType, iframe)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	var box struct{ X, Y, Width, Height int }
+	var buf []byte
+	err = chromedp.Run(timeoutCtx,
+		chromedp.WaitReady(input.Selector, queryOpts...),
+		chromedp.QueryAfter(input.Selector, func(ctx context.Context, _ runtime.ExecutionContextID, nodes ...*cdp.Node) error {
+			if len(nodes) < 1 {
+				return fmt.Errorf("selector %q did not return any nodes", input.Selector)
+			}
+			obj, err := dom.ResolveNode().WithNodeID(nodes[0].NodeID).Do(ctx)
+			if err != nil {
+				return err
+			}
+			defer runtime.ReleaseObject(obj.ObjectID).Do(ctx)
+			err = chromedp.CallFunctionOn(scrollToSettleJS, &box, func(p *runtime.CallFunctionOnParams) *runtime.CallFunctionOnParams {
+				return p.WithObjectID(obj.ObjectID).WithAwaitPromise(true)
+			}, input.Outline).Do(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to scroll to %q: %w", input.Selector, err)
+			}
+			if input.Outline {
+				defer callFunctionOnNode(ctx, nodes[0], unoutlineJS, nil)
+			}
+			buf, err = page.CaptureScreenshot().WithFormat(page.CaptureScreenshotFormatPng).WithFromSurface(true).Do(ctx)
+			return err
+		}, queryOpts...),
+	)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	out := b.screenshotToolOut(buf, "png", 0, "", map[string]any{
+		"selector": input.Selector,
+		"frame":    input.Frame,
+	})
+	if out.Error == nil {
+		out.LLMContent[0].Text += fmt.Sprintf("\nThe element is %dx%d at (%d, %d) in the viewport", box.Width, box.Height, box.X, box.Y)
+	}
+	return out
+}
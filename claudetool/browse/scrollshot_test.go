@@ -0,0 +1,61 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScrollToScreenshotRunErrorPaths(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{
+		`{`,
+		`{}`,
+	} {
+		if out := tools.scrollToScreenshotRun(context.Background(), []byte(input)); out.Error == nil {
+			t.Errorf("Expected an error for %s", input)
+		}
+	}
+}
+
+func TestScrollToScreenshot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: `data:text/html,<div style="height:5000px"></div><button id="far">Far</button><div style="height:5000px"></div>`})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.scrollToScreenshotRun(ctx, []byte(`{"selector": "#far", "outline": true}`))
+	if out.Error != nil {
+		t.Fatalf("scrollToScreenshotRun error: %v", out.Error)
+	}
+	if len(out.LLMContent) != 2 || out.LLMContent[1].MediaType != "image/png" {
+		t.Fatalf("Expected a description and a png, got %+v", out.LLMContent)
+	}
+
+	evalInput := []byte(`{"expression": "[scrollY > 4000, document.querySelectorAll('[data-shelley-highlight]').length]"}`)
+	if text := tools.evalRun(ctx, evalInput).LLMContent[0].Text; !strings.Contains(text, "[true,0]") {
+		t.Errorf("Expected the page scrolled to the button with the outline removed, got %s", text)
+	}
+}
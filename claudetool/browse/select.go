@@ -0,0 +1,202 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// chooseOptionsJS selects options of a <select> (this) and fires input and change events.
+// by is "value", "label", "index", or "" to match either value or label.
+const chooseOptionsJS = `function(by, wanted) {
+	if (this.tagName !== 'SELECT') throw new Error('element is not a select');
+	if (wanted.length > 1 && !this.multiple) throw new Error('cannot select multiple options in a single select');
+	const options = Array.from(this.options);
+	const matches = wanted.map(w => {
+		let o;
+		if (by === 'index') o = options[w];
+		if (by === 'value' || by === '') o = options.find(o => o.value === w);
+		if (!o && (by === 'label' || by === '')) o = options.find(o => o.label.trim() === w);
+		if (!o) throw new Error('no option with ' + (by || 'value or label') + ' ' + JSON.stringify(w) +
+			'; options: ' + JSON.stringify(options.map(o => ({value: o.value, label: o.label}))));
+		return o;
+	});
+	this.focus();
+	options.forEach(o => { o.selected = matches.includes(o); });
+	this.dispatchEvent(new Event('input', {bubbles: true}));
+	this.dispatchEvent(new Event('change', {bubbles: true}));
+	return matches.map(o => ({value: o.value, label: o.label, selected: true}));
+}`
+
+// chooseOptions runs chooseOptionsJS on node and returns the selected options
+func chooseOptions(ctx context.Context, node *cdp.Node, by string, wanted []any) ([]fieldOption, error) {
+	var selected []fieldOption
+	if err := callFunctionOnNode(ctx, node, chooseOptionsJS, &selected, by, wanted); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// SelectTool definition
+type selectInput struct {
+	Selector string `json:"selector" description:"The <select> or custom dropdown trigger, as a CSS selector or XPath expression (see selector_type)"`
+	selectorTypeInput
+	frameInput
+	Value          any    `json:"value,omitempty" description:"Option value to choose, or an array of values for multi-selects"`
+	Label          any    `json:"label,omitempty" description:"Visible option text to choose, or an array of them for multi-selects"`
+	Index          any    `json:"index,omitempty" description:"Zero-based option index to choose, or an array of indexes for multi-selects"`
+	OptionSelector string `json:"option_selector,omitempty" description:"For custom dropdowns: the option to click after opening, instead of matching value/label/index (same selector syntax)"`
+	Timeout        string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewSelectTool creates a tool for choosing options in dropdowns
+func (b *BrowseTools) NewSelectTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_select",
+		Description: `Choose options in a dropdown by value, label, or index.
+For a <select>, the options are selected directly and input/change events fire.
+For a custom dropdown, the element is clicked to open it, then the matching [role=option] element
+(or option_selector, if given) is clicked.`,
+		InputSchema: llm.SchemaFor[selectInput](),
+		Run:         b.selectRun,
+	}
+}
+
+// criterion returns how options should be matched and the wanted values
+func (in *selectInput) criterion() (string, []any, error) {
+	var by string
+	var value any
+	for name, v := range map[string]any{"value": in.Value, "label": in.Label, "index": in.Index} {
+		if v == nil {
+			continue
+		}
+		if by != "" {
+			return "", nil, fmt.Errorf("only one of value, label, or index may be given")
+		}
+		by, value = name, v
+	}
+	if by == "" {
+		if in.OptionSelector == "" {
+			return "", nil, fmt.Errorf("one of value, label, index, or option_selector is required")
+		}
+		return "", nil, nil
+	}
+
+	wanted, ok := value.([]any)
+	if !ok {
+		wanted = []any{value}
+	}
+	if len(wanted) == 0 {
+		return "", nil, fmt.Errorf("%s must not be empty", by)
+	}
+	for i, w := range wanted {
+		if by == "index" {
+			n, ok := w.(float64)
+			if !ok || n < 0 || n != float64(int(n)) {
+				return "", nil, fmt.Errorf("index must be a non-negative integer, got %v", w)
+			}
+			wanted[i] = int(n)
+		} else if _, ok := w.(string); !ok {
+			return "", nil, fmt.Errorf("%s must be a string, got %T", by, w)
+		}
+	}
+	return by, wanted, nil
+}
+
+func (b *BrowseTools) selectRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input selectInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Selector == "" {
+		return llm.ErrorfToolOut("selector is required")
+	}
+	by, wanted, err := input.criterion()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var iframe *cdp.Node
+	if input.Frame != "" {
+		if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+
+	var msg string
+	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		node, err := queryNode(ctx, input.Selector, input.SelectorType, iframe)
+		if err != nil {
+			return err
+		}
+
+		if strings.EqualFold(node.NodeName, "select") {
+			if input.OptionSelector != "" {
+				return fmt.Errorf("option_selector is only for custom dropdowns; use value, label, or index for a <select>")
+			}
+			selected, err := chooseOptions(ctx, node, by, wanted)
+			if err != nil {
+				return err
+			}
+			labels := make([]string, len(selected))
+			for i, o := range selected {
+				labels[i] = fmt.Sprintf("%q (value %q)", o.Label, o.Value)
+			}
+			msg = "Selected " + strings.Join(labels, ", ")
+			return nil
+		}
+
+		// Custom dropdown: open it, then click the option
+		if len(wanted) > 1 {
+			return fmt.Errorf("custom dropdowns support choosing only one option")
+		}
+		if err := chromedp.MouseClickNode(node).Do(ctx); err != nil {
+			return fmt.Errorf("failed to open dropdown: %w", err)
+		}
+		optionSel, optionType := input.OptionSelector, ""
+		if optionSel == "" {
+			optionSel, optionType = customOptionXPath(by, wanted[0]), selectorTypeXPath
+		}
+		option, err := queryNode(ctx, optionSel, optionType, iframe)
+		if err != nil {
+			return fmt.Errorf("failed to find option: %w", err)
+		}
+		if err := chromedp.MouseClickNode(option).Do(ctx); err != nil {
+			return fmt.Errorf("failed to click option: %w", err)
+		}
+		msg = "Opened the custom dropdown and clicked the option"
+		return nil
+	}))
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	return b.actionToolOut(msg)
+}
+
+// customOptionXPath returns an XPath matching a visible-text, value, or position option
+// in an ARIA listbox or similar custom dropdown
+func customOptionXPath(by string, want any) string {
+	const options = `//*[@role='option' or @role='menuitemradio' or @role='menuitem']`
+	switch by {
+	case "index":
+		return fmt.Sprintf("(%s)[%d]", options, want.(int)+1)
+	case "value":
+		v := xpathString(want.(string))
+		return fmt.Sprintf("%s[@data-value=%s or @value=%s]", options, v, v)
+	default:
+		return fmt.Sprintf("%s[normalize-space(.)=%s]", options, xpathString(strings.TrimSpace(want.(string))))
+	}
+}
@@ -0,0 +1,121 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelectCriterion(t *testing.T) {
+	tests := []struct {
+		input   string
+		by      string
+		wanted  []any
+		wantErr string
+	}{
+		{`{"value": "a"}`, "value", []any{"a"}, ""},
+		{`{"label": ["A", "B"]}`, "label", []any{"A", "B"}, ""},
+		{`{"index": 2}`, "index", []any{2}, ""},
+		{`{"index": [0, 1]}`, "index", []any{0, 1}, ""},
+		{`{"option_selector": "li.x"}`, "", nil, ""},
+		{`{}`, "", nil, "is required"},
+		{`{"value": "a", "label": "A"}`, "", nil, "only one of"},
+		{`{"index": -1}`, "", nil, "non-negative integer"},
+		{`{"index": 1.5}`, "", nil, "non-negative integer"},
+		{`{"value": 3}`, "", nil, "must be a string"},
+		{`{"label": []}`, "", nil, "must not be empty"},
+	}
+	for _, tt := range tests {
+		var in selectInput
+		if err := json.Unmarshal([]byte(tt.input), &in); err != nil {
+			t.Fatal(err)
+		}
+		by, wanted, err := in.criterion()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("criterion(%s) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || by != tt.by || !slices.Equal(wanted, tt.wanted) {
+			t.Errorf("criterion(%s) = %q, %v, %v; want %q, %v", tt.input, by, wanted, err, tt.by, tt.wanted)
+		}
+	}
+}
+
+func TestCustomOptionXPath(t *testing.T) {
+	if got := customOptionXPath("index", 0); !strings.HasSuffix(got, ")[1]") {
+		t.Errorf("index XPath = %s", got)
+	}
+	if got := customOptionXPath("value", "it's"); !strings.Contains(got, `@data-value="it's"`) {
+		t.Errorf("value XPath = %s", got)
+	}
+	if got := customOptionXPath("label", " Blue "); !strings.Contains(got, "normalize-space(.)='Blue'") {
+		t.Errorf("label XPath = %s", got)
+	}
+}
+
+func TestSelectRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{`{`, `{"value": "a"}`, `{"selector": "select"}`} {
+		if out := tools.selectRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("selectRun(%s) succeeded, want error", input)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	html := `<select id="s" onchange="document.title=this.value"><option value="r">Red</option><option value="b">Blue</option></select>` +
+		`<div id="dd" onclick="document.getElementById('lb').hidden=false">Pick</div>` +
+		`<ul id="lb" role="listbox" hidden><li role="option" onclick="document.title='custom:'+this.textContent">Green</li></ul>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.NewNavigateTool().Run(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	title := func() string {
+		out := tools.evalRun(ctx, []byte(`{"expression": "document.title"}`))
+		if out.Error != nil {
+			t.Fatalf("eval error: %v", out.Error)
+		}
+		return out.LLMContent[0].Text
+	}
+
+	if out := tools.selectRun(ctx, []byte(`{"selector": "#s", "label": "Blue"}`)); out.Error != nil {
+		t.Fatalf("select error: %v", out.Error)
+	}
+	if got := title(); !strings.Contains(got, `"b"`) {
+		t.Errorf("change event not fired, title = %s", got)
+	}
+
+	if out := tools.selectRun(ctx, []byte(`{"selector": "#dd", "label": "Green"}`)); out.Error != nil {
+		t.Fatalf("custom select error: %v", out.Error)
+	}
+	if got := title(); !strings.Contains(got, "custom:Green") {
+		t.Errorf("custom option not clicked, title = %s", got)
+	}
+}
@@ -0,0 +1,166 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// shadowPierce separates the steps of a selector that descends into shadow roots
+const shadowPierce = ">>>"
+
+// selectorTypeInput is the selector_type parameter that accompanies a selector, embedded in the inputs of tools that locate elements.
+// Its description documents the selector syntax, which the selector parameters refer to.
+type selectorTypeInput struct {
+	SelectorType string `json:"selector_type,omitempty" enum:"css,xpath" description:"How to interpret the selector: css, where '>>>' steps into an element's shadow root (e.g. 'my-app >>> button.submit'), or xpath (e.g. //button[contains(., 'Submit')]). Default: xpath if the selector starts with '/', './', or '(', otherwise css"`
+}
+
+// Selector types
+const (
+	selectorTypeCSS   = "css"
+	selectorTypeXPath = "xpath"
+)
+
+// resolveSelectorType returns the effective type of sel, auto-detecting XPath when selectorType is empty
+func resolveSelectorType(sel, selectorType string) (string, error) {
+	switch selectorType {
+	case selectorTypeCSS, selectorTypeXPath:
+		return selectorType, nil
+	case "":
+		if strings.HasPrefix(sel, "/") || strings.HasPrefix(sel, "./") || strings.HasPrefix(sel, "(") {
+			return selectorTypeXPath, nil
+		}
+		return selectorTypeCSS, nil
+	default:
+		return "", fmt.Errorf("unsupported selector_type %q: must be css or xpath", selectorType)
+	}
+}
+
+// selectorQuery returns the query options that locate sel, within iframe if it is non-nil.
+// selectorType is "css", "xpath", or empty to auto-detect.
+func selectorQuery(sel, selectorType string, iframe *cdp.Node) ([]chromedp.QueryOption, error) {
+	typ, err := resolveSelectorType(sel, selectorType)
+	if err != nil {
+		return nil, err
+	}
+	var opts []chromedp.QueryOption
+	if typ == selectorTypeXPath {
+		opts = append(opts, chromedp.ByFunc(byXPath(sel)))
+	} else if steps := splitShadowPath(sel); len(steps) > 1 {
+		opts = append(opts, chromedp.ByFunc(byShadowPath(steps)))
+	} else {
+		opts = append(opts, chromedp.ByQuery)
+	}
+	if iframe != nil {
+		opts = append(opts, chromedp.FromNode(iframe))
+	}
+	return opts, nil
+}
+
+// splitShadowPath splits a ">>>" selector into its trimmed CSS steps
+func splitShadowPath(sel string) []string {
+	steps := strings.Split(sel, shadowPierce)
+	for i := range steps {
+		steps[i] = strings.TrimSpace(steps[i])
+	}
+	return steps
+}
+
+// byShadowPath matches the first step from the query root, then each following
+// step inside the shadow root of the previous match.
+// It returns no nodes (so the query keeps waiting) until every step matches.
+func byShadowPath(steps []string) func(context.Context, *cdp.Node) ([]cdp.NodeID, error) {
+	return func(ctx context.Context, n *cdp.Node) ([]cdp.NodeID, error) {
+		id := n.NodeID
+		for i, step := range steps {
+			if i > 0 {
+				host, err := dom.DescribeNode().WithNodeID(id).WithPierce(true).Do(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if len(host.ShadowRoots) == 0 {
+					return []cdp.NodeID{}, nil
+				}
+				ids, err := dom.PushNodesByBackendIDsToFrontend([]cdp.BackendNodeID{host.ShadowRoots[0].BackendNodeID}).Do(ctx)
+				if err != nil {
+					return nil, err
+				}
+				id = ids[0]
+			}
+			var err error
+			id, err = dom.QuerySelector(id, step).Do(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if id == cdp.EmptyNodeID {
+				return []cdp.NodeID{}, nil
+			}
+		}
+		return []cdp.NodeID{id}, nil
+	}
+}
+
+// byXPath matches the first node selected by an XPath expression evaluated against the query root.
+// Unlike chromedp.BySearch, it respects chromedp.FromNode, so it works inside iframes.
+func byXPath(expr string) func(context.Context, *cdp.Node) ([]cdp.NodeID, error) {
+	return func(ctx context.Context, n *cdp.Node) ([]cdp.NodeID, error) {
+		var match *runtime.RemoteObject
+		err := callFunctionOnNode(ctx, n, `function(expr) {
+			const doc = this.ownerDocument || this;
+			return doc.evaluate(expr, this, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue;
+		}`, &match, expr)
+		if err != nil {
+			return nil, err
+		}
+		if match.ObjectID == "" {
+			return []cdp.NodeID{}, nil
+		}
+		defer runtime.ReleaseObject(match.ObjectID).Do(ctx)
+
+		id, err := dom.RequestNode(match.ObjectID).Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if id == cdp.EmptyNodeID {
+			return []cdp.NodeID{}, nil
+		}
+		return []cdp.NodeID{id}, nil
+	}
+}
+
+// callFunctionOnNode calls a JavaScript function with node as this, unmarshaling the result into res
+func callFunctionOnNode(ctx context.Context, node *cdp.Node, fn string, res any, args ...any) error {
+	obj, err := dom.ResolveNode().WithNodeID(node.NodeID).Do(ctx)
+	if err != nil {
+		return err
+	}
+	defer runtime.ReleaseObject(obj.ObjectID).Do(ctx)
+	return chromedp.CallFunctionOn(fn, res, func(p *runtime.CallFunctionOnParams) *runtime.CallFunctionOnParams {
+		return p.WithObjectID(obj.ObjectID)
+	}, args...).Do(ctx)
+}
+
+// cssString quotes s as a CSS string literal, e.g. for attribute selectors
+func cssString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// xpathString quotes s as an XPath string literal, using concat() if it contains both quote characters
+func xpathString(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+	parts := strings.Split(s, "'")
+	for i := range parts {
+		parts[i] = "'" + parts[i] + "'"
+	}
+	return "concat(" + strings.Join(parts, `, "'", `) + ")"
+}
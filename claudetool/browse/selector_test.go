@@ -0,0 +1,90 @@
+package browse
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitShadowPath(t *testing.T) {
+	tests := []struct {
+		sel  string
+		want []string
+	}{
+		{"button.submit", []string{"button.submit"}},
+		{"my-app >>> button", []string{"my-app", "button"}},
+		{"my-app>>>settings-panel >>> input[name=q]", []string{"my-app", "settings-panel", "input[name=q]"}},
+	}
+	for _, tt := range tests {
+		if got := splitShadowPath(tt.sel); !slices.Equal(got, tt.want) {
+			t.Errorf("splitShadowPath(%q) = %q, want %q", tt.sel, got, tt.want)
+		}
+	}
+}
+
+func TestSelectorQuery(t *testing.T) {
+	for _, sel := range []string{"div", "my-app >>> div", "//div"} {
+		opts, err := selectorQuery(sel, "", nil)
+		if err != nil {
+			t.Fatalf("selectorQuery(%q) error: %v", sel, err)
+		}
+		if len(opts) != 1 {
+			t.Errorf("selectorQuery(%q): got %d options, want 1", sel, len(opts))
+		}
+	}
+	if _, err := selectorQuery("div", "regex", nil); err == nil {
+		t.Error("expected error for unsupported selector_type")
+	}
+}
+
+func TestResolveSelectorType(t *testing.T) {
+	tests := []struct {
+		sel, selectorType, want string
+	}{
+		{"button.submit", "", "css"},
+		{"//button[contains(., 'Submit')]", "", "xpath"},
+		{"./span", "", "xpath"},
+		{"(//li)[2]", "", "xpath"},
+		{"button", "xpath", "xpath"},
+		{"//weird-but-css", "css", "css"},
+	}
+	for _, tt := range tests {
+		got, err := resolveSelectorType(tt.sel, tt.selectorType)
+		if err != nil {
+			t.Fatalf("resolveSelectorType(%q, %q) error: %v", tt.sel, tt.selectorType, err)
+		}
+		if got != tt.want {
+			t.Errorf("resolveSelectorType(%q, %q) = %q, want %q", tt.sel, tt.selectorType, got, tt.want)
+		}
+	}
+}
+
+func TestCSSString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"email", `"email"`},
+		{`user["name"]`, `"user[\"name\"]"`},
+		{`a\b`, `"a\\b"`},
+	}
+	for _, tt := range tests {
+		if got := cssString(tt.in); got != tt.want {
+			t.Errorf("cssString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestXPathString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "'plain'"},
+		{"it's", `"it's"`},
+		{`say "hi"`, `'say "hi"'`},
+		{`it's "x"`, `concat('it', "'", 's "x"')`},
+	}
+	for _, tt := range tests {
+		if got := xpathString(tt.in); got != tt.want {
+			t.Errorf("xpathString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// serviceWorkersJS lists the current origin's service worker registrations whose scope starts
+// with scopePrefix, unregistering them (and deleting the origin's Cache Storage) if asked.
+const serviceWorkersJS = `async function(scopePrefix, unregister, clearCaches) {
+	if (!navigator.serviceWorker) throw new Error('service workers are unavailable on this page (it must be served over https or from localhost)');
+	const out = [];
+	for (const reg of await navigator.serviceWorker.getRegistrations()) {
+		if (!reg.scope.startsWith(scopePrefix)) continue;
+		const worker = reg.active || reg.waiting || reg.installing;
+		const r = {
+			scope: reg.scope,
+			script_url: worker ? worker.scriptURL : '',
+			state: reg.active ? 'active' : reg.waiting ? 'waiting' : reg.installing ? 'installing' : 'none',
+			controlling: !!navigator.serviceWorker.controller && reg.active === navigator.serviceWorker.controller,
+		};
+		if (unregister) r.unregistered = await reg.unregister();
+		out.push(r);
+	}
+	let caches_deleted = [];
+	if (clearCaches && window.caches) {
+		caches_deleted = await caches.keys();
+		await Promise.all(caches_deleted.map(k => caches.delete(k)));
+	}
+	return {registrations: out, caches_deleted};
+}`
+
+// serviceWorkerRegistration is a registration reported by serviceWorkersJS
+type serviceWorkerRegistration struct {
+	Scope        string `json:"scope"`
+	ScriptURL    string `json:"script_url"`
+	State        string `json:"state"`
+	Controlling  bool   `json:"controlling"`
+	Unregistered bool   `json:"unregistered,omitempty"`
+}
+
+// serviceWorkersResult is the result of browser_service_workers
+type serviceWorkersResult struct {
+	Registrations  []serviceWorkerRegistration `json:"registrations"`
+	CachesDeleted  []string                    `json:"caches_deleted,omitempty"`
+	RunningWorkers []string                    `json:"running_workers,omitempty"`
+}
+
+// ServiceWorkersTool definition
+type serviceWorkersInput struct {
+	Action      string `json:"action,omitempty" enum:"list,unregister,bypass" description:"What to do (default: list)"`
+	Scope       string `json:"scope,omitempty" description:"For list and unregister: only registrations whose scope URL starts with this"`
+	ClearCaches bool   `json:"clear_caches,omitempty" description:"For unregister: also delete the origin's Cache Storage (default: false)"`
+	Bypass      *bool  `json:"bypass,omitempty" description:"For bypass: true to bypass service workers, false to use them again"`
+	Timeout     string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewServiceWorkersTool creates a tool for inspecting and controlling service workers
+func (b *BrowseTools) NewServiceWorkersTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_service_workers",
+		Description: `Inspect and control service workers, which often keep serving cached old code while testing.
+list: the current origin's registrations and every running service worker.
+unregister: unregister the current origin's registrations (optionally only under scope), optionally deleting its Cache Storage; reload afterwards.
+bypass: make page requests skip service workers and go to the network (until turned off or the browser restarts).`,
+		InputSchema: llm.SchemaFor[serviceWorkersInput](),
+		Run:         b.serviceWorkersRun,
+	}
+}
+
+func (b *BrowseTools) serviceWorkersRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input serviceWorkersInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	switch input.Action {
+	case "":
+		input.Action = "list"
+	case "list", "unregister":
+	case "bypass":
+		if input.Bypass == nil {
+			return llm.ErrorfToolOut("bypass is required for the bypass action")
+		}
+	default:
+		return llm.ErrorfToolOut("unsupported action %q: must be list, unregister, or bypass", input.Action)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	if input.Action == "bypass" {
+		if err := chromedp.Run(timeoutCtx, network.SetBypassServiceWorker(*input.Bypass)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+		if *input.Bypass {
+			return llm.ToolOut{LLMContent: llm.TextContent("Page requests now bypass service workers")}
+		}
+		return llm.ToolOut{LLMContent: llm.TextContent("Page requests go through service workers again")}
+	}
+
+	unregister := input.Action == "unregister"
+	var result serviceWorkersResult
+	if err := chromedp.Run(timeoutCtx, callWithArgs(serviceWorkersJS,
+		[]any{input.Scope, unregister, unregister && input.ClearCaches}, true, &result)); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if result.Registrations == nil {
+		result.Registrations = []serviceWorkerRegistration{}
+	}
+
+	var summary string
+	if unregister {
+		summary = fmt.Sprintf("Unregistered %d service workers", len(result.Registrations))
+		if len(result.CachesDeleted) > 0 {
+			summary += fmt.Sprintf(" and deleted %d caches", len(result.CachesDeleted))
+		}
+		summary += "; reload the page to load it without them"
+	} else {
+		// Running workers include other origins' and those the page isn't controlled by
+		var targets []*target.Info
+		if err := chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			targets, err = target.GetTargets().Do(cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser))
+			return err
+		})); err != nil {
+			return llm.ErrorfToolOut("failed to list service worker targets: %w", err)
+		}
+		for _, t := range targets {
+			if t.Type == "service_worker" {
+				result.RunningWorkers = append(result.RunningWorkers, t.URL)
+			}
+		}
+		summary = fmt.Sprintf("%d service worker registrations for this origin, %d running workers",
+			len(result.Registrations), len(result.RunningWorkers))
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal service workers: %w", err)
+	}
+	return jsonToolOut("service_workers", summary, data)
+}
@@ -0,0 +1,85 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceWorkersRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, tc := range []struct{ input, want string }{
+		{`{`, "invalid input"},
+		{`{"action": "restart"}`, "unsupported action"},
+		{`{"action": "bypass"}`, "bypass is required"},
+	} {
+		out := tools.serviceWorkersRun(ctx, []byte(tc.input))
+		if out.Error == nil || !strings.Contains(out.Error.Error(), tc.want) {
+			t.Errorf("serviceWorkersRun(%s) error = %v, want %q", tc.input, out.Error, tc.want)
+		}
+	}
+}
+
+func TestServiceWorkers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<p>app</p>`))
+	})
+	mux.HandleFunc("/sw.js", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript")
+		w.Write([]byte(`self.addEventListener('fetch', () => {});`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+	toolOut = tools.evalRun(ctx, []byte(`{"expression": "navigator.serviceWorker.register('/sw.js').then(() => navigator.serviceWorker.ready).then(() => 'ok')"}`))
+	if toolOut.Error != nil {
+		t.Fatalf("Registering service worker: %v", toolOut.Error)
+	}
+
+	toolOut = tools.serviceWorkersRun(ctx, []byte(`{}`))
+	if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, "/sw.js") {
+		t.Fatalf("Expected the registration to be listed, got %v %v", toolOut.LLMContent, toolOut.Error)
+	}
+
+	toolOut = tools.serviceWorkersRun(ctx, []byte(`{"action": "unregister"}`))
+	if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, "Unregistered 1 service workers") {
+		t.Errorf("Expected one unregistration, got %v %v", toolOut.LLMContent, toolOut.Error)
+	}
+
+	for _, bypass := range []string{"true", "false"} {
+		if out := tools.serviceWorkersRun(ctx, []byte(`{"action": "bypass", "bypass": `+bypass+`}`)); out.Error != nil {
+			t.Errorf("bypass %s error: %v", bypass, out.Error)
+		}
+	}
+}
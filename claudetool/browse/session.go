@@ -0,0 +1,175 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// validSessionID restricts session IDs to characters that are safe in file paths
+var validSessionID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// SessionManager hands out an isolated BrowseTools per session (typically a conversation),
+// so concurrent conversations each get their own browser, console buffer, and
+// screenshot and download directories instead of sharing one page.
+type SessionManager struct {
+	ctx            context.Context
+	idleTimeout    time.Duration
+	pool           *BrowserPool
+	policy         *NavigationPolicy
+	stealth        bool
+	polite         bool
+	politeDelay    time.Duration
+	autoScreenshot bool
+	launch         LaunchOptions
+
+	mu       sync.Mutex
+	sessions map[string]*BrowseTools
+}
+
+// NewSessionManager creates a session manager. Browsers are started lazily per session
+// and shut down after idleTimeout of inactivity (0 uses default).
+func NewSessionManager(ctx context.Context, idleTimeout time.Duration) *SessionManager {
+	return &SessionManager{
+		ctx:         ctx,
+		idleTimeout: idleTimeout,
+		policy:      DefaultNavigationPolicy(),
+		sessions:    make(map[string]*BrowseTools),
+	}
+}
+
+// UsePool makes new sessions take pre-launched browsers from pool.
+// It must be called before the first Session.
+func (m *SessionManager) UsePool(pool *BrowserPool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pool = pool
+}
+
+// SetNavigationPolicy sets the navigation policy for new sessions; nil disables all checks
+func (m *SessionManager) SetNavigationPolicy(p *NavigationPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policy = p
+}
+
+// SetStealth enables or disables the stealth profile for new sessions
+func (m *SessionManager) SetStealth(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stealth = enabled
+}
+
+// SetAutoScreenshot makes new sessions attach a screenshot to the output of page actions
+func (m *SessionManager) SetAutoScreenshot(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.autoScreenshot = enabled
+}
+
+// SetLaunchOptions sets the options of browsers launched by new sessions.
+// A pool given to UsePool must be created with the same options.
+func (m *SessionManager) SetLaunchOptions(o LaunchOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.launch = o
+}
+
+// SetPolite makes new sessions honor robots.txt and wait at least minDelay between page loads on a host
+func (m *SessionManager) SetPolite(enabled bool, minDelay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.polite = enabled
+	m.politeDelay = minDelay
+}
+
+// Session returns the browser tools for sessionID, creating them on first use.
+func (m *SessionManager) Session(sessionID string) (*BrowseTools, error) {
+	if !validSessionID.MatchString(sessionID) {
+		return nil, fmt.Errorf("invalid browser session ID %q", sessionID)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if b, ok := m.sessions[sessionID]; ok {
+		return b, nil
+	}
+	if err := m.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("session manager closed: %w", err)
+	}
+
+	b := NewBrowseTools(m.ctx, m.idleTimeout)
+	b.sessionID = sessionID
+	b.pool = m.pool
+	b.policy = m.policy
+	b.stealth = m.stealth
+	b.autoScreenshot = m.autoScreenshot
+	b.launch = m.launch
+	if m.polite {
+		b.polite = newPoliteness(m.politeDelay)
+	}
+	for _, dir := range []string{b.screenshotDir(), b.downloadDir()} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create session directory: %w", err)
+		}
+	}
+	m.sessions[sessionID] = b
+	return b, nil
+}
+
+// CloseSession shuts down the browser for sessionID and forgets it.
+// Screenshots and downloads are left on disk.
+func (m *SessionManager) CloseSession(sessionID string) {
+	m.mu.Lock()
+	b, ok := m.sessions[sessionID]
+	delete(m.sessions, sessionID)
+	m.mu.Unlock()
+
+	if ok {
+		b.Close()
+	}
+}
+
+// Sessions returns the IDs of all open sessions, sorted
+func (m *SessionManager) Sessions() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(m.sessions))
+	for id := range m.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Close shuts down every session's browser
+func (m *SessionManager) Close() {
+	m.mu.Lock()
+	sessions := m.sessions
+	m.sessions = make(map[string]*BrowseTools)
+	m.mu.Unlock()
+
+	for _, b := range sessions {
+		b.Close()
+	}
+	if m.pool != nil {
+		m.pool.Close()
+	}
+}
+
+// screenshotDir returns the directory for this session's screenshots
+func (b *BrowseTools) screenshotDir() string {
+	return filepath.Join(ScreenshotDir, b.sessionID)
+}
+
+// downloadDir returns the directory for this session's downloads
+func (b *BrowseTools) downloadDir() string {
+	return filepath.Join(DownloadDir, b.sessionID)
+}
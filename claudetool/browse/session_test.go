@@ -0,0 +1,72 @@
+package browse
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSessionManager(t *testing.T) {
+	m := NewSessionManager(context.Background(), 0)
+	t.Cleanup(m.Close)
+
+	a, err := m.Session("conv-a")
+	if err != nil {
+		t.Fatalf("Session(conv-a) error: %v", err)
+	}
+	b, err := m.Session("conv-b")
+	if err != nil {
+		t.Fatalf("Session(conv-b) error: %v", err)
+	}
+	if a == b {
+		t.Fatal("Expected distinct tools for distinct sessions")
+	}
+	if again, _ := m.Session("conv-a"); again != a {
+		t.Error("Expected the same tools for the same session ID")
+	}
+	if got := m.Sessions(); !slices.Equal(got, []string{"conv-a", "conv-b"}) {
+		t.Errorf("Sessions() = %v", got)
+	}
+
+	// Screenshots land in the session's own directory
+	id := a.SaveScreenshot([]byte("fake"), "png")
+	if !strings.HasPrefix(id, "conv-a/") {
+		t.Errorf("Expected screenshot ID namespaced by session, got %q", id)
+	}
+	path := GetScreenshotPath(id, "png")
+	if filepath.Dir(path) != filepath.Join(ScreenshotDir, "conv-a") {
+		t.Errorf("Screenshot saved outside session directory: %s", path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Screenshot not written: %v", err)
+	}
+	os.Remove(path)
+
+	// Console buffers are per session
+	a.consoleLogs = append(a.consoleLogs, nil)
+	if len(b.consoleLogs) != 0 {
+		t.Error("Console logs leaked between sessions")
+	}
+
+	m.CloseSession("conv-a")
+	if got := m.Sessions(); !slices.Equal(got, []string{"conv-b"}) {
+		t.Errorf("Sessions() after close = %v", got)
+	}
+	if fresh, _ := m.Session("conv-a"); fresh == a {
+		t.Error("Expected new tools after closing a session")
+	}
+}
+
+func TestSessionManagerInvalidID(t *testing.T) {
+	m := NewSessionManager(context.Background(), 0)
+	t.Cleanup(m.Close)
+
+	for _, id := range []string{"", "../etc", "a/b", "a b"} {
+		if _, err := m.Session(id); err == nil {
+			t.Errorf("Session(%q) succeeded, want error", id)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+)
+
+// stealthJS patches the properties that most commonly give away headless or automated Chrome.
+// It runs before page scripts in every document.
+const stealthJS = `(() => {
+	const define = (obj, prop, value) => Object.defineProperty(obj, prop, {get: () => value, configurable: true});
+
+	// Automation sets navigator.webdriver to true
+	define(Navigator.prototype, 'webdriver', false);
+	define(Navigator.prototype, 'languages', Object.freeze(['en-US', 'en']));
+
+	// Headless Chrome has no plugins; desktop Chrome lists its built-in PDF viewer under several names
+	const plugins = ['PDF Viewer', 'Chrome PDF Viewer', 'Chromium PDF Viewer', 'Microsoft Edge PDF Viewer', 'WebKit built-in PDF']
+		.map(name => ({name, filename: 'internal-pdf-viewer', description: 'Portable Document Format', length: 1}));
+	plugins.item = i => plugins[i] ?? null;
+	plugins.namedItem = name => plugins.find(p => p.name === name) ?? null;
+	plugins.refresh = () => {};
+	define(Navigator.prototype, 'plugins', plugins);
+
+	if (!window.chrome) window.chrome = {};
+	if (!window.chrome.runtime) window.chrome.runtime = {};
+
+	// Headless reports notification permission as denied while Notification.permission is default
+	if (navigator.permissions && window.Notification) {
+		const query = navigator.permissions.query.bind(navigator.permissions);
+		navigator.permissions.query = p => p && p.name === 'notifications'
+			? Promise.resolve({state: Notification.permission === 'default' ? 'prompt' : Notification.permission, onchange: null})
+			: query(p);
+	}
+
+	// Headless renders WebGL with SwiftShader
+	for (const ctx of [window.WebGLRenderingContext, window.WebGL2RenderingContext]) {
+		if (!ctx) continue;
+		const getParameter = ctx.prototype.getParameter;
+		ctx.prototype.getParameter = function(p) {
+			if (p === 37445) return 'Intel Inc.'; // UNMASKED_VENDOR_WEBGL
+			if (p === 37446) return 'Intel Iris OpenGL Engine'; // UNMASKED_RENDERER_WEBGL
+			return getParameter.call(this, p);
+		};
+	}
+
+	// Headless windows have no browser chrome, so the outer size is zero
+	if (!window.outerWidth) {
+		define(window, 'outerWidth', window.innerWidth);
+		define(window, 'outerHeight', window.innerHeight + 85);
+	}
+})();`
+
+// SetStealth enables or disables the stealth profile, which makes the browser look like
+// desktop Chrome to sites that block headless or automated browsers.
+// It takes effect the next time the browser starts.
+func (b *BrowseTools) SetStealth(enabled bool) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.stealth = enabled
+}
+
+// applyStealth overrides the user agent and client hints and installs stealthJS
+func applyStealth(ctx context.Context) error {
+	_, product, _, userAgent, _, err := browser.GetVersion().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get browser version: %w", err)
+	}
+	ua, metadata := stealthUserAgent(product, userAgent)
+	if err := emulation.SetUserAgentOverride(ua).
+		WithAcceptLanguage("en-US,en;q=0.9").
+		WithPlatform("Linux x86_64").
+		WithUserAgentMetadata(metadata).
+		Do(ctx); err != nil {
+		return fmt.Errorf("failed to override user agent: %w", err)
+	}
+	if _, err := page.AddScriptToEvaluateOnNewDocument(stealthJS).Do(ctx); err != nil {
+		return fmt.Errorf("failed to add stealth script: %w", err)
+	}
+	return nil
+}
+
+// stealthUserAgent returns the user agent and client hints of the desktop Chrome matching
+// product (e.g. "HeadlessChrome/120.0.6099.109") and userAgent
+func stealthUserAgent(product, userAgent string) (string, *emulation.UserAgentMetadata) {
+	_, fullVersion, _ := strings.Cut(product, "/")
+	major, _, _ := strings.Cut(fullVersion, ".")
+	brands := func(version string) []*emulation.UserAgentBrandVersion {
+		return []*emulation.UserAgentBrandVersion{
+			{Brand: "Google Chrome", Version: version},
+			{Brand: "Chromium", Version: version},
+			{Brand: "Not/A)Brand", Version: "99"},
+		}
+	}
+	return strings.ReplaceAll(userAgent, "HeadlessChrome", "Chrome"), &emulation.UserAgentMetadata{
+		Brands:          brands(major),
+		FullVersionList: brands(fullVersion),
+		Platform:        "Linux",
+		Architecture:    "x86",
+		Bitness:         "64",
+	}
+}
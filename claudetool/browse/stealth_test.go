@@ -0,0 +1,56 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStealthUserAgent(t *testing.T) {
+	ua, metadata := stealthUserAgent("HeadlessChrome/120.0.6099.109",
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.6099.109 Safari/537.36")
+	if want := "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.109 Safari/537.36"; ua != want {
+		t.Errorf("user agent = %q, want %q", ua, want)
+	}
+	if metadata.Brands[0].Version != "120" || metadata.FullVersionList[0].Version != "120.0.6099.109" {
+		t.Errorf("unexpected brand versions %q and %q", metadata.Brands[0].Version, metadata.FullVersionList[0].Version)
+	}
+	for _, b := range metadata.Brands {
+		if strings.Contains(b.Brand, "Headless") {
+			t.Errorf("brand %q gives away headless", b.Brand)
+		}
+	}
+}
+
+func TestStealthProfile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	tools.SetStealth(true)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<p>page</p>"})
+	toolOut := tools.NewNavigateTool().Run(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	expr := `[navigator.webdriver, navigator.userAgent.includes('Headless'), navigator.plugins.length > 0, !!window.chrome].join()`
+	evalInput, _ := json.Marshal(evalInput{Expression: expr})
+	toolOut = tools.evalRun(ctx, evalInput)
+	if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, `"false,false,true,true"`) {
+		t.Errorf("Expected patched fingerprints, got %v %v", toolOut.LLMContent, toolOut.Error)
+	}
+}
@@ -0,0 +1,112 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// defaultStyleProperties are the computed properties reported when none are asked for:
+// those that usually explain layout and color problems
+var defaultStyleProperties = []string{
+	"display", "position", "top", "right", "bottom", "left", "z-index",
+	"width", "height", "min-width", "max-width", "min-height", "max-height", "box-sizing",
+	"margin", "padding", "border", "border-radius", "overflow", "transform",
+	"flex-direction", "flex-wrap", "justify-content", "align-items", "flex", "gap",
+	"grid-template-columns", "grid-template-rows",
+	"color", "background-color", "background-image", "opacity", "visibility",
+	"font-family", "font-size", "font-weight", "line-height", "text-align", "white-space",
+	"pointer-events", "cursor",
+}
+
+// computedStylesJS returns the computed values of properties (all of them if empty)
+// for this element, or for its pseudo-element such as "::before"
+const computedStylesJS = `function(properties, pseudo) {
+	const s = getComputedStyle(this, pseudo || null);
+	const names = properties.length ? properties : Array.from(s);
+	const out = {};
+	for (const name of names) out[name] = s.getPropertyValue(name);
+	return out;
+}`
+
+// GetStylesTool definition
+type getStylesInput struct {
+	Selector string `json:"selector" description:"The element to inspect, as a CSS selector or XPath expression (see selector_type)"`
+	selectorTypeInput
+	frameInput
+	Properties []string `json:"properties,omitempty" description:"CSS property names to get, e.g. [\"display\", \"--brand-color\"] (default: common layout and color properties)"`
+	All        bool     `json:"all,omitempty" description:"Get every computed property instead (several hundred; default: false)"`
+	Pseudo     string   `json:"pseudo,omitempty" description:"Pseudo-element to inspect instead of the element, e.g. ::before or ::placeholder"`
+	Timeout    string   `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewGetStylesTool creates a tool for reading an element's computed CSS
+func (b *BrowseTools) NewGetStylesTool() *llm.Tool {
+	return &llm.Tool{
+		Name:        "browser_get_styles",
+		Description: "Get the computed CSS of an element: the named properties, a default set of layout, box, color, and font properties, or all of them",
+		InputSchema: llm.SchemaFor[getStylesInput](),
+		Run:         b.getStylesRun,
+	}
+}
+
+func (b *BrowseTools) getStylesRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input getStylesInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	if input.Selector == "" {
+		return llm.ErrorfToolOut("selector is required")
+	}
+	if input.All && len(input.Properties) > 0 {
+		return llm.ErrorfToolOut("give either properties or all, not both")
+	}
+	if input.Pseudo != "" && !strings.HasPrefix(input.Pseudo, ":") {
+		return llm.ErrorfToolOut("pseudo must start with a colon, e.g. ::before")
+	}
+	properties := input.Properties
+	if len(properties) == 0 && !input.All {
+		properties = defaultStyleProperties
+	}
+	if properties == nil {
+		properties = []string{}
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var iframe *cdp.Node
+	if input.Frame != "" {
+		if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+
+	var styles map[string]string
+	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		node, err := queryNode(ctx, input.Selector, input.SelectorType, iframe)
+		if err != nil {
+			return err
+		}
+		return callFunctionOnNode(ctx, node, computedStylesJS, &styles, properties, input.Pseudo)
+	}))
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	data, err := json.Marshal(styles)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal styles: %w", err)
+	}
+	return jsonToolOut("styles", fmt.Sprintf("%d computed properties of %s%s", len(styles), input.Selector, input.Pseudo), data)
+}
@@ -0,0 +1,62 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetStylesRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, tc := range []struct{ input, want string }{
+		{`{`, "invalid input"},
+		{`{}`, "selector is required"},
+		{`{"selector": "p", "all": true, "properties": ["color"]}`, "not both"},
+		{`{"selector": "p", "pseudo": "before"}`, "colon"},
+	} {
+		out := tools.getStylesRun(ctx, []byte(tc.input))
+		if out.Error == nil || !strings.Contains(out.Error.Error(), tc.want) {
+			t.Errorf("getStylesRun(%s) error = %v, want %q", tc.input, out.Error, tc.want)
+		}
+	}
+}
+
+func TestGetStyles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	html := `<style>p { color: rgb(255, 0, 0); --brand: teal } p::before { content: "x" }</style><p>hi</p>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.getStylesRun(ctx, []byte(`{"selector": "p", "properties": ["color", "--brand"]}`))
+	if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, `{"--brand":"teal","color":"rgb(255, 0, 0)"}`) {
+		t.Errorf("Expected color and custom property, got %v %v", out.LLMContent, out.Error)
+	}
+	out = tools.getStylesRun(ctx, []byte(`{"selector": "p", "pseudo": "::before", "properties": ["content"]}`))
+	if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, `\"x\"`) {
+		t.Errorf("Expected ::before content, got %v %v", out.LLMContent, out.Error)
+	}
+}
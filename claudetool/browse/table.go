@@ -0,0 +1,212 @@
+package browse
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// extractTableJS expands a table (this) into a grid of cell texts, repeating the text of
+// cells that span several rows or columns. Header rows are those in <thead>, or the
+// first row if it contains only <th> cells. It also collects nearby pagination controls.
+const extractTableJS = `function() {
+	if (this.tagName !== 'TABLE') throw new Error('element is not a table');
+	const text = (el) => (el.innerText || el.textContent || '').replace(/\s+/g, ' ').trim();
+	const rows = Array.from(this.rows);
+	const grid = rows.map(() => []);
+	rows.forEach((tr, r) => {
+		let c = 0;
+		for (const cell of tr.cells) {
+			while (grid[r][c] !== undefined) c++;
+			const t = text(cell);
+			const colSpan = Math.max(1, cell.colSpan);
+			const rowSpan = Math.max(1, cell.rowSpan || 1);
+			for (let i = 0; i < rowSpan && r + i < rows.length; i++) {
+				for (let j = 0; j < colSpan; j++) grid[r + i][c + j] = t;
+			}
+			c += colSpan;
+		}
+	});
+	const width = Math.max(0, ...grid.map(row => row.length));
+	const cells = grid.map(row => Array.from({length: width}, (_, i) => row[i] ?? ''));
+
+	let headerRows = rows.filter(tr => tr.parentElement.tagName === 'THEAD').length;
+	if (headerRows === 0 && rows.length > 1 && Array.from(rows[0].cells).every(c => c.tagName === 'TH')) headerRows = 1;
+
+	// Pagination hints: rel=next links and next/previous controls in the table's container
+	const scope = this.closest('section, article, main, [class*=table], [class*=grid]') || this.ownerDocument;
+	const hints = [];
+	for (const el of scope.querySelectorAll('a, button, [role=button]')) {
+		const t = text(el);
+		const label = el.getAttribute('aria-label') || '';
+		if (el.rel === 'next' || el.rel === 'prev' || /^(next|previous|prev|older|newer|load more|show more|[›»<>‹«]|\d+)$/i.test(t) || /next|previous|page/i.test(label)) {
+			if (el.closest('table') === this) continue;
+			hints.push({text: t || label, href: el.href || '', disabled: !!el.disabled || el.getAttribute('aria-disabled') === 'true'});
+			if (hints.length >= 20) break;
+		}
+	}
+
+	return {
+		caption: this.caption ? text(this.caption) : '',
+		header_rows: Math.min(headerRows, cells.length),
+		cells,
+		pagination: hints,
+	};
+}`
+
+// rawTable is the result of extractTableJS
+type rawTable struct {
+	Caption    string           `json:"caption"`
+	HeaderRows int              `json:"header_rows"`
+	Cells      [][]string       `json:"cells"`
+	Pagination []paginationHint `json:"pagination"`
+}
+
+// paginationHint is a control near a table that probably loads more rows
+type paginationHint struct {
+	Text     string `json:"text"`
+	Href     string `json:"href,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// pageTable is a table reported by browser_extract_table in JSON format
+type pageTable struct {
+	Caption    string           `json:"caption,omitempty"`
+	Headers    []string         `json:"headers"`
+	Rows       [][]string       `json:"rows"`
+	Pagination []paginationHint `json:"pagination,omitempty"`
+}
+
+// table combines multiple header rows into one header per column
+func (t rawTable) table() pageTable {
+	out := pageTable{Caption: t.Caption, Rows: t.Cells[t.HeaderRows:], Pagination: t.Pagination}
+	if out.Rows == nil {
+		out.Rows = [][]string{}
+	}
+	if len(t.Cells) == 0 {
+		out.Headers = []string{}
+		return out
+	}
+	out.Headers = make([]string, len(t.Cells[0]))
+	for col := range out.Headers {
+		// Join distinct header texts top to bottom, e.g. a colspan group and its sub-column
+		var parts []string
+		for _, row := range t.Cells[:t.HeaderRows] {
+			if text := row[col]; text != "" && (len(parts) == 0 || parts[len(parts)-1] != text) {
+				parts = append(parts, text)
+			}
+		}
+		out.Headers[col] = strings.Join(parts, " / ")
+	}
+	return out
+}
+
+// csv encodes the table as CSV, with a header line if it has headers
+func (t pageTable) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if strings.Join(t.Headers, "") != "" {
+		w.Write(t.Headers)
+	}
+	w.WriteAll(t.Rows)
+	return buf.Bytes(), w.Error()
+}
+
+// ExtractTableTool definition
+type extractTableInput struct {
+	Selector string `json:"selector,omitempty" description:"The table to extract (default: the first table), as a CSS selector or XPath expression (see selector_type)"`
+	selectorTypeInput
+	frameInput
+	Format  string `json:"format,omitempty" enum:"json,csv" description:"Output format (default: json)"`
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewExtractTableTool creates a tool for extracting an HTML table's data
+func (b *BrowseTools) NewExtractTableTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_extract_table",
+		Description: `Extract an HTML table as JSON (headers and rows) or CSV.
+Cells spanning several rows or columns are repeated in each; multiple header rows are joined per column.
+Also reports nearby pagination controls (next/previous links and buttons) if the table may continue on other pages.`,
+		InputSchema: llm.SchemaFor[extractTableInput](),
+		Run:         b.extractTableRun,
+	}
+}
+
+func (b *BrowseTools) extractTableRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input extractTableInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	switch input.Format {
+	case "":
+		input.Format = "json"
+	case "json", "csv":
+	default:
+		return llm.ErrorfToolOut("unsupported format %q: must be json or csv", input.Format)
+	}
+	if input.Selector == "" {
+		input.Selector, input.SelectorType = "table", selectorTypeCSS
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	var iframe *cdp.Node
+	if input.Frame != "" {
+		if err := chromedp.Run(timeoutCtx, findFrame(input.Frame, &iframe)); err != nil {
+			return llm.ErrorToolOut(err)
+		}
+	}
+
+	var raw rawTable
+	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		node, err := queryNode(ctx, input.Selector, input.SelectorType, iframe)
+		if err != nil {
+			return err
+		}
+		return callFunctionOnNode(ctx, node, extractTableJS, &raw)
+	}))
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	table := raw.table()
+	summary := fmt.Sprintf("Table with %d columns and %d rows", len(table.Headers), len(table.Rows))
+	if table.Caption != "" {
+		summary += fmt.Sprintf(" (%s)", table.Caption)
+	}
+
+	if input.Format == "csv" {
+		data, err := table.csv()
+		if err != nil {
+			return llm.ErrorfToolOut("failed to encode CSV: %w", err)
+		}
+		if len(table.Pagination) > 0 {
+			hints := make([]string, len(table.Pagination))
+			for i, h := range table.Pagination {
+				hints[i] = h.Text
+			}
+			summary += fmt.Sprintf("; possible pagination controls: %s", strings.Join(hints, ", "))
+		}
+		return fileToolOut("table_csv", "csv", summary, data)
+	}
+
+	data, err := json.Marshal(table)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal table: %w", err)
+	}
+	return jsonToolOut("table", summary, data)
+}
@@ -0,0 +1,118 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRawTableHeaders(t *testing.T) {
+	raw := rawTable{
+		HeaderRows: 2,
+		Cells: [][]string{
+			{"Name", "Score", "Score"},
+			{"Name", "Home", "Away"},
+			{"A", "1", "2"},
+		},
+	}
+	table := raw.table()
+	if want := []string{"Name", "Score / Home", "Score / Away"}; !slices.Equal(table.Headers, want) {
+		t.Errorf("Headers = %q, want %q", table.Headers, want)
+	}
+	if len(table.Rows) != 1 || !slices.Equal(table.Rows[0], []string{"A", "1", "2"}) {
+		t.Errorf("Rows = %q", table.Rows)
+	}
+
+	data, err := table.csv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Name,Score / Home,Score / Away\nA,1,2\n"; string(data) != want {
+		t.Errorf("csv = %q, want %q", data, want)
+	}
+}
+
+func TestRawTableNoHeaders(t *testing.T) {
+	table := rawTable{Cells: [][]string{{"a", "b,c"}}}.table()
+	if !slices.Equal(table.Headers, []string{"", ""}) {
+		t.Errorf("Headers = %q", table.Headers)
+	}
+	data, err := table.csv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a,\"b,c\"\n"; string(data) != want {
+		t.Errorf("csv = %q, want %q", data, want)
+	}
+
+	empty := rawTable{}.table()
+	if empty.Headers == nil || empty.Rows == nil {
+		t.Error("Expected empty, non-nil headers and rows")
+	}
+}
+
+func TestExtractTableRunErrorPaths(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	for _, input := range []string{`{`, `{"format": "xml"}`} {
+		if out := tools.extractTableRun(ctx, []byte(input)); out.Error == nil {
+			t.Errorf("extractTableRun(%s) succeeded, want error", input)
+		}
+	}
+}
+
+func TestExtractTable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	html := `<table><caption>Results</caption><thead><tr><th rowspan=2>Team</th><th colspan=2>Goals</th></tr>` +
+		`<tr><th>For</th><th>Against</th></tr></thead><tbody><tr><td>A</td><td colspan=2>3</td></tr></tbody></table>` +
+		`<a rel="next" href="?page=2">Next</a>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.NewNavigateTool().Run(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	toolOut = tools.extractTableRun(ctx, []byte(`{}`))
+	if toolOut.Error != nil {
+		t.Fatalf("extractTableRun error: %v", toolOut.Error)
+	}
+	text := toolOut.LLMContent[0].Text
+	start, end := strings.Index(text, "<table>"), strings.Index(text, "</table>")
+	if start < 0 || end < 0 {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	var table pageTable
+	if err := json.Unmarshal([]byte(text[start+len("<table>"):end]), &table); err != nil {
+		t.Fatalf("Failed to parse table: %v", err)
+	}
+	if !slices.Equal(table.Headers, []string{"Team", "Goals / For", "Goals / Against"}) {
+		t.Errorf("Headers = %q", table.Headers)
+	}
+	if len(table.Rows) != 1 || !slices.Equal(table.Rows[0], []string{"A", "3", "3"}) {
+		t.Errorf("Rows = %q", table.Rows)
+	}
+	if table.Caption != "Results" || len(table.Pagination) != 1 {
+		t.Errorf("Caption = %q, Pagination = %+v", table.Caption, table.Pagination)
+	}
+}
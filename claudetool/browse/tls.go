@@ -0,0 +1,180 @@
+package browse
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/security"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// certExpiryWarning is how close to expiry a certificate is flagged
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// tlsCertificate describes a certificate of the chain
+type tlsCertificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	DNSNames  []string  `json:"dns_names,omitempty"`
+}
+
+// tlsInfo is the result of browser_tls_info
+type tlsInfo struct {
+	URL           string           `json:"url"`
+	SecurityState string           `json:"security_state"`
+	Protocol      string           `json:"protocol,omitempty"`
+	KeyExchange   string           `json:"key_exchange,omitempty"`
+	Cipher        string           `json:"cipher,omitempty"`
+	Chain         []tlsCertificate `json:"chain,omitempty"`
+	Warnings      []string         `json:"warnings"`
+}
+
+// describeTLS summarizes Chrome's security state for the page at url, with warnings as of now
+func describeTLS(url string, state *security.VisibleSecurityState, now time.Time) (*tlsInfo, error) {
+	info := &tlsInfo{URL: url, SecurityState: string(state.SecurityState), Warnings: []string{}}
+	warn := func(format string, args ...any) {
+		info.Warnings = append(info.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	cert := state.CertificateSecurityState
+	if cert == nil {
+		if strings.HasPrefix(url, "http:") {
+			warn("the page is not served over HTTPS")
+		}
+		return info, nil
+	}
+	info.Protocol, info.KeyExchange, info.Cipher = cert.Protocol, cert.KeyExchange, cert.Cipher
+	for i, encoded := range cert.Certificate {
+		der, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode certificate %d: %w", i, err)
+		}
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %d: %w", i, err)
+		}
+		info.Chain = append(info.Chain, tlsCertificate{
+			Subject:   c.Subject.String(),
+			Issuer:    c.Issuer.String(),
+			NotBefore: c.NotBefore,
+			NotAfter:  c.NotAfter,
+			DNSNames:  c.DNSNames,
+		})
+		switch {
+		case now.After(c.NotAfter):
+			warn("%s expired on %s", c.Subject, c.NotAfter.Format(time.DateOnly))
+		case now.Before(c.NotBefore):
+			warn("%s is not valid until %s", c.Subject, c.NotBefore.Format(time.DateOnly))
+		case c.NotAfter.Sub(now) < certExpiryWarning:
+			warn("%s expires on %s", c.Subject, c.NotAfter.Format(time.DateOnly))
+		}
+	}
+
+	if cert.CertificateNetworkError != "" {
+		warn("certificate error: %s", cert.CertificateNetworkError)
+	}
+	if cert.CertificateHasWeakSignature {
+		warn("the certificate uses a weak signature algorithm")
+	}
+	if cert.CertificateHasSha1signature {
+		warn("the chain has a SHA-1 signature")
+	}
+	for _, obsolete := range []struct {
+		is   bool
+		what string
+	}{
+		{cert.ObsoleteSslProtocol, "protocol " + cert.Protocol},
+		{cert.ObsoleteSslKeyExchange, "key exchange " + cert.KeyExchange},
+		{cert.ObsoleteSslCipher, "cipher " + cert.Cipher},
+		{cert.ObsoleteSslSignature, "server signature"},
+	} {
+		if obsolete.is {
+			warn("obsolete %s", obsolete.what)
+		}
+	}
+	if tip := state.SafetyTipInfo; tip != nil && tip.SafetyTipStatus != "" {
+		warn("safety tip: %s %s", tip.SafetyTipStatus, tip.SafeURL)
+	}
+	for _, issue := range state.SecurityStateIssueIDs {
+		warn("security issue: %s", issue)
+	}
+	return info, nil
+}
+
+// TLSInfoTool definition
+type tlsInfoInput struct {
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewTLSInfoTool creates a tool for inspecting the current page's certificate and connection security
+func (b *BrowseTools) NewTLSInfoTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_tls_info",
+		Description: `Report the current page's security state as Chrome sees it: TLS protocol and cipher, the certificate chain with
+issuers and validity dates, and warnings such as certificate errors, expired or soon-expiring certificates, and obsolete TLS settings.`,
+		InputSchema: llm.SchemaFor[tlsInfoInput](),
+		Run:         b.tlsInfoRun,
+	}
+}
+
+func (b *BrowseTools) tlsInfoRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input tlsInfoInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	// Enabling the Security domain reports the current state
+	states := make(chan *security.VisibleSecurityState, 1)
+	listenCtx, stopListening := context.WithCancel(timeoutCtx)
+	defer stopListening()
+	chromedp.ListenTarget(listenCtx, func(ev any) {
+		if e, ok := ev.(*security.EventVisibleSecurityStateChanged); ok {
+			select {
+			case states <- e.VisibleSecurityState:
+			default:
+			}
+		}
+	})
+	var url string
+	if err := chromedp.Run(timeoutCtx, chromedp.Location(&url), security.Enable()); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	defer chromedp.Run(browserCtx, security.Disable())
+
+	var state *security.VisibleSecurityState
+	select {
+	case state = <-states:
+	case <-timeoutCtx.Done():
+		return llm.ErrorfToolOut("timed out waiting for the security state: %w", timeoutCtx.Err())
+	}
+
+	info, err := describeTLS(url, state, time.Now())
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return llm.ErrorfToolOut("failed to marshal TLS info: %w", err)
+	}
+	summary := fmt.Sprintf("Security state %s with %d warnings", info.SecurityState, len(info.Warnings))
+	if info.Protocol != "" {
+		summary = fmt.Sprintf("%s, %s", summary, info.Protocol)
+	}
+	return jsonToolOut("tls_info", summary, data)
+}
@@ -0,0 +1,84 @@
+package browse
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/security"
+)
+
+func TestDescribeTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	cert := server.Certificate()
+
+	state := &security.VisibleSecurityState{
+		SecurityState: security.StateInsecureBroken,
+		CertificateSecurityState: &security.CertificateSecurityState{
+			Protocol:                "TLS 1.3",
+			Cipher:                  "AES_128_GCM",
+			Certificate:             []string{base64.StdEncoding.EncodeToString(cert.Raw)},
+			CertificateNetworkError: "net::ERR_CERT_AUTHORITY_INVALID",
+		},
+	}
+	info, err := describeTLS(server.URL, state, cert.NotAfter.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Chain) != 1 || info.Chain[0].Issuer != cert.Issuer.String() || info.Protocol != "TLS 1.3" {
+		t.Errorf("describeTLS() = %+v", info)
+	}
+	warnings := strings.Join(info.Warnings, "\n")
+	for _, want := range []string{"expired on", "ERR_CERT_AUTHORITY_INVALID"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected a warning containing %q, got %q", want, warnings)
+		}
+	}
+
+	plain, err := describeTLS("http://example.com/", &security.VisibleSecurityState{SecurityState: security.StateInsecure}, time.Now())
+	if err != nil || len(plain.Warnings) != 1 || !strings.Contains(plain.Warnings[0], "not served over HTTPS") {
+		t.Errorf("describeTLS(http) = %+v, %v", plain, err)
+	}
+}
+
+func TestTLSInfo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<p>plain</p>"))
+	}))
+	defer server.Close()
+
+	navInput, _ := json.Marshal(navigateInput{URL: server.URL})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.tlsInfoRun(ctx, []byte(`{}`))
+	if out.Error != nil {
+		t.Fatalf("tlsInfoRun error: %v", out.Error)
+	}
+	if text := out.LLMContent[0].Text; !strings.Contains(text, "not served over HTTPS") {
+		t.Errorf("Expected a plain http warning, got %s", text)
+	}
+}
@@ -0,0 +1,122 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/input"
+	"github.com/chromedp/chromedp"
+	"shelley.exe.dev/llm"
+)
+
+// pinchStartDistance is the distance in CSS pixels between the two fingers when a pinch starts
+const pinchStartDistance = 100
+
+// TouchTool definition
+type touchInput struct {
+	Action  string  `json:"action" enum:"tap,swipe,pinch" description:"Gesture to perform"`
+	X       float64 `json:"x" description:"X coordinate of the touch (the start of a swipe, the center of a pinch)"`
+	Y       float64 `json:"y" description:"Y coordinate of the touch (the start of a swipe, the center of a pinch)"`
+	ToX     float64 `json:"to_x,omitempty" description:"For swipe: X coordinate where the finger lifts"`
+	ToY     float64 `json:"to_y,omitempty" description:"For swipe: Y coordinate where the finger lifts"`
+	Scale   float64 `json:"scale,omitempty" description:"For pinch: how far the fingers spread, relative to their start (e.g. 2 to zoom in, 0.5 to zoom out)"`
+	Steps   int     `json:"steps,omitempty" description:"For swipe and pinch: number of intermediate touchmove events (default: 10)"`
+	Timeout string  `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewTouchTool creates a tool for touch gestures
+func (b *BrowseTools) NewTouchTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_touch",
+		Description: `Perform a touch gesture at viewport coordinates in CSS pixels: tap, swipe from x,y to to_x,to_y, or pinch around x,y.
+Dispatches real touch events (touchstart/touchmove/touchend), enabling touch emulation if needed; use a mobile browser_resize device preset for mobile layouts.`,
+		InputSchema: llm.SchemaFor[touchInput](),
+		Run:         b.touchRun,
+	}
+}
+
+// touchActions returns the touch events for a touch tool input
+func (in *touchInput) touchActions() ([]chromedp.Action, error) {
+	if in.X < 0 || in.Y < 0 || in.ToX < 0 || in.ToY < 0 {
+		return nil, fmt.Errorf("coordinates must not be negative")
+	}
+	steps := in.Steps
+	if steps == 0 {
+		steps = 10
+	}
+	if steps < 1 || steps > 100 {
+		return nil, fmt.Errorf("steps must be between 1 and 100")
+	}
+
+	touch := func(typ input.TouchType, points ...*input.TouchPoint) chromedp.Action {
+		return input.DispatchTouchEvent(typ, points)
+	}
+	point := func(id int, x, y float64) *input.TouchPoint {
+		return &input.TouchPoint{X: x, Y: y, ID: float64(id)}
+	}
+	lerp := func(from, to float64, i int) float64 {
+		return from + (to-from)*float64(i)/float64(steps)
+	}
+
+	switch in.Action {
+	case "tap":
+		return []chromedp.Action{
+			touch(input.TouchStart, point(0, in.X, in.Y)),
+			touch(input.TouchEnd),
+		}, nil
+
+	case "swipe":
+		actions := []chromedp.Action{touch(input.TouchStart, point(0, in.X, in.Y))}
+		for i := 1; i <= steps; i++ {
+			actions = append(actions, touch(input.TouchMove, point(0, lerp(in.X, in.ToX, i), lerp(in.Y, in.ToY, i))))
+		}
+		return append(actions, touch(input.TouchEnd)), nil
+
+	case "pinch":
+		if in.Scale <= 0 || in.Scale > 10 || in.Scale == 1 {
+			return nil, fmt.Errorf("scale must be greater than 0, at most 10, and not 1")
+		}
+		// Two fingers on a horizontal line through the center, moving apart or together
+		fingers := func(distance float64) []*input.TouchPoint {
+			half := distance / 2
+			return []*input.TouchPoint{point(0, math.Max(0, in.X-half), in.Y), point(1, in.X+half, in.Y)}
+		}
+		actions := []chromedp.Action{touch(input.TouchStart, fingers(pinchStartDistance)...)}
+		for i := 1; i <= steps; i++ {
+			actions = append(actions, touch(input.TouchMove, fingers(lerp(pinchStartDistance, pinchStartDistance*in.Scale, i))...))
+		}
+		return append(actions, touch(input.TouchEnd)), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported action %q: must be tap, swipe, or pinch", in.Action)
+	}
+}
+
+func (b *BrowseTools) touchRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input touchInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+	actions, err := input.touchActions()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	// Pages only listen for touch events if the browser reports a touch screen
+	enable := emulation.SetTouchEmulationEnabled(true).WithMaxTouchPoints(maxTouchPoints)
+	if err := chromedp.Run(timeoutCtx, append([]chromedp.Action{enable}, actions...)...); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	return b.actionToolOut("done")
+}
@@ -0,0 +1,79 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTouchActions(t *testing.T) {
+	tests := []struct {
+		input   string
+		actions int
+		wantErr string
+	}{
+		{`{"action": "tap", "x": 1, "y": 2}`, 2, ""},
+		{`{"action": "swipe", "x": 100, "y": 200, "to_x": 100, "to_y": 20}`, 12, ""},
+		{`{"action": "swipe", "x": 100, "y": 200, "to_x": 100, "to_y": 20, "steps": 3}`, 5, ""},
+		{`{"action": "pinch", "x": 100, "y": 100, "scale": 2}`, 12, ""},
+		{`{"action": "pinch", "x": 100, "y": 100}`, 0, "scale"},
+		{`{"action": "swipe", "x": 1, "y": 2, "steps": 1000}`, 0, "steps"},
+		{`{"action": "tap", "x": -1, "y": 2}`, 0, "negative"},
+		{`{"action": "press", "x": 1, "y": 2}`, 0, "unsupported action"},
+	}
+	for _, tt := range tests {
+		var in touchInput
+		if err := json.Unmarshal([]byte(tt.input), &in); err != nil {
+			t.Fatal(err)
+		}
+		actions, err := in.touchActions()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("touchActions(%s) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || len(actions) != tt.actions {
+			t.Errorf("touchActions(%s) = %d actions, %v; want %d", tt.input, len(actions), err, tt.actions)
+		}
+	}
+}
+
+func TestTouch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	html := `<body style="margin:0;height:100vh" ontouchstart="document.title='start:'+event.touches.length"` +
+		` ontouchend="document.title+=',end:'+event.changedTouches[0].clientY"></body>`
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html," + html})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+	if out := tools.resizeRun(ctx, []byte(`{"device": "iphone-15"}`)); out.Error != nil {
+		t.Fatalf("resizeRun error: %v", out.Error)
+	}
+
+	toolOut = tools.touchRun(ctx, []byte(`{"action": "swipe", "x": 100, "y": 300, "to_x": 100, "to_y": 50}`))
+	if toolOut.Error != nil {
+		t.Fatalf("touchRun error: %v", toolOut.Error)
+	}
+	toolOut = tools.evalRun(ctx, []byte(`{"expression": "document.title"}`))
+	if toolOut.Error != nil || !strings.Contains(toolOut.LLMContent[0].Text, "start:1,end:50") {
+		t.Errorf("Expected a swipe ending at y=50, got %v %v", toolOut.LLMContent, toolOut.Error)
+	}
+}
@@ -0,0 +1,262 @@
+package browse
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/tracing"
+	"github.com/chromedp/chromedp"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/google/uuid"
+	"shelley.exe.dev/llm"
+)
+
+// TraceDir is the directory where Chrome trace files are stored
+const TraceDir = "/tmp/shelley-traces"
+
+// LongTaskThreshold is the duration above which a main-thread task counts as a long task
+const LongTaskThreshold = 50 * time.Millisecond
+
+// defaultTraceCategories are recorded when the caller doesn't specify categories.
+// They match what DevTools' Performance panel records.
+var defaultTraceCategories = []string{
+	"-*",
+	"devtools.timeline",
+	"disabled-by-default-devtools.timeline",
+	"disabled-by-default-devtools.timeline.frame",
+	"toplevel",
+	"blink.console",
+	"blink.user_timing",
+	"loading",
+	"latencyInfo",
+	"v8.execute",
+	"disabled-by-default-v8.cpu_profiler",
+}
+
+// traceSession collects events for an in-progress trace
+type traceSession struct {
+	started time.Time
+	events  []jsontext.Value
+	done    chan struct{}
+}
+
+// StartTraceTool definition
+type startTraceInput struct {
+	Categories []string `json:"categories,omitempty" description:"Trace categories to record (default: the DevTools Performance panel categories)"`
+	Timeout    string   `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewStartTraceTool creates a tool for starting a Chrome trace
+func (b *BrowseTools) NewStartTraceTool() *llm.Tool {
+	return &llm.Tool{
+		Name: "browser_start_trace",
+		Description: `Start recording a Chrome performance trace of the current page.
+Perform the actions to investigate, then call browser_stop_trace to write the trace file and get a summary.`,
+		InputSchema: llm.SchemaFor[startTraceInput](),
+		Run:         b.startTraceRun,
+	}
+}
+
+func (b *BrowseTools) startTraceRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input startTraceInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	categories := input.Categories
+	if len(categories) == 0 {
+		categories = defaultTraceCategories
+	}
+
+	b.traceMutex.Lock()
+	defer b.traceMutex.Unlock()
+	if b.trace != nil {
+		return llm.ErrorfToolOut("a trace is already in progress; call browser_stop_trace first")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	err = chromedp.Run(timeoutCtx,
+		tracing.Start().
+			WithTransferMode(tracing.TransferModeReportEvents).
+			WithTraceConfig(&tracing.TraceConfig{IncludedCategories: categories}),
+	)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	b.trace = &traceSession{started: time.Now(), done: make(chan struct{})}
+	return llm.ToolOut{LLMContent: llm.TextContent("Tracing started.")}
+}
+
+// StopTraceTool definition
+type stopTraceInput struct {
+	Timeout string `json:"timeout,omitempty" description:"Timeout as a Go duration string (default: 15s)"`
+}
+
+// NewStopTraceTool creates a tool for stopping a Chrome trace
+func (b *BrowseTools) NewStopTraceTool() *llm.Tool {
+	return &llm.Tool{
+		Name:        "browser_stop_trace",
+		Description: "Stop the Chrome performance trace, write it to a JSON file (loadable in chrome://tracing or Perfetto), and summarize long tasks and main-thread time",
+		InputSchema: llm.SchemaFor[stopTraceInput](),
+		Run:         b.stopTraceRun,
+	}
+}
+
+func (b *BrowseTools) stopTraceRun(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input stopTraceInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("invalid input: %w", err)
+	}
+
+	b.traceMutex.Lock()
+	session := b.trace
+	b.traceMutex.Unlock()
+	if session == nil {
+		return llm.ErrorfToolOut("no trace in progress; call browser_start_trace first")
+	}
+	// Whether or not the trace ends cleanly, it is over, so another may start;
+	// one that already has (after a concurrent stop) is left alone
+	defer func() {
+		b.traceMutex.Lock()
+		if b.trace == session {
+			b.trace = nil
+		}
+		b.traceMutex.Unlock()
+	}()
+
+	browserCtx, err := b.GetBrowserContext()
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(browserCtx, parseTimeout(input.Timeout))
+	defer cancel()
+
+	if err := chromedp.Run(timeoutCtx, tracing.End()); err != nil {
+		return llm.ErrorToolOut(err)
+	}
+
+	select {
+	case <-session.done:
+	case <-timeoutCtx.Done():
+		return llm.ErrorfToolOut("timed out waiting for trace data: %w", timeoutCtx.Err())
+	}
+
+	data, err := json.Marshal(map[string]any{"traceEvents": session.events})
+	if err != nil {
+		return llm.ErrorfToolOut("failed to serialize trace: %w", err)
+	}
+	filePath := filepath.Join(TraceDir, fmt.Sprintf("trace_%s.json", uuid.New().String()[:8]))
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return llm.ErrorfToolOut("failed to write trace file: %w", err)
+	}
+
+	summary := summarizeTrace(session.events)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Trace (%d events, %s wall time, %d bytes) written to: %s\n",
+		len(session.events), time.Since(session.started).Round(time.Millisecond), len(data), filePath)
+	fmt.Fprintf(&sb, "Main thread busy: %s\n", summary.MainThreadTime.Round(time.Millisecond))
+	fmt.Fprintf(&sb, "Long tasks (>%s): %d, total %s", LongTaskThreshold, len(summary.LongTasks), summary.LongTaskTime().Round(time.Millisecond))
+	for _, d := range summary.LongTasks[:min(len(summary.LongTasks), 10)] {
+		fmt.Fprintf(&sb, "\n  - %s", d.Round(time.Millisecond))
+	}
+	if len(summary.LongTasks) > 10 {
+		fmt.Fprintf(&sb, "\n  ... and %d more", len(summary.LongTasks)-10)
+	}
+	return llm.ToolOut{LLMContent: llm.TextContent(sb.String())}
+}
+
+// handleTraceDataCollected appends a bucket of trace events to the current session
+func (b *BrowseTools) handleTraceDataCollected(e *tracing.EventDataCollected) {
+	b.traceMutex.Lock()
+	defer b.traceMutex.Unlock()
+	if b.trace != nil {
+		b.trace.events = append(b.trace.events, e.Value...)
+	}
+}
+
+// handleTraceComplete signals that all trace events have been delivered, and ends the session,
+// so that Chrome reporting completion again does not close its done channel twice
+func (b *BrowseTools) handleTraceComplete(e *tracing.EventTracingComplete) {
+	b.traceMutex.Lock()
+	defer b.traceMutex.Unlock()
+	if b.trace != nil {
+		close(b.trace.done)
+		b.trace = nil
+	}
+}
+
+// traceSummary is a digest of a trace's main-thread activity
+type traceSummary struct {
+	MainThreadTime time.Duration
+	LongTasks      []time.Duration // sorted longest first
+}
+
+// LongTaskTime returns the total duration of all long tasks
+func (s traceSummary) LongTaskTime() time.Duration {
+	var total time.Duration
+	for _, d := range s.LongTasks {
+		total += d
+	}
+	return total
+}
+
+// traceEvent is the subset of the Trace Event Format we inspect
+type traceEvent struct {
+	Name string  `json:"name"`
+	Ph   string  `json:"ph"`
+	Dur  float64 `json:"dur"` // microseconds
+	Pid  int     `json:"pid"`
+	Tid  int     `json:"tid"`
+	Args struct {
+		Name string `json:"name"`
+	} `json:"args"`
+}
+
+// summarizeTrace computes main-thread busy time and long tasks from raw trace events.
+// Renderer main threads are identified by their "CrRendererMain" thread_name metadata,
+// and busy time is the sum of their top-level RunTask durations.
+func summarizeTrace(raw []jsontext.Value) traceSummary {
+	type thread struct{ pid, tid int }
+	events := make([]traceEvent, 0, len(raw))
+	mainThreads := make(map[thread]bool)
+	for _, r := range raw {
+		var ev traceEvent
+		if err := json.Unmarshal(r, &ev); err != nil {
+			continue
+		}
+		if ev.Ph == "M" && ev.Name == "thread_name" && ev.Args.Name == "CrRendererMain" {
+			mainThreads[thread{ev.Pid, ev.Tid}] = true
+		}
+		events = append(events, ev)
+	}
+
+	var s traceSummary
+	for _, ev := range events {
+		if ev.Ph != "X" || ev.Name != "RunTask" || !mainThreads[thread{ev.Pid, ev.Tid}] {
+			continue
+		}
+		d := time.Duration(ev.Dur * float64(time.Microsecond))
+		s.MainThreadTime += d
+		if d > LongTaskThreshold {
+			s.LongTasks = append(s.LongTasks, d)
+		}
+	}
+	slices.SortFunc(s.LongTasks, func(a, b time.Duration) int { return cmp.Compare(b, a) })
+	return s
+}
@@ -0,0 +1,100 @@
+package browse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/tracing"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+func TestSummarizeTrace(t *testing.T) {
+	raw := []jsontext.Value{
+		jsontext.Value(`{"name":"thread_name","ph":"M","pid":1,"tid":10,"args":{"name":"CrRendererMain"}}`),
+		jsontext.Value(`{"name":"thread_name","ph":"M","pid":1,"tid":11,"args":{"name":"Compositor"}}`),
+		jsontext.Value(`{"name":"RunTask","ph":"X","pid":1,"tid":10,"dur":10000}`),
+		jsontext.Value(`{"name":"RunTask","ph":"X","pid":1,"tid":10,"dur":60000}`),
+		jsontext.Value(`{"name":"RunTask","ph":"X","pid":1,"tid":10,"dur":120000}`),
+		// Not on the main thread
+		jsontext.Value(`{"name":"RunTask","ph":"X","pid":1,"tid":11,"dur":500000}`),
+		// Not a top-level task
+		jsontext.Value(`{"name":"FunctionCall","ph":"X","pid":1,"tid":10,"dur":90000}`),
+		jsontext.Value(`not json`),
+	}
+
+	s := summarizeTrace(raw)
+	if s.MainThreadTime != 190*time.Millisecond {
+		t.Errorf("MainThreadTime = %v, want 190ms", s.MainThreadTime)
+	}
+	want := []time.Duration{120 * time.Millisecond, 60 * time.Millisecond}
+	if len(s.LongTasks) != len(want) {
+		t.Fatalf("LongTasks = %v, want %v", s.LongTasks, want)
+	}
+	for i := range want {
+		if s.LongTasks[i] != want[i] {
+			t.Errorf("LongTasks[%d] = %v, want %v", i, s.LongTasks[i], want[i])
+		}
+	}
+	if s.LongTaskTime() != 180*time.Millisecond {
+		t.Errorf("LongTaskTime() = %v, want 180ms", s.LongTaskTime())
+	}
+}
+
+func TestStopTraceWithoutStart(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	toolOut := tools.stopTraceRun(ctx, []byte(`{}`))
+	if toolOut.Error == nil {
+		t.Error("Expected error when stopping a trace that was never started")
+	}
+
+	toolOut = tools.startTraceRun(ctx, []byte(`{"categories": "not-an-array"}`))
+	if toolOut.Error == nil {
+		t.Error("Expected error for invalid JSON input")
+	}
+}
+
+func TestStopTraceFailureEndsTrace(t *testing.T) {
+	ctx := context.Background()
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	// Chrome, if there is one, has no trace to end, so stopping this one fails
+	tools.trace = &traceSession{started: time.Now(), done: make(chan struct{})}
+	toolOut := tools.stopTraceRun(ctx, []byte(`{"timeout": "5s"}`))
+	if toolOut.Error == nil {
+		t.Fatal("Expected error when stopping a trace Chrome never started")
+	}
+	tools.traceMutex.Lock()
+	defer tools.traceMutex.Unlock()
+	if tools.trace != nil {
+		t.Error("Expected the failed trace to be over, so another can start")
+	}
+}
+
+func TestTraceCompleteTwice(t *testing.T) {
+	tools := NewBrowseTools(context.Background(), 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+
+	session := &traceSession{started: time.Now(), done: make(chan struct{})}
+	tools.trace = session
+	tools.handleTraceComplete(&tracing.EventTracingComplete{})
+	tools.handleTraceComplete(&tracing.EventTracingComplete{})
+	select {
+	case <-session.done:
+	default:
+		t.Error("Expected the trace's done channel to be closed")
+	}
+	if tools.trace != nil {
+		t.Error("Expected the completed trace to be over, so another can start")
+	}
+}
@@ -0,0 +1,61 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// WebRTCPolicy controls what WebRTC may reveal about the host's network, e.g. the
+// real IP address of a browser that reaches the web through a proxy
+type WebRTCPolicy string
+
+const (
+	// WebRTCDefault leaves WebRTC as Chrome configures it
+	WebRTCDefault WebRTCPolicy = ""
+	// WebRTCProxyOnly sends WebRTC traffic only through the proxy, so no local or public address leaks
+	WebRTCProxyOnly WebRTCPolicy = "proxy"
+	// WebRTCDisabled also removes the WebRTC APIs from pages
+	WebRTCDisabled WebRTCPolicy = "disabled"
+)
+
+// ParseWebRTCPolicy parses "default", "proxy", or "disabled"
+func ParseWebRTCPolicy(s string) (WebRTCPolicy, error) {
+	switch p := WebRTCPolicy(s); p {
+	case "default":
+		return WebRTCDefault, nil
+	case WebRTCDefault, WebRTCProxyOnly, WebRTCDisabled:
+		return p, nil
+	}
+	return "", fmt.Errorf("unsupported WebRTC policy %q: must be default, proxy, or disabled", s)
+}
+
+// flags returns the Chrome flags for p
+func (p WebRTCPolicy) flags() ([]chromedp.ExecAllocatorOption, error) {
+	switch p {
+	case WebRTCDefault:
+		return nil, nil
+	case WebRTCProxyOnly, WebRTCDisabled:
+		return []chromedp.ExecAllocatorOption{
+			chromedp.Flag("force-webrtc-ip-handling-policy", "disable_non_proxied_udp"),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported WebRTC policy %q", p)
+}
+
+// disableWebRTCJS removes the WebRTC constructors, so pages see a browser without WebRTC
+const disableWebRTCJS = `(() => {
+	for (const name of ['RTCPeerConnection', 'webkitRTCPeerConnection', 'RTCDataChannel', 'RTCIceCandidate', 'RTCSessionDescription']) {
+		delete window[name];
+	}
+})()`
+
+// disableWebRTC removes the WebRTC APIs from documents loaded from now on
+func disableWebRTC(ctx context.Context) error {
+	if _, err := page.AddScriptToEvaluateOnNewDocument(disableWebRTCJS).Do(ctx); err != nil {
+		return fmt.Errorf("failed to add the WebRTC script: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package browse
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWebRTCPolicy(t *testing.T) {
+	for in, want := range map[string]WebRTCPolicy{
+		"":         WebRTCDefault,
+		"default":  WebRTCDefault,
+		"proxy":    WebRTCProxyOnly,
+		"disabled": WebRTCDisabled,
+	} {
+		if got, err := ParseWebRTCPolicy(in); err != nil || got != want {
+			t.Errorf("ParseWebRTCPolicy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseWebRTCPolicy("off"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+
+	if opts, err := WebRTCDefault.flags(); err != nil || len(opts) != 0 {
+		t.Errorf("WebRTCDefault.flags() = %d options, %v", len(opts), err)
+	}
+	if opts, err := WebRTCProxyOnly.flags(); err != nil || len(opts) != 1 {
+		t.Errorf("WebRTCProxyOnly.flags() = %d options, %v", len(opts), err)
+	}
+	if _, err := (LaunchOptions{WebRTC: "off"}).flags(); err == nil {
+		t.Error("Expected an error launching with an unknown policy")
+	}
+}
+
+func TestWebRTCDisabled(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tools := NewBrowseTools(ctx, 0)
+	t.Cleanup(func() {
+		tools.Close()
+	})
+	tools.SetLaunchOptions(LaunchOptions{WebRTC: WebRTCDisabled})
+
+	navInput, _ := json.Marshal(navigateInput{URL: "data:text/html,<p>rtc</p>"})
+	toolOut := tools.navigateRun(ctx, navInput)
+	if toolOut.Error != nil {
+		if strings.Contains(toolOut.Error.Error(), "failed to start browser") {
+			t.Skip("Browser automation not available in this environment")
+		}
+		t.Fatalf("Navigation error: %v", toolOut.Error)
+	}
+
+	out := tools.evalRun(ctx, []byte(`{"expression": "typeof RTCPeerConnection"}`))
+	if out.Error != nil || !strings.Contains(out.LLMContent[0].Text, "undefined") {
+		t.Errorf("Expected RTCPeerConnection removed, got %v %v", out.LLMContent, out.Error)
+	}
+}
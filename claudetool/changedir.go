@@ -61,7 +61,9 @@ func (c *ChangeDirTool) Tool() *llm.Tool {
 		Name:        changeDirName,
 		Description: changeDirDescription,
 		InputSchema: llm.MustSchema(changeDirInputSchema),
-		Run:         c.Run,
+		// The other tools run in the working directory, so they must not run while it changes
+		Exclusive: true,
+		Run:       c.Run,
 	}
 }
 
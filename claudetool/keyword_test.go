@@ -24,8 +24,8 @@ func (m *mockService) TokenContextWindow() int {
 	return 4096
 }
 
-func (m *mockService) MaxImageDimension() int {
-	return 0
+func (m *mockService) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{}
 }
 
 func (m *mockLLMProvider) GetService(modelID string) (llm.Service, error) {
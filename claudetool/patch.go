@@ -63,7 +63,11 @@ func (p *PatchTool) Tool() *llm.Tool {
 		Name:        PatchName,
 		Description: strings.TrimSpace(description),
 		InputSchema: llm.MustSchema(schema),
-		Run:         p.Run,
+		// patchParse accepts near misses of the schema
+		LenientInput: true,
+		// Patches share clipboards and edit the workspace bash commands build and commit
+		Serialize: WorkspaceSerialize,
+		Run:       p.Run,
 	}
 }
 
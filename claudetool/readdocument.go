@@ -0,0 +1,89 @@
+package claudetool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"shelley.exe.dev/llm"
+)
+
+// ReadDocumentTool hands the model a document, such as a PDF, to read whole, pages, figures and all.
+type ReadDocumentTool struct {
+	WorkingDir *MutableWorkingDir
+}
+
+const (
+	readDocumentName        = "read_document"
+	readDocumentDescription = `Read a PDF document, including its layout, tables, and figures.
+
+Use this for specs, papers, invoices, and other PDFs you need to understand.
+For plain text files, use bash instead.`
+
+	// maxDocumentBytes keeps a document, base64 encoded, within every provider's request size limit
+	maxDocumentBytes = 10 << 20
+	// maxDocumentPages is the most pages of a PDF every provider that accepts documents reads; Claude's limit
+	maxDocumentPages = 100
+)
+
+// pdfPage matches the dictionary of a page of a PDF, but not that of a node of its page tree (/Type /Pages).
+// Pages in compressed object streams are not matched, so pdfPageCount may undercount.
+var pdfPage = regexp.MustCompile(`/Type\s*/Page\b`)
+
+// pdfPageCount returns the number of pages of the PDF in data that can be found without decompressing it.
+func pdfPageCount(data []byte) int {
+	return len(pdfPage.FindAllIndex(data, -1))
+}
+
+type readDocumentInput struct {
+	Path string `json:"path" description:"Path to the PDF file. Relative paths are resolved from the working directory."`
+}
+
+// Tool returns an llm.Tool for reading documents.
+func (t *ReadDocumentTool) Tool() *llm.Tool {
+	return &llm.Tool{
+		Name:        readDocumentName,
+		Description: readDocumentDescription,
+		InputSchema: llm.SchemaFor[readDocumentInput](),
+		Run:         t.Run,
+	}
+}
+
+// Run executes the read_document tool.
+func (t *ReadDocumentTool) Run(ctx context.Context, m json.RawMessage) llm.ToolOut {
+	var input readDocumentInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return llm.ErrorfToolOut("failed to parse read_document input: %w", err)
+	}
+	path := input.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.WorkingDir.Get(), path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if info.Size() > maxDocumentBytes {
+		return llm.ErrorfToolOut("document is %d bytes, more than the %d that can be read; split it, e.g. with qpdf, and read the parts", info.Size(), maxDocumentBytes)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return llm.ErrorToolOut(err)
+	}
+	if mediaType := http.DetectContentType(data); mediaType != "application/pdf" {
+		return llm.ErrorfToolOut("%s is not a PDF (detected %s)", input.Path, mediaType)
+	}
+	if pages := pdfPageCount(data); pages > maxDocumentPages {
+		return llm.ErrorfToolOut("document has %d pages, more than the %d that can be read; split it, e.g. with qpdf --split-pages=%d, and read the parts", pages, maxDocumentPages, maxDocumentPages)
+	}
+
+	return llm.ToolOut{LLMContent: []llm.Content{
+		llm.StringContent(fmt.Sprintf("Document from %s (%d bytes)", path, len(data))),
+		llm.DocumentContent("application/pdf", data, filepath.Base(path)),
+	}}
+}
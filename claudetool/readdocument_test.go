@@ -0,0 +1,66 @@
+package claudetool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"shelley.exe.dev/llm"
+)
+
+func TestReadDocumentTool(t *testing.T) {
+	dir := t.TempDir()
+	pdf := []byte("%PDF-1.4\n%%EOF\n")
+	if err := os.WriteFile(filepath.Join(dir, "spec.pdf"), pdf, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tool := &ReadDocumentTool{WorkingDir: NewMutableWorkingDir(dir)}
+
+	input, _ := json.Marshal(readDocumentInput{Path: "spec.pdf"})
+	out := tool.Run(context.Background(), input)
+	if out.Error != nil {
+		t.Fatalf("unexpected error: %v", out.Error)
+	}
+	if len(out.LLMContent) != 2 {
+		t.Fatalf("expected a description and the document, got %+v", out.LLMContent)
+	}
+	if want := llm.DocumentContent("application/pdf", pdf, "spec.pdf"); !reflect.DeepEqual(out.LLMContent[1], want) {
+		t.Errorf("document = %+v, want %+v", out.LLMContent[1], want)
+	}
+
+	for _, path := range []string{"notes.txt", "missing.pdf"} {
+		input, _ := json.Marshal(readDocumentInput{Path: path})
+		if out := tool.Run(context.Background(), input); out.Error == nil {
+			t.Errorf("reading %s: expected an error", path)
+		}
+	}
+	input, _ = json.Marshal(readDocumentInput{Path: "notes.txt"})
+	if out := tool.Run(context.Background(), input); !strings.Contains(out.Error.Error(), "not a PDF") {
+		t.Errorf("reading a text file: got %v, want it to say it is not a PDF", out.Error)
+	}
+
+	// The page tree's node is not a page, so 100 pages are within the limit and 101 are not
+	for pages, wantErr := range map[int]bool{maxDocumentPages: false, maxDocumentPages + 1: true} {
+		book := "%PDF-1.4\n1 0 obj << /Type /Pages /Count " + strconv.Itoa(pages) + " >> endobj\n" +
+			strings.Repeat("2 0 obj << /Type /Page /Parent 1 0 R >> endobj\n", pages) + "%%EOF\n"
+		if err := os.WriteFile(filepath.Join(dir, "book.pdf"), []byte(book), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		input, _ := json.Marshal(readDocumentInput{Path: "book.pdf"})
+		out := tool.Run(context.Background(), input)
+		if gotErr := out.Error != nil; gotErr != wantErr {
+			t.Errorf("reading %d pages: got error %v, want error %v", pages, out.Error, wantErr)
+		}
+		if wantErr && out.Error != nil && !strings.Contains(out.Error.Error(), "101 pages") {
+			t.Errorf("reading %d pages: got %v, want it to give the page count", pages, out.Error)
+		}
+	}
+}
@@ -2,13 +2,21 @@ package claudetool
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
 
 	"shelley.exe.dev/claudetool/browse"
 	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/redact"
+	"shelley.exe.dev/llm/telemetry"
 )
 
+// WorkspaceSerialize is the llm.Tool Serialize of tools that change the workspace's files or depend on those changes,
+// so that calls to them in one response run in order.
+const WorkspaceSerialize = "workspace"
+
 // WorkingDir is a thread-safe mutable working directory.
 type MutableWorkingDir struct {
 	mu  sync.RWMutex
@@ -44,6 +52,16 @@ type ToolSetConfig struct {
 	EnableJITInstall bool
 	// EnableBrowser enables browser tools.
 	EnableBrowser bool
+	// EnableDocuments enables the read_document tool. Set it when the conversation's service
+	// accepts documents (see llm.AcceptsDocuments); others would only see a note in place of them.
+	EnableDocuments bool
+	// Middleware wraps the Run of every tool in the set, the first outermost.
+	Middleware []llm.ToolMiddleware
+	// Redactor, if set, scrubs secrets from tool outputs before they are sent to the model.
+	Redactor *redact.Redactor
+	// BrowserSessions, if set, gives each conversation its own browser session keyed by ConversationID.
+	// Otherwise each ToolSet starts a standalone browser.
+	BrowserSessions *browse.SessionManager
 	// ModelID is the model being used for this conversation.
 	// Used to determine tool configuration (e.g., simplified patch schema for weaker models).
 	ModelID string
@@ -72,21 +90,84 @@ type ToolSetConfig struct {
 
 // ToolSet holds a set of tools for a single conversation.
 // Each conversation should have its own ToolSet.
+// Tools may be added and removed while the conversation runs; it is safe for concurrent use.
 type ToolSet struct {
-	tools   []*llm.Tool
-	cleanup func()
-	wd      *MutableWorkingDir
+	cfg ToolSetConfig
+	wd  *MutableWorkingDir
+
+	mu       sync.Mutex
+	registry llm.ToolRegistry
+	browser  bool // whether the browser tools have been added
+	cleanup  func()
 }
 
-// Tools returns the tools in this set.
+// Tools returns the tools now in this set.
+// Call it again for each LLM request rather than keeping the result, as the set may change.
 func (ts *ToolSet) Tools() []*llm.Tool {
-	return ts.tools
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.registry.Tools()
+}
+
+// Add adds externally provided tools to the set under namespace, such as "mcp.github",
+// renamed to their llm.NamespacedName and wrapped in the set's middleware.
+// It adds none of them if any collides with a tool already in the set.
+func (ts *ToolSet) Add(namespace string, tools ...*llm.Tool) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.registry.Register(namespace, llm.Namespaced(namespace, tools)...)
+}
+
+// Remove removes the tools with the given names from the set.
+func (ts *ToolSet) Remove(names ...string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.registry.Unregister(names...)
+}
+
+// RemoveNamespace removes the tools of namespace from the set.
+func (ts *ToolSet) RemoveNamespace(namespace string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.registry.UnregisterNamespace(namespace)
+}
+
+// EnableBrowser adds the browser tools to the set if it does not have them yet,
+// such as once the user grants a conversation browser access. The browser lives until ctx is done or Cleanup.
+func (ts *ToolSet) EnableBrowser(ctx context.Context) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.browser {
+		return nil
+	}
+	var tools []*llm.Tool
+	var cleanup func()
+	if ts.cfg.BrowserSessions != nil && ts.cfg.ConversationID != "" {
+		session, err := ts.cfg.BrowserSessions.Session(ts.cfg.ConversationID)
+		if err != nil {
+			return fmt.Errorf("failed to create browser session: %w", err)
+		}
+		tools = session.GetTools(true)
+		cleanup = func() { ts.cfg.BrowserSessions.CloseSession(ts.cfg.ConversationID) }
+	} else {
+		tools, cleanup = browse.RegisterBrowserTools(ctx, true)
+	}
+	if err := ts.registry.Register("browser", tools...); err != nil {
+		cleanup()
+		return err
+	}
+	ts.browser = true
+	ts.cleanup = cleanup
+	return nil
 }
 
 // Cleanup releases resources held by the tools (e.g., browser).
 func (ts *ToolSet) Cleanup() {
-	if ts.cleanup != nil {
-		ts.cleanup()
+	ts.mu.Lock()
+	cleanup := ts.cleanup
+	ts.mu.Unlock()
+	if cleanup != nil {
+		cleanup()
 	}
 }
 
@@ -141,6 +222,11 @@ func NewToolSet(ctx context.Context, cfg ToolSetConfig) *ToolSet {
 		outputIframeTool.Tool(),
 	}
 
+	if cfg.EnableDocuments {
+		readDocumentTool := &ReadDocumentTool{WorkingDir: wd}
+		tools = append(tools, readDocumentTool.Tool())
+	}
+
 	// Add subagent tool if configured and depth limit not reached.
 	// MaxSubagentDepth of 0 means no limit; otherwise, only add if depth < max.
 	canSpawnSubagents := cfg.SubagentRunner != nil && cfg.SubagentDB != nil && cfg.ParentConversationID != ""
@@ -154,25 +240,25 @@ func NewToolSet(ctx context.Context, cfg ToolSetConfig) *ToolSet {
 		tools = append(tools, subagentTool.Tool())
 	}
 
-	var cleanup func()
-	if cfg.EnableBrowser {
-		// Get max image dimension from the LLM service
-		maxImageDimension := 0
-		if cfg.LLMProvider != nil && cfg.ModelID != "" {
-			if svc, err := cfg.LLMProvider.GetService(cfg.ModelID); err == nil {
-				maxImageDimension = svc.MaxImageDimension()
-			}
-		}
-		browserTools, browserCleanup := browse.RegisterBrowserTools(ctx, true, maxImageDimension)
-		if len(browserTools) > 0 {
-			tools = append(tools, browserTools...)
-		}
-		cleanup = browserCleanup
+	// Results too large to send whole go a page at a time, which the model fetches with fetch_more
+	pages := &llm.OutputPages{}
+	tools = append(tools, pages.Tool())
+	// Telemetry is outermost, so tool spans cover all the other middleware
+	middleware := append([]llm.ToolMiddleware{telemetry.ToolMiddleware}, cfg.Middleware...)
+	middleware = append(middleware, pages.Middleware)
+	if cfg.Redactor != nil {
+		// Innermost, so secrets are redacted before output is paged and none straddles a page
+		middleware = append(middleware, cfg.Redactor.Middleware)
 	}
 
-	return &ToolSet{
-		tools:   tools,
-		cleanup: cleanup,
-		wd:      wd,
+	ts := &ToolSet{cfg: cfg, wd: wd, registry: llm.ToolRegistry{Middleware: middleware}}
+	if err := ts.registry.Register("builtin", tools...); err != nil {
+		panic(err)
+	}
+	if cfg.EnableBrowser {
+		if err := ts.EnableBrowser(ctx); err != nil {
+			slog.ErrorContext(ctx, "failed to enable browser tools", "conversation_id", cfg.ConversationID, "error", err)
+		}
 	}
+	return ts
 }
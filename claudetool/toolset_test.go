@@ -2,7 +2,15 @@ package claudetool
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
+
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/redact"
 )
 
 func TestIsStrongModel(t *testing.T) {
@@ -48,7 +56,7 @@ func TestNewToolSet(t *testing.T) {
 		t.Error("Working directory not initialized")
 	}
 
-	if ts.tools == nil {
+	if len(ts.Tools()) == 0 {
 		t.Error("Tools not initialized")
 	}
 }
@@ -75,6 +83,111 @@ func TestToolSet_Tools(t *testing.T) {
 	}
 }
 
+func TestNewToolSet_Middleware(t *testing.T) {
+	var wrapped []string
+	cfg := ToolSetConfig{
+		LLMProvider: &mockLLMProvider{},
+		ModelID:     "test-model",
+		WorkingDir:  t.TempDir(),
+		Middleware: []llm.ToolMiddleware{func(tool *llm.Tool, next llm.ToolRunFunc) llm.ToolRunFunc {
+			wrapped = append(wrapped, tool.Name)
+			return func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+				return llm.ErrorfToolOut("%s blocked", tool.Name)
+			}
+		}},
+	}
+	ts := NewToolSet(context.Background(), cfg)
+
+	if len(wrapped) != len(ts.Tools()) {
+		t.Errorf("middleware wrapped %v, want all %d tools", wrapped, len(ts.Tools()))
+	}
+	for _, tool := range ts.Tools() {
+		if out := tool.Run(context.Background(), json.RawMessage(`{}`)); out.Error == nil || out.Error.Error() != tool.Name+" blocked" {
+			t.Errorf("%s: Run() = %v, want it to go through the middleware", tool.Name, out.Error)
+		}
+	}
+}
+
+func TestNewToolSet_Documents(t *testing.T) {
+	hasReadDocument := func(ts *ToolSet) bool {
+		for _, tool := range ts.Tools() {
+			if tool.Name == readDocumentName {
+				return true
+			}
+		}
+		return false
+	}
+	ctx := context.Background()
+	if hasReadDocument(NewToolSet(ctx, ToolSetConfig{WorkingDir: "/test"})) {
+		t.Error("expected no read_document tool for a service that does not accept documents")
+	}
+	if !hasReadDocument(NewToolSet(ctx, ToolSetConfig{WorkingDir: "/test", EnableDocuments: true})) {
+		t.Error("expected the read_document tool when documents are enabled")
+	}
+}
+
+func TestToolSet_AddRemove(t *testing.T) {
+	var wrapped []string
+	cfg := ToolSetConfig{
+		LLMProvider: &mockLLMProvider{},
+		ModelID:     "test-model",
+		WorkingDir:  t.TempDir(),
+		Middleware: []llm.ToolMiddleware{func(tool *llm.Tool, next llm.ToolRunFunc) llm.ToolRunFunc {
+			wrapped = append(wrapped, tool.Name)
+			return next
+		}},
+	}
+	ts := NewToolSet(context.Background(), cfg)
+	has := func(name string) bool {
+		return slices.ContainsFunc(ts.Tools(), func(t *llm.Tool) bool { return t.Name == name })
+	}
+
+	// External tools may share the names of built-in ones, as they are namespaced
+	if err := ts.Add("mcp.shell", &llm.Tool{Name: "bash", InputSchema: llm.EmptySchema()}, &llm.Tool{Name: "ls"}); err != nil {
+		t.Fatal(err)
+	}
+	if !has("bash") || !has("mcp_shell_bash") || wrapped[len(wrapped)-1] != "mcp_shell_ls" {
+		t.Errorf("Add() should add the tools namespaced and wrapped in the middleware; wrapped %v", wrapped)
+	}
+	if err := ts.Add("mcp.shell", &llm.Tool{Name: "cat"}, &llm.Tool{Name: "ls"}); err == nil || has("mcp_shell_cat") {
+		t.Errorf("Add() of a taken name = %v, want an error and nothing added", err)
+	}
+
+	ts.Remove("bash")
+	if has("bash") || !has("mcp_shell_bash") || !has("patch") {
+		t.Errorf("Remove() should remove only the named tool")
+	}
+	ts.RemoveNamespace("mcp.shell")
+	if has("mcp_shell_bash") || has("mcp_shell_ls") || !has("patch") {
+		t.Errorf("RemoveNamespace() should remove only the tools of the namespace")
+	}
+}
+
+func TestToolSet_Redactor(t *testing.T) {
+	ts := NewToolSet(context.Background(), ToolSetConfig{
+		LLMProvider: &mockLLMProvider{},
+		WorkingDir:  t.TempDir(),
+		Redactor:    redact.New([]string{"hunter2hunter2"}, nil),
+	})
+	// A secret at the end of a large output is redacted before the output is paged, so fetch_more doesn't reveal it
+	output := strings.Repeat("x", 2*llm.DefaultPageBytes) + "hunter2hunter2"
+	if err := ts.Add("test", &llm.Tool{Name: "leak", Run: func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+		return llm.ToolOut{LLMContent: llm.TextContent(output)}
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	tool := func(name string) *llm.Tool {
+		return ts.Tools()[slices.IndexFunc(ts.Tools(), func(t *llm.Tool) bool { return t.Name == name })]
+	}
+	first := tool("test_leak").Run(context.Background(), nil).LLMContent[0].Text
+	token := regexp.MustCompile(`token "([0-9a-f]+)"`).FindStringSubmatch(first)[1]
+	input := json.RawMessage(fmt.Sprintf(`{"token": %q, "offset": %d}`, token, 2*llm.DefaultPageBytes))
+	last := tool(llm.FetchMoreName).Run(context.Background(), input).LLMContent[0].Text
+	if strings.Contains(last, "hunter2") || !strings.Contains(last, redact.Placeholder) {
+		t.Errorf("last page = %q, want the secret redacted", last)
+	}
+}
+
 func TestToolSet_WorkingDir(t *testing.T) {
 	provider := &mockLLMProvider{}
 
@@ -153,7 +266,7 @@ func TestNewToolSet_WithBrowser(t *testing.T) {
 		t.Error("Working directory not initialized")
 	}
 
-	if ts.tools == nil {
+	if len(ts.Tools()) == 0 {
 		t.Error("Tools not initialized")
 	}
 }
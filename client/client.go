@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -118,6 +119,8 @@ func Run(args []string) {
 		fmt.Fprintf(fs.Output(), "  read     Read conversation messages\n")
 		fmt.Fprintf(fs.Output(), "  list     List conversations\n")
 		fmt.Fprintf(fs.Output(), "  archive  Archive a conversation\n")
+		fmt.Fprintf(fs.Output(), "  export   Export a conversation as JSON lines\n")
+		fmt.Fprintf(fs.Output(), "  import   Import an exported conversation\n")
 		fmt.Fprintf(fs.Output(), "  help     Print detailed help\n")
 	}
 	fs.Parse(args)
@@ -149,6 +152,10 @@ func Run(args []string) {
 		cmdList(cc, subArgs[1:])
 	case "archive":
 		cmdArchive(cc, subArgs[1:])
+	case "export":
+		cmdExport(cc, subArgs[1:])
+	case "import":
+		cmdImport(cc, subArgs[1:])
 	case "help":
 		cmdHelp()
 	default:
@@ -465,6 +472,98 @@ func cmdArchive(cc *clientConfig, args []string) {
 	fmt.Fprintf(os.Stderr, "Archived %s\n", conversationID)
 }
 
+func cmdExport(cc *clientConfig, args []string) {
+	fs := flag.NewFlagSet("client export", flag.ExitOnError)
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: shelley client export CONVERSATION_ID > FILE.jsonl\n")
+		os.Exit(1)
+	}
+	conversationID := fs.Arg(0)
+
+	client, baseURL, err := cc.newHTTPClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	req, err := cc.newRequest("GET", baseURL+"/api/conversation/"+conversationID+"/export", nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating request: %v\n", err)
+		os.Exit(1)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "Error: HTTP %d\n", resp.StatusCode)
+		os.Exit(1)
+	}
+
+	if _, err := io.Copy(os.Stdout, resp.Body); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading export: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func cmdImport(cc *clientConfig, args []string) {
+	fs := flag.NewFlagSet("client import", flag.ExitOnError)
+	fs.Parse(args)
+
+	var export []byte
+	var err error
+	if fs.NArg() > 0 {
+		export, err = os.ReadFile(fs.Arg(0))
+	} else {
+		export, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	client, baseURL, err := cc.newHTTPClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	req, err := cc.newRequest("POST", baseURL+"/api/conversations/import", strings.NewReader(string(export)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating request: %v\n", err)
+		os.Exit(1)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		fmt.Fprintf(os.Stderr, "Error: HTTP %d: %s\n", resp.StatusCode, strings.TrimSpace(string(body)))
+		os.Exit(1)
+	}
+
+	var c struct {
+		ConversationID string  `json:"conversation_id"`
+		Slug           *string `json:"slug"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&c); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
+		os.Exit(1)
+	}
+	json.NewEncoder(os.Stdout).Encode(c)
+}
+
 // --- Wire types for JSON parsing ---
 
 type streamResponseWire struct {
@@ -562,6 +661,15 @@ Subcommands:
   archive CONVERSATION_ID
       Archive a conversation.
 
+  export CONVERSATION_ID
+      Print the conversation, with its messages and subagents, as JSON lines,
+      to archive it, share it for debugging, or move it to another machine.
+      Screenshots are exported by their paths, not their contents.
+
+  import [FILE]
+      Import a conversation exported with export from FILE, or stdin.
+      Prints JSON with the new conversation_id to stdout.
+
   help
       Print this help text.
 
@@ -579,6 +687,10 @@ Examples:
   # Read current state
   shelley client read "$ID"
 
+  # Move a conversation to another server
+  shelley client export "$ID" > conversation.jsonl
+  shelley client -url http://otherhost:9000 import conversation.jsonl
+
 NOTE: This feature is EXPERIMENTAL and may change without notice.
 `, DefaultSocketPath())
 }
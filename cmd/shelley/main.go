@@ -10,10 +10,16 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"shelley.exe.dev/claudetool"
+	"shelley.exe.dev/claudetool/browse"
 	"shelley.exe.dev/client"
 	"shelley.exe.dev/db"
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/ratelimit"
+	"shelley.exe.dev/llm/redact"
+	"shelley.exe.dev/llm/retry"
 	"shelley.exe.dev/models"
 	"shelley.exe.dev/server"
 	_ "shelley.exe.dev/server/notifications/channels" // register channel types
@@ -87,6 +93,19 @@ func runServe(global GlobalConfig, args []string) {
 	systemdActivation := fs.Bool("systemd-activation", false, "Use systemd socket activation (listen on fd from systemd)")
 	requireHeader := fs.String("require-header", "", "Require this header on all API requests (e.g., X-Exedev-Userid)")
 	socketPath := fs.String("socket", client.DefaultSocketPath(), "Path to Unix socket for local CLI client access (set to 'none' to disable)")
+	browserAll := fs.Bool("browser", false, "Give every conversation the browser tools; otherwise each gets them once the user enables them")
+	browserPool := fs.Int("browser-pool", 0, "Number of browsers to keep pre-launched for new conversations (0 disables)")
+	browserPolicy := fs.String("browser-policy", "", "Path to a JSON browser navigation policy (allow, deny, schemes, deny_ports, block_private_networks, allow_private)")
+	browserStealth := fs.Bool("browser-stealth", false, "Make the browser look like desktop Chrome to sites that block headless browsers")
+	browserAutoScreenshot := fs.Bool("browser-auto-screenshot", false, "Attach a small screenshot to the output of every browser navigation, click, and typing action")
+	browserPolite := fs.Bool("browser-polite", false, "Make browser navigation honor robots.txt and wait between page loads on the same host")
+	browserPoliteDelay := fs.Duration("browser-polite-delay", time.Second, "Minimum time between page loads on the same host with -browser-polite")
+	browserFakeMedia := fs.Bool("browser-fake-media", false, "Give the browser a fake camera and microphone and accept getUserMedia without a prompt")
+	browserFakeVideo := fs.String("browser-fake-video", "", "Path to a .y4m or .mjpeg file to play as the fake camera with -browser-fake-media")
+	browserFakeAudio := fs.String("browser-fake-audio", "", "Path to a .wav file to play as the fake microphone with -browser-fake-media")
+	browserExtensions := fs.String("browser-extensions", "", "Comma-separated directories of unpacked Chrome extensions to load into the browser")
+	browserWebRTC := fs.String("browser-webrtc", "default", "WebRTC leak prevention for browsers behind a proxy: default, proxy (only send WebRTC through the proxy), or disabled (also remove the WebRTC APIs)")
+	redactSecrets := fs.Bool("redact-secrets", true, "Scrub secrets (values of secret environment variables, .netrc passwords, and API keys) from tool outputs before they are sent to the model")
 	fs.Parse(args)
 
 	logger := setupLogging(global.Debug)
@@ -107,10 +126,43 @@ func runServe(global GlobalConfig, args []string) {
 	availableModels := llmManager.GetAvailableModels()
 	logger.Info("Available models", "models", strings.Join(availableModels, ", "))
 
-	toolSetConfig := setupToolSetConfig(llmManager)
+	var launch browse.LaunchOptions
+	if *browserFakeMedia {
+		launch.FakeMedia = &browse.FakeMedia{VideoFile: *browserFakeVideo, AudioFile: *browserFakeAudio}
+	}
+	if *browserExtensions != "" {
+		launch.Extensions = strings.Split(*browserExtensions, ",")
+	}
+	webRTC, err := browse.ParseWebRTCPolicy(*browserWebRTC)
+	if err != nil {
+		logger.Error("Invalid -browser-webrtc", "error", err)
+		os.Exit(1)
+	}
+	launch.WebRTC = webRTC
+	toolSetConfig := setupToolSetConfig(llmManager, *browserAll, *browserPool, launch)
+	if *browserPolicy != "" {
+		policy, err := browse.LoadNavigationPolicy(*browserPolicy)
+		if err != nil {
+			logger.Error("Failed to load browser navigation policy", "error", err)
+			os.Exit(1)
+		}
+		toolSetConfig.BrowserSessions.SetNavigationPolicy(policy)
+	}
+	toolSetConfig.BrowserSessions.SetStealth(*browserStealth)
+	toolSetConfig.BrowserSessions.SetAutoScreenshot(*browserAutoScreenshot)
+	toolSetConfig.BrowserSessions.SetPolite(*browserPolite, *browserPoliteDelay)
+	if *redactSecrets {
+		redactor, err := redact.FromEnvironment()
+		if err != nil {
+			logger.Error("Failed to load secrets to redact", "error", err)
+			os.Exit(1)
+		}
+		toolSetConfig.Redactor = redactor
+	}
 
 	// Create server
 	svr := server.NewServer(database, llmManager, toolSetConfig, logger, global.PredictableOnly, llmConfig.TerminalURL, llmConfig.DefaultModel, *requireHeader, llmConfig.Links)
+	svr.SetConversationBudget(llmConfig.ConversationBudgetUSD)
 
 	// Seed notification channels from config file if DB is empty (one-time migration)
 	svr.SeedNotificationChannelsFromConfig(llmConfig.NotificationChannels)
@@ -123,7 +175,6 @@ func runServe(global GlobalConfig, args []string) {
 		effectiveSocket = ""
 	}
 
-	var err error
 	if *systemdActivation {
 		listener, listenerErr := systemdListener()
 		if listenerErr != nil {
@@ -248,17 +299,23 @@ func runVersion() {
 	}
 }
 
-func setupToolSetConfig(llmProvider claudetool.LLMServiceProvider) claudetool.ToolSetConfig {
+func setupToolSetConfig(llmProvider claudetool.LLMServiceProvider, browser bool, browserPool int, launch browse.LaunchOptions) claudetool.ToolSetConfig {
 	wd, err := os.Getwd()
 	if err != nil {
 		// Fallback to "/" if we can't get working directory
 		wd = "/"
 	}
+	browserSessions := browse.NewSessionManager(context.Background(), 0)
+	browserSessions.SetLaunchOptions(launch)
+	if browserPool > 0 {
+		browserSessions.UsePool(browse.NewBrowserPool(context.Background(), browserPool, launch))
+	}
 	return claudetool.ToolSetConfig{
 		WorkingDir:       wd,
 		LLMProvider:      llmProvider,
 		EnableJITInstall: claudetool.EnableBashToolJITInstall,
-		EnableBrowser:    true,
+		EnableBrowser:    browser,
+		BrowserSessions:  browserSessions,
 	}
 }
 
@@ -285,11 +342,21 @@ func buildLLMConfig(logger *slog.Logger, configPath, terminalURL, defaultModel s
 		}
 
 		var cfg struct {
-			LLMGateway           string           `json:"llm_gateway"`
-			TerminalURL          string           `json:"terminal_url"`
-			DefaultModel         string           `json:"default_model"`
-			Links                []server.Link    `json:"links"`
-			NotificationChannels []map[string]any `json:"notification_channels"`
+			LLMGateway           string                      `json:"llm_gateway"`
+			TerminalURL          string                      `json:"terminal_url"`
+			DefaultModel         string                      `json:"default_model"`
+			Links                []server.Link               `json:"links"`
+			NotificationChannels []map[string]any            `json:"notification_channels"`
+			ModelFallbacks       map[string][]string         `json:"model_fallbacks"`
+			ConversationBudget   float64                     `json:"conversation_budget_usd"`
+			RateLimits           map[string]ratelimit.Limits `json:"rate_limits"`
+			ResponseCacheDir     string                      `json:"response_cache_dir"`
+			ThinkingLevel        *llm.ThinkingLevel          `json:"thinking_level"`
+			LLMRetry             struct {
+				MaxAttempts      int     `json:"max_attempts"`
+				BaseDelaySeconds float64 `json:"base_delay_seconds"`
+				MaxDelaySeconds  float64 `json:"max_delay_seconds"`
+			} `json:"llm_retry"`
 		}
 		if err := json.Unmarshal(data, &cfg); err != nil {
 			logger.Warn("Failed to parse config file", "path", configPath, "error", err)
@@ -338,6 +405,37 @@ func buildLLMConfig(logger *slog.Logger, configPath, terminalURL, defaultModel s
 			llmCfg.NotificationChannels = cfg.NotificationChannels
 			logger.Info("Notification channels configured", "count", len(cfg.NotificationChannels))
 		}
+
+		if len(cfg.ModelFallbacks) > 0 {
+			llmCfg.ModelFallbacks = cfg.ModelFallbacks
+			logger.Info("Model fallbacks configured", "count", len(cfg.ModelFallbacks))
+		}
+
+		llmCfg.Retry = retry.Policy{
+			MaxAttempts: cfg.LLMRetry.MaxAttempts,
+			BaseDelay:   time.Duration(cfg.LLMRetry.BaseDelaySeconds * float64(time.Second)),
+			MaxDelay:    time.Duration(cfg.LLMRetry.MaxDelaySeconds * float64(time.Second)),
+		}
+
+		if len(cfg.RateLimits) > 0 {
+			llmCfg.RateLimits = cfg.RateLimits
+			logger.Info("Rate limits configured", "providers", len(cfg.RateLimits))
+		}
+
+		if cfg.ResponseCacheDir != "" {
+			llmCfg.ResponseCacheDir = cfg.ResponseCacheDir
+			logger.Info("Caching LLM responses", "dir", cfg.ResponseCacheDir)
+		}
+
+		if cfg.ThinkingLevel != nil {
+			llmCfg.ThinkingLevel = cfg.ThinkingLevel
+			logger.Info("Thinking level configured", "level", *cfg.ThinkingLevel)
+		}
+
+		if cfg.ConversationBudget > 0 {
+			llmCfg.ConversationBudgetUSD = cfg.ConversationBudget
+			logger.Info("Conversation budget configured", "usd", cfg.ConversationBudget)
+		}
 	}
 
 	return llmCfg
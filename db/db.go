@@ -43,6 +43,21 @@ type DB struct {
 	pool *Pool
 }
 
+// ConversationStore persists conversations as they run, so they can resume after a restart and be audited later.
+// A message's LLM data holds the tool calls and tool outputs of the exchange, and its display data the Display of a tool.
+// DB, backed by SQLite, is a ConversationStore; the server's conversation managers depend on this interface alone.
+type ConversationStore interface {
+	GetConversationByID(ctx context.Context, conversationID string) (*generated.Conversation, error)
+	UpdateConversationCwd(ctx context.Context, conversationID, cwd string) error
+	UpdateConversationModel(ctx context.Context, conversationID, model string) error
+	UpdateConversationTimestamp(ctx context.Context, conversationID string) error
+	CreateMessage(ctx context.Context, params CreateMessageParams) (*generated.Message, error)
+	ListMessages(ctx context.Context, conversationID string) ([]generated.Message, error)
+	ListMessagesForContext(ctx context.Context, conversationID string) ([]generated.Message, error)
+}
+
+var _ ConversationStore = (*DB)(nil)
+
 // Config holds database configuration
 type Config struct {
 	DSN string // Data Source Name for SQLite database
@@ -375,6 +390,13 @@ func (db *DB) UpdateConversationModel(ctx context.Context, conversationID, model
 	})
 }
 
+// UpdateConversationTimestamp sets the updated_at of a conversation to now.
+func (db *DB) UpdateConversationTimestamp(ctx context.Context, conversationID string) error {
+	return db.pool.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		return generated.New(tx.Conn()).UpdateConversationTimestamp(ctx, conversationID)
+	})
+}
+
 // Message methods (moved from MessageService)
 
 // MessageType represents the type of message
@@ -628,6 +650,18 @@ func (db *DB) SearchArchivedConversations(ctx context.Context, query string, lim
 	return conversations, err
 }
 
+// EnableConversationBrowser records that the user gave a conversation the browser tools
+func (db *DB) EnableConversationBrowser(ctx context.Context, conversationID string) (*generated.Conversation, error) {
+	var conversation generated.Conversation
+	err := db.pool.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		q := generated.New(tx.Conn())
+		var err error
+		conversation, err = q.EnableConversationBrowser(ctx, conversationID)
+		return err
+	})
+	return &conversation, err
+}
+
 // ArchiveConversation archives a conversation
 func (db *DB) ArchiveConversation(ctx context.Context, conversationID string) (*generated.Conversation, error) {
 	var conversation generated.Conversation
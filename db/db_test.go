@@ -414,6 +414,30 @@ func TestLLMRequestNoConversationID(t *testing.T) {
 	}
 }
 
+func TestCreateModelProviderTypes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	for _, providerType := range []string{"anthropic", "openai", "openai-responses", "gemini", "azure-openai"} {
+		_, err := db.CreateModel(ctx, generated.CreateModelParams{
+			ModelID:      "custom-" + providerType,
+			DisplayName:  providerType,
+			ProviderType: providerType,
+			Endpoint:     "https://example.com",
+			ApiKey:       "key",
+			ModelName:    "model",
+			MaxTokens:    200000,
+		})
+		if err != nil {
+			t.Errorf("CreateModel(%q) error = %v", providerType, err)
+		}
+	}
+	if _, err := db.CreateModel(ctx, generated.CreateModelParams{ModelID: "custom-bad", ProviderType: "bogus"}); err == nil {
+		t.Error("Expected CreateModel to reject an unknown provider type")
+	}
+}
+
 func safeDeref(s *string) string {
 	if s == nil {
 		return "<nil>"
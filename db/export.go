@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"shelley.exe.dev/db/generated"
+)
+
+// ExportVersion is the version of the conversation export format. ImportConversation refuses other versions.
+const ExportVersion = 1
+
+// ErrInvalidExport is wrapped by the errors ImportConversation returns for input that is not a valid export,
+// as opposed to failures of the database.
+var ErrInvalidExport = errors.New("invalid conversation export")
+
+// ExportRecord is a line of a conversation export, which is JSONL.
+// Each line is a conversation or a message. The first is the exported conversation, with the Version of the format;
+// its messages follow, in order, then each of its subagent conversations, followed by their messages.
+//
+// Screenshots and other files tools saved are exported by reference: the paths to them in display data, not their contents.
+type ExportRecord struct {
+	Version      int                     `json:"version,omitempty"`
+	Conversation *generated.Conversation `json:"conversation,omitempty"`
+	Message      *ExportedMessage        `json:"message,omitempty"`
+}
+
+// ExportedMessage is a message as exported, its JSON data inline rather than quoted.
+type ExportedMessage struct {
+	MessageID           string          `json:"message_id"`
+	ConversationID      string          `json:"conversation_id"`
+	SequenceID          int64           `json:"sequence_id"`
+	Type                string          `json:"type"`
+	LLMData             json.RawMessage `json:"llm_data,omitempty"`
+	UserData            json.RawMessage `json:"user_data,omitempty"`
+	UsageData           json.RawMessage `json:"usage_data,omitempty"`
+	DisplayData         json.RawMessage `json:"display_data,omitempty"`
+	CreatedAt           time.Time       `json:"created_at"`
+	ExcludedFromContext bool            `json:"excluded_from_context"`
+}
+
+// ExportConversation writes the conversation, its messages, and its subagent conversations to w as JSONL.
+func (db *DB) ExportConversation(ctx context.Context, w io.Writer, conversationID string) error {
+	conversation, err := db.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	return db.export(ctx, json.NewEncoder(w), ExportRecord{Version: ExportVersion, Conversation: conversation})
+}
+
+// export writes the conversation of rec, then its messages and subagents
+func (db *DB) export(ctx context.Context, enc *json.Encoder, rec ExportRecord) error {
+	if err := enc.Encode(rec); err != nil {
+		return err
+	}
+	id := rec.Conversation.ConversationID
+	messages, err := db.ListMessages(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, m := range messages {
+		if err := enc.Encode(ExportRecord{Message: &ExportedMessage{
+			MessageID:           m.MessageID,
+			ConversationID:      m.ConversationID,
+			SequenceID:          m.SequenceID,
+			Type:                m.Type,
+			LLMData:             rawJSON(m.LlmData),
+			UserData:            rawJSON(m.UserData),
+			UsageData:           rawJSON(m.UsageData),
+			DisplayData:         rawJSON(m.DisplayData),
+			CreatedAt:           m.CreatedAt,
+			ExcludedFromContext: m.ExcludedFromContext,
+		}}); err != nil {
+			return err
+		}
+	}
+	subagents, err := db.GetSubagents(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subagents {
+		if err := db.export(ctx, enc, ExportRecord{Conversation: &sub}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportConversation reads a conversation exported by ExportConversation from r and adds it to the database,
+// returning the imported conversation. Conversations and messages get new IDs, so a conversation may be imported
+// more than once, and a slug already taken gets a numeric suffix. Nothing is imported if r is not a valid export,
+// in which case the error wraps ErrInvalidExport, and the error reading r, if any.
+func (db *DB) ImportConversation(ctx context.Context, r io.Reader) (*generated.Conversation, error) {
+	var records []ExportRecord
+	dec := json.NewDecoder(r)
+	for {
+		var rec ExportRecord
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading record %d: %w", ErrInvalidExport, len(records)+1, err)
+		}
+		if (rec.Conversation == nil) == (rec.Message == nil) {
+			return nil, fmt.Errorf("%w: record %d has neither or both of a conversation and a message", ErrInvalidExport, len(records)+1)
+		}
+		if rec.Message != nil && !validMessageType(MessageType(rec.Message.Type)) {
+			return nil, fmt.Errorf("%w: record %d: unknown message type %q", ErrInvalidExport, len(records)+1, rec.Message.Type)
+		}
+		records = append(records, rec)
+	}
+	if len(records) == 0 || records[0].Conversation == nil {
+		return nil, fmt.Errorf("%w: it does not start with a conversation", ErrInvalidExport)
+	}
+	if records[0].Version != ExportVersion {
+		return nil, fmt.Errorf("%w: unsupported export version %d, want %d", ErrInvalidExport, records[0].Version, ExportVersion)
+	}
+
+	var imported generated.Conversation
+	err := db.pool.Tx(ctx, func(ctx context.Context, tx *Tx) error {
+		q := generated.New(tx.Conn())
+		ids := make(map[string]string) // new conversation IDs by exported ID
+		for i, rec := range records {
+			if c := rec.Conversation; c != nil {
+				var parent *string
+				if i > 0 {
+					if c.ParentConversationID != nil {
+						if id, ok := ids[*c.ParentConversationID]; ok {
+							parent = &id
+						}
+					}
+					if parent == nil {
+						return fmt.Errorf("%w: record %d: subagent conversation %s does not follow its parent", ErrInvalidExport, i+1, c.ConversationID)
+					}
+				}
+				id, err := generateConversationID()
+				if err != nil {
+					return fmt.Errorf("failed to generate conversation ID: %w", err)
+				}
+				slug, err := freeSlug(ctx, q, c.Slug)
+				if err != nil {
+					return err
+				}
+				conversation, err := q.ImportConversation(ctx, generated.ImportConversationParams{
+					ConversationID:       id,
+					Slug:                 slug,
+					UserInitiated:        c.UserInitiated,
+					CreatedAt:            c.CreatedAt,
+					UpdatedAt:            c.UpdatedAt,
+					Cwd:                  c.Cwd,
+					Archived:             c.Archived,
+					ParentConversationID: parent,
+					Model:                c.Model,
+				})
+				if err != nil {
+					return fmt.Errorf("record %d: %w", i+1, err)
+				}
+				if i == 0 {
+					imported = conversation
+				}
+				ids[c.ConversationID] = id
+				continue
+			}
+			m := rec.Message
+			id, ok := ids[m.ConversationID]
+			if !ok {
+				return fmt.Errorf("%w: record %d: message %s does not follow its conversation", ErrInvalidExport, i+1, m.MessageID)
+			}
+			if _, err := q.ImportMessage(ctx, generated.ImportMessageParams{
+				MessageID:           uuid.New().String(),
+				ConversationID:      id,
+				SequenceID:          m.SequenceID,
+				Type:                m.Type,
+				LlmData:             stringJSON(m.LLMData),
+				UserData:            stringJSON(m.UserData),
+				UsageData:           stringJSON(m.UsageData),
+				CreatedAt:           m.CreatedAt,
+				DisplayData:         stringJSON(m.DisplayData),
+				ExcludedFromContext: m.ExcludedFromContext,
+			}); err != nil {
+				return fmt.Errorf("record %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &imported, nil
+}
+
+// validMessageType reports whether t is one of the message types the messages table accepts
+func validMessageType(t MessageType) bool {
+	switch t {
+	case MessageTypeUser, MessageTypeAgent, MessageTypeTool, MessageTypeSystem, MessageTypeError, MessageTypeGitInfo:
+		return true
+	}
+	return false
+}
+
+// freeSlug returns slug, or if it is taken, slug with the lowest numeric suffix that is not
+func freeSlug(ctx context.Context, q *generated.Queries, slug *string) (*string, error) {
+	if slug == nil {
+		return nil, nil
+	}
+	candidate := *slug
+	for n := 2; ; n++ {
+		_, err := q.GetConversationBySlug(ctx, &candidate)
+		if errors.Is(err, sql.ErrNoRows) {
+			return &candidate, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		candidate = fmt.Sprintf("%s-%d", *slug, n)
+	}
+}
+
+func rawJSON(s *string) json.RawMessage {
+	if s == nil {
+		return nil
+	}
+	return json.RawMessage(*s)
+}
+
+func stringJSON(raw json.RawMessage) *string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExportImportConversation(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	conv, err := db.CreateConversation(ctx, stringPtr("fix-the-bug"), true, stringPtr("/src"), stringPtr("claude-opus-4.6"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := db.CreateSubagentConversation(ctx, "fix-the-bug-helper", conv.ConversationID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []CreateMessageParams{
+		{ConversationID: conv.ConversationID, Type: MessageTypeUser, LLMData: map[string]string{"text": "fix it"}, UserData: map[string]string{"text": "fix it"}},
+		{ConversationID: conv.ConversationID, Type: MessageTypeTool, LLMData: map[string]string{"text": "done"}, DisplayData: map[string]string{"path": "/tmp/shelley-screenshots/1.png"}, ExcludedFromContext: true},
+		{ConversationID: sub.ConversationID, Type: MessageTypeAgent, LLMData: map[string]string{"text": "helping"}, UsageData: map[string]int{"input_tokens": 5}},
+	} {
+		if _, err := db.CreateMessage(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var export bytes.Buffer
+	if err := db.ExportConversation(ctx, &export, conv.ConversationID); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(export.String(), "\n"); lines != 5 {
+		t.Errorf("export has %d lines, want one for each of 2 conversations and 3 messages:\n%s", lines, export.String())
+	}
+
+	// Importing into the same database gives new IDs and a free slug
+	imported, err := db.ImportConversation(ctx, bytes.NewReader(export.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.ConversationID == conv.ConversationID || *imported.Slug != "fix-the-bug-2" || *imported.Cwd != "/src" || *imported.Model != "claude-opus-4.6" {
+		t.Errorf("imported conversation = %+v", imported)
+	}
+	if !imported.CreatedAt.Equal(conv.CreatedAt) {
+		t.Errorf("imported created_at %v, want the original %v", imported.CreatedAt, conv.CreatedAt)
+	}
+	original, err := db.ListMessages(ctx, conv.ConversationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, err := db.ListMessages(ctx, imported.ConversationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != len(original) {
+		t.Fatalf("imported %d messages, want %d", len(messages), len(original))
+	}
+	for i, m := range messages {
+		o := original[i]
+		if m.MessageID == o.MessageID || m.SequenceID != o.SequenceID || m.Type != o.Type || *m.LlmData != *o.LlmData ||
+			m.ExcludedFromContext != o.ExcludedFromContext || !m.CreatedAt.Equal(o.CreatedAt) {
+			t.Errorf("imported message %d = %+v, want a copy of %+v", i, m, o)
+		}
+	}
+	if *messages[1].DisplayData != *original[1].DisplayData {
+		t.Errorf("imported display data %s, want %s", *messages[1].DisplayData, *original[1].DisplayData)
+	}
+	subagents, err := db.GetSubagents(ctx, imported.ConversationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subagents) != 1 || *subagents[0].Slug != "fix-the-bug-helper-2" {
+		t.Fatalf("imported subagents = %+v, want the helper", subagents)
+	}
+	if messages, err := db.ListMessages(ctx, subagents[0].ConversationID); err != nil || len(messages) != 1 || *messages[0].UsageData != `{"input_tokens":5}` {
+		t.Errorf("imported subagent messages = %+v, %v", messages, err)
+	}
+}
+
+func TestImportConversationInvalid(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	for name, export := range map[string]string{
+		"empty":         "",
+		"not json":      "not an export",
+		"no header":     `{"message":{"message_id":"m","conversation_id":"c","type":"user"}}`,
+		"wrong version": `{"version":99,"conversation":{"conversation_id":"c"}}`,
+		"orphan":        `{"version":1,"conversation":{"conversation_id":"c"}}` + "\n" + `{"message":{"message_id":"m","conversation_id":"other","type":"user"}}`,
+		"bad type":      `{"version":1,"conversation":{"conversation_id":"c"}}` + "\n" + `{"message":{"message_id":"m","conversation_id":"c","type":"bogus"}}`,
+	} {
+		if _, err := db.ImportConversation(ctx, strings.NewReader(export)); !errors.Is(err, ErrInvalidExport) {
+			t.Errorf("%s: got error %v, want ErrInvalidExport", name, err)
+		}
+	}
+	if n, err := db.ListConversations(ctx, 10, 0); err != nil || len(n) != 0 {
+		t.Errorf("failed imports left conversations %+v, %v; want none", n, err)
+	}
+}
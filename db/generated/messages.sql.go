@@ -7,6 +7,7 @@ package generated
 
 import (
 	"context"
+	"time"
 )
 
 const countMessagesByType = `-- name: CountMessagesByType :one
@@ -165,6 +166,54 @@ func (q *Queries) GetNextSequenceID(ctx context.Context, conversationID string)
 	return column_1, err
 }
 
+const importMessage = `-- name: ImportMessage :one
+INSERT INTO messages (message_id, conversation_id, sequence_id, type, llm_data, user_data, usage_data, created_at, display_data, excluded_from_context)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING message_id, conversation_id, sequence_id, type, llm_data, user_data, usage_data, created_at, display_data, excluded_from_context
+`
+
+type ImportMessageParams struct {
+	MessageID           string    `json:"message_id"`
+	ConversationID      string    `json:"conversation_id"`
+	SequenceID          int64     `json:"sequence_id"`
+	Type                string    `json:"type"`
+	LlmData             *string   `json:"llm_data"`
+	UserData            *string   `json:"user_data"`
+	UsageData           *string   `json:"usage_data"`
+	CreatedAt           time.Time `json:"created_at"`
+	DisplayData         *string   `json:"display_data"`
+	ExcludedFromContext bool      `json:"excluded_from_context"`
+}
+
+func (q *Queries) ImportMessage(ctx context.Context, arg ImportMessageParams) (Message, error) {
+	row := q.db.QueryRowContext(ctx, importMessage,
+		arg.MessageID,
+		arg.ConversationID,
+		arg.SequenceID,
+		arg.Type,
+		arg.LlmData,
+		arg.UserData,
+		arg.UsageData,
+		arg.CreatedAt,
+		arg.DisplayData,
+		arg.ExcludedFromContext,
+	)
+	var i Message
+	err := row.Scan(
+		&i.MessageID,
+		&i.ConversationID,
+		&i.SequenceID,
+		&i.Type,
+		&i.LlmData,
+		&i.UserData,
+		&i.UsageData,
+		&i.CreatedAt,
+		&i.DisplayData,
+		&i.ExcludedFromContext,
+	)
+	return i, err
+}
+
 const listMessages = `-- name: ListMessages :many
 SELECT message_id, conversation_id, sequence_id, type, llm_data, user_data, usage_data, created_at, display_data, excluded_from_context FROM messages
 WHERE conversation_id = ?
@@ -82,6 +82,12 @@ SET archived = FALSE, updated_at = CURRENT_TIMESTAMP
 WHERE conversation_id = ?
 RETURNING *;
 
+-- name: EnableConversationBrowser :one
+UPDATE conversations
+SET browser_enabled = TRUE
+WHERE conversation_id = ?
+RETURNING *;
+
 -- name: UpdateConversationCwd :one
 UPDATE conversations
 SET cwd = ?, updated_at = CURRENT_TIMESTAMP
@@ -94,6 +100,11 @@ INSERT INTO conversations (conversation_id, slug, user_initiated, cwd, parent_co
 VALUES (?, ?, FALSE, ?, ?)
 RETURNING *;
 
+-- name: ImportConversation :one
+INSERT INTO conversations (conversation_id, slug, user_initiated, created_at, updated_at, cwd, archived, parent_conversation_id, model)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
 -- name: GetSubagents :many
 SELECT * FROM conversations
 WHERE parent_conversation_id = ?
@@ -3,6 +3,11 @@ INSERT INTO messages (message_id, conversation_id, sequence_id, type, llm_data,
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
+-- name: ImportMessage :one
+INSERT INTO messages (message_id, conversation_id, sequence_id, type, llm_data, user_data, usage_data, created_at, display_data, excluded_from_context)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
 -- name: GetNextSequenceID :one
 SELECT COALESCE(MAX(sequence_id), 0) + 1 
 FROM messages 
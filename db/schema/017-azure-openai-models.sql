@@ -0,0 +1,22 @@
+-- Add 'azure-openai' to the model provider type check constraint
+-- SQLite doesn't support ALTER TABLE to modify CHECK constraints, so the table is recreated
+
+CREATE TABLE models_new (
+    model_id TEXT PRIMARY KEY,
+    display_name TEXT NOT NULL,
+    provider_type TEXT NOT NULL CHECK (provider_type IN ('anthropic', 'openai', 'openai-responses', 'gemini', 'azure-openai')),
+    endpoint TEXT NOT NULL,
+    api_key TEXT NOT NULL,
+    model_name TEXT NOT NULL,  -- The actual model name sent to the API (e.g., "claude-sonnet-4-5-20250514"), or the Azure deployment name
+    max_tokens INTEGER NOT NULL DEFAULT 200000,
+    tags TEXT NOT NULL DEFAULT '',  -- Comma-separated tags (e.g., "slug" for slug generation)
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO models_new (model_id, display_name, provider_type, endpoint, api_key, model_name, max_tokens, tags, created_at, updated_at)
+SELECT model_id, display_name, provider_type, endpoint, api_key, model_name, max_tokens, tags, created_at, updated_at FROM models;
+
+DROP TABLE models;
+
+ALTER TABLE models_new RENAME TO models;
@@ -0,0 +1,3 @@
+-- Add browser_enabled column to conversations
+-- This records that the user gave the conversation the browser tools
+ALTER TABLE conversations ADD COLUMN browser_enabled BOOLEAN NOT NULL DEFAULT FALSE;
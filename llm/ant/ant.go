@@ -6,12 +6,11 @@ import (
 	"cmp"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"math/rand/v2"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -76,10 +75,28 @@ func (s *Service) TokenContextWindow() int {
 	}
 }
 
-// MaxImageDimension returns the maximum allowed image dimension for multi-image requests.
-// Anthropic enforces a 2000 pixel limit when multiple images are in a conversation.
-func (s *Service) MaxImageDimension() int {
-	return 2000
+// ImageLimits returns Anthropic's image limits.
+// Images may be up to 8000 pixels on a side, but only 2000 when a request has more than 20 of them,
+// which conversations with screenshots soon do.
+func (s *Service) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{MaxDimension: 2000, MaxBytes: 5 * 1024 * 1024, MaxCount: 100}
+}
+
+// AcceptsDocuments reports that Claude reads documents, PDFs of up to 100 pages.
+func (s *Service) AcceptsDocuments() bool {
+	return true
+}
+
+// pricing is the list price of each model, used when no gateway reports the cost of requests.
+// See https://docs.anthropic.com/en/docs/about-claude/pricing
+var pricing = map[string]llm.Pricing{
+	Claude45Haiku:  {Input: 1, Output: 5, CacheWrite: 1.25, CacheRead: 0.1},
+	Claude37Sonnet: {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	Claude4Sonnet:  {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	Claude45Sonnet: {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	Claude46Sonnet: {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	Claude45Opus:   {Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.5},
+	Claude46Opus:   {Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.5},
 }
 
 // Service provides Claude completions.
@@ -111,7 +128,8 @@ type content struct {
 	// is somewhat acceptable but hard to read.
 	Text      *string         `json:"text,omitempty"`
 	MediaType string          `json:"media_type,omitempty"` // for image
-	Source    json.RawMessage `json:"source,omitempty"`     // for image
+	Source    json.RawMessage `json:"source,omitempty"`     // for image or document
+	Title     string          `json:"title,omitempty"`      // for document
 
 	// for thinking
 	Thinking  string `json:"thinking,omitempty"`
@@ -277,6 +295,8 @@ var (
 		llm.ContentTypeRedactedThinking: "redacted_thinking",
 		llm.ContentTypeToolUse:          "tool_use",
 		llm.ContentTypeToolResult:       "tool_result",
+		llm.ContentTypeDocument:         "document",
+		llm.ContentTypeImage:            "image",
 	}
 	toLLMContentType = inverted(fromLLMContentType)
 
@@ -308,17 +328,7 @@ func fromLLMContent(c llm.Content) content {
 	if len(c.ToolResult) > 0 {
 		toolResult = make([]content, len(c.ToolResult))
 		for i, tr := range c.ToolResult {
-			// For image content inside a tool_result, we need to map it to "image" type
-			if tr.MediaType != "" && tr.MediaType == "image/jpeg" || tr.MediaType == "image/png" {
-				// Format as an image for Claude
-				toolResult[i] = content{
-					Type: "image",
-					Source: json.RawMessage(fmt.Sprintf(`{"type":"base64","media_type":"%s","data":"%s"}`,
-						tr.MediaType, tr.Data)),
-				}
-			} else {
-				toolResult[i] = fromLLMContent(tr)
-			}
+			toolResult[i] = fromLLMContent(tr)
 		}
 	}
 
@@ -330,14 +340,12 @@ func fromLLMContent(c llm.Content) content {
 	// Set fields based on content type to avoid sending invalid fields
 	switch c.Type {
 	case llm.ContentTypeText:
-		// Images are represented as text with MediaType and Data
-		if c.MediaType != "" {
-			d.Type = "image"
-			d.Source = json.RawMessage(fmt.Sprintf(`{"type":"base64","media_type":"%s","data":"%s"}`,
-				c.MediaType, c.Data))
-		} else {
-			d.Text = &c.Text
-		}
+		d.Text = &c.Text
+	case llm.ContentTypeImage:
+		d.Source = base64Source(c)
+	case llm.ContentTypeDocument:
+		d.Source = base64Source(c)
+		d.Title = c.Text
 	case llm.ContentTypeThinking:
 		d.Thinking = c.Thinking
 		d.Signature = c.Signature
@@ -361,6 +369,11 @@ func fromLLMContent(c llm.Content) content {
 	return d
 }
 
+// base64Source returns the source of the image or document c
+func base64Source(c llm.Content) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"type":"base64","media_type":"%s","data":"%s"}`, c.MediaType, c.Data))
+}
+
 func fromLLMToolUse(tu *llm.ToolUse) *toolUse {
 	if tu == nil {
 		return nil
@@ -374,11 +387,17 @@ func fromLLMToolUse(tu *llm.ToolUse) *toolUse {
 func fromLLMMessage(msg llm.Message) message {
 	return message{
 		Role:    fromLLMRole[msg.Role],
-		Content: mapped(msg.Content, fromLLMContent),
+		Content: mapped(slices.DeleteFunc(slices.Clone(msg.Content), isForeignThinking), fromLLMContent),
 		ToolUse: fromLLMToolUse(msg.ToolUse),
 	}
 }
 
+// isForeignThinking reports whether c is thinking Claude did not produce, such as an OpenAI reasoning item,
+// which Claude cannot verify. Claude's thinking has a signature and no ID.
+func isForeignThinking(c llm.Content) bool {
+	return c.Type == llm.ContentTypeThinking && (c.Signature == "" || c.ID != "")
+}
+
 func fromLLMToolChoice(tc *llm.ToolChoice) *toolChoice {
 	if tc == nil {
 		return nil
@@ -419,8 +438,10 @@ func (s *Service) fromLLMRequest(r *llm.Request) *request {
 		System:     mapped(r.System, fromLLMSystem),
 	}
 
-	// Enable extended thinking if a thinking level is set
-	if s.ThinkingLevel != llm.ThinkingLevelOff {
+	// Enable extended thinking if a thinking level is set,
+	// unless the request forces a tool call, which the API does not allow with thinking
+	forcesTool := r.ToolChoice != nil && (r.ToolChoice.Type == llm.ToolChoiceTypeAny || r.ToolChoice.Type == llm.ToolChoiceTypeTool)
+	if s.ThinkingLevel != llm.ThinkingLevelOff && !forcesTool {
 		budget := s.ThinkingLevel.ThinkingBudgetTokens()
 		// Ensure max_tokens > budget_tokens as required by Anthropic API
 		if maxTokens <= budget {
@@ -499,6 +520,25 @@ type streamEvent struct {
 
 	// message_delta
 	Usage *usage `json:"usage,omitempty"`
+
+	// error
+	Error *struct {
+		Type string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+// errorStatus is the HTTP status of each type of error, for errors sent in a stream,
+// which says whether they are worth retrying.
+// See https://docs.anthropic.com/en/api/errors
+var errorStatus = map[string]int{
+	"invalid_request_error": http.StatusBadRequest,
+	"authentication_error":  http.StatusUnauthorized,
+	"permission_error":      http.StatusForbidden,
+	"not_found_error":       http.StatusNotFound,
+	"request_too_large":     http.StatusRequestEntityTooLarge,
+	"rate_limit_error":      http.StatusTooManyRequests,
+	"api_error":             http.StatusInternalServerError,
+	"overloaded_error":      529,
 }
 
 // streamDelta represents the delta field in content_block_delta and message_delta events.
@@ -522,8 +562,9 @@ type streamDelta struct {
 	StopSequence *string `json:"stop_sequence,omitempty"`
 }
 
-// parseSSEStream reads an SSE stream and assembles the complete response.
-func parseSSEStream(r io.Reader) (*response, error) {
+// parseSSEStream reads an SSE stream and assembles the complete response,
+// reporting each increment of content to onDelta as it arrives.
+func parseSSEStream(r io.Reader, onDelta llm.StreamFunc) (*response, error) {
 	var (
 		resp     *response
 		contents []content // indexed by content block index
@@ -556,6 +597,7 @@ func parseSSEStream(r io.Reader) (*response, error) {
 			}
 			resp = event.Message
 			resp.Content = nil // will be rebuilt from content blocks
+			onDelta(llm.StreamDelta{Reset: true})
 
 		case "content_block_start":
 			if event.ContentBlock == nil {
@@ -572,6 +614,11 @@ func parseSSEStream(r io.Reader) (*response, error) {
 				block.ToolInput = nil
 			}
 			contents[event.Index] = block
+			start := llm.StreamDelta{Index: event.Index, Type: toLLMContentType[block.Type], ID: block.ID, ToolName: block.ToolName, Text: block.Thinking}
+			if block.Text != nil {
+				start.Text = *block.Text
+			}
+			onDelta(start)
 
 		case "content_block_delta":
 			if event.Index >= len(contents) {
@@ -582,17 +629,24 @@ func parseSSEStream(r io.Reader) (*response, error) {
 				return nil, fmt.Errorf("parsing content_block_delta: %w", err)
 			}
 			c := &contents[event.Index]
+			update := llm.StreamDelta{Index: event.Index, Type: toLLMContentType[c.Type]}
 			switch delta.Type {
 			case "text_delta":
 				if c.Text == nil {
 					c.Text = new(string)
 				}
 				*c.Text += delta.Text
+				update.Text = delta.Text
+				onDelta(update)
 			case "thinking_delta":
 				c.Thinking += delta.Thinking
+				update.Text = delta.Thinking
+				onDelta(update)
 			case "input_json_delta":
 				// Accumulate raw JSON for tool_use input
 				c.ToolInput = append(c.ToolInput, []byte(delta.PartialJSON)...)
+				update.ToolInput = delta.PartialJSON
+				onDelta(update)
 			case "signature_delta":
 				c.Signature += delta.Signature
 			}
@@ -621,7 +675,13 @@ func parseSSEStream(r io.Reader) (*response, error) {
 			// keepalive, ignore
 
 		case "error":
-			return nil, fmt.Errorf("stream error event: %s", data)
+			err := fmt.Errorf("stream error event: %s", data)
+			if event.Error != nil {
+				if status, ok := errorStatus[event.Error.Type]; ok {
+					return nil, &llm.StatusError{StatusCode: status, Err: err}
+				}
+			}
+			return nil, err
 		}
 	}
 
@@ -648,9 +708,14 @@ func parseSSEStream(r io.Reader) (*response, error) {
 	return resp, nil
 }
 
-// Do sends a streaming request to Anthropic and collects the full response.
+// Do sends a streaming request to Anthropic and collects the full response,
+// reporting its deltas to the context's StreamFunc as they arrive.
 func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
 	startTime := time.Now()
+	ir, err := llm.FitImages(ir, s.ImageLimits())
+	if err != nil {
+		return nil, err
+	}
 	request := s.fromLLMRequest(ir)
 	request.Stream = true
 	payload, err := json.Marshal(request)
@@ -659,85 +724,58 @@ func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error
 	}
 	payload = append(payload, '\n')
 
-	backoff := []time.Duration{15 * time.Second, 30 * time.Second, time.Minute}
-
 	url := cmp.Or(s.URL, DefaultURL)
-	httpc := cmp.Or(s.HTTPC, http.DefaultClient)
+	resp, err := s.send(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-	// retry loop
-	var errs error // accumulated errors across all attempts
-	for attempts := 0; ; attempts++ {
-		if attempts > 10 {
-			return nil, fmt.Errorf("anthropic request failed after %d attempts: %w", attempts, errs)
-		}
-		if attempts > 0 {
-			sleep := backoff[min(attempts, len(backoff)-1)] + time.Duration(rand.Int64N(int64(time.Second)))
-			slog.WarnContext(ctx, "anthropic request sleep before retry", "sleep", sleep, "attempts", attempts)
-			time.Sleep(sleep)
-		}
-		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
-		if err != nil {
-			return nil, errors.Join(errs, err)
-		}
+	response, err := parseSSEStream(resp.Body, llm.StreamFuncFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	// Calculate and set the cost_usd field
+	response.Usage.CostUSD = llm.CostUSDFromResponse(resp.Header)
+
+	endTime := time.Now()
+	result := toLLMResponse(response)
+	if result.Usage.CostUSD == 0 {
+		result.Usage.CostUSD = pricing[cmp.Or(s.Model, DefaultModel)].Cost(result.Usage)
+	}
+	result.StartTime = &startTime
+	result.EndTime = &endTime
+	return result, nil
+}
 
+// send sends an API request with body, which is JSON if not nil, failing with a StatusError unless it succeeds.
+// The caller closes the response body.
+func (s *Service) send(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("X-API-Key", s.APIKey)
-		req.Header.Set("Anthropic-Version", "2023-06-01")
-
-		resp, err := httpc.Do(req)
-		if err != nil {
-			// Don't retry httprr cache misses
-			if strings.Contains(err.Error(), "cached HTTP response not found") {
-				return nil, err
-			}
-			errs = errors.Join(errs, err)
-			continue
-		}
+	}
+	req.Header.Set("X-API-Key", s.APIKey)
+	req.Header.Set("Anthropic-Version", "2023-06-01")
 
-		switch {
-		case resp.StatusCode == http.StatusOK:
-			response, err := parseSSEStream(resp.Body)
-			resp.Body.Close()
-			if err != nil {
-				// Stream parse errors might be transient (connection reset, etc.)
-				errs = errors.Join(errs, err)
-				continue
-			}
-			// Calculate and set the cost_usd field
-			response.Usage.CostUSD = llm.CostUSDFromResponse(resp.Header)
-
-			endTime := time.Now()
-			result := toLLMResponse(response)
-			result.StartTime = &startTime
-			result.EndTime = &endTime
-			return result, nil
-		default:
-			buf, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-
-			switch {
-			case resp.StatusCode >= 500 && resp.StatusCode < 600:
-				// server error, retry
-				slog.WarnContext(ctx, "anthropic_request_failed", "response", string(buf), "status_code", resp.StatusCode, "url", url, "model", s.Model)
-				errs = errors.Join(errs, fmt.Errorf("status %v (url=%s, model=%s): %s", resp.Status, url, cmp.Or(s.Model, DefaultModel), buf))
-				continue
-			case resp.StatusCode == 429:
-				// rate limited, retry
-				slog.WarnContext(ctx, "anthropic_request_rate_limited", "response", string(buf), "url", url, "model", s.Model)
-				errs = errors.Join(errs, fmt.Errorf("status %v (url=%s, model=%s): %s", resp.Status, url, cmp.Or(s.Model, DefaultModel), buf))
-				continue
-			case resp.StatusCode >= 400 && resp.StatusCode < 500:
-				// some other 400, probably unrecoverable
-				slog.WarnContext(ctx, "anthropic_request_failed", "response", string(buf), "status_code", resp.StatusCode, "url", url, "model", s.Model)
-				return nil, errors.Join(errs, fmt.Errorf("status %v (url=%s, model=%s): %s", resp.Status, url, cmp.Or(s.Model, DefaultModel), buf))
-			default:
-				// ...retry, I guess?
-				slog.WarnContext(ctx, "anthropic_request_failed", "response", string(buf), "status_code", resp.StatusCode, "url", url, "model", s.Model)
-				errs = errors.Join(errs, fmt.Errorf("status %v (url=%s, model=%s): %s", resp.Status, url, cmp.Or(s.Model, DefaultModel), buf))
-				continue
-			}
+	resp, err := cmp.Or(s.HTTPC, http.DefaultClient).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		buf, _ := io.ReadAll(resp.Body)
+		slog.WarnContext(ctx, "anthropic_request_failed", "response", string(buf), "status_code", resp.StatusCode, "url", url, "model", s.Model)
+		return nil, &llm.StatusError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("status %v (url=%s, model=%s): %s", resp.Status, url, cmp.Or(s.Model, DefaultModel), buf),
+			RetryAfter: llm.RetryAfter(resp.Header),
 		}
 	}
+	return resp, nil
 }
 
 // For debugging only, Claude can definitely handle the full patch tool.
@@ -15,7 +15,7 @@ func TestAnthropicImageToolResult(t *testing.T) {
 	}
 
 	imageContent := llm.Content{
-		Type:      llm.ContentTypeText, // Will be mapped to "image" in Anthropic format
+		Type:      llm.ContentTypeImage,
 		MediaType: "image/jpeg",
 		Data:      "/9j/4AAQSkZJRg...", // Shortened base64 encoded image
 	}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -83,11 +85,11 @@ func TestTokenContextWindow(t *testing.T) {
 	}
 }
 
-func TestMaxImageDimension(t *testing.T) {
+func TestImageLimits(t *testing.T) {
 	s := &Service{}
-	want := 2000
-	if got := s.MaxImageDimension(); got != want {
-		t.Errorf("MaxImageDimension() = %v, want %v", got, want)
+	want := llm.ImageLimits{MaxDimension: 2000, MaxBytes: 5 * 1024 * 1024, MaxCount: 100}
+	if got := s.ImageLimits(); got != want {
+		t.Errorf("ImageLimits() = %+v, want %+v", got, want)
 	}
 }
 
@@ -597,6 +599,17 @@ func TestFromLLMRequest(t *testing.T) {
 	}
 }
 
+func TestFromLLMRequestThinking(t *testing.T) {
+	s := &Service{ThinkingLevel: llm.ThinkingLevelMedium}
+	if got := s.fromLLMRequest(&llm.Request{}); got.Thinking == nil {
+		t.Error("expected thinking to be enabled")
+	}
+	forced := &llm.Request{ToolChoice: &llm.ToolChoice{Type: llm.ToolChoiceTypeTool, Name: "answer"}}
+	if got := s.fromLLMRequest(forced); got.Thinking != nil {
+		t.Error("expected thinking to be disabled for a request forcing a tool call")
+	}
+}
+
 func TestConfigDetails(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -726,17 +739,51 @@ func TestDo(t *testing.T) {
 	}
 }
 
+func TestDoCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		wantCost float64
+	}{
+		{"list price", nil, 0.00105}, // 100 input tokens at $3/MTok and 50 output tokens at $15/MTok
+		{"gateway", http.Header{"Skaband-Cost-Microcents": {"200000"}}, 0.002},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				APIKey: "test-key",
+				HTTPC: &http.Client{Transport: &mockHTTPTransport{
+					responseBody: mockSSEResponse("msg_123", Claude45Sonnet, "Hello, world!", 100, 50),
+					statusCode:   200,
+					header:       tt.header,
+				}},
+			}
+			resp, err := s.Do(context.Background(), &llm.Request{Messages: []llm.Message{llm.UserStringMessage("Hello, Claude!")}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := resp.Usage.CostUSD - tt.wantCost; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Usage.CostUSD = %v, want %v", resp.Usage.CostUSD, tt.wantCost)
+			}
+		})
+	}
+}
+
 // mockHTTPTransport is a mock HTTP transport for testing
 type mockHTTPTransport struct {
 	responseBody string
 	statusCode   int
+	header       http.Header // additional response headers
 }
 
 func (m *mockHTTPTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	resp := &http.Response{
 		StatusCode: m.statusCode,
 		Body:       io.NopCloser(strings.NewReader(m.responseBody)),
-		Header:     make(http.Header),
+		Header:     m.header.Clone(),
+	}
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
 	}
 	if m.statusCode == 200 {
 		resp.Header.Set("content-type", "text/event-stream")
@@ -851,9 +898,9 @@ func TestFromLLMContent(t *testing.T) {
 			},
 		},
 		{
-			name: "image content as text",
+			name: "image content",
 			c: llm.Content{
-				Type:      llm.ContentTypeText,
+				Type:      llm.ContentTypeImage,
 				MediaType: "image/jpeg",
 				Data:      "base64image",
 			},
@@ -892,7 +939,7 @@ func TestFromLLMContent(t *testing.T) {
 				ToolUseID: "tool-use-id",
 				ToolResult: []llm.Content{
 					{
-						Type:      llm.ContentTypeText,
+						Type:      llm.ContentTypeImage,
 						MediaType: "image/png",
 						Data:      "base64image",
 					},
@@ -1077,9 +1124,39 @@ func TestToLLMContentWithNestedToolResults(t *testing.T) {
 	}
 }
 
+// noDeltas discards stream deltas
+func noDeltas(llm.StreamDelta) {}
+
+func TestParseSSEStreamDeltas(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_d\",\"type\":\"message\",\"role\":\"assistant\",\"model\":\"test\",\"content\":[],\"stop_reason\":null,\"usage\":{\"input_tokens\":1,\"output_tokens\":0}}}\n\n")
+	b.WriteString(`data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}` + "\n\n")
+	b.WriteString(`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Listing"}}` + "\n\n")
+	b.WriteString(`data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"bash","input":{}}}` + "\n\n")
+	b.WriteString(`data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"command\":"}}` + "\n\n")
+	b.WriteString(`data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"ls\"}"}}` + "\n\n")
+	b.WriteString(`data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":3}}` + "\n\n")
+
+	var deltas []llm.StreamDelta
+	if _, err := parseSSEStream(strings.NewReader(b.String()), func(d llm.StreamDelta) { deltas = append(deltas, d) }); err != nil {
+		t.Fatalf("parseSSEStream() error = %v", err)
+	}
+	want := []llm.StreamDelta{
+		{Reset: true},
+		{Index: 0, Type: llm.ContentTypeText},
+		{Index: 0, Type: llm.ContentTypeText, Text: "Listing"},
+		{Index: 1, Type: llm.ContentTypeToolUse, ID: "toolu_1", ToolName: "bash"},
+		{Index: 1, Type: llm.ContentTypeToolUse, ToolInput: `{"command":`},
+		{Index: 1, Type: llm.ContentTypeToolUse, ToolInput: `"ls"}`},
+	}
+	if !reflect.DeepEqual(deltas, want) {
+		t.Errorf("deltas = %+v, want %+v", deltas, want)
+	}
+}
+
 func TestParseSSEStreamText(t *testing.T) {
 	stream := mockSSEResponse("msg_abc", Claude45Sonnet, "Hello!", 10, 5)
-	resp, err := parseSSEStream(strings.NewReader(stream))
+	resp, err := parseSSEStream(strings.NewReader(stream), noDeltas)
 	if err != nil {
 		t.Fatalf("parseSSEStream() error = %v", err)
 	}
@@ -1120,7 +1197,7 @@ func TestParseSSEStreamMultipleDeltas(t *testing.T) {
 	b.WriteString("event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},\"usage\":{\"output_tokens\":3}}\n\n")
 	b.WriteString("event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
 
-	resp, err := parseSSEStream(strings.NewReader(b.String()))
+	resp, err := parseSSEStream(strings.NewReader(b.String()), noDeltas)
 	if err != nil {
 		t.Fatalf("parseSSEStream() error = %v", err)
 	}
@@ -1147,7 +1224,7 @@ func TestParseSSEStreamToolUse(t *testing.T) {
 	b.WriteString("event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"tool_use\"},\"usage\":{\"output_tokens\":25}}\n\n")
 	b.WriteString("event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
 
-	resp, err := parseSSEStream(strings.NewReader(b.String()))
+	resp, err := parseSSEStream(strings.NewReader(b.String()), noDeltas)
 	if err != nil {
 		t.Fatalf("parseSSEStream() error = %v", err)
 	}
@@ -1198,7 +1275,7 @@ func TestParseSSEStreamToolUseEmptyInput(t *testing.T) {
 	b.WriteString("event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"tool_use\"},\"usage\":{\"output_tokens\":10}}\n\n")
 	b.WriteString("event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
 
-	resp, err := parseSSEStream(strings.NewReader(b.String()))
+	resp, err := parseSSEStream(strings.NewReader(b.String()), noDeltas)
 	if err != nil {
 		t.Fatalf("parseSSEStream() error = %v", err)
 	}
@@ -1236,7 +1313,7 @@ func TestParseSSEStreamThinking(t *testing.T) {
 	b.WriteString("event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},\"usage\":{\"output_tokens\":15}}\n\n")
 	b.WriteString("event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
 
-	resp, err := parseSSEStream(strings.NewReader(b.String()))
+	resp, err := parseSSEStream(strings.NewReader(b.String()), noDeltas)
 	if err != nil {
 		t.Fatalf("parseSSEStream() error = %v", err)
 	}
@@ -1271,7 +1348,7 @@ func TestParseSSEStreamPing(t *testing.T) {
 	b.WriteString("event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},\"usage\":{\"output_tokens\":1}}\n\n")
 	b.WriteString("event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
 
-	resp, err := parseSSEStream(strings.NewReader(b.String()))
+	resp, err := parseSSEStream(strings.NewReader(b.String()), noDeltas)
 	if err != nil {
 		t.Fatalf("parseSSEStream() error = %v", err)
 	}
@@ -1282,7 +1359,7 @@ func TestParseSSEStreamPing(t *testing.T) {
 
 func TestParseSSEStreamNoMessageStart(t *testing.T) {
 	stream := "event: content_block_start\ndata: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n"
-	_, err := parseSSEStream(strings.NewReader(stream))
+	_, err := parseSSEStream(strings.NewReader(stream), noDeltas)
 	if err == nil {
 		t.Fatal("expected error for missing message_start")
 	}
@@ -1296,13 +1373,16 @@ func TestParseSSEStreamError(t *testing.T) {
 	b.WriteString("event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_err\",\"type\":\"message\",\"role\":\"assistant\",\"model\":\"test\",\"content\":[],\"stop_reason\":null,\"usage\":{\"input_tokens\":1,\"output_tokens\":0}}}\n\n")
 	b.WriteString(`event: error` + "\n" + `data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}` + "\n\n")
 
-	_, err := parseSSEStream(strings.NewReader(b.String()))
+	_, err := parseSSEStream(strings.NewReader(b.String()), noDeltas)
 	if err == nil {
 		t.Fatal("expected error for stream error event")
 	}
 	if !strings.Contains(err.Error(), "stream error event") {
 		t.Errorf("error = %q, want to contain %q", err.Error(), "stream error event")
 	}
+	if !llm.IsTransient(err) {
+		t.Errorf("expected overloaded error to be transient, got %v", err)
+	}
 }
 
 func TestDoClientError(t *testing.T) {
@@ -1459,3 +1539,43 @@ func TestDoStartTimeEndTime(t *testing.T) {
 		}
 	}
 }
+
+func TestFromLLMContentDocument(t *testing.T) {
+	doc := llm.DocumentContent("application/pdf", []byte("%PDF-1.4"), "spec.pdf")
+	for _, c := range []llm.Content{doc, {Type: llm.ContentTypeToolResult, ToolUseID: "t1", ToolResult: []llm.Content{doc}}} {
+		got, err := json.Marshal(fromLLMContent(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"type":"document","source":{"type":"base64","media_type":"application/pdf","data":"JVBERi0xLjQ="},"title":"spec.pdf"}`
+		if c.Type == llm.ContentTypeToolResult {
+			want = `{"type":"tool_result","tool_use_id":"t1","content":[` + want + `]}`
+		}
+		if string(got) != want {
+			t.Errorf("fromLLMContent(%v) = %s, want %s", c.Type, got, want)
+		}
+	}
+}
+
+func TestFromLLMMessageForeignThinking(t *testing.T) {
+	msg := fromLLMMessage(llm.Message{
+		Role: llm.MessageRoleAssistant,
+		Content: []llm.Content{
+			{Type: llm.ContentTypeThinking, Thinking: "Claude's thoughts", Signature: "sig"},
+			{ID: "rs_1", Type: llm.ContentTypeThinking, Thinking: "OpenAI reasoning", Signature: "encrypted"},
+			{Type: llm.ContentTypeThinking, Thinking: "unsigned thoughts"},
+			{Type: llm.ContentTypeRedactedThinking, Data: "redacted"},
+			llm.StringContent("Hello"),
+		},
+	})
+	var types []string
+	for _, c := range msg.Content {
+		types = append(types, c.Type)
+	}
+	if want := []string{"thinking", "redacted_thinking", "text"}; !slices.Equal(types, want) {
+		t.Errorf("content types = %v, want %v, keeping only Claude's thinking", types, want)
+	}
+	if msg.Content[0].Thinking != "Claude's thoughts" {
+		t.Errorf("kept thinking %q, want Claude's", msg.Content[0].Thinking)
+	}
+}
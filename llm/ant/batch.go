@@ -0,0 +1,170 @@
+package ant
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"shelley.exe.dev/llm"
+)
+
+var _ llm.Batcher = (*Service)(nil)
+
+// https://docs.anthropic.com/en/api/creating-message-batches
+
+type batchRequest struct {
+	CustomID string   `json:"custom_id"`
+	Params   *request `json:"params"`
+}
+
+type messageBatch struct {
+	ID               string `json:"id"`
+	ProcessingStatus string `json:"processing_status"`
+	RequestCounts    struct {
+		Processing int `json:"processing"`
+		Succeeded  int `json:"succeeded"`
+		Errored    int `json:"errored"`
+		Canceled   int `json:"canceled"`
+		Expired    int `json:"expired"`
+	} `json:"request_counts"`
+	CreatedAt  time.Time  `json:"created_at"`
+	EndedAt    *time.Time `json:"ended_at"`
+	ResultsURL string     `json:"results_url"`
+}
+
+// batchResult is a line of the results of a batch
+type batchResult struct {
+	CustomID string `json:"custom_id"`
+	Result   struct {
+		Type    string    `json:"type"` // succeeded, errored, canceled, or expired
+		Message *response `json:"message"`
+		Error   *struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"error"`
+	} `json:"result"`
+}
+
+// batchesURL returns the URL of the Message Batches API, which is under that of the Messages API
+func (s *Service) batchesURL() string {
+	return cmp.Or(s.URL, DefaultURL) + "/batches"
+}
+
+// SubmitBatch submits reqs as a Message Batch.
+func (s *Service) SubmitBatch(ctx context.Context, reqs []llm.BatchRequest) (*llm.Batch, error) {
+	body := struct {
+		Requests []batchRequest `json:"requests"`
+	}{}
+	for _, r := range reqs {
+		ir, err := llm.FitImages(r.Request, s.ImageLimits())
+		if err != nil {
+			return nil, fmt.Errorf("batch request %s: %w", r.ID, err)
+		}
+		body.Requests = append(body.Requests, batchRequest{CustomID: r.ID, Params: s.fromLLMRequest(ir)})
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return s.batchDo(ctx, "POST", s.batchesURL(), payload)
+}
+
+// Batch returns the current state of the Message Batch with the given ID.
+func (s *Service) Batch(ctx context.Context, id string) (*llm.Batch, error) {
+	return s.batchDo(ctx, "GET", s.batchesURL()+"/"+id, nil)
+}
+
+// CancelBatch cancels the Message Batch with the given ID.
+func (s *Service) CancelBatch(ctx context.Context, id string) (*llm.Batch, error) {
+	return s.batchDo(ctx, "POST", s.batchesURL()+"/"+id+"/cancel", nil)
+}
+
+func (s *Service) batchDo(ctx context.Context, method, url string, payload []byte) (*llm.Batch, error) {
+	mb, err := s.messageBatch(ctx, method, url, payload)
+	if err != nil {
+		return nil, err
+	}
+	batch := &llm.Batch{
+		ID:        mb.ID,
+		Status:    llm.BatchStatus(mb.ProcessingStatus),
+		Counts:    llm.BatchCounts(mb.RequestCounts),
+		CreatedAt: mb.CreatedAt,
+	}
+	if mb.EndedAt != nil {
+		batch.EndedAt = *mb.EndedAt
+	}
+	return batch, nil
+}
+
+func (s *Service) messageBatch(ctx context.Context, method, url string, payload []byte) (*messageBatch, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	resp, err := s.send(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var mb messageBatch
+	if err := json.NewDecoder(resp.Body).Decode(&mb); err != nil {
+		return nil, fmt.Errorf("decoding message batch: %w", err)
+	}
+	return &mb, nil
+}
+
+// BatchResults returns the results of the ended Message Batch with the given ID.
+// Their cost is half that of the same requests sent one at a time.
+func (s *Service) BatchResults(ctx context.Context, id string) ([]llm.BatchResult, error) {
+	mb, err := s.messageBatch(ctx, "GET", s.batchesURL()+"/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if mb.ResultsURL == "" {
+		return nil, fmt.Errorf("batch %s has no results yet; it is %s", id, mb.ProcessingStatus)
+	}
+	resp, err := s.send(ctx, "GET", mb.ResultsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var results []llm.BatchResult
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		var br batchResult
+		if err := json.Unmarshal(scanner.Bytes(), &br); err != nil {
+			return nil, fmt.Errorf("decoding batch result: %w", err)
+		}
+		results = append(results, s.toLLMBatchResult(&br))
+	}
+	return results, scanner.Err()
+}
+
+func (s *Service) toLLMBatchResult(br *batchResult) llm.BatchResult {
+	result := llm.BatchResult{ID: br.CustomID}
+	switch {
+	case br.Result.Type == "succeeded" && br.Result.Message != nil:
+		result.Response = toLLMResponse(br.Result.Message)
+		result.Response.Usage.CostUSD = pricing[cmp.Or(s.Model, DefaultModel)].Cost(result.Response.Usage) / 2
+	case br.Result.Type == "errored" && br.Result.Error != nil:
+		e := br.Result.Error.Error
+		result.Error = &llm.StatusError{
+			StatusCode: cmp.Or(errorStatus[e.Type], http.StatusInternalServerError),
+			Err:        fmt.Errorf("%s: %s", e.Type, e.Message),
+		}
+	default:
+		result.Error = errors.New("batch request " + br.Result.Type)
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+package ant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"shelley.exe.dev/llm"
+)
+
+func TestBatch(t *testing.T) {
+	polls := 0
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	batchJSON := func(status, resultsURL string) string {
+		return fmt.Sprintf(`{"id":"msgbatch_1","type":"message_batch","processing_status":%q,"created_at":"2025-01-02T03:04:05Z",
+			"request_counts":{"processing":0,"succeeded":1,"errored":1,"canceled":0,"expired":1},"results_url":%q}`, status, resultsURL)
+	}
+	mux.HandleFunc("POST /v1/messages/batches", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []struct {
+				CustomID string          `json:"custom_id"`
+				Params   json.RawMessage `json:"params"`
+			} `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		if r.Header.Get("X-API-Key") != "test-key" || len(body.Requests) != 2 || body.Requests[1].CustomID != "page-2" {
+			t.Errorf("unexpected batch submission: %+v", body)
+		}
+		fmt.Fprint(w, batchJSON("in_progress", ""))
+	})
+	mux.HandleFunc("GET /v1/messages/batches/msgbatch_1", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls == 1 {
+			fmt.Fprint(w, batchJSON("in_progress", ""))
+			return
+		}
+		fmt.Fprint(w, batchJSON("ended", srv.URL+"/results"))
+	})
+	mux.HandleFunc("GET /results", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"custom_id":"page-1","result":{"type":"succeeded","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4-5-20250929",`+
+			`"content":[{"type":"text","text":"A summary"}],"stop_reason":"end_turn","usage":{"input_tokens":1000,"output_tokens":100}}}}`)
+		fmt.Fprintln(w, `{"custom_id":"page-2","result":{"type":"errored","error":{"type":"error","error":{"type":"invalid_request_error","message":"bad"}}}}`)
+		fmt.Fprintln(w, `{"custom_id":"page-3","result":{"type":"expired"}}`)
+	})
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	s := &Service{APIKey: "test-key", URL: srv.URL + "/v1/messages"}
+	ctx := context.Background()
+	batch, err := s.SubmitBatch(ctx, []llm.BatchRequest{
+		{ID: "page-1", Request: &llm.Request{Messages: []llm.Message{llm.UserStringMessage("Summarize page 1")}}},
+		{ID: "page-2", Request: &llm.Request{Messages: []llm.Message{llm.UserStringMessage("Summarize page 2")}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.ID != "msgbatch_1" || batch.Status != llm.BatchInProgress || batch.Counts.Expired != 1 || batch.CreatedAt.Year() != 2025 {
+		t.Errorf("unexpected batch: %+v", batch)
+	}
+
+	results, err := llm.WaitBatch(ctx, s, batch.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 {
+		t.Errorf("polled %d times, want 2 until the batch ended and 1 for its results", polls)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if r := results[0]; r.ID != "page-1" || r.Error != nil || r.Response.Content[0].Text != "A summary" {
+		t.Errorf("unexpected successful result: %+v", r)
+	}
+	// Batches cost half the list price: 1000 input tokens at $3/MTok and 100 output tokens at $15/MTok, halved
+	if cost := results[0].Response.Usage.CostUSD; cost < 0.00224 || cost > 0.00226 {
+		t.Errorf("cost = %v, want 0.00225", cost)
+	}
+	var se *llm.StatusError
+	if r := results[1]; r.ID != "page-2" || !errors.As(r.Error, &se) || se.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected errored result: %+v", r)
+	}
+	if r := results[2]; r.ID != "page-3" || r.Error == nil || r.Response != nil {
+		t.Errorf("unexpected expired result: %+v", r)
+	}
+}
+
+func TestBatchResultsBeforeEnd(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"msgbatch_1","processing_status":"in_progress","results_url":null}`)
+	}))
+	defer srv.Close()
+	s := &Service{APIKey: "test-key", URL: srv.URL}
+	if _, err := s.BatchResults(context.Background(), "msgbatch_1"); err == nil {
+		t.Error("expected an error for the results of a batch in progress")
+	}
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Batcher is implemented by services that can send many requests as one batch job,
+// which the provider answers within a day at a discount. Batches suit work nobody is waiting on,
+// such as summarizing the many pages a crawl found.
+//
+// Anthropic models implement it, as do OpenAI Chat Completions models served by the OpenAI API.
+// Gemini, the OpenAI Responses API, and other OpenAI-compatible providers do not.
+// Nothing in shelley submits batches yet; callers get a Batcher from models.Manager.GetBatcher.
+type Batcher interface {
+	// SubmitBatch submits reqs as a batch job.
+	SubmitBatch(ctx context.Context, reqs []BatchRequest) (*Batch, error)
+	// Batch returns the current state of the batch with the given ID.
+	Batch(ctx context.Context, id string) (*Batch, error)
+	// BatchResults returns the results of the ended batch with the given ID, in no particular order.
+	BatchResults(ctx context.Context, id string) ([]BatchResult, error)
+	// CancelBatch asks the provider to stop processing the batch with the given ID.
+	// Requests already answered still have results.
+	CancelBatch(ctx context.Context, id string) (*Batch, error)
+}
+
+// BatchRequest is one request of a batch.
+type BatchRequest struct {
+	ID      string // identifies the request's result; unique within the batch
+	Request *Request
+}
+
+// BatchStatus is the processing status of a batch.
+type BatchStatus string
+
+const (
+	BatchInProgress BatchStatus = "in_progress"
+	BatchCanceling  BatchStatus = "canceling"
+	BatchEnded      BatchStatus = "ended" // every request succeeded, failed, was canceled, or expired
+)
+
+// Batch is the state of a batch job.
+type Batch struct {
+	ID        string
+	Status    BatchStatus
+	Counts    BatchCounts
+	CreatedAt time.Time
+	EndedAt   time.Time // zero until the batch ends
+}
+
+// BatchCounts counts the requests of a batch by their state.
+type BatchCounts struct {
+	Processing int
+	Succeeded  int
+	Errored    int
+	Canceled   int
+	Expired    int
+}
+
+// BatchResult is the outcome of one request of a batch: its Response, or the Error it failed with.
+type BatchResult struct {
+	ID       string // the ID of the BatchRequest
+	Response *Response
+	Error    error
+}
+
+// WaitBatch polls the batch with the given ID every interval until it ends, then returns its results.
+func WaitBatch(ctx context.Context, b Batcher, id string, interval time.Duration) ([]BatchResult, error) {
+	for {
+		batch, err := b.Batch(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("polling batch %s: %w", id, err)
+		}
+		if batch.Status == BatchEnded {
+			return b.BatchResults(ctx, id)
+		}
+		if err := Sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
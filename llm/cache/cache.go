@@ -0,0 +1,122 @@
+// Package cache provides an llm.Service that answers requests it has seen before from a cache on disk,
+// so re-running a scripted scenario during development does not pay for every request again.
+//
+// Responses are keyed by the model, the service's settings that change its responses, such as its thinking level,
+// and everything about the request the model sees: its messages, tools, and system prompt.
+// A changed prompt or tool description is a new key, so stale entries are never served, only left behind;
+// delete the directory to clear them. Only successful responses are cached.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"shelley.exe.dev/llm"
+)
+
+// Service answers requests to Service from the cache in Dir, sending only those it has not seen.
+type Service struct {
+	Service llm.Service
+	Dir     string // the cache directory, created as needed
+	Model   string // identifies the model in cache keys, so models don't share responses
+	// Settings are the settings of Service that change its responses, such as its thinking level and
+	// maximum output tokens. They are part of cache keys as JSON, so changing one doesn't serve stale responses.
+	Settings any
+}
+
+var (
+	_ llm.Service           = (*Service)(nil)
+	_ llm.SimplifiedPatcher = (*Service)(nil)
+)
+
+// Do returns the cached response to ir, or sends ir to the service and caches its response.
+// Cached responses cost nothing, so their Usage.CostUSD is zero.
+func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	path, err := s.path(ir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		var resp llm.Response
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("reading cached response %s: %w", path, err)
+		}
+		resp.Usage.CostUSD = 0
+		return &resp, nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+
+	resp, err := s.Service.Do(ctx, ir)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = json.Marshal(resp); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	// Write to a temporary file and rename it, so concurrent runs never read a partial response
+	tmp, err := os.CreateTemp(s.Dir, "tmp-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// path returns the path of the cache entry of ir, named for the hash of its key
+func (s *Service) path(ir *llm.Request) (string, error) {
+	req, err := llm.RequestJSON(ir)
+	if err != nil {
+		return "", err
+	}
+	settings, err := json.Marshal(s.Settings)
+	if err != nil {
+		return "", fmt.Errorf("encoding cache settings: %w", err)
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", s.Model, settings)
+	h.Write(req)
+	return filepath.Join(s.Dir, hex.EncodeToString(h.Sum(nil))+".json"), nil
+}
+
+// TokenContextWindow returns the context window of the service.
+func (s *Service) TokenContextWindow() int {
+	return s.Service.TokenContextWindow()
+}
+
+// ImageLimits returns the image limits of the service.
+func (s *Service) ImageLimits() llm.ImageLimits {
+	return s.Service.ImageLimits()
+}
+
+// UseSimplifiedPatch reports whether the service uses the simplified patch input schema.
+func (s *Service) UseSimplifiedPatch() bool {
+	return llm.UseSimplifiedPatch(s.Service)
+}
+
+// AcceptsDocuments reports whether the service sends documents to the model.
+func (s *Service) AcceptsDocuments() bool {
+	return llm.AcceptsDocuments(s.Service)
+}
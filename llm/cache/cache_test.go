@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"shelley.exe.dev/llm"
+)
+
+// echoService answers each request with the text of its last message, and fails on "fail"
+type echoService struct{ calls int }
+
+func (e *echoService) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	e.calls++
+	text := ir.Messages[len(ir.Messages)-1].Content[0].Text
+	if text == "fail" {
+		return nil, errors.New("overloaded")
+	}
+	return &llm.Response{
+		Role:    llm.MessageRoleAssistant,
+		Content: []llm.Content{llm.StringContent("echo: " + text)},
+		Usage:   llm.Usage{InputTokens: 10, CostUSD: 0.01},
+	}, nil
+}
+
+func (e *echoService) TokenContextWindow() int      { return 1000 }
+func (e *echoService) ImageLimits() llm.ImageLimits { return llm.ImageLimits{} }
+
+func request(text string) *llm.Request {
+	now := time.Now()
+	return &llm.Request{Messages: []llm.Message{{Role: llm.MessageRoleUser, Content: []llm.Content{
+		llm.StringContent(text),
+		// Tool call times are not sent to the LLM, so they don't change the key
+		{Type: llm.ContentTypeToolResult, ToolUseID: "call_1", ToolUseStartTime: &now, ToolResult: []llm.Content{llm.StringContent("ok")}},
+	}}}}
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	svc := &echoService{}
+	c := &Service{Service: svc, Dir: dir, Model: "echo"}
+
+	first, err := c.Do(ctx, request("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := c.Do(ctx, request("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svc.calls != 1 {
+		t.Errorf("service called %d times, want once for a repeated request", svc.calls)
+	}
+	if cached.Content[0].Text != first.Content[0].Text || cached.Usage.InputTokens != 10 {
+		t.Errorf("cached response = %+v, want %+v", cached, first)
+	}
+	if cached.Usage.CostUSD != 0 {
+		t.Errorf("cached response cost %v, want 0", cached.Usage.CostUSD)
+	}
+
+	// A new cache on the same directory, as in the next run of a scenario, has the response too
+	if _, err := (&Service{Service: svc, Dir: dir, Model: "echo"}).Do(ctx, request("hello")); err != nil || svc.calls != 1 {
+		t.Errorf("second run: err %v, %d calls; want the cached response", err, svc.calls)
+	}
+
+	for _, other := range []*Service{c, {Service: svc, Dir: dir, Model: "other"}} {
+		if _, err := other.Do(ctx, request("goodbye")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := (&Service{Service: svc, Dir: dir, Model: "other"}).Do(ctx, request("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if svc.calls != 4 {
+		t.Errorf("service called %d times, want 4 for the new request to each model and hello to the other model", svc.calls)
+	}
+
+	// The same model with a different thinking level answers differently
+	if _, err := (&Service{Service: svc, Dir: dir, Model: "echo", Settings: map[string]any{"thinking_level": llm.ThinkingLevelHigh}}).Do(ctx, request("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if svc.calls != 5 {
+		t.Errorf("service called %d times, want 5 for hello with other settings", svc.calls)
+	}
+
+	for range 2 {
+		if _, err := c.Do(ctx, request("fail")); err == nil {
+			t.Fatal("expected the service's error")
+		}
+	}
+	if svc.calls != 7 {
+		t.Errorf("service called %d times, want failures not cached", svc.calls)
+	}
+}
@@ -484,6 +484,10 @@ func (c *Convo) ToolResultContents(ctx context.Context, resp *llm.Response) ([]l
 				sendErr(err)
 				return
 			}
+			if err := tool.ValidateInput(part.ToolInput); err != nil {
+				sendErr(err)
+				return
+			}
 			// Create a new context for just this tool_use call, and register its
 			// cancel function so that it can be canceled individually.
 			toolUseCtx, cancel := c.newToolUseContext(ctx, part.ID)
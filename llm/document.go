@@ -0,0 +1,49 @@
+package llm
+
+import "encoding/base64"
+
+// DocumentContent returns a document of mediaType, such as "application/pdf", holding data, titled title if not empty.
+func DocumentContent(mediaType string, data []byte, title string) Content {
+	return Content{Type: ContentTypeDocument, MediaType: mediaType, Data: base64.StdEncoding.EncodeToString(data), Text: title}
+}
+
+// DocumentAcceptor is implemented by services whose providers accept documents.
+type DocumentAcceptor interface {
+	// AcceptsDocuments reports whether the service sends documents to the model, rather than omitting them.
+	AcceptsDocuments() bool
+}
+
+// AcceptsDocuments reports whether svc sends documents to the model. Services that do not implement
+// DocumentAcceptor are assumed not to.
+func AcceptsDocuments(svc Service) bool {
+	if da, ok := svc.(DocumentAcceptor); ok {
+		return da.AcceptsDocuments()
+	}
+	return false
+}
+
+// OmitDocuments returns req with its documents, including those in tool results, replaced with a note,
+// for providers that do not accept documents. req itself is not modified.
+func OmitDocuments(req *Request) *Request {
+	omitted := *req
+	omitted.Messages = make([]Message, len(req.Messages))
+	for i, msg := range req.Messages {
+		msg.Content = omitDocuments(msg.Content)
+		omitted.Messages[i] = msg
+	}
+	return &omitted
+}
+
+func omitDocuments(contents []Content) []Content {
+	out := make([]Content, len(contents))
+	for i, c := range contents {
+		switch c.Type {
+		case ContentTypeToolResult:
+			c.ToolResult = omitDocuments(c.ToolResult)
+		case ContentTypeDocument:
+			c = StringContent("[document omitted: this model does not accept documents]")
+		}
+		out[i] = c
+	}
+	return out
+}
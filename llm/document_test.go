@@ -0,0 +1,43 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOmitDocuments(t *testing.T) {
+	doc := DocumentContent("application/pdf", []byte("%PDF-1.4"), "spec.pdf")
+	if doc.Data != "JVBERi0xLjQ=" {
+		t.Errorf("DocumentContent data = %q, want it base64 encoded", doc.Data)
+	}
+	req := &Request{Messages: []Message{{
+		Role: MessageRoleUser,
+		Content: []Content{
+			doc,
+			{Type: ContentTypeToolResult, ToolUseID: "t1", ToolResult: []Content{StringContent("read"), doc}},
+		},
+	}}}
+	contents := OmitDocuments(req).Messages[0].Content
+	if contents[0].Type != ContentTypeText || !strings.Contains(contents[0].Text, "does not accept documents") || contents[1].ToolResult[1].Type != ContentTypeText {
+		t.Errorf("Expected documents to be replaced with a note, got %+v", contents)
+	}
+	if contents[1].ToolResult[0].Text != "read" {
+		t.Errorf("Expected text to be kept, got %+v", contents[1].ToolResult[0])
+	}
+	if req.Messages[0].Content[0].Type != ContentTypeDocument {
+		t.Error("OmitDocuments modified the original request")
+	}
+}
+
+type documentService struct{ mockService }
+
+func (s *documentService) AcceptsDocuments() bool { return true }
+
+func TestAcceptsDocuments(t *testing.T) {
+	if AcceptsDocuments(&mockService{}) {
+		t.Error("expected a service without AcceptsDocuments not to accept documents")
+	}
+	if !AcceptsDocuments(&documentService{}) {
+		t.Error("expected a DocumentAcceptor returning true to accept documents")
+	}
+}
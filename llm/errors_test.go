@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&StatusError{StatusCode: 429, Err: errors.New("rate limited")}, true},
+		{&StatusError{StatusCode: 529, Err: errors.New("overloaded")}, true},
+		{&StatusError{StatusCode: 400, Err: errors.New("bad request")}, false},
+		// The outermost status wins over those of earlier attempts
+		{&StatusError{StatusCode: 400, Err: errors.Join(&StatusError{StatusCode: 500, Err: errors.New("retry")})}, false},
+		{fmt.Errorf("request: %w", context.DeadlineExceeded), true},
+		{context.Canceled, false},
+		{fmt.Errorf("reading SSE stream: %w", io.ErrUnexpectedEOF), true},
+		{&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+		{errors.New("invalid tool input"), false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"soon", 0},
+		{"Mon, 02 Jan 2006 15:04:05 GMT", 0}, // in the past
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.header != "" {
+			h.Set("Retry-After", tt.header)
+		}
+		if got := RetryAfter(h); got != tt.want {
+			t.Errorf("RetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+
+	h := http.Header{"Retry-After": {time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}}
+	if got := RetryAfter(h); got <= 58*time.Second || got > time.Minute {
+		t.Errorf("RetryAfter(a minute from now) = %v, want about a minute", got)
+	}
+}
@@ -0,0 +1,157 @@
+// Package fallback provides an llm.Service that fails over between services when they are unavailable.
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"shelley.exe.dev/llm"
+)
+
+const (
+	// DefaultCooldown is how long a backend is tried last after its first transient failure.
+	// The cooldown doubles with each consecutive failure, up to maxCooldown.
+	DefaultCooldown = 30 * time.Second
+	maxCooldown     = 10 * time.Minute
+)
+
+// Backend is one of the services a Service sends requests to.
+type Backend struct {
+	Name    string // identifies the backend in logs and errors, e.g. a model ID
+	Service llm.Service
+}
+
+// Service sends each request to its backends in order of preference until one succeeds,
+// failing over on transient errors (see llm.IsTransient). A backend that failed is tried
+// after the healthy ones until its cooldown passes, so outages don't slow every request.
+// Errors that are not transient, such as invalid requests, are returned without failing over.
+type Service struct {
+	Backends []Backend     // in order of preference; must not be empty
+	Timeout  time.Duration // how long to wait for a backend before failing over; no limit if zero
+	Cooldown time.Duration // defaults to DefaultCooldown if zero
+
+	mu     sync.Mutex
+	health map[int]health // by index in Backends; absent for healthy backends
+	now    func() time.Time
+}
+
+var (
+	_ llm.Service           = (*Service)(nil)
+	_ llm.SimplifiedPatcher = (*Service)(nil)
+)
+
+// health is the record of a backend's consecutive transient failures
+type health struct {
+	failures       int
+	unhealthyUntil time.Time
+}
+
+// Do sends ir to the backends in turn until one responds or fails with an error that is not transient.
+func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	var errs error
+	for _, i := range s.order() {
+		b := s.Backends[i]
+		resp, err := s.do(ctx, b, ir)
+		if err == nil {
+			s.recordSuccess(i)
+			return resp, nil
+		}
+		errs = errors.Join(errs, fmt.Errorf("%s: %w", b.Name, err))
+		if ctx.Err() != nil || !llm.IsTransient(err) {
+			return nil, errs
+		}
+		s.recordFailure(i)
+		slog.WarnContext(ctx, "llm backend failed, failing over", "backend", b.Name, "error", err)
+	}
+	return nil, fmt.Errorf("all backends failed: %w", errs)
+}
+
+// do sends ir to b, giving up after s.Timeout
+func (s *Service) do(ctx context.Context, b Backend, ir *llm.Request) (*llm.Response, error) {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+	return b.Service.Do(ctx, ir)
+}
+
+// order returns the indexes of the backends in the order to try them:
+// healthy backends by preference, then unhealthy ones by when they recover.
+func (s *Service) order() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.timeNow()
+	order := make([]int, len(s.Backends))
+	for i := range order {
+		order[i] = i
+	}
+	until := func(i int) time.Time {
+		if h, ok := s.health[i]; ok && h.unhealthyUntil.After(now) {
+			return h.unhealthyUntil
+		}
+		return time.Time{}
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return until(a).Compare(until(b)) })
+	return order
+}
+
+func (s *Service) recordSuccess(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.health, i)
+}
+
+func (s *Service) recordFailure(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.health == nil {
+		s.health = make(map[int]health)
+	}
+	h := s.health[i]
+	h.failures++
+	cooldown := s.Cooldown
+	if cooldown == 0 {
+		cooldown = DefaultCooldown
+	}
+	cooldown = min(cooldown<<min(h.failures-1, 10), maxCooldown)
+	h.unhealthyUntil = s.timeNow().Add(cooldown)
+	s.health[i] = h
+}
+
+func (s *Service) timeNow() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// TokenContextWindow returns the smallest context window of the backends, which any of them may have to serve.
+func (s *Service) TokenContextWindow() int {
+	window := s.Backends[0].Service.TokenContextWindow()
+	for _, b := range s.Backends[1:] {
+		window = min(window, b.Service.TokenContextWindow())
+	}
+	return window
+}
+
+// ImageLimits returns the image limits of the preferred backend. Each backend fits images to its own limits.
+func (s *Service) ImageLimits() llm.ImageLimits {
+	return s.Backends[0].Service.ImageLimits()
+}
+
+// UseSimplifiedPatch reports whether the preferred backend uses the simplified patch input schema.
+func (s *Service) UseSimplifiedPatch() bool {
+	return llm.UseSimplifiedPatch(s.Backends[0].Service)
+}
+
+// AcceptsDocuments reports whether the preferred backend sends documents to the model.
+// The others omit documents if they do not accept them.
+func (s *Service) AcceptsDocuments() bool {
+	return llm.AcceptsDocuments(s.Backends[0].Service)
+}
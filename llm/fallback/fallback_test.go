@@ -0,0 +1,143 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"shelley.exe.dev/llm"
+)
+
+// fakeService returns err, or a response naming itself if err is nil
+type fakeService struct {
+	name   string
+	err    error
+	window int
+	calls  int
+}
+
+func (f *fakeService) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &llm.Response{Content: []llm.Content{llm.StringContent(f.name)}}, nil
+}
+
+func (f *fakeService) TokenContextWindow() int      { return f.window }
+func (f *fakeService) ImageLimits() llm.ImageLimits { return llm.ImageLimits{} }
+
+var (
+	rateLimited = &llm.StatusError{StatusCode: 429, Err: errors.New("rate limited")}
+	badRequest  = &llm.StatusError{StatusCode: 400, Err: errors.New("bad request")}
+)
+
+func newService(services ...*fakeService) *Service {
+	s := &Service{}
+	for _, f := range services {
+		s.Backends = append(s.Backends, Backend{Name: f.name, Service: f})
+	}
+	return s
+}
+
+func responder(t *testing.T, s *Service) string {
+	t.Helper()
+	resp, err := s.Do(context.Background(), &llm.Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Content[0].Text
+}
+
+func TestFailsOverOnTransientErrors(t *testing.T) {
+	for _, err := range []error{
+		rateLimited,
+		&llm.StatusError{StatusCode: 503, Err: errors.New("unavailable")},
+		context.DeadlineExceeded,
+	} {
+		primary, backup := &fakeService{name: "primary", err: err}, &fakeService{name: "backup"}
+		if got := responder(t, newService(primary, backup)); got != "backup" {
+			t.Errorf("%v: got response from %s, want backup", err, got)
+		}
+	}
+}
+
+func TestDoesNotFailOverOnRequestErrors(t *testing.T) {
+	primary, backup := &fakeService{name: "primary", err: badRequest}, &fakeService{name: "backup"}
+	_, err := newService(primary, backup).Do(context.Background(), &llm.Request{})
+	if !errors.Is(err, badRequest) || backup.calls != 0 {
+		t.Errorf("Expected the request error without failing over, got %v and %d backup calls", err, backup.calls)
+	}
+}
+
+func TestAllBackendsFail(t *testing.T) {
+	s := newService(&fakeService{name: "primary", err: rateLimited}, &fakeService{name: "backup", err: context.DeadlineExceeded})
+	_, err := s.Do(context.Background(), &llm.Request{})
+	if err == nil || !strings.Contains(err.Error(), "primary: rate limited") || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the errors of all backends, got %v", err)
+	}
+}
+
+func TestUnhealthyBackendsAreTriedLast(t *testing.T) {
+	now := time.Now()
+	primary, backup := &fakeService{name: "primary", err: rateLimited}, &fakeService{name: "backup"}
+	s := newService(primary, backup)
+	s.now = func() time.Time { return now }
+
+	responder(t, s)
+	responder(t, s)
+	if primary.calls != 1 {
+		t.Errorf("Expected unhealthy primary to be skipped while backup works, got %d calls", primary.calls)
+	}
+
+	// After the cooldown, the primary is preferred again, and its cooldown doubles when it fails again
+	now = now.Add(DefaultCooldown)
+	responder(t, s)
+	if primary.calls != 2 {
+		t.Errorf("Expected primary to be retried after its cooldown, got %d calls", primary.calls)
+	}
+	now = now.Add(DefaultCooldown)
+	responder(t, s)
+	if primary.calls != 2 {
+		t.Errorf("Expected primary cooldown to double, got %d calls", primary.calls)
+	}
+
+	// A success restores the primary's health
+	primary.err = nil
+	now = now.Add(DefaultCooldown)
+	if got := responder(t, s); got != "primary" {
+		t.Errorf("got response from %s, want primary", got)
+	}
+	if _, ok := s.health[0]; ok {
+		t.Error("Expected primary to be healthy after a success")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	blocked := &blockingService{}
+	backup := &fakeService{name: "backup"}
+	s := &Service{
+		Backends: []Backend{{Name: "blocked", Service: blocked}, {Name: "backup", Service: backup}},
+		Timeout:  time.Millisecond,
+	}
+	if got := responder(t, s); got != "backup" {
+		t.Errorf("got response from %s, want backup", got)
+	}
+}
+
+// blockingService blocks until its context is done
+type blockingService struct{ fakeService }
+
+func (b *blockingService) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestTokenContextWindow(t *testing.T) {
+	s := newService(&fakeService{window: 200000}, &fakeService{window: 128000}, &fakeService{window: 1000000})
+	if got := s.TokenContextWindow(); got != 128000 {
+		t.Errorf("TokenContextWindow() = %d, want the smallest, 128000", got)
+	}
+}
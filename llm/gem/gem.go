@@ -4,9 +4,9 @@ import (
 	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand"
 	"net/http"
 	"strings"
 	"time"
@@ -20,6 +20,17 @@ const (
 	GeminiAPIKeyEnv = "GEMINI_API_KEY"
 )
 
+// pricing is the list price of each model, used when no gateway reports the cost of requests.
+// Prices are those of prompts up to 200k tokens, where they depend on the prompt's length.
+// See https://ai.google.dev/gemini-api/docs/pricing
+var pricing = map[string]llm.Pricing{
+	"gemini-3-pro-preview":   {Input: 2, Output: 12, CacheRead: 0.2},
+	"gemini-3-flash-preview": {Input: 0.5, Output: 3, CacheRead: 0.05},
+	"gemini-2.5-pro":         {Input: 1.25, Output: 10, CacheRead: 0.31},
+	"gemini-2.5-flash":       {Input: 0.3, Output: 2.5, CacheRead: 0.075},
+	"gemini-2.0-flash":       {Input: 0.1, Output: 0.4, CacheRead: 0.025},
+}
+
 // Service provides Gemini completions.
 // Fields should not be altered concurrently with calling any method on Service.
 type Service struct {
@@ -187,7 +198,11 @@ func (s *Service) buildGeminiRequest(req *llm.Request) (*gemini.Request, error)
 		// Map each content item to Gemini's format
 		for _, c := range msg.Content {
 			switch c.Type {
-			case llm.ContentTypeText, llm.ContentTypeThinking, llm.ContentTypeRedactedThinking:
+			case llm.ContentTypeImage, llm.ContentTypeDocument:
+				content.Parts = append(content.Parts, inlinePart(c))
+			case llm.ContentTypeThinking, llm.ContentTypeRedactedThinking:
+				// Thinking from other providers means nothing to Gemini
+			case llm.ContentTypeText:
 				// Simple text content
 				content.Parts = append(content.Parts, gemini.Part{
 					Text: c.Text,
@@ -229,13 +244,16 @@ func (s *Service) buildGeminiRequest(req *llm.Request) (*gemini.Request, error)
 				}
 
 				// Handle tool results: Gemini only supports string results
-				// Combine all text content into a single string
+				// Combine all text content into a single string, and send images and documents as parts after the response
 				var resultText string
+				var images []gemini.Part
 				if len(c.ToolResult) > 0 {
 					// Collect all text from content objects
 					texts := make([]string, 0, len(c.ToolResult))
 					for _, result := range c.ToolResult {
-						if result.Text != "" {
+						if result.Type == llm.ContentTypeImage || result.Type == llm.ContentTypeDocument {
+							images = append(images, inlinePart(result))
+						} else if result.Text != "" {
 							texts = append(texts, result.Text)
 						}
 					}
@@ -279,6 +297,7 @@ func (s *Service) buildGeminiRequest(req *llm.Request) (*gemini.Request, error)
 						Response: response,
 					},
 				})
+				content.Parts = append(content.Parts, images...)
 			}
 		}
 
@@ -300,6 +319,20 @@ func (s *Service) buildGeminiRequest(req *llm.Request) (*gemini.Request, error)
 	return gemReq, nil
 }
 
+// statusError wraps err as an llm.StatusError if it is an HTTP error status from the Gemini API
+func statusError(err error) error {
+	var se *gemini.StatusError
+	if errors.As(err, &se) {
+		return &llm.StatusError{StatusCode: se.StatusCode, Err: err, RetryAfter: llm.RetryAfter(se.Header)}
+	}
+	return err
+}
+
+// inlinePart returns the image or document c as an inline data part
+func inlinePart(c llm.Content) gemini.Part {
+	return gemini.Part{InlineData: &gemini.Blob{MimeType: c.MediaType, Data: c.Data}}
+}
+
 // convertGeminiResponsesToContent converts a Gemini response to llm.Content
 func convertGeminiResponseToContent(res *gemini.Response) []llm.Content {
 	if res == nil || len(res.Candidates) == 0 || len(res.Candidates[0].Content.Parts) == 0 {
@@ -421,6 +454,9 @@ func calculateUsage(req *gemini.Request, res *gemini.Response) llm.Usage {
 				// Estimate function response tokens
 				resBytes, _ := json.Marshal(part.FunctionResponse.Response)
 				inputTokens += uint64(len(part.FunctionResponse.Name)+len(resBytes)) / 4
+			} else if part.InlineData != nil {
+				// Gemini counts each image as at least 258 tokens
+				inputTokens += 258
 			}
 		}
 	}
@@ -469,10 +505,16 @@ func (s *Service) TokenContextWindow() int {
 	}
 }
 
-// MaxImageDimension returns the maximum allowed image dimension.
-// TODO: determine actual Gemini image dimension limits
-func (s *Service) MaxImageDimension() int {
-	return 0 // No known limit
+// ImageLimits returns the Gemini image limits.
+// Gemini scales images to fit 3072x3072, and inline data is limited to 20MB per request,
+// so only the latest 16 images of up to 1MB each are sent.
+func (s *Service) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{MaxDimension: 3072, MaxBytes: 1024 * 1024, MaxCount: 16}
+}
+
+// AcceptsDocuments reports that Gemini reads documents, which it is sent inline.
+func (s *Service) AcceptsDocuments() bool {
+	return true
 }
 
 // Do sends a request to Gemini.
@@ -519,6 +561,10 @@ func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error
 			"role", msg.Role.String(),
 			"content_types", contentTypes)
 	}
+	ir, err := llm.FitImages(ir, s.ImageLimits())
+	if err != nil {
+		return nil, err
+	}
 	// Build the Gemini request
 	gemReq, err := s.buildGeminiRequest(ir)
 	if err != nil {
@@ -538,44 +584,16 @@ func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error
 		HTTPC:    cmp.Or(s.HTTPC, http.DefaultClient),
 	}
 
-	// Send the request to Gemini with retry logic
+	// Send the request to Gemini
 	startTime := time.Now()
-	endTime := startTime // Initialize endTime
-	var gemRes *gemini.Response
-
-	// Retry mechanism for handling server errors and rate limiting
-	backoff := []time.Duration{1 * time.Second, 3 * time.Second, 5 * time.Second, 10 * time.Second}
-	for attempts := 0; attempts <= len(backoff); attempts++ {
-		gemApiErr := error(nil)
-		gemRes, gemApiErr = model.GenerateContent(ctx, gemReq)
-		endTime = time.Now()
-
-		if gemApiErr == nil {
-			// Successful response
-			// Log the structured Gemini response
-			if resJSON, err := json.MarshalIndent(gemRes, "", "  "); err == nil {
-				slog.DebugContext(ctx, "gemini_response_json", "response", string(resJSON))
-			}
-			break
-		}
-
-		if attempts == len(backoff) {
-			// We've exhausted all retry attempts
-			return nil, fmt.Errorf("gemini: API error after %d attempts: %w", attempts, gemApiErr)
-		}
-
-		// Check if the error is retryable (e.g., server error or rate limiting)
-		if strings.Contains(gemApiErr.Error(), "429") || strings.Contains(gemApiErr.Error(), "5") {
-			// Rate limited or server error - wait and retry
-			random := time.Duration(rand.Int63n(int64(time.Second)))
-			sleep := backoff[attempts] + random
-			slog.WarnContext(ctx, "gemini_request_retry", "error", gemApiErr.Error(), "attempt", attempts+1, "sleep", sleep)
-			time.Sleep(sleep)
-			continue
-		}
-
-		// Non-retryable error
-		return nil, fmt.Errorf("gemini: API error: %w", gemApiErr)
+	gemRes, err := model.GenerateContent(ctx, gemReq)
+	endTime := time.Now()
+	if err != nil {
+		return nil, statusError(fmt.Errorf("gemini: API error: %w", err))
+	}
+	// Log the structured Gemini response
+	if resJSON, err := json.MarshalIndent(gemRes, "", "  "); err == nil {
+		slog.DebugContext(ctx, "gemini_response_json", "response", string(resJSON))
 	}
 
 	content := convertGeminiResponseToContent(gemRes)
@@ -584,6 +602,9 @@ func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error
 
 	usage := calculateUsage(gemReq, gemRes)
 	usage.CostUSD = llm.CostUSDFromResponse(gemRes.Header())
+	if usage.CostUSD == 0 {
+		usage.CostUSD = pricing[cmp.Or(s.Model, DefaultModel)].Cost(usage)
+	}
 
 	stopReason := llm.StopReasonEndTurn
 	for _, part := range content {
@@ -365,6 +365,29 @@ func TestHeaderCostIntegration(t *testing.T) {
 	}
 }
 
+func TestListPriceCost(t *testing.T) {
+	service := &Service{
+		Model:  "gemini-2.5-pro",
+		APIKey: "test-key",
+		HTTPC: &http.Client{Transport: &mockRoundTripper{response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewBufferString(`{"candidates": [{"content": {"parts": [{"text": "Test response"}]}}]}`)),
+		}}},
+		URL: "https://test.googleapis.com",
+	}
+	res, err := service.Do(context.Background(), &llm.Request{Messages: []llm.Message{llm.UserStringMessage("Hello")}})
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	// Without a gateway, the estimated tokens are priced at $1.25/MTok of input and $10/MTok of output
+	want := (float64(res.Usage.InputTokens)*1.25 + float64(res.Usage.OutputTokens)*10) / 1_000_000
+	if res.Usage.CostUSD == 0 || res.Usage.CostUSD != want {
+		t.Errorf("Usage.CostUSD = %v, want %v", res.Usage.CostUSD, want)
+	}
+}
+
 func TestTokenContextWindow(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -446,13 +469,82 @@ func TestTokenContextWindow(t *testing.T) {
 	}
 }
 
-func TestMaxImageDimension(t *testing.T) {
+func TestImageLimits(t *testing.T) {
 	service := &Service{}
-	got := service.MaxImageDimension()
-	// Currently returns 0 as per implementation
-	expected := 0
+	got := service.ImageLimits()
+	expected := llm.ImageLimits{MaxDimension: 3072, MaxBytes: 1024 * 1024, MaxCount: 16}
 	if got != expected {
-		t.Errorf("MaxImageDimension() = %v, want %v", got, expected)
+		t.Errorf("ImageLimits() = %+v, want %+v", got, expected)
+	}
+}
+
+func TestBuildGeminiRequestImages(t *testing.T) {
+	image := llm.Content{Type: llm.ContentTypeImage, MediaType: "image/png", Data: "aW1n"}
+	req := &llm.Request{
+		Messages: []llm.Message{
+			{
+				Role:    llm.MessageRoleAssistant,
+				Content: []llm.Content{{Type: llm.ContentTypeToolUse, ID: "t1", ToolName: "browser_take_screenshot", ToolInput: json.RawMessage(`{}`)}},
+			},
+			{
+				Role: llm.MessageRoleUser,
+				Content: []llm.Content{
+					{Type: llm.ContentTypeToolResult, ToolUseID: "t1", ToolName: "browser_take_screenshot", ToolResult: []llm.Content{llm.StringContent("taken"), image}},
+					llm.StringContent("what is this?"),
+					image,
+				},
+			},
+		},
+	}
+	gemReq, err := (&Service{}).buildGeminiRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := gemReq.Contents[1].Parts
+	if len(parts) != 4 {
+		t.Fatalf("Expected function response, its image, text, and image parts, got %+v", parts)
+	}
+	if parts[0].FunctionResponse == nil || parts[0].FunctionResponse.Response["result"] != "taken" {
+		t.Errorf("Expected function response with text only, got %+v", parts[0])
+	}
+	want := gemini.Blob{MimeType: "image/png", Data: "aW1n"}
+	for _, i := range []int{1, 3} {
+		if parts[i].InlineData == nil || *parts[i].InlineData != want {
+			t.Errorf("Expected part %d to be inline image data, got %+v", i, parts[i])
+		}
+	}
+	if parts[2].Text != "what is this?" {
+		t.Errorf("Expected text part, got %+v", parts[2])
+	}
+}
+
+func TestBuildGeminiRequestDocuments(t *testing.T) {
+	doc := llm.DocumentContent("application/pdf", []byte("%PDF-1.4"), "spec.pdf")
+	req := &llm.Request{
+		Messages: []llm.Message{
+			{
+				Role:    llm.MessageRoleAssistant,
+				Content: []llm.Content{{Type: llm.ContentTypeToolUse, ID: "t1", ToolName: "read_document", ToolInput: json.RawMessage(`{}`)}},
+			},
+			{
+				Role:    llm.MessageRoleUser,
+				Content: []llm.Content{{Type: llm.ContentTypeToolResult, ToolUseID: "t1", ToolName: "read_document", ToolResult: []llm.Content{llm.StringContent("read"), doc}}, doc},
+			},
+		},
+	}
+	gemReq, err := (&Service{}).buildGeminiRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := gemReq.Contents[1].Parts
+	if len(parts) != 3 || parts[0].FunctionResponse == nil {
+		t.Fatalf("Expected function response, its document, and document parts, got %+v", parts)
+	}
+	want := gemini.Blob{MimeType: "application/pdf", Data: "JVBERi0xLjQ="}
+	for _, i := range []int{1, 2} {
+		if parts[i].InlineData == nil || *parts[i].InlineData != want {
+			t.Errorf("Expected part %d to be inline document data, got %+v", i, parts[i])
+		}
 	}
 }
 
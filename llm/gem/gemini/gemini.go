@@ -53,10 +53,16 @@ type Part struct {
 	// ThoughtSignature is required for Gemini 3 models when using function calling.
 	// It must be passed back exactly as received when sending the conversation history.
 	ThoughtSignature string `json:"thoughtSignature,omitempty"`
-	// TODO inlineData
+	InlineData       *Blob  `json:"inlineData,omitempty"`
 	// TODO fileData
 }
 
+// https://ai.google.dev/api/caching#Blob
+type Blob struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"` // base64-encoded
+}
+
 type FunctionCall struct {
 	Name string         `json:"name"`
 	Args map[string]any `json:"args"`
@@ -168,7 +174,7 @@ func (m Model) GenerateContent(ctx context.Context, req *Request) (*Response, er
 		return nil, fmt.Errorf("GenerateContent: reading response body: %w", err)
 	}
 	if httpResp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GenerateContent: HTTP status: %d, %s", httpResp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: httpResp.StatusCode, Body: string(body), Header: httpResp.Header}
 	}
 	var res Response
 	if err := json.Unmarshal(body, &res); err != nil {
@@ -178,6 +184,17 @@ func (m Model) GenerateContent(ctx context.Context, req *Request) (*Response, er
 	return &res, nil
 }
 
+// StatusError is returned by GenerateContent when the API responds with an HTTP error status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	Header     http.Header
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GenerateContent: HTTP status: %d, %s", e.StatusCode, e.Body)
+}
+
 func (m Model) endpoint() string {
 	if m.Endpoint != "" {
 		return m.Endpoint
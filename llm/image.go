@@ -0,0 +1,230 @@
+package llm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"shelley.exe.dev/llm/imageutil"
+)
+
+// ImageLimits are a provider's constraints on the images in a request. Zero fields are unlimited.
+type ImageLimits struct {
+	// MaxDimension is the largest width or height of an image in pixels
+	MaxDimension int
+	// MaxBytes is the largest size of an image as sent, base64 encoded
+	MaxBytes int
+	// MaxCount is the most images a request may carry
+	MaxCount int
+}
+
+// ImageMediaTypes are the media types of images every provider accepts
+var ImageMediaTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// ImageContent returns an image holding data, whose media type it detects.
+// It fails if data is not an image of one of ImageMediaTypes that can be decoded, rather than leave FitImages to omit it.
+func ImageContent(data []byte) (Content, error) {
+	mediaType := http.DetectContentType(data)
+	if !slices.Contains(ImageMediaTypes, mediaType) {
+		return Content{}, fmt.Errorf("unsupported image type %s; supported types are %s", mediaType, strings.Join(ImageMediaTypes, ", "))
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return Content{}, fmt.Errorf("invalid %s image: %w", mediaType, err)
+	}
+	return Content{Type: ContentTypeImage, MediaType: mediaType, Data: base64.StdEncoding.EncodeToString(data)}, nil
+}
+
+// FitImages returns req with its images, including those in tool results, made to fit limits.
+// Images much taller than wide are split into overlapping tiles so they stay legible, and images
+// still too large are scaled down and recompressed. Images that can't be decoded, and the oldest
+// images if there are still too many, are replaced with a note saying so. req itself is not modified.
+func FitImages(req *Request, limits ImageLimits) (*Request, error) {
+	if limits == (ImageLimits{}) {
+		return req, nil
+	}
+	fitted := *req
+	fitted.Messages = make([]Message, len(req.Messages))
+	count := 0
+	for i, msg := range req.Messages {
+		var err error
+		if msg.Content, err = fitContents(msg.Content, limits, &count); err != nil {
+			return nil, err
+		}
+		fitted.Messages[i] = msg
+	}
+	if limits.MaxCount > 0 && count > limits.MaxCount {
+		drop := count - limits.MaxCount
+		note := fmt.Sprintf("[image omitted: only the latest %d images fit in a request]", limits.MaxCount)
+		for i := range fitted.Messages {
+			fitted.Messages[i].Content = dropImages(fitted.Messages[i].Content, note, &drop)
+		}
+	}
+	return &fitted, nil
+}
+
+// fitContents fits the images in contents to limits, adding the number of images after fitting to count
+func fitContents(contents []Content, limits ImageLimits, count *int) ([]Content, error) {
+	var out []Content
+	for _, c := range contents {
+		switch {
+		case c.Type == ContentTypeToolResult:
+			var err error
+			if c.ToolResult, err = fitContents(c.ToolResult, limits, count); err != nil {
+				return nil, err
+			}
+			out = append(out, c)
+		case c.Type == ContentTypeImage:
+			images, err := fitImage(c, limits)
+			if err != nil {
+				return nil, err
+			}
+			for _, img := range images {
+				if img.Type == ContentTypeImage {
+					*count++
+				}
+			}
+			out = append(out, images...)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// OmitImages returns req with its images, including those in tool results, replaced with a note,
+// for models that do not accept images. req itself is not modified.
+func OmitImages(req *Request) *Request {
+	omitted := *req
+	omitted.Messages = make([]Message, len(req.Messages))
+	drop := math.MaxInt
+	for i, msg := range req.Messages {
+		msg.Content = dropImages(msg.Content, "[image omitted: this model does not accept images]", &drop)
+		omitted.Messages[i] = msg
+	}
+	return &omitted
+}
+
+// dropImages replaces the first *drop images in contents with note, decrementing *drop for each
+func dropImages(contents []Content, note string, drop *int) []Content {
+	if *drop == 0 {
+		return contents
+	}
+	out := make([]Content, len(contents))
+	for i, c := range contents {
+		switch {
+		case c.Type == ContentTypeToolResult:
+			c.ToolResult = dropImages(c.ToolResult, note, drop)
+		case c.Type == ContentTypeImage && *drop > 0:
+			c = StringContent(note)
+			*drop--
+		}
+		out[i] = c
+	}
+	return out
+}
+
+// fitCacheSize bounds fitCache; the whole history is resent each turn, so it need only hold one conversation's images
+const fitCacheSize = 256
+
+// fitKey identifies an image fitted to some limits
+type fitKey struct {
+	sum    [sha256.Size]byte
+	limits ImageLimits
+}
+
+var (
+	fitCacheMu sync.Mutex
+	// fitCache holds the images and notes that images were fitted to, to save refitting them on every request
+	fitCache = map[fitKey][]Content{}
+)
+
+// fitImage fits the image c to limits, splitting it into several if it is too tall
+func fitImage(c Content, limits ImageLimits) ([]Content, error) {
+	key := fitKey{sha256.Sum256([]byte(c.Data)), limits}
+	fitCacheMu.Lock()
+	fitted, ok := fitCache[key]
+	fitCacheMu.Unlock()
+	if !ok {
+		var err error
+		if fitted, err = fitImageData(c.MediaType, c.Data, limits); err != nil {
+			return nil, err
+		}
+		fitCacheMu.Lock()
+		if len(fitCache) >= fitCacheSize {
+			clear(fitCache)
+		}
+		fitCache[key] = fitted
+		fitCacheMu.Unlock()
+	}
+
+	// Fitted images keep the other fields of c, and its cache breakpoint moves to the last of them
+	out := make([]Content, len(fitted))
+	for i, f := range fitted {
+		if f.Type == ContentTypeImage {
+			img := c
+			img.MediaType, img.Data = f.MediaType, f.Data
+			f = img
+		}
+		f.Cache = c.Cache && i == len(fitted)-1
+		out[i] = f
+	}
+	return out, nil
+}
+
+// fitImageData fits the base64 encoded image data of mediaType to limits, returning the images it becomes
+// (with only MediaType and Data set) and any note about them
+func fitImageData(mediaType, encoded string, limits ImageLimits) ([]Content, error) {
+	// An image that can't be decoded can't be fitted either, and would only make the provider reject
+	// the whole request, every turn, so it becomes a note instead
+	undecodable := []Content{StringContent(fmt.Sprintf("[image omitted: the %s image data could not be decoded]", mediaType))}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return undecodable, nil
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return undecodable, nil
+	}
+	pieces := [][]byte{data}
+	if limits.MaxDimension > 0 && config.Height > limits.MaxDimension && config.Height > 2*config.Width {
+		if pieces, _, err = imageutil.Tile(data, limits.MaxDimension, limits.MaxDimension/10); err != nil {
+			return nil, fmt.Errorf("failed to split tall image: %w", err)
+		}
+	}
+
+	var out []Content
+	if len(pieces) > 1 {
+		out = append(out, StringContent(fmt.Sprintf("(The next %d images are overlapping parts of one tall image, from top to bottom.)", len(pieces))))
+	}
+	for _, piece := range pieces {
+		pieceType := mediaType
+		if limits.MaxDimension > 0 {
+			mode := imageutil.ResizeSmooth
+			if mediaType == "image/png" {
+				// Most PNGs sent are screenshots
+				mode = imageutil.ResizeText
+			}
+			var format string
+			if piece, format, _, err = imageutil.ResizeImageMode(piece, limits.MaxDimension, mode); err != nil {
+				return nil, err
+			}
+			pieceType = "image/" + format
+		}
+		if limits.MaxBytes > 0 && base64.StdEncoding.EncodedLen(len(piece)) > limits.MaxBytes {
+			var format string
+			if piece, format, err = imageutil.CompressToSize(piece, base64.StdEncoding.DecodedLen(limits.MaxBytes)); err != nil {
+				return nil, err
+			}
+			pieceType = "image/" + format
+		}
+		out = append(out, Content{Type: ContentTypeImage, MediaType: pieceType, Data: base64.StdEncoding.EncodeToString(piece)})
+	}
+	return out, nil
+}
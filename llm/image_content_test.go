@@ -1,62 +1,59 @@
 package llm
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
 func TestImageContent(t *testing.T) {
-	// Create a Content structure with an image
-	imageContent := Content{
-		Type:      ContentTypeText, // In the future, we might add a specific ContentTypeImage
-		MediaType: "image/jpeg",
-		Data:      "/9j/4AAQSkZJRg...", // Shortened base64 encoded image
-	}
-
-	// Verify the structure is correct
-	if imageContent.MediaType != "image/jpeg" {
-		t.Errorf("Expected MediaType to be 'image/jpeg', got '%s'", imageContent.MediaType)
-	}
-
-	if imageContent.Data != "/9j/4AAQSkZJRg..." {
-		t.Errorf("Expected Data to contain base64 image data")
-	}
-
-	// Create a tool result that contains text and image content
-	toolResult := Content{
-		Type:      ContentTypeToolResult,
-		ToolUseID: "toolu_01A09q90qw90lq917835lq9",
-		ToolResult: []Content{
-			{
-				Type: ContentTypeText,
-				Text: "15 degrees",
-			},
-			imageContent,
-		},
-	}
-
-	// Check that the tool result contains two content items
-	if len(toolResult.ToolResult) != 2 {
-		t.Errorf("Expected tool result to contain 2 content items, got %d", len(toolResult.ToolResult))
+	png := pngContent(t, 10, 10, false)
+	data, err := base64.StdEncoding.DecodeString(png.Data)
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	// Verify JSON marshaling works as expected
-	bytes, err := json.Marshal(toolResult)
+	img, err := ImageContent(data)
 	if err != nil {
-		t.Errorf("Failed to marshal content to JSON: %v", err)
+		t.Fatal(err)
 	}
-
-	// Unmarshal and verify structure is preserved
-	var unmarshaled Content
-	if err := json.Unmarshal(bytes, &unmarshaled); err != nil {
-		t.Errorf("Failed to unmarshal JSON: %v", err)
+	if img.Type != ContentTypeImage || img.MediaType != "image/png" || img.Data != png.Data {
+		t.Errorf("ImageContent() = %+v, want a PNG image", img)
 	}
 
-	if len(unmarshaled.ToolResult) != 2 {
-		t.Errorf("Expected unmarshaled tool result to contain 2 content items, got %d", len(unmarshaled.ToolResult))
+	for _, tt := range []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"text", []byte("not an image"), "unsupported image type text/plain"},
+		{"bmp", []byte("BM" + strings.Repeat("\x00", 64)), "unsupported image type image/bmp"},
+		{"truncated png", data[:20], "invalid image/png image"},
+	} {
+		if _, err := ImageContent(tt.data); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ImageContent() error = %v, want it to contain %q", tt.name, err, tt.want)
+		}
 	}
+}
 
-	if unmarshaled.ToolResult[1].MediaType != "image/jpeg" {
-		t.Errorf("Expected unmarshaled image MediaType to be 'image/jpeg', got '%s'", unmarshaled.ToolResult[1].MediaType)
+func TestContentUnmarshalStoredImage(t *testing.T) {
+	// Images stored before ContentTypeImage were text content with a MediaType and Data, in messages and tool results
+	stored := `{"Role":0,"Content":[
+		{"Type":2,"Text":"look"},
+		{"Type":2,"MediaType":"image/png","Data":"iVBORw0KGgo="},
+		{"Type":6,"ToolUseID":"t1","ToolResult":[{"Type":2,"MediaType":"image/jpeg","Data":"/9j/4AAQ"}]}
+	]}`
+	var msg Message
+	if err := json.Unmarshal([]byte(stored), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Content[0].Type != ContentTypeText || msg.Content[0].Text != "look" {
+		t.Errorf("text content = %+v, want it unchanged", msg.Content[0])
+	}
+	if c := msg.Content[1]; c.Type != ContentTypeImage || c.MediaType != "image/png" || c.Data != "iVBORw0KGgo=" {
+		t.Errorf("stored image = %+v, want an image", c)
+	}
+	if c := msg.Content[2].ToolResult[0]; c.Type != ContentTypeImage || c.MediaType != "image/jpeg" {
+		t.Errorf("stored tool result image = %+v, want an image", c)
 	}
 }
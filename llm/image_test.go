@@ -0,0 +1,161 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// pngContent returns a w x h PNG image as Content, filled with noise if noisy
+func pngContent(t *testing.T, w, h int, noisy bool) Content {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	r := rand.New(rand.NewPCG(1, 2))
+	for y := range h {
+		for x := range w {
+			c := color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 0xff}
+			if noisy {
+				c = color.RGBA{R: uint8(r.IntN(256)), G: uint8(r.IntN(256)), B: uint8(r.IntN(256)), A: 0xff}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return Content{Type: ContentTypeImage, MediaType: "image/png", Data: base64.StdEncoding.EncodeToString(buf.Bytes())}
+}
+
+// imageSize decodes the dimensions of the image c
+func imageSize(t *testing.T, c Content) (int, int) {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(c.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config.Width, config.Height
+}
+
+func TestFitImagesResizesToolResults(t *testing.T) {
+	large := pngContent(t, 3000, 2500, false)
+	large.Cache = true
+	req := &Request{Messages: []Message{{
+		Role: MessageRoleUser,
+		Content: []Content{{
+			Type:       ContentTypeToolResult,
+			ToolUseID:  "t1",
+			ToolResult: []Content{StringContent("a picture"), large},
+		}},
+	}}}
+
+	fitted, err := FitImages(req, ImageLimits{MaxDimension: 2000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := fitted.Messages[0].Content[0].ToolResult
+	if len(result) != 2 || result[0].Text != "a picture" {
+		t.Fatalf("Expected text and image, got %+v", result)
+	}
+	if w, h := imageSize(t, result[1]); w != 2000 || h != 1666 {
+		t.Errorf("Expected 2000x1666 image, got %dx%d", w, h)
+	}
+	if !result[1].Cache {
+		t.Error("Expected fitted image to keep its cache breakpoint")
+	}
+	if req.Messages[0].Content[0].ToolResult[1].Data != large.Data {
+		t.Error("FitImages modified the original request")
+	}
+}
+
+func TestFitImagesSplitsTallImages(t *testing.T) {
+	req := &Request{Messages: []Message{{Role: MessageRoleUser, Content: []Content{pngContent(t, 500, 5000, false)}}}}
+	fitted, err := FitImages(req, ImageLimits{MaxDimension: 2000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := fitted.Messages[0].Content
+	if len(contents) < 4 || !strings.Contains(contents[0].Text, "overlapping parts of one tall image") {
+		t.Fatalf("Expected a note and at least 3 tiles, got %d contents", len(contents))
+	}
+	for _, c := range contents[1:] {
+		if w, h := imageSize(t, c); w != 500 || h > 2000 {
+			t.Errorf("Expected tiles 500 wide and at most 2000 tall, got %dx%d", w, h)
+		}
+	}
+}
+
+func TestFitImagesCompressesToMaxBytes(t *testing.T) {
+	req := &Request{Messages: []Message{{Role: MessageRoleUser, Content: []Content{pngContent(t, 400, 400, true)}}}}
+	const maxBytes = 100 * 1024
+	if len(req.Messages[0].Content[0].Data) <= maxBytes {
+		t.Fatal("Test image is already small enough")
+	}
+	fitted, err := FitImages(req, ImageLimits{MaxBytes: maxBytes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fitted.Messages[0].Content[0]; len(got.Data) > maxBytes {
+		t.Errorf("Expected at most %d encoded bytes, got %d (%s)", maxBytes, len(got.Data), got.MediaType)
+	}
+}
+
+func TestFitImagesDropsOldestOverMaxCount(t *testing.T) {
+	var messages []Message
+	for range 3 {
+		messages = append(messages, Message{Role: MessageRoleUser, Content: []Content{pngContent(t, 10, 10, false)}})
+	}
+	fitted, err := FitImages(&Request{Messages: messages}, ImageLimits{MaxCount: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fitted.Messages[0].Content[0]; got.Type == ContentTypeImage || !strings.Contains(got.Text, "image omitted") {
+		t.Errorf("Expected oldest image to be omitted, got %+v", got)
+	}
+	for _, msg := range fitted.Messages[1:] {
+		if msg.Content[0].Type != ContentTypeImage {
+			t.Errorf("Expected latest images to be kept, got %+v", msg.Content[0])
+		}
+	}
+}
+
+func TestFitImagesInvalidImage(t *testing.T) {
+	req := &Request{Messages: []Message{{Role: MessageRoleUser, Content: []Content{{Type: ContentTypeImage, MediaType: "image/png", Data: "bm90IGFuIGltYWdl"}}}}}
+	fitted, err := FitImages(req, ImageLimits{MaxDimension: 2000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fitted.Messages[0].Content; len(got) != 1 || got[0].Type != ContentTypeText || !strings.Contains(got[0].Text, "could not be decoded") {
+		t.Errorf("Expected invalid image to be replaced with a note, got %+v", got)
+	}
+}
+
+func TestOmitImages(t *testing.T) {
+	req := &Request{Messages: []Message{{
+		Role: MessageRoleUser,
+		Content: []Content{
+			pngContent(t, 10, 10, false),
+			{Type: ContentTypeToolResult, ToolUseID: "t1", ToolResult: []Content{StringContent("shot"), pngContent(t, 10, 10, false)}},
+		},
+	}}}
+	omitted := OmitImages(req)
+	contents := omitted.Messages[0].Content
+	if contents[0].Type == ContentTypeImage || contents[1].ToolResult[1].Type == ContentTypeImage || !strings.Contains(contents[0].Text, "does not accept images") {
+		t.Errorf("Expected images to be replaced with a note, got %+v", contents)
+	}
+	if contents[1].ToolResult[0].Text != "shot" {
+		t.Errorf("Expected text to be kept, got %+v", contents[1].ToolResult[0])
+	}
+	if req.Messages[0].Content[0].Type != ContentTypeImage {
+		t.Error("OmitImages modified the original request")
+	}
+}
@@ -0,0 +1,172 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// AnnotationColor is the default color of annotations, chosen to stand out on typical pages
+var AnnotationColor = color.RGBA{R: 0xff, G: 0x00, B: 0x40, A: 0xff}
+
+// AnnotationKind is the shape of an Annotation
+type AnnotationKind string
+
+const (
+	// AnnotateRect outlines Rect
+	AnnotateRect AnnotationKind = "rect"
+	// AnnotateArrow points from From to To
+	AnnotateArrow AnnotationKind = "arrow"
+	// AnnotateLabel writes Text on a filled box with its top-left corner at From
+	AnnotateLabel AnnotationKind = "label"
+)
+
+// Annotation is a mark drawn onto an image by Annotate
+type Annotation struct {
+	Kind     AnnotationKind
+	Rect     image.Rectangle
+	From, To image.Point
+	Text     string
+	// Color defaults to AnnotationColor
+	Color color.Color
+}
+
+// annotationWidth is the line width of annotations in pixels
+const annotationWidth = 3
+
+// labelSize is the font size of labels in pixels
+const labelSize = 16
+
+// labelPadding is the space in pixels between a label's text and the edges of its box
+const labelPadding = 4
+
+// labelFace loads the label font once
+var labelFace = sync.OnceValues(func() (font.Face, error) {
+	f, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, err
+	}
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: labelSize, DPI: 72, Hinting: font.HintingFull})
+})
+
+// Annotate draws annotations onto an image in order and returns the result as a PNG
+func Annotate(data []byte, annotations []Annotation) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	img := image.NewRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+	for i, a := range annotations {
+		c := a.Color
+		if c == nil {
+			c = AnnotationColor
+		}
+		switch a.Kind {
+		case AnnotateRect:
+			DrawRect(img, a.Rect, c, annotationWidth)
+		case AnnotateArrow:
+			DrawArrow(img, a.From, a.To, c, annotationWidth)
+		case AnnotateLabel:
+			if err := DrawLabel(img, a.From, a.Text, c); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("annotation %d: unsupported kind %q", i, a.Kind)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode annotated image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DrawRect outlines r on dst with lines width pixels thick, drawn inside r
+func DrawRect(dst draw.Image, r image.Rectangle, c color.Color, width int) {
+	r = r.Canon()
+	fill := image.NewUniform(c)
+	for _, band := range []image.Rectangle{
+		{r.Min, image.Pt(r.Max.X, r.Min.Y+width)},
+		{image.Pt(r.Min.X, r.Max.Y-width), r.Max},
+		{r.Min, image.Pt(r.Min.X+width, r.Max.Y)},
+		{image.Pt(r.Max.X-width, r.Min.Y), r.Max},
+	} {
+		draw.Draw(dst, band.Intersect(r), fill, image.Point{}, draw.Over)
+	}
+}
+
+// DrawArrow draws a line width pixels thick from from to to, with an arrowhead at to
+func DrawArrow(dst draw.Image, from, to image.Point, c color.Color, width int) {
+	drawLine(dst, from, to, c, width)
+	dx, dy := float64(from.X-to.X), float64(from.Y-to.Y)
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	head := float64(max(12, 4*width))
+	for _, angle := range []float64{math.Pi / 6, -math.Pi / 6} {
+		sin, cos := math.Sincos(angle)
+		x := (dx*cos - dy*sin) / length * head
+		y := (dx*sin + dy*cos) / length * head
+		drawLine(dst, to, to.Add(image.Pt(int(math.Round(x)), int(math.Round(y)))), c, width)
+	}
+}
+
+// drawLine draws a line width pixels thick by stamping squares along it
+func drawLine(dst draw.Image, from, to image.Point, c color.Color, width int) {
+	fill := image.NewUniform(c)
+	steps := max(abs(to.X-from.X), abs(to.Y-from.Y), 1)
+	for i := 0; i <= steps; i++ {
+		x := from.X + (to.X-from.X)*i/steps
+		y := from.Y + (to.Y-from.Y)*i/steps
+		draw.Draw(dst, image.Rect(x-width/2, y-width/2, x-width/2+width, y-width/2+width), fill, image.Point{}, draw.Over)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// DrawLabel writes text on a box filled with c, with its top-left corner at at,
+// moved inside dst if it would stick out. The text is white or black, whichever reads better on c.
+func DrawLabel(dst draw.Image, at image.Point, text string, c color.Color) error {
+	face, err := labelFace()
+	if err != nil {
+		return fmt.Errorf("failed to load label font: %w", err)
+	}
+	metrics := face.Metrics()
+	width := font.MeasureString(face, text).Ceil() + 2*labelPadding
+	height := (metrics.Ascent + metrics.Descent).Ceil() + 2*labelPadding
+	bounds := dst.Bounds()
+	at.X = max(bounds.Min.X, min(at.X, bounds.Max.X-width))
+	at.Y = max(bounds.Min.Y, min(at.Y, bounds.Max.Y-height))
+	draw.Draw(dst, image.Rect(at.X, at.Y, at.X+width, at.Y+height), image.NewUniform(c), image.Point{}, draw.Over)
+
+	r, g, b, _ := c.RGBA()
+	ink := image.White
+	if 0.299*float64(r)+0.587*float64(g)+0.114*float64(b) > 0.6*0xffff {
+		ink = image.Black
+	}
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  ink,
+		Face: face,
+		Dot:  fixed.P(at.X+labelPadding, at.Y+labelPadding+metrics.Ascent.Ceil()),
+	}
+	d.DrawString(text)
+	return nil
+}
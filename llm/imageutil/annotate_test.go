@@ -0,0 +1,46 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestAnnotate(t *testing.T) {
+	white := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for i := range white.Pix {
+		white.Pix[i] = 0xff
+	}
+	blue := color.RGBA{B: 0xff, A: 0xff}
+	out, err := Annotate(encodePNG(t, white), []Annotation{
+		{Kind: AnnotateRect, Rect: image.Rect(10, 10, 60, 40)},
+		{Kind: AnnotateArrow, From: image.Pt(150, 90), To: image.Pt(100, 50), Color: blue},
+		{Kind: AnnotateLabel, From: image.Pt(190, 0), Text: "Submit"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(x, y int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	}
+	if at(11, 25) != AnnotationColor || at(35, 25) != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("Expected an outline at the rectangle's edge only, got %v inside the edge and %v in the middle", at(11, 25), at(35, 25))
+	}
+	if at(125, 70) != blue {
+		t.Errorf("Expected the arrow's line at (125, 70), got %v", at(125, 70))
+	}
+	// The label is moved left to fit, so its box reaches the right edge
+	if at(199, 2) != AnnotationColor {
+		t.Errorf("Expected the label box at the top right corner, got %v", at(199, 2))
+	}
+
+	if _, err := Annotate(encodePNG(t, white), []Annotation{{Kind: "circle"}}); err == nil {
+		t.Error("Expected an error for an unsupported kind")
+	}
+}
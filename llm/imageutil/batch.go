@@ -0,0 +1,50 @@
+package imageutil
+
+import (
+	"fmt"
+	"runtime"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Batch runs fn on each of images concurrently, with at most one worker per CPU, and returns the results
+// in the order of images. If any call fails, Batch returns the error of the first image that failed.
+func Batch[T any](images [][]byte, fn func(data []byte) (T, error)) ([]T, error) {
+	results := make([]T, len(images))
+	errs := make([]error, len(images))
+	var eg errgroup.Group
+	eg.SetLimit(runtime.GOMAXPROCS(0))
+	for i, data := range images {
+		eg.Go(func() error {
+			results[i], errs[i] = fn(data)
+			return nil
+		})
+	}
+	eg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+	}
+	return results, nil
+}
+
+// ResizedImage is an image resized by ResizeImages
+type ResizedImage struct {
+	Data    []byte
+	Format  string
+	Resized bool
+}
+
+// ResizeImages resizes images concurrently as ResizeImageMode does
+func ResizeImages(images [][]byte, maxDimension int, mode ResizeMode) ([]ResizedImage, error) {
+	return Batch(images, func(data []byte) (ResizedImage, error) {
+		resized, format, didResize, err := ResizeImageMode(data, maxDimension, mode)
+		return ResizedImage{Data: resized, Format: format, Resized: didResize}, err
+	})
+}
+
+// OptimizePNGs optimizes PNG images concurrently as OptimizePNG does
+func OptimizePNGs(images [][]byte) ([][]byte, error) {
+	return Batch(images, OptimizePNG)
+}
@@ -0,0 +1,34 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestResizeImages(t *testing.T) {
+	images := [][]byte{createTestPNG(t, 1000, 500), createTestPNG(t, 100, 100), createTestPNG(t, 400, 800)}
+	resized, err := ResizeImages(images, 200, ResizeText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []image.Point{{200, 100}, {100, 100}, {100, 200}}
+	for i, r := range resized {
+		config, _, err := image.DecodeConfig(bytes.NewReader(r.Data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := image.Pt(config.Width, config.Height); got != want[i] {
+			t.Errorf("image %d: got %v, want %v", i, got, want[i])
+		}
+		if r.Resized != (i != 1) {
+			t.Errorf("image %d: Resized = %v", i, r.Resized)
+		}
+	}
+
+	_, err = ResizeImages([][]byte{images[0], []byte("not an image"), []byte("nor this")}, 200, ResizeSmooth)
+	if err == nil || !strings.HasPrefix(err.Error(), "image 1:") {
+		t.Errorf("Expected error for image 1, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// compressQualities are the JPEG qualities CompressToSize tries at each size, best first
+var compressQualities = []int{85, 70, 55, 40}
+
+// compressMinDimension is the smallest width or height CompressToSize scales an image down to
+const compressMinDimension = 32
+
+// CompressToSize re-encodes an image so it takes at most maxBytes, since provider limits are in bytes
+// rather than pixels. It lowers the JPEG quality, then scales the image down by a quarter and tries again.
+// Images with transparency stay PNG. Returns the encoded bytes and the format ("png" or "jpeg"),
+// or the original data and its format if it already fits.
+func CompressToSize(data []byte, maxBytes int) (compressed []byte, format string, err error) {
+	if maxBytes <= 0 {
+		return nil, "", fmt.Errorf("byte budget must be positive")
+	}
+	img, detectedFormat, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if len(data) <= maxBytes {
+		return data, detectedFormat, nil
+	}
+
+	preferred := encodingFormat(img, detectedFormat)
+	opaque := true
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		opaque = o.Opaque()
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	scaled := img
+	for {
+		var buf bytes.Buffer
+		// Flat images are smallest as PNG; try it first, then JPEG unless transparency rules it out
+		if preferred == "png" {
+			if err := png.Encode(&buf, scaled); err != nil {
+				return nil, "", fmt.Errorf("failed to encode image: %w", err)
+			}
+			if buf.Len() <= maxBytes {
+				return buf.Bytes(), "png", nil
+			}
+		}
+		if opaque {
+			for _, quality := range compressQualities {
+				buf.Reset()
+				if err := jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: quality}); err != nil {
+					return nil, "", fmt.Errorf("failed to encode image: %w", err)
+				}
+				if buf.Len() <= maxBytes {
+					return buf.Bytes(), "jpeg", nil
+				}
+			}
+		}
+
+		width, height = width*3/4, height*3/4
+		if width < compressMinDimension || height < compressMinDimension {
+			return nil, "", fmt.Errorf("cannot compress %dx%d image to %d bytes", bounds.Dx(), bounds.Dy(), maxBytes)
+		}
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.BiLinear.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+		scaled = dst
+	}
+}
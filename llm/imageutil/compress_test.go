@@ -0,0 +1,67 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand/v2"
+	"testing"
+)
+
+// createNoisePNG returns a PNG of random pixels, which compresses poorly
+func createNoisePNG(t *testing.T, width, height int, alpha uint8) []byte {
+	rng := rand.New(rand.NewPCG(1, 2))
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.NRGBA{R: uint8(rng.IntN(256)), G: uint8(rng.IntN(256)), B: uint8(rng.IntN(256)), A: alpha})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to create test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCompressToSize(t *testing.T) {
+	small := createTestPNG(t, 100, 100)
+	if out, format, err := CompressToSize(small, len(small)); err != nil || format != "png" || !bytes.Equal(out, small) {
+		t.Errorf("CompressToSize(fits) = %d bytes, %q, %v; want the original", len(out), format, err)
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		maxBytes   int
+		wantFormat string
+	}{
+		{"opaque noise", createNoisePNG(t, 600, 400, 255), 50_000, "jpeg"},
+		{"transparent noise", createNoisePNG(t, 600, 400, 128), 100_000, "png"},
+		{"flat color", createTestPNG(t, 3000, 3000), 20_000, "png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, format, err := CompressToSize(tt.data, tt.maxBytes)
+			if err != nil {
+				t.Fatalf("CompressToSize() error = %v", err)
+			}
+			if len(out) > tt.maxBytes || format != tt.wantFormat {
+				t.Errorf("CompressToSize() = %d bytes of %s, want at most %d of %s", len(out), format, tt.maxBytes, tt.wantFormat)
+			}
+			if _, decoded, err := image.DecodeConfig(bytes.NewReader(out)); err != nil || decoded != format {
+				t.Errorf("Compressed image decodes as %q (%v), want %s", decoded, err, format)
+			}
+		})
+	}
+
+	for _, maxBytes := range []int{0, 100} {
+		if _, _, err := CompressToSize(createNoisePNG(t, 200, 200, 255), maxBytes); err == nil {
+			t.Errorf("Expected an error for a budget of %d bytes", maxBytes)
+		}
+	}
+	if _, _, err := CompressToSize([]byte("not an image"), 1000); err == nil {
+		t.Error("Expected an error for invalid data")
+	}
+}
@@ -0,0 +1,52 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// contactSheetGap is the space in pixels between images on a contact sheet
+const contactSheetGap = 16
+
+// ContactSheet lays out images left to right, each scaled to height pixels tall, and returns the sheet as a PNG
+func ContactSheet(images [][]byte, height int) ([]byte, error) {
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no images for contact sheet")
+	}
+	if height <= 0 {
+		return nil, fmt.Errorf("contact sheet height must be positive")
+	}
+
+	decoded := make([]image.Image, len(images))
+	width := contactSheetGap * (len(images) + 1)
+	for i, data := range images {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image %d: %w", i, err)
+		}
+		decoded[i] = img
+		b := img.Bounds()
+		width += max(1, b.Dx()*height/b.Dy())
+	}
+
+	sheet := image.NewRGBA(image.Rect(0, 0, width, height+2*contactSheetGap))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(color.RGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff}), image.Point{}, draw.Src)
+	x := contactSheetGap
+	for _, img := range decoded {
+		b := img.Bounds()
+		w := max(1, b.Dx()*height/b.Dy())
+		draw.BiLinear.Scale(sheet, image.Rect(x, contactSheetGap, x+w, contactSheetGap+height), img, b, draw.Over, nil)
+		x += w + contactSheetGap
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sheet); err != nil {
+		return nil, fmt.Errorf("failed to encode contact sheet: %w", err)
+	}
+	return buf.Bytes(), nil
+}
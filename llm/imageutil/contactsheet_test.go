@@ -0,0 +1,29 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"testing"
+)
+
+func TestContactSheet(t *testing.T) {
+	sheet, err := ContactSheet([][]byte{createTestPNG(t, 200, 400), createTestPNG(t, 800, 400)}, 100)
+	if err != nil {
+		t.Fatalf("ContactSheet() error = %v", err)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(sheet))
+	if err != nil {
+		t.Fatalf("Failed to decode contact sheet: %v", err)
+	}
+	// 50 + 200 pixels of images plus three gaps, and a gap above and below
+	if format != "png" || config.Width != 50+200+3*contactSheetGap || config.Height != 100+2*contactSheetGap {
+		t.Errorf("ContactSheet() = %s %dx%d", format, config.Width, config.Height)
+	}
+
+	if _, err := ContactSheet(nil, 100); err == nil {
+		t.Error("ContactSheet(nil) succeeded, want error")
+	}
+	if _, err := ContactSheet([][]byte{[]byte("not an image")}, 100); err == nil {
+		t.Error("ContactSheet(garbage) succeeded, want error")
+	}
+}
@@ -0,0 +1,100 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// diffThreshold is the perceptual color distance, from 0 to 1, above which pixels differ.
+// It ignores JPEG noise and subpixel antialiasing but not a visible color change.
+const diffThreshold = 0.1
+
+// maxYIQDelta is the largest possible yiqDelta, which normalizes it
+const maxYIQDelta = 35215.0
+
+// DiffResult is the outcome of comparing two images
+type DiffResult struct {
+	// Mismatched is the number of differing pixels, counting those only one image covers
+	Mismatched int
+	// Score is the fraction of pixels that differ, from 0 (identical) to 1
+	Score float64
+	// Bounds encloses the differing pixels; empty if the images match
+	Bounds image.Rectangle
+	// Image is a PNG of a faded copy of a with the differing pixels in red and outlined
+	Image []byte
+}
+
+// Diff compares two images pixel by pixel using perceptual (YIQ) color distance.
+// Images of different sizes are compared over the larger area, aligned at the top left.
+func Diff(a, b []byte) (*DiffResult, error) {
+	imgA, _, err := image.Decode(bytes.NewReader(a))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode first image: %w", err)
+	}
+	imgB, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode second image: %w", err)
+	}
+
+	ba, bb := imgA.Bounds(), imgB.Bounds()
+	width, height := max(ba.Dx(), bb.Dx()), max(ba.Dy(), bb.Dy())
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("cannot diff empty images")
+	}
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	result := &DiffResult{}
+	red := color.RGBA{R: 0xff, A: 0xff}
+	for y := range height {
+		for x := range width {
+			pa, pb := image.Pt(x, y).Add(ba.Min), image.Pt(x, y).Add(bb.Min)
+			inA, inB := pa.In(ba), pb.In(bb)
+			if inA && inB && yiqDelta(imgA.At(pa.X, pa.Y), imgB.At(pb.X, pb.Y)) <= diffThreshold*diffThreshold*maxYIQDelta {
+				out.Set(x, y, fade(imgA.At(pa.X, pa.Y)))
+				continue
+			}
+			out.Set(x, y, red)
+			result.Mismatched++
+			result.Bounds = result.Bounds.Union(image.Rect(x, y, x+1, y+1))
+		}
+	}
+	result.Score = float64(result.Mismatched) / float64(width*height)
+	if !result.Bounds.Empty() {
+		// Outline the changes so small ones are easy to spot
+		DrawRect(out, result.Bounds.Inset(-2*annotationWidth), AnnotationColor, annotationWidth)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, fmt.Errorf("failed to encode diff image: %w", err)
+	}
+	result.Image = buf.Bytes()
+	return result, nil
+}
+
+// yiqDelta is the squared perceptual distance between two colors blended onto white,
+// weighting brightness over hue as the eye does (Kotsarenko and Ramos, 2010)
+func yiqDelta(c1, c2 color.Color) float64 {
+	r1, g1, b1 := onWhite(c1)
+	r2, g2, b2 := onWhite(c2)
+	y := 0.29889531*(r1-r2) + 0.58662247*(g1-g2) + 0.11448223*(b1-b2)
+	i := 0.59597799*(r1-r2) - 0.27417610*(g1-g2) - 0.32180189*(b1-b2)
+	q := 0.21147017*(r1-r2) - 0.52261711*(g1-g2) + 0.31114694*(b1-b2)
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+// onWhite returns c composited onto white as 0-255 RGB
+func onWhite(c color.Color) (r, g, b float64) {
+	cr, cg, cb, ca := c.RGBA()
+	white := float64(0xffff - ca)
+	return (float64(cr) + white) / 257, (float64(cg) + white) / 257, (float64(cb) + white) / 257
+}
+
+// fade lightens c to a faint gray so differences stand out
+func fade(c color.Color) color.Color {
+	r, g, b := onWhite(c)
+	gray := 0.299*r + 0.587*g + 0.114*b
+	return color.Gray{Y: uint8(255 - (255-gray)*0.1)}
+}
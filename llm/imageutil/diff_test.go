@@ -0,0 +1,74 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// encodePNG encodes img as a PNG
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDiff(t *testing.T) {
+	base := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	for y := range 50 {
+		for x := range 100 {
+			base.Set(x, y, color.RGBA{R: 240, G: 240, B: 240, A: 255})
+		}
+	}
+	changed := image.NewRGBA(base.Bounds())
+	copy(changed.Pix, base.Pix)
+	for y := 10; y < 20; y++ {
+		for x := 30; x < 40; x++ {
+			changed.Set(x, y, color.RGBA{R: 20, G: 20, B: 200, A: 255})
+		}
+	}
+	// A slight shift in shade is not a visible difference
+	changed.Set(0, 0, color.RGBA{R: 238, G: 240, B: 241, A: 255})
+
+	same, err := Diff(encodePNG(t, base), encodePNG(t, base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same.Mismatched != 0 || same.Score != 0 || !same.Bounds.Empty() {
+		t.Errorf("Diff(same) = %+v", same)
+	}
+
+	result, err := Diff(encodePNG(t, base), encodePNG(t, changed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Mismatched != 100 || result.Score != 0.02 || result.Bounds != image.Rect(30, 10, 40, 20) {
+		t.Errorf("Diff() = %d mismatched, score %v, bounds %v", result.Mismatched, result.Score, result.Bounds)
+	}
+	diffImg, err := png.Decode(bytes.NewReader(result.Image))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, g, _, _ := diffImg.At(35, 15).RGBA(); r != 0xffff || g != 0 {
+		t.Errorf("Expected a differing pixel in red, got %v", diffImg.At(35, 15))
+	}
+
+	// Area only one image covers counts as different
+	taller := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	copy(taller.Pix, base.Pix)
+	result, err = Diff(encodePNG(t, base), encodePNG(t, taller))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Score != 0.5 || result.Bounds != image.Rect(0, 50, 100, 100) {
+		t.Errorf("Diff(different sizes) = score %v, bounds %v", result.Score, result.Bounds)
+	}
+
+	if _, err := Diff([]byte("not an image"), encodePNG(t, base)); err == nil {
+		t.Error("Expected an error for invalid data")
+	}
+}
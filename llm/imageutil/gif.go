@@ -0,0 +1,63 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/png"
+)
+
+// IsGIF checks if data is a GIF image based on file magic
+func IsGIF(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("GIF87a")) || bytes.HasPrefix(data, []byte("GIF89a"))
+}
+
+// GIFFrames returns up to n evenly spaced frames of a GIF as PNGs, each as it appears on screen
+// (frames may only update part of the picture), and the number of frames in the GIF.
+// A single frame is taken from the middle of the animation, which shows more than the first.
+func GIFFrames(data []byte, n int) (frames [][]byte, total int, err error) {
+	if n <= 0 {
+		return nil, 0, fmt.Errorf("frame count must be positive")
+	}
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode gif: %w", err)
+	}
+	total = len(g.Image)
+	n = min(n, total)
+	// Take the middle frame of each of n equal runs of frames
+	want := make(map[int]bool, n)
+	for i := range n {
+		want[(2*i+1)*total/(2*n)] = true
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	var previous *image.RGBA
+	for i, frame := range g.Image {
+		disposal := byte(gif.DisposalNone)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(canvas.Bounds())
+			copy(previous.Pix, canvas.Pix)
+		}
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		if want[i] {
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, canvas); err != nil {
+				return nil, 0, fmt.Errorf("failed to encode frame %d: %w", i, err)
+			}
+			frames = append(frames, buf.Bytes())
+		}
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return frames, total, nil
+}
@@ -0,0 +1,98 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+)
+
+// createAnimatedGIF returns a 40x20 GIF whose frame i paints a 10x10 square at x = 10*(i%4) red,
+// on top of the previous frames
+func createAnimatedGIF(t *testing.T, frames int) []byte {
+	palette := color.Palette{color.Transparent, color.RGBA{R: 0xff, A: 0xff}, color.White}
+	g := &gif.GIF{Config: image.Config{Width: 40, Height: 20, ColorModel: palette}}
+	for i := range frames {
+		var frame *image.Paletted
+		if i == 0 {
+			frame = image.NewPaletted(image.Rect(0, 0, 40, 20), palette)
+			for p := range frame.Pix {
+				frame.Pix[p] = 2
+			}
+		} else {
+			x := 10 * (i % 4)
+			frame = image.NewPaletted(image.Rect(x, 0, x+10, 10), palette)
+			for p := range frame.Pix {
+				frame.Pix[p] = 1
+			}
+		}
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 10)
+		g.Disposal = append(g.Disposal, gif.DisposalNone)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatalf("Failed to create test gif: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// redSquares counts the 10x10 squares along the top of a frame that are red
+func redSquares(t *testing.T, data []byte) int {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for x := 5; x < 40; x += 10 {
+		if r, g, _, _ := img.At(x, 5).RGBA(); r == 0xffff && g == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGIFFrames(t *testing.T) {
+	data := createAnimatedGIF(t, 4)
+	if !IsGIF(data) || IsGIF(createTestPNG(t, 1, 1)) {
+		t.Error("IsGIF misdetected")
+	}
+
+	// Frames are composited, so frame i shows squares 1 to i
+	middle, total, err := GIFFrames(data, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 || len(middle) != 1 || redSquares(t, middle[0]) != 2 {
+		t.Errorf("GIFFrames(1) = %d frames of %d, %d red squares; want frame 2", len(middle), total, redSquares(t, middle[0]))
+	}
+
+	all, _, err := GIFFrames(data, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("GIFFrames(10) = %d frames, want all 4", len(all))
+	}
+	for i, frame := range all {
+		if got := redSquares(t, frame); got != i {
+			t.Errorf("Frame %d has %d red squares, want %d", i, got, i)
+		}
+	}
+
+	if _, _, err := GIFFrames(data, 0); err == nil {
+		t.Error("Expected an error for 0 frames")
+	}
+}
+
+func TestResizeImageAnimatedGIF(t *testing.T) {
+	resized, format, _, err := ResizeImage(createAnimatedGIF(t, 4), 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" || redSquares(t, resized) != 2 {
+		t.Errorf("ResizeImage(animated gif) = %s with %d red squares, want the middle frame as png", format, redSquares(t, resized))
+	}
+}
@@ -0,0 +1,118 @@
+package imageutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// OCRBox is a bounding box in image pixels
+type OCRBox struct {
+	Left   int `json:"left"`
+	Top    int `json:"top"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// union returns the smallest box containing b and o
+func (b OCRBox) union(o OCRBox) OCRBox {
+	left, top := min(b.Left, o.Left), min(b.Top, o.Top)
+	right := max(b.Left+b.Width, o.Left+o.Width)
+	bottom := max(b.Top+b.Height, o.Top+o.Height)
+	return OCRBox{Left: left, Top: top, Width: right - left, Height: bottom - top}
+}
+
+// OCRWord is a recognized word
+type OCRWord struct {
+	Text       string  `json:"text"`
+	Box        OCRBox  `json:"box"`
+	Confidence float64 `json:"confidence"`
+}
+
+// OCRLine is a line of recognized words
+type OCRLine struct {
+	Text       string    `json:"text"`
+	Box        OCRBox    `json:"box"`
+	Confidence float64   `json:"confidence"`
+	Words      []OCRWord `json:"words,omitempty"`
+}
+
+// OCR extracts text lines with bounding boxes from image data using the tesseract command.
+// Confidence is tesseract's 0-100 score; a line's is the mean of its words'.
+func OCR(ctx context.Context, data []byte) ([]OCRLine, error) {
+	if _, err := exec.LookPath("tesseract"); err != nil {
+		return nil, fmt.Errorf("tesseract not found (install tesseract-ocr): %w", err)
+	}
+	cmd := exec.CommandContext(ctx, "tesseract", "stdin", "stdout", "tsv")
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("tesseract: %w: %s", err, stderr.String())
+	}
+	return parseTesseractTSV(stdout.Bytes())
+}
+
+// parseTesseractTSV groups the words in tesseract's TSV output into lines
+func parseTesseractTSV(data []byte) ([]OCRLine, error) {
+	var lines []OCRLine
+	var lineKey string
+	rows := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	for i, row := range rows {
+		if i == 0 || row == "" {
+			// Header: level page_num block_num par_num line_num word_num left top width height conf text
+			continue
+		}
+		f := strings.SplitN(row, "\t", 12)
+		if len(f) != 12 {
+			return nil, fmt.Errorf("tesseract output line %d: expected 12 fields, got %d", i+1, len(f))
+		}
+		// Level 5 rows are words; the others are page, block, paragraph, and line boxes
+		if f[0] != "5" || strings.TrimSpace(f[11]) == "" {
+			continue
+		}
+		var nums [4]int
+		for j := range nums {
+			n, err := strconv.Atoi(f[6+j])
+			if err != nil {
+				return nil, fmt.Errorf("tesseract output line %d: %w", i+1, err)
+			}
+			nums[j] = n
+		}
+		conf, err := strconv.ParseFloat(f[10], 64)
+		if err != nil {
+			return nil, fmt.Errorf("tesseract output line %d: %w", i+1, err)
+		}
+		word := OCRWord{
+			Text:       strings.TrimSpace(f[11]),
+			Box:        OCRBox{Left: nums[0], Top: nums[1], Width: nums[2], Height: nums[3]},
+			Confidence: conf,
+		}
+
+		key := strings.Join(f[1:5], ".")
+		if len(lines) == 0 || key != lineKey {
+			lines = append(lines, OCRLine{Box: word.Box})
+			lineKey = key
+		}
+		line := &lines[len(lines)-1]
+		line.Words = append(line.Words, word)
+		line.Box = line.Box.union(word.Box)
+	}
+
+	for i := range lines {
+		line := &lines[i]
+		texts := make([]string, len(line.Words))
+		var total float64
+		for j, w := range line.Words {
+			texts[j] = w.Text
+			total += w.Confidence
+		}
+		line.Text = strings.Join(texts, " ")
+		line.Confidence = total / float64(len(line.Words))
+	}
+	return lines, nil
+}
@@ -0,0 +1,63 @@
+package imageutil
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"os/exec"
+	"testing"
+)
+
+func TestParseTesseractTSV(t *testing.T) {
+	tsv := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+		"1\t1\t0\t0\t0\t0\t0\t0\t400\t100\t-1\t\n" +
+		"4\t1\t1\t1\t1\t0\t10\t10\t120\t20\t-1\t\n" +
+		"5\t1\t1\t1\t1\t1\t10\t10\t50\t20\t90\tHello\n" +
+		"5\t1\t1\t1\t1\t2\t70\t12\t60\t20\t80\tworld\n" +
+		"5\t1\t1\t1\t2\t1\t10\t50\t40\t18\t70\tBye\n" +
+		"5\t1\t1\t1\t2\t2\t60\t50\t40\t18\t-1\t \n"
+	lines, err := parseTesseractTSV([]byte(tsv))
+	if err != nil {
+		t.Fatalf("parseTesseractTSV error: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %+v", len(lines), lines)
+	}
+	if lines[0].Text != "Hello world" || lines[0].Confidence != 85 || len(lines[0].Words) != 2 {
+		t.Errorf("Unexpected first line %+v", lines[0])
+	}
+	if want := (OCRBox{Left: 10, Top: 10, Width: 120, Height: 22}); lines[0].Box != want {
+		t.Errorf("First line box = %+v, want %+v", lines[0].Box, want)
+	}
+	if lines[1].Text != "Bye" {
+		t.Errorf("Second line = %q, want Bye", lines[1].Text)
+	}
+
+	if _, err := parseTesseractTSV([]byte("header\n5\t1\t1\n")); err == nil {
+		t.Error("Expected error for truncated row")
+	}
+}
+
+func TestOCR(t *testing.T) {
+	if _, err := exec.LookPath("tesseract"); err != nil {
+		t.Skip("tesseract not installed")
+	}
+
+	// A blank image has no text
+	img := image.NewGray(image.Rect(0, 0, 200, 100))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	lines, err := OCR(context.Background(), buf.Bytes())
+	if err != nil {
+		t.Fatalf("OCR error: %v", err)
+	}
+	if len(lines) != 0 {
+		t.Errorf("Expected no text in a blank image, got %+v", lines)
+	}
+}
@@ -0,0 +1,59 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// OptimizePNG losslessly shrinks a PNG: an image of at most 256 colors, like most UI screenshots,
+// is stored with a palette, and all are compressed harder than browsers bother to.
+// Returns the original data if that is no larger.
+func OptimizePNG(data []byte) ([]byte, error) {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PNG: %w", err)
+	}
+	if p := exactPalette(img); p != nil {
+		img = p
+	}
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	if buf.Len() >= len(data) {
+		return data, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// exactPalette returns img as a paletted image if it has at most 256 colors, and nil otherwise
+func exactPalette(img image.Image) *image.Paletted {
+	if _, ok := img.(*image.Paletted); ok {
+		return nil
+	}
+	b := img.Bounds()
+	index := make(map[color.NRGBA]uint8)
+	var palette color.Palette
+	out := image.NewPaletted(b, nil)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			i, ok := index[c]
+			if !ok {
+				if len(palette) == 256 {
+					return nil
+				}
+				i = uint8(len(palette))
+				index[c] = i
+				palette = append(palette, c)
+			}
+			out.SetColorIndex(x, y, i)
+		}
+	}
+	out.Palette = palette
+	return out
+}
@@ -0,0 +1,65 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestOptimizePNG(t *testing.T) {
+	// A flat UI-like image: a few solid colors
+	img := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for y := range 300 {
+		for x := range 400 {
+			c := color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff}
+			if y < 40 {
+				c = color.RGBA{R: 0x20, G: 0x40, B: 0x80, A: 0xff}
+			} else if x%50 < 2 {
+				c = color.RGBA{A: 0xff}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := (&png.Encoder{CompressionLevel: png.NoCompression}).Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := OptimizePNG(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) >= buf.Len() {
+		t.Errorf("OptimizePNG() is %d bytes, want fewer than %d", len(out), buf.Len())
+	}
+	optimized, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := optimized.(*image.Paletted); !ok {
+		t.Errorf("Expected a paletted image, got %T", optimized)
+	}
+	for _, p := range []image.Point{{0, 0}, {100, 100}, {101, 100}, {102, 100}} {
+		if got, want := color.NRGBAModel.Convert(optimized.At(p.X, p.Y)), color.NRGBAModel.Convert(img.At(p.X, p.Y)); got != want {
+			t.Errorf("Pixel %v is %v, want %v", p, got, want)
+		}
+	}
+
+	// Noise has too many colors for a palette but is still returned intact
+	noise := createNoisePNG(t, 64, 64, 0xff)
+	out, err = OptimizePNG(noise)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded, err := png.Decode(bytes.NewReader(out)); err != nil {
+		t.Fatal(err)
+	} else if _, ok := decoded.(*image.Paletted); ok {
+		t.Error("Expected noise to keep all its colors")
+	}
+
+	if _, err := OptimizePNG([]byte("not a png")); err == nil {
+		t.Error("Expected an error for invalid data")
+	}
+}
@@ -10,12 +10,41 @@ import (
 	"strings"
 
 	"golang.org/x/image/draw"
+	// Registers the WebP decoder; there is no Go WebP encoder, so WebP is re-encoded as JPEG or PNG
+	_ "golang.org/x/image/webp"
+)
+
+// ResizeMode selects how ResizeImageMode scales an image down
+type ResizeMode int
+
+const (
+	// ResizeSmooth scales bilinearly, which suits photographs
+	ResizeSmooth ResizeMode = iota
+	// ResizeText scales with a sharper filter, then sharpens edges, so small UI text stays legible
+	ResizeText
 )
 
 // ResizeImage resizes an image if any dimension exceeds maxDimension.
 // Returns the resized image bytes and the format ("png" or "jpeg").
+// Photographs, and JPEG and WebP sources, are re-encoded as JPEG since PNG is several times larger for them.
+// Animated GIFs are reduced to their middle frame as a PNG.
 // If no resize is needed, returns the original data unchanged.
 func ResizeImage(data []byte, maxDimension int) (resized []byte, format string, didResize bool, err error) {
+	return ResizeImageMode(data, maxDimension, ResizeSmooth)
+}
+
+// ResizeImageMode is ResizeImage with a choice of scaling; use ResizeText for screenshots
+func ResizeImageMode(data []byte, maxDimension int, mode ResizeMode) (resized []byte, format string, didResize bool, err error) {
+	if IsGIF(data) {
+		frames, total, err := GIFFrames(data, 1)
+		if err != nil {
+			return nil, "", false, err
+		}
+		if total > 1 {
+			data = frames[0]
+		}
+	}
+
 	img, detectedFormat, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
 		return nil, "", false, fmt.Errorf("failed to decode image: %w", err)
@@ -29,34 +58,78 @@ func ResizeImage(data []byte, maxDimension int) (resized []byte, format string,
 		return data, detectedFormat, false, nil
 	}
 
-	// Calculate new dimensions preserving aspect ratio
-	newWidth, newHeight := width, height
-	if width > height {
-		newWidth = maxDimension
-		newHeight = height * maxDimension / width
-	} else {
-		newHeight = maxDimension
-		newWidth = width * maxDimension / height
-	}
+	newWidth, newHeight := FitDimensions(width, height, maxDimension)
 
 	// Create resized image
 	resizedImg := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
-	draw.BiLinear.Scale(resizedImg, resizedImg.Bounds(), img, bounds, draw.Over, nil)
+	if mode == ResizeText {
+		draw.CatmullRom.Scale(resizedImg, resizedImg.Bounds(), img, bounds, draw.Over, nil)
+		resizedImg = sharpen(resizedImg, textSharpening)
+	} else {
+		draw.BiLinear.Scale(resizedImg, resizedImg.Bounds(), img, bounds, draw.Over, nil)
+	}
 
-	// Encode to the same format
 	var buf bytes.Buffer
-	switch strings.ToLower(detectedFormat) {
-	case "jpeg", "jpg":
+	format = encodingFormat(img, detectedFormat)
+	if format == "jpeg" {
 		err = jpeg.Encode(&buf, resizedImg, &jpeg.Options{Quality: 85})
-		format = "jpeg"
-	default:
+	} else {
 		err = png.Encode(&buf, resizedImg)
-		format = "png"
 	}
-
 	if err != nil {
 		return nil, "", false, fmt.Errorf("failed to encode resized image: %w", err)
 	}
 
 	return buf.Bytes(), format, true, nil
 }
+
+// FitDimensions returns width and height scaled down, preserving the aspect ratio, so neither exceeds maxDimension
+func FitDimensions(width, height, maxDimension int) (int, int) {
+	if width <= maxDimension && height <= maxDimension {
+		return width, height
+	}
+	if width > height {
+		return maxDimension, height * maxDimension / width
+	}
+	return width * maxDimension / height, maxDimension
+}
+
+// encodingFormat picks "jpeg" or "png" for re-encoding img, decoded from sourceFormat:
+// JPEG for opaque images from lossy sources or that look like photographs, PNG otherwise
+func encodingFormat(img image.Image, sourceFormat string) string {
+	switch strings.ToLower(sourceFormat) {
+	case "jpeg", "jpg":
+		return "jpeg"
+	}
+	if o, ok := img.(interface{ Opaque() bool }); ok && !o.Opaque() {
+		return "png"
+	}
+	if strings.EqualFold(sourceFormat, "webp") || isPhotographic(img) {
+		return "jpeg"
+	}
+	return "png"
+}
+
+// photoSamples bounds the pixels isPhotographic looks at
+const photoSamples = 64 * 1024
+
+// isPhotographic reports whether img has the many distinct colors of a photograph or gradient,
+// which JPEG compresses well, rather than the flat colors of text and UI, which PNG compresses well
+func isPhotographic(img image.Image) bool {
+	b := img.Bounds()
+	step := 1
+	for (b.Dx()/step)*(b.Dy()/step) > photoSamples {
+		step++
+	}
+	colors := make(map[[3]uint32]struct{})
+	samples := 0
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			// Ignore the lowest bits, so noise and dithering don't count as distinct colors
+			colors[[3]uint32{r >> 11, g >> 11, bl >> 11}] = struct{}{}
+			samples++
+		}
+	}
+	return len(colors) >= 1024 && len(colors)*8 >= samples
+}
@@ -6,6 +6,8 @@ import (
 	"image/color"
 	"image/jpeg"
 	"image/png"
+	"math/rand/v2"
+	"os"
 	"testing"
 )
 
@@ -146,3 +148,93 @@ func TestResizeImageNoResizeNeeded(t *testing.T) {
 		t.Error("Expected original data when no resize needed")
 	}
 }
+
+func TestResizeImageWebP(t *testing.T) {
+	data, err := os.ReadFile("testdata/blue-purple-pink.lossy.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	same, format, didResize, err := ResizeImage(data, 2000)
+	if err != nil || didResize || format != "webp" || !bytes.Equal(same, data) {
+		t.Errorf("ResizeImage(small webp) = %d bytes, %q, %v, %v; want the original webp", len(same), format, didResize, err)
+	}
+
+	resized, format, didResize, err := ResizeImage(data, 50)
+	if err != nil {
+		t.Fatalf("ResizeImage() error = %v", err)
+	}
+	if !didResize || format != "jpeg" {
+		t.Errorf("ResizeImage() = %q, %v; want a resized jpeg", format, didResize)
+	}
+	config, decodedFormat, err := image.DecodeConfig(bytes.NewReader(resized))
+	if err != nil || decodedFormat != "jpeg" || config.Width > 50 || config.Height > 50 {
+		t.Errorf("Resized image is %s %dx%d (%v)", decodedFormat, config.Width, config.Height, err)
+	}
+}
+
+func TestResizeImagePhotographicPNG(t *testing.T) {
+	// Noise stands in for a photograph: many distinct colors
+	rng := rand.New(rand.NewPCG(1, 2))
+	photo := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for y := range 300 {
+		for x := range 400 {
+			photo.Set(x, y, color.RGBA{R: uint8(rng.IntN(256)), G: uint8(rng.IntN(256)), B: uint8(rng.IntN(256)), A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, photo); err != nil {
+		t.Fatal(err)
+	}
+	if _, format, _, err := ResizeImage(buf.Bytes(), 200); err != nil || format != "jpeg" {
+		t.Errorf("ResizeImage(photo) format = %q, %v; want jpeg", format, err)
+	}
+
+	// Transparency needs PNG, however many colors there are
+	photo.Set(0, 0, color.RGBA{})
+	buf.Reset()
+	if err := png.Encode(&buf, photo); err != nil {
+		t.Fatal(err)
+	}
+	if _, format, _, err := ResizeImage(buf.Bytes(), 200); err != nil || format != "png" {
+		t.Errorf("ResizeImage(transparent photo) format = %q, %v; want png", format, err)
+	}
+}
+
+func TestResizeImageModeText(t *testing.T) {
+	// Thin dark strokes, like small text, spaced closely enough to blur together when scaled down
+	page := image.NewGray(image.Rect(0, 0, 1000, 200))
+	for y := range 200 {
+		for x := range 1000 {
+			c := uint8(0xff)
+			if x%8 < 2 {
+				c = 0x20
+			}
+			page.SetGray(x, y, color.Gray{Y: c})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, page); err != nil {
+		t.Fatal(err)
+	}
+
+	contrast := func(mode ResizeMode) int {
+		out, _, didResize, err := ResizeImageMode(buf.Bytes(), 300, mode)
+		if err != nil || !didResize {
+			t.Fatalf("ResizeImageMode(%d) = %v, %v", mode, didResize, err)
+		}
+		img, _, err := image.Decode(bytes.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lo, hi := 0xff, 0
+		for x := range img.Bounds().Dx() {
+			r, _, _, _ := img.At(x, 30).RGBA()
+			lo, hi = min(lo, int(r>>8)), max(hi, int(r>>8))
+		}
+		return hi - lo
+	}
+	if smooth, text := contrast(ResizeSmooth), contrast(ResizeText); text <= smooth {
+		t.Errorf("Expected ResizeText to keep more stroke contrast than ResizeSmooth, got %d vs %d", text, smooth)
+	}
+}
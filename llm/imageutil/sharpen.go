@@ -0,0 +1,39 @@
+package imageutil
+
+import "image"
+
+// textSharpening is how strongly ResizeText sharpens: enough to restore the stroke contrast of
+// downscaled text without haloing flat UI colors
+const textSharpening = 0.5
+
+// sharpen applies an unsharp mask to img, adding amount times the difference between each pixel
+// and the average of its 3x3 neighborhood
+func sharpen(img *image.RGBA, amount float64) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			var sum [3]int
+			n := 0
+			for ny := max(y-1, b.Min.Y); ny <= min(y+1, b.Max.Y-1); ny++ {
+				for nx := max(x-1, b.Min.X); nx <= min(x+1, b.Max.X-1); nx++ {
+					j := img.PixOffset(nx, ny)
+					sum[0] += int(img.Pix[j])
+					sum[1] += int(img.Pix[j+1])
+					sum[2] += int(img.Pix[j+2])
+					n++
+				}
+			}
+			// Colors are premultiplied, so keep them within alpha
+			alpha := float64(img.Pix[i+3])
+			for c := range 3 {
+				v := float64(img.Pix[i+c])
+				v += amount * (v - float64(sum[c])/float64(n))
+				out.Pix[i+c] = uint8(max(0, min(alpha, v+0.5)))
+			}
+			out.Pix[i+3] = img.Pix[i+3]
+		}
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// smartCropSamples is about how many pixels SmartCrop samples along the long side when looking for content
+const smartCropSamples = 512
+
+// SmartCrop reduces an image to width by height pixels, first cropping it to that aspect ratio where it has
+// the most detail (edges), so text and controls rather than empty margins survive the reduction.
+// The image is only scaled down, never up. Returns the encoded bytes and the format ("png" or "jpeg").
+func SmartCrop(data []byte, width, height int) (cropped []byte, format string, err error) {
+	if width <= 0 || height <= 0 {
+		return nil, "", fmt.Errorf("invalid crop size %dx%d", width, height)
+	}
+	img, detectedFormat, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	b := img.Bounds()
+	crop := b
+	if b.Dx()*height > b.Dy()*width {
+		w := b.Dy() * width / height
+		x := bestWindow(columnEnergy(img), b.Dx(), w)
+		crop = image.Rect(b.Min.X+x, b.Min.Y, b.Min.X+x+w, b.Max.Y)
+	} else if b.Dx()*height < b.Dy()*width {
+		h := b.Dx() * height / width
+		y := bestWindow(rowEnergy(img), b.Dy(), h)
+		crop = image.Rect(b.Min.X, b.Min.Y+y, b.Max.X, b.Min.Y+y+h)
+	}
+
+	w, h := min(width, crop.Dx()), min(height, crop.Dy())
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+
+	var buf bytes.Buffer
+	format = encodingFormat(img, detectedFormat)
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode cropped image: %w", err)
+	}
+	return buf.Bytes(), format, nil
+}
+
+// edgeStep is the sampling step for measuring the detail of img
+func edgeStep(img image.Image) int {
+	b := img.Bounds()
+	return max(1, max(b.Dx(), b.Dy())/smartCropSamples)
+}
+
+// edgeAt is the luminance gradient at (x, y) of img, measured step pixels right and down
+func edgeAt(img image.Image, x, y, step int) int {
+	b := img.Bounds()
+	gray := func(x, y int) int {
+		return int(color.GrayModel.Convert(img.At(min(x, b.Max.X-1), min(y, b.Max.Y-1))).(color.Gray).Y)
+	}
+	l := gray(x, y)
+	return abs(gray(x+step, y)-l) + abs(gray(x, y+step)-l)
+}
+
+// columnEnergy sums the detail in each sampled column of img, indexed by sample
+func columnEnergy(img image.Image) []int {
+	b, step := img.Bounds(), edgeStep(img)
+	energy := make([]int, (b.Dx()+step-1)/step)
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			energy[(x-b.Min.X)/step] += edgeAt(img, x, y, step)
+		}
+	}
+	return energy
+}
+
+// rowEnergy sums the detail in each sampled row of img, indexed by sample
+func rowEnergy(img image.Image) []int {
+	b, step := img.Bounds(), edgeStep(img)
+	energy := make([]int, (b.Dy()+step-1)/step)
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			energy[(y-b.Min.Y)/step] += edgeAt(img, x, y, step)
+		}
+	}
+	return energy
+}
+
+// bestWindow returns the offset of the window size pixels long, within length pixels sampled as energy,
+// that holds the most energy; of equally good windows, the one nearest the middle wins.
+func bestWindow(energy []int, length, size int) int {
+	step := (length + len(energy) - 1) / len(energy)
+	n := max(1, size/step)
+	best, bestOffset := -1, 0
+	middle := (len(energy) - n) / 2
+	sum := 0
+	for i, e := range energy {
+		sum += e
+		if i >= n {
+			sum -= energy[i-n]
+		}
+		start := i - n + 1
+		if start < 0 {
+			continue
+		}
+		if sum > best || sum == best && abs(start-middle) < abs(bestOffset-middle) {
+			best, bestOffset = sum, start
+		}
+	}
+	return min(bestOffset*step, length-size)
+}
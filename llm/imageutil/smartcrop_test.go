@@ -0,0 +1,63 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestSmartCrop(t *testing.T) {
+	// A wide white image with a checkered block near its right edge
+	img := image.NewRGBA(image.Rect(0, 0, 1200, 300))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	for y := 100; y < 200; y++ {
+		for x := 900; x < 1000; x++ {
+			if (x/10+y/10)%2 == 0 {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	out, format, err := SmartCrop(encodePNG(t, img), 150, 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" {
+		t.Errorf("format = %q, want png", format)
+	}
+	cropped, _, err := image.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := cropped.Bounds(); b.Dx() != 150 || b.Dy() != 150 {
+		t.Fatalf("SmartCrop() is %dx%d, want 150x150", b.Dx(), b.Dy())
+	}
+	// The 300px square crop, halved, should show the block
+	dark := 0
+	for y := range 150 {
+		for x := range 150 {
+			if r, _, _, _ := cropped.At(x, y).RGBA(); r < 0x8000 {
+				dark++
+			}
+		}
+	}
+	if dark < 1000 {
+		t.Errorf("Expected the crop to show the checkered block, found %d dark pixels", dark)
+	}
+
+	// A blank image is cropped around its middle without upscaling
+	blank := image.NewGray(image.Rect(0, 0, 100, 400))
+	out, _, err = SmartCrop(encodePNG(t, blank), 200, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config, _, err := image.DecodeConfig(bytes.NewReader(out)); err != nil || config.Width != 100 || config.Height != 50 {
+		t.Errorf("SmartCrop() of a blank image is %+v, %v; want 100x50", config, err)
+	}
+
+	if _, _, err := SmartCrop(encodePNG(t, blank), 0, 100); err == nil {
+		t.Error("Expected an error for a zero width")
+	}
+}
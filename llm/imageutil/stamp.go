@@ -0,0 +1,54 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/font"
+)
+
+// stampColor is the translucent background of a Stamp
+var stampColor = color.NRGBA{A: 0xb0}
+
+// Stamp writes a line of text, such as when and where a screenshot was taken, into the bottom-right corner
+// of an image, cutting it short with an ellipsis if it is wider than the image.
+// JPEG images stay JPEG and others become PNG. Returns the encoded bytes and the format.
+func Stamp(data []byte, text string) (stamped []byte, format string, err error) {
+	img, detectedFormat, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	face, err := labelFace()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load label font: %w", err)
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, img, b.Min, draw.Src)
+
+	runes := []rune(text)
+	for len(runes) > 1 && font.MeasureString(face, string(runes)).Ceil()+2*labelPadding > b.Dx() {
+		runes = append(runes[:len(runes)-2], '…')
+	}
+	if err := DrawLabel(dst, b.Max, string(runes), stampColor); err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	format = "png"
+	if detectedFormat == "jpeg" {
+		format = "jpeg"
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode stamped image: %w", err)
+	}
+	return buf.Bytes(), format, nil
+}
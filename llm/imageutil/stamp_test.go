@@ -0,0 +1,40 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"testing"
+)
+
+func TestStamp(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	text := "2026-10-16 12:00:00 UTC | https://example.com/a/very/long/path/that/does/not/fit | 1280x720"
+
+	out, format, err := Stamp(encodePNG(t, img), text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" {
+		t.Errorf("format = %q, want png", format)
+	}
+	stamped, _, err := image.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stamped.Bounds() != img.Bounds() {
+		t.Fatalf("Stamp() changed the bounds to %v", stamped.Bounds())
+	}
+	// The stamp darkens the bottom-right corner and leaves the top-left alone
+	if r, _, _, _ := stamped.At(298, 198).RGBA(); r > 0x8000 {
+		t.Errorf("Expected a dark stamp in the bottom-right corner, got red %#x", r)
+	}
+	if r, _, _, _ := stamped.At(1, 1).RGBA(); r != 0xffff {
+		t.Errorf("Expected the top-left corner unchanged, got red %#x", r)
+	}
+
+	if _, _, err := Stamp([]byte("not an image"), text); err == nil {
+		t.Error("Expected an error for invalid data")
+	}
+}
@@ -0,0 +1,89 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+	"image"
+	"image/draw"
+	"image/png"
+	"slices"
+)
+
+// StitchVertical stacks images of the same width top to bottom into one PNG, such as captures
+// of a page scrolled a viewport at a time. Where the bottom rows of an image are repeated at the
+// top of the next, as when the last scroll was cut short by the end of the page, they appear once.
+func StitchVertical(images [][]byte) ([]byte, error) {
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no images to stitch")
+	}
+	decoded := make([]image.Image, len(images))
+	rows := make([][]uint64, len(images))
+	seed := maphash.MakeSeed()
+	for i, data := range images {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image %d: %w", i, err)
+		}
+		if i > 0 && img.Bounds().Dx() != decoded[0].Bounds().Dx() {
+			return nil, fmt.Errorf("image %d is %d pixels wide, not %d like the first", i, img.Bounds().Dx(), decoded[0].Bounds().Dx())
+		}
+		decoded[i] = img
+		rows[i] = rowHashes(img, seed)
+	}
+
+	// skip[i] is how many top rows of image i repeat the previous image
+	skip := make([]int, len(images))
+	height := len(rows[0])
+	for i := 1; i < len(images); i++ {
+		skip[i] = verticalOverlap(rows[i-1], rows[i])
+		height += len(rows[i]) - skip[i]
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, decoded[0].Bounds().Dx(), height))
+	y := 0
+	for i, img := range decoded {
+		b := img.Bounds()
+		h := b.Dy() - skip[i]
+		draw.Draw(out, image.Rect(0, y, b.Dx(), y+h), img, image.Pt(b.Min.X, b.Min.Y+skip[i]), draw.Src)
+		y += h
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, fmt.Errorf("failed to encode stitched image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// rowHashes hashes each row of img's pixels
+func rowHashes(img image.Image, seed maphash.Seed) []uint64 {
+	b := img.Bounds()
+	hashes := make([]uint64, b.Dy())
+	var h maphash.Hash
+	h.SetSeed(seed)
+	px := make([]byte, 0, 8*b.Dx())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		px = px[:0]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			px = append(px, byte(r>>8), byte(g>>8), byte(bl>>8), byte(a>>8))
+		}
+		h.Reset()
+		h.Write(px)
+		hashes[y-b.Min.Y] = h.Sum64()
+	}
+	return hashes
+}
+
+// verticalOverlap returns the most rows at the bottom of above that repeat at the top of below.
+// Overlaps of uniform rows (e.g. a blank background) are ignored, since any amount would match.
+func verticalOverlap(above, below []uint64) int {
+	for n := min(len(above), len(below)) - 1; n > 0; n-- {
+		top := below[:n]
+		if slices.Equal(above[len(above)-n:], top) && slices.ContainsFunc(top, func(h uint64) bool { return h != top[0] }) {
+			return n
+		}
+	}
+	return 0
+}
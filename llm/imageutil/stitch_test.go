@@ -0,0 +1,47 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestStitchVertical(t *testing.T) {
+	// A 300px tall page, captured in 100px viewports at 0, 100, and (at the end of the page) 150
+	page := image.NewRGBA(image.Rect(0, 0, 10, 250))
+	for y := range 250 {
+		for x := range 10 {
+			page.Set(x, y, color.RGBA{R: uint8(y), G: uint8(x), A: 255})
+		}
+	}
+	var captures [][]byte
+	for _, top := range []int{0, 100, 150} {
+		captures = append(captures, encodePNG(t, page.SubImage(image.Rect(0, top, 10, top+100))))
+	}
+
+	out, err := StitchVertical(captures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 10 || b.Dy() != 250 {
+		t.Fatalf("StitchVertical() is %dx%d, want 10x250", b.Dx(), b.Dy())
+	}
+	for _, y := range []int{0, 99, 100, 199, 249} {
+		if r, _, _, _ := img.At(3, y).RGBA(); int(r>>8) != y {
+			t.Errorf("Row %d shows page row %d", y, r>>8)
+		}
+	}
+
+	if _, err := StitchVertical([][]byte{captures[0], createTestPNG(t, 20, 100)}); err == nil {
+		t.Error("Expected an error for images of different widths")
+	}
+	if _, err := StitchVertical(nil); err == nil {
+		t.Error("Expected an error for no images")
+	}
+}
@@ -0,0 +1,56 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// Tile splits an image taller than tileHeight into full-width tiles tileHeight tall, each overlapping
+// the previous by overlap pixels so content cut at a tile edge appears whole in one of them.
+// The last tile is aligned with the bottom of the image, so it may overlap more.
+// Tiles keep the source format if it is JPEG and are PNG otherwise; an image that fits is returned as is.
+func Tile(data []byte, tileHeight, overlap int) (tiles [][]byte, format string, err error) {
+	if tileHeight <= 0 || overlap < 0 || overlap >= tileHeight {
+		return nil, "", fmt.Errorf("invalid tile height %d with overlap %d", tileHeight, overlap)
+	}
+	img, detectedFormat, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	b := img.Bounds()
+	if b.Dy() <= tileHeight {
+		return [][]byte{data}, detectedFormat, nil
+	}
+
+	format = "png"
+	if detectedFormat == "jpeg" {
+		format = "jpeg"
+	}
+	sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, "", fmt.Errorf("cannot tile %s images", detectedFormat)
+	}
+	step := tileHeight - overlap
+	for y := b.Min.Y; ; y += step {
+		y = min(y, b.Max.Y-tileHeight)
+		tile := sub.SubImage(image.Rect(b.Min.X, y, b.Max.X, y+tileHeight))
+		var buf bytes.Buffer
+		if format == "jpeg" {
+			err = jpeg.Encode(&buf, tile, &jpeg.Options{Quality: 85})
+		} else {
+			err = png.Encode(&buf, tile)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode tile: %w", err)
+		}
+		tiles = append(tiles, buf.Bytes())
+		if y+tileHeight >= b.Max.Y {
+			return tiles, format, nil
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestTile(t *testing.T) {
+	// Each row's color encodes its y, so tiles can be located in the original
+	tall := image.NewRGBA(image.Rect(0, 0, 20, 250))
+	for y := range 250 {
+		for x := range 20 {
+			tall.Set(x, y, color.RGBA{R: uint8(y), A: 255})
+		}
+	}
+	data := encodePNG(t, tall)
+
+	tiles, format, err := Tile(data, 100, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" || len(tiles) != 3 {
+		t.Fatalf("Tile() = %d %s tiles, want 3 png", len(tiles), format)
+	}
+	for i, wantTop := range []uint8{0, 80, 150} {
+		img, err := png.Decode(bytes.NewReader(tiles[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 100 {
+			t.Errorf("Tile %d is %dx%d, want 20x100", i, b.Dx(), b.Dy())
+		}
+		if r, _, _, _ := img.At(img.Bounds().Min.X, img.Bounds().Min.Y).RGBA(); uint8(r>>8) != wantTop {
+			t.Errorf("Tile %d starts at row %d, want %d", i, r>>8, wantTop)
+		}
+	}
+
+	if tiles, _, err := Tile(data, 300, 20); err != nil || len(tiles) != 1 || !bytes.Equal(tiles[0], data) {
+		t.Errorf("Tile(short image) = %d tiles, %v; want the original", len(tiles), err)
+	}
+	for _, overlap := range []int{-1, 100} {
+		if _, _, err := Tile(data, 100, overlap); err == nil {
+			t.Errorf("Expected an error for overlap %d", overlap)
+		}
+	}
+}
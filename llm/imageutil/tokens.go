@@ -0,0 +1,9 @@
+package imageutil
+
+// pixelsPerToken is how many pixels of an image make up one input token, per Anthropic's vision documentation
+const pixelsPerToken = 750
+
+// EstimateTokens estimates the input tokens an LLM charges for an image of width by height pixels
+func EstimateTokens(width, height int) int {
+	return (width*height + pixelsPerToken - 1) / pixelsPerToken
+}
@@ -0,0 +1,34 @@
+package imageutil
+
+import "testing"
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		width, height, want int
+	}{
+		{1000, 1000, 1334},
+		{750, 1, 1},
+		{751, 1, 2},
+		{0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.width, tt.height); got != tt.want {
+			t.Errorf("EstimateTokens(%d, %d) = %d, want %d", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestFitDimensions(t *testing.T) {
+	tests := []struct {
+		width, height, max, wantWidth, wantHeight int
+	}{
+		{800, 600, 1000, 800, 600},
+		{4000, 3000, 2000, 2000, 1500},
+		{1000, 3000, 1500, 500, 1500},
+	}
+	for _, tt := range tests {
+		if w, h := FitDimensions(tt.width, tt.height, tt.max); w != tt.wantWidth || h != tt.wantHeight {
+			t.Errorf("FitDimensions(%d, %d, %d) = %d, %d, want %d, %d", tt.width, tt.height, tt.max, w, h, tt.wantWidth, tt.wantHeight)
+		}
+	}
+}
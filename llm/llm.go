@@ -19,10 +19,8 @@ type Service interface {
 	Do(context.Context, *Request) (*Response, error)
 	// TokenContextWindow returns the maximum token context window size for this service
 	TokenContextWindow() int
-	// MaxImageDimension returns the maximum allowed dimension (width or height) for images.
-	// For multi-image requests, some providers enforce stricter limits.
-	// Returns 0 if there is no limit.
-	MaxImageDimension() int
+	// ImageLimits returns the provider's constraints on images, which Do fits images to before sending them.
+	ImageLimits() ImageLimits
 }
 
 type SimplifiedPatcher interface {
@@ -67,6 +65,7 @@ const (
 	ErrorTypeNone       ErrorType = ""            // Not an error
 	ErrorTypeTruncation ErrorType = "truncation"  // Response truncated due to max tokens
 	ErrorTypeLLMRequest ErrorType = "llm_request" // LLM request failed
+	ErrorTypeBudget     ErrorType = "budget"      // Conversation reached its cost budget
 )
 
 type Request struct {
@@ -88,7 +87,7 @@ type Message struct {
 	ExcludedFromContext bool `json:"ExcludedFromContext,omitempty"`
 
 	// ErrorType indicates this is a system-generated error message (not LLM content).
-	// Empty string means not an error. Values: "truncation", "llm_request".
+	// Empty string means not an error; the others are the ErrorType constants above.
 	ErrorType ErrorType `json:"ErrorType,omitempty"`
 }
 
@@ -121,10 +120,20 @@ type Tool struct {
 	EndsTurn bool
 	// Cache indicates whether to use prompt caching for this tool
 	Cache bool
+	// LenientInput skips validating input against InputSchema before Run,
+	// for tools that make sense of near misses of their schema themselves.
+	LenientInput bool
+	// Serialize names state the tool shares with other tools, such as a browser.
+	// When the model calls several tools at once, the calls run concurrently,
+	// except that calls to tools with the same Serialize run one at a time, in order.
+	Serialize string
+	// Exclusive runs each call to the tool alone: after the calls before it in a response finish,
+	// and before the calls after it start. It is for tools, such as change_dir, that alter what the others depend on.
+	Exclusive bool
 
 	// The Run function is automatically called when the tool is used.
 	// Run functions may be called concurrently with each other and themselves.
-	// The input to Run function is the input to the tool, as provided by Claude, in compliance with the input schema.
+	// The input to Run function is the input to the tool, as provided by Claude, validated against the input schema (see ValidateInput).
 	// The outputs from Run will be sent back to Claude.
 	// If you do not want to respond to the tool call request from Claude, return ErrDoNotRespond.
 	// ctx contains extra (rarely used) tool call information; retrieve it with ToolCallInfoFromContext.
@@ -149,14 +158,14 @@ type ToolOut struct {
 type Content struct {
 	ID   string
 	Type ContentType
-	Text string
+	Text string // for document, its title, if any
 
-	// Media type for image content
+	// Media type for image and document content
 	MediaType string
 
 	// for thinking
 	Thinking  string
-	Data      string
+	Data      string // also the base64 encoded data of an image or document
 	Signature string
 
 	// for tool_use
@@ -178,6 +187,19 @@ type Content struct {
 	Cache bool
 }
 
+// UnmarshalJSON decodes c, turning images stored before they had ContentTypeImage,
+// as text with a MediaType and Data, into images.
+func (c *Content) UnmarshalJSON(data []byte) error {
+	type content Content // without this method
+	if err := json.Unmarshal(data, (*content)(c)); err != nil {
+		return err
+	}
+	if c.Type == ContentTypeText && c.MediaType != "" && c.Data != "" {
+		c.Type = ContentTypeImage
+	}
+	return nil
+}
+
 func StringContent(s string) Content {
 	return Content{Type: ContentTypeText, Text: s}
 }
@@ -199,6 +221,8 @@ func ContentsAttr(contents []Content) slog.Attr {
 			attrs = append(attrs, slog.Bool("tool_error", content.ToolError))
 		case ContentTypeThinking:
 			attrs = append(attrs, slog.String("thinking", content.Thinking))
+		case ContentTypeImage, ContentTypeDocument:
+			attrs = append(attrs, slog.String("media_type", content.MediaType), slog.Int("data_len", len(content.Data)))
 		default:
 			attrs = append(attrs, slog.String("unknown_content_type", content.Type.String()))
 			attrs = append(attrs, slog.Any("text", content)) // just log it all raw, better to have too much than not enough
@@ -240,6 +264,12 @@ const (
 	StopReasonRefusal
 )
 
+// Content types added later have their own block, so the values of those above, which are stored, do not change.
+const (
+	ContentTypeDocument ContentType = ContentTypeToolResult + 1 + iota // a document, such as a PDF, with MediaType and Data
+	ContentTypeImage                                                   // an image with MediaType and Data; see ImageContent
+)
+
 // ThinkingLevel controls how much thinking/reasoning the model does.
 // ThinkingLevelOff is the zero value and disables thinking.
 const (
@@ -282,6 +312,17 @@ func (t ThinkingLevel) ThinkingEffort() string {
 	}
 }
 
+// UnmarshalText sets t from its ThinkingEffort, or "off", as in a configuration file.
+func (t *ThinkingLevel) UnmarshalText(text []byte) error {
+	for l := ThinkingLevelOff; l <= ThinkingLevelHigh; l++ {
+		if string(text) == l.ThinkingEffort() || l == ThinkingLevelOff && string(text) == "off" {
+			*t = l
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown thinking level %q: want off, minimal, low, medium, or high", text)
+}
+
 type Response struct {
 	ID           string
 	Type         string
@@ -339,6 +380,23 @@ func (u *Usage) Add(other Usage) {
 	u.CostUSD += other.CostUSD
 }
 
+// Pricing is the price of a model's tokens, in USD per million tokens.
+// It is used to compute the cost of requests when the API does not report it.
+type Pricing struct {
+	Input      float64
+	Output     float64
+	CacheWrite float64
+	CacheRead  float64
+}
+
+// Cost returns the cost of u in USD.
+func (p Pricing) Cost(u Usage) float64 {
+	return (float64(u.InputTokens)*p.Input +
+		float64(u.OutputTokens)*p.Output +
+		float64(u.CacheCreationInputTokens)*p.CacheWrite +
+		float64(u.CacheReadInputTokens)*p.CacheRead) / 1_000_000
+}
+
 func (u *Usage) String() string {
 	return fmt.Sprintf("in: %d, out: %d", u.InputTokens, u.OutputTokens)
 }
@@ -373,6 +431,96 @@ func (u *Usage) Attr() slog.Attr {
 	)
 }
 
+// RequestJSON returns what of ir the LLM sees as JSON, such as message text, tool calls, and tool schemas,
+// so that requests the LLM would see as the same have the same JSON. It is an explicit subset of ir,
+// which fields added to the llm types for shelley's own use, like the times of tool calls, don't change.
+func RequestJSON(ir *Request) ([]byte, error) {
+	r := requestJSON{Messages: make([]messageJSON, len(ir.Messages))}
+	for i, m := range ir.Messages {
+		r.Messages[i] = messageJSON{Role: m.Role.String(), Content: contentsJSON(m.Content)}
+	}
+	if ir.ToolChoice != nil {
+		r.ToolChoice = &toolChoiceJSON{Type: ir.ToolChoice.Type.String(), Name: ir.ToolChoice.Name}
+	}
+	for _, t := range ir.Tools {
+		r.Tools = append(r.Tools, toolJSON{Name: t.Name, Type: t.Type, Description: t.Description, InputSchema: t.InputSchema})
+	}
+	for _, sc := range ir.System {
+		r.System = append(r.System, systemJSON{Text: sc.Text, Type: sc.Type})
+	}
+	return json.Marshal(r)
+}
+
+// requestJSON and the types it holds are the JSON of RequestJSON
+type requestJSON struct {
+	System     []systemJSON    `json:"system,omitempty"`
+	Tools      []toolJSON      `json:"tools,omitempty"`
+	ToolChoice *toolChoiceJSON `json:"tool_choice,omitempty"`
+	Messages   []messageJSON   `json:"messages"`
+}
+
+type systemJSON struct {
+	Text string `json:"text"`
+	Type string `json:"type,omitempty"`
+}
+
+type toolJSON struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type,omitempty"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema,omitempty"`
+}
+
+type toolChoiceJSON struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type messageJSON struct {
+	Role    string        `json:"role"`
+	Content []contentJSON `json:"content"`
+}
+
+type contentJSON struct {
+	Type       string          `json:"type"`
+	ID         string          `json:"id,omitempty"`
+	Text       string          `json:"text,omitempty"`
+	MediaType  string          `json:"media_type,omitempty"`
+	Thinking   string          `json:"thinking,omitempty"`
+	Data       string          `json:"data,omitempty"`
+	Signature  string          `json:"signature,omitempty"`
+	ToolName   string          `json:"tool_name,omitempty"`
+	ToolInput  json.RawMessage `json:"tool_input,omitempty"`
+	ToolUseID  string          `json:"tool_use_id,omitempty"`
+	ToolError  bool            `json:"tool_error,omitempty"`
+	ToolResult []contentJSON   `json:"tool_result,omitempty"`
+}
+
+func contentsJSON(contents []Content) []contentJSON {
+	out := make([]contentJSON, len(contents))
+	for i, c := range contents {
+		// Contents decoded from JSON have a null ToolInput rather than none
+		if string(c.ToolInput) == "null" {
+			c.ToolInput = nil
+		}
+		out[i] = contentJSON{
+			Type:       c.Type.String(),
+			ID:         c.ID,
+			Text:       c.Text,
+			MediaType:  c.MediaType,
+			Thinking:   c.Thinking,
+			Data:       c.Data,
+			Signature:  c.Signature,
+			ToolName:   c.ToolName,
+			ToolInput:  c.ToolInput,
+			ToolUseID:  c.ToolUseID,
+			ToolError:  c.ToolError,
+			ToolResult: contentsJSON(c.ToolResult),
+		}
+	}
+	return out
+}
+
 // UserStringMessage creates a user message with a single text content item.
 func UserStringMessage(text string) Message {
 	return Message{
@@ -32,11 +32,13 @@ func _() {
 	_ = x[ContentTypeRedactedThinking-4]
 	_ = x[ContentTypeToolUse-5]
 	_ = x[ContentTypeToolResult-6]
+	_ = x[ContentTypeDocument-7]
+	_ = x[ContentTypeImage-8]
 }
 
-const _ContentType_name = "ContentTypeTextContentTypeThinkingContentTypeRedactedThinkingContentTypeToolUseContentTypeToolResult"
+const _ContentType_name = "ContentTypeTextContentTypeThinkingContentTypeRedactedThinkingContentTypeToolUseContentTypeToolResultContentTypeDocumentContentTypeImage"
 
-var _ContentType_index = [...]uint8{0, 15, 34, 61, 79, 100}
+var _ContentType_index = [...]uint8{0, 15, 34, 61, 79, 100, 119, 135}
 
 func (i ContentType) String() string {
 	idx := int(i) - 2
@@ -4,14 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 // mockService implements Service interface for testing
 type mockService struct {
 	tokenContextWindow   int
-	maxImageDimension    int
+	imageLimits          ImageLimits
 	useSimplifiedPatch   bool
 	implementsSimplified bool
 }
@@ -24,8 +27,8 @@ func (m *mockService) TokenContextWindow() int {
 	return m.tokenContextWindow
 }
 
-func (m *mockService) MaxImageDimension() int {
-	return m.maxImageDimension
+func (m *mockService) ImageLimits() ImageLimits {
+	return m.imageLimits
 }
 
 // mockSimplifiedService implements both Service and SimplifiedPatcher interfaces
@@ -538,6 +541,20 @@ func TestUsageAttr(t *testing.T) {
 	}
 }
 
+func TestPricingCost(t *testing.T) {
+	p := Pricing{Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3}
+	usage := Usage{
+		InputTokens:              1000,
+		OutputTokens:             2000,
+		CacheCreationInputTokens: 10000,
+		CacheReadInputTokens:     100000,
+	}
+	// 0.003 + 0.03 + 0.0375 + 0.03
+	if got, want := p.Cost(usage), 0.1005; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost() = %v, want %v", got, want)
+	}
+}
+
 func TestDumpToFile(t *testing.T) {
 	// This test just verifies the function exists and can be called
 	// We don't actually want to write files during testing
@@ -547,3 +564,55 @@ func TestDumpToFile(t *testing.T) {
 	// This might fail due to permissions, but it shouldn't panic
 	_ = DumpToFile("test", "http://example.com", content)
 }
+
+func TestThinkingLevelUnmarshalText(t *testing.T) {
+	var cfg struct {
+		Level ThinkingLevel `json:"level"`
+	}
+	for text, want := range map[string]ThinkingLevel{"off": ThinkingLevelOff, "minimal": ThinkingLevelMinimal, "high": ThinkingLevelHigh} {
+		if err := json.Unmarshal([]byte(`{"level":"`+text+`"}`), &cfg); err != nil || cfg.Level != want {
+			t.Errorf("unmarshaling %q = %v, %v; want %v", text, cfg.Level, err, want)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"level":"extreme"}`), &cfg); err == nil {
+		t.Error("unmarshaling an unknown level should fail")
+	}
+}
+
+func TestRequestJSON(t *testing.T) {
+	request := func(internal bool) *Request {
+		now := time.Now()
+		tool := &Tool{Name: "patch", Description: "Edits files", InputSchema: EmptySchema()}
+		result := Content{Type: ContentTypeToolResult, ToolUseID: "call_1", ToolResult: []Content{StringContent("ok")}}
+		if internal {
+			// What shelley keeps for itself, and is not sent to the LLM
+			tool.LenientInput, tool.Serialize, tool.Exclusive, tool.Cache = true, "workspace", true, true
+			result.ToolUseStartTime, result.ToolUseEndTime, result.Display, result.Cache = &now, &now, "shown", true
+		}
+		return &Request{
+			System: []SystemContent{{Text: "Be terse.", Cache: internal}},
+			Tools:  []*Tool{tool},
+			Messages: []Message{
+				UserStringMessage("edit it"),
+				{Role: MessageRoleAssistant, Content: []Content{{ID: "call_1", Type: ContentTypeToolUse, ToolName: "patch", ToolInput: json.RawMessage(`{}`)}}, EndOfTurn: internal},
+				{Role: MessageRoleUser, Content: []Content{result}},
+			},
+		}
+	}
+	plain, err := RequestJSON(request(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	internal, err := RequestJSON(request(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plain) != string(internal) {
+		t.Errorf("fields not sent to the LLM changed the request JSON:\n%s\n%s", plain, internal)
+	}
+	for _, want := range []string{`"role":"MessageRoleAssistant"`, `"tool_input":{}`, `"tool_use_id":"call_1"`, `"input_schema"`} {
+		if !strings.Contains(string(plain), want) {
+			t.Errorf("request JSON lacks %s: %s", want, plain)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ToolRunFunc runs a tool; it is the type of Tool.Run.
+type ToolRunFunc func(ctx context.Context, input json.RawMessage) ToolOut
+
+// ToolMiddleware adds behavior shared by all tools, such as logging, redaction, approval, or caching,
+// around the Run of tool. It returns a function that runs the tool, usually by calling next.
+type ToolMiddleware func(tool *Tool, next ToolRunFunc) ToolRunFunc
+
+// WithMiddleware returns copies of tools whose Run goes through middleware, the first outermost.
+func WithMiddleware(tools []*Tool, middleware ...ToolMiddleware) []*Tool {
+	if len(middleware) == 0 {
+		return tools
+	}
+	wrapped := make([]*Tool, len(tools))
+	for i, t := range tools {
+		tool := *t
+		run := ToolRunFunc(t.Run)
+		for j := len(middleware) - 1; j >= 0; j-- {
+			run = middleware[j](t, run)
+		}
+		tool.Run = run
+		wrapped[i] = &tool
+	}
+	return wrapped
+}
@@ -0,0 +1,42 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	tool := &Tool{Name: "echo", Run: func(ctx context.Context, input json.RawMessage) ToolOut {
+		return ToolOut{LLMContent: TextContent(string(input))}
+	}}
+	var calls []string
+	trace := func(name string) ToolMiddleware {
+		return func(tool *Tool, next ToolRunFunc) ToolRunFunc {
+			return func(ctx context.Context, input json.RawMessage) ToolOut {
+				calls = append(calls, name+":"+tool.Name)
+				return next(ctx, input)
+			}
+		}
+	}
+	redact := func(tool *Tool, next ToolRunFunc) ToolRunFunc {
+		return func(ctx context.Context, input json.RawMessage) ToolOut {
+			out := next(ctx, input)
+			out.LLMContent = TextContent(strings.ReplaceAll(out.LLMContent[0].Text, "secret", "[REDACTED]"))
+			return out
+		}
+	}
+
+	wrapped := WithMiddleware([]*Tool{tool}, trace("outer"), redact, trace("inner"))
+	out := wrapped[0].Run(context.Background(), json.RawMessage(`"my secret"`))
+	if got := out.LLMContent[0].Text; got != `"my [REDACTED]"` {
+		t.Errorf("Run() = %s, want the output redacted", got)
+	}
+	if got := strings.Join(calls, ","); got != "outer:echo,inner:echo" {
+		t.Errorf("middleware ran in order %s, want outer:echo,inner:echo", got)
+	}
+	if out := tool.Run(context.Background(), json.RawMessage(`"my secret"`)); out.LLMContent[0].Text != `"my secret"` {
+		t.Error("expected the original tool to be left alone")
+	}
+}
@@ -0,0 +1,31 @@
+package oai
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultAzureAPIVersion is the Azure OpenAI API version used when the endpoint does not specify one.
+const DefaultAzureAPIVersion = "2024-10-21"
+
+// azureConfig returns a client config for the Azure OpenAI resource at endpoint
+// (e.g. "https://my-resource.openai.azure.com"), which may set the API version with an api-version
+// query parameter. Requests go to the deployment named by the request's model name.
+func azureConfig(apiKey, endpoint string) (openai.ClientConfig, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return openai.ClientConfig{}, fmt.Errorf("invalid Azure OpenAI endpoint %q: %w", endpoint, err)
+	}
+	version := u.Query().Get("api-version")
+	if version == "" {
+		version = DefaultAzureAPIVersion
+	}
+	u.RawQuery = ""
+	config := openai.DefaultAzureConfig(apiKey, u.String())
+	config.APIVersion = version
+	// The default mapper strips dots from model names, but deployment names are used verbatim
+	config.AzureModelMapperFunc = func(deployment string) string { return deployment }
+	return config, nil
+}
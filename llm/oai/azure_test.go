@@ -0,0 +1,57 @@
+package oai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"shelley.exe.dev/llm"
+)
+
+func TestServiceDoAzure(t *testing.T) {
+	tests := []struct {
+		name        string
+		endpoint    string
+		wantVersion string
+	}{
+		{name: "default version", endpoint: "", wantVersion: DefaultAzureAPIVersion},
+		{name: "version from endpoint", endpoint: "?api-version=2025-01-01-preview", wantVersion: "2025-01-01-preview"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/openai/deployments/gpt-4.1-prod/chat/completions" {
+					t.Errorf("Expected deployment path, got %s", r.URL.Path)
+				}
+				if got := r.URL.Query().Get("api-version"); got != tt.wantVersion {
+					t.Errorf("api-version = %q, expected %q", got, tt.wantVersion)
+				}
+				if got := r.Header.Get("api-key"); got != "test-api-key" {
+					t.Errorf("api-key header = %q, expected test-api-key", got)
+				}
+				json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+					Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "ok"}, FinishReason: "stop"}},
+				})
+			}))
+			defer server.Close()
+
+			svc := &Service{
+				APIKey:   "test-api-key",
+				Model:    Model{ModelName: "gpt-4.1-prod"},
+				ModelURL: server.URL + tt.endpoint,
+				Azure:    true,
+			}
+			req := &llm.Request{Messages: []llm.Message{{Role: llm.MessageRoleUser, Content: []llm.Content{llm.StringContent("Hello!")}}}}
+			resp, err := svc.Do(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Content[0].Text != "ok" {
+				t.Errorf("Expected response text ok, got %+v", resp.Content)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package oai
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"shelley.exe.dev/llm"
+)
+
+var _ llm.Batcher = (*Service)(nil)
+
+// https://platform.openai.com/docs/guides/batch
+
+// BatchesSupported reports whether the service's endpoint is the OpenAI API, the only one whose batches it speaks.
+// Other OpenAI-compatible providers, and Azure, have batch APIs of their own or none.
+func (s *Service) BatchesSupported() bool {
+	return !s.Azure && cmp.Or(s.ModelURL, cmp.Or(s.Model, DefaultModel).URL, OpenAIURL) == OpenAIURL
+}
+
+// batchOutput is a line of the output or error file of a batch
+type batchOutput struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// SubmitBatch uploads reqs as a file of Chat Completions requests and creates a batch of them.
+func (s *Service) SubmitBatch(ctx context.Context, reqs []llm.BatchRequest) (*llm.Batch, error) {
+	model := cmp.Or(s.Model, DefaultModel)
+	var file openai.UploadBatchFileRequest
+	for _, r := range reqs {
+		ir, err := fitMedia(r.Request, model)
+		if err != nil {
+			return nil, fmt.Errorf("batch request %s: %w", r.ID, err)
+		}
+		file.AddChatCompletion(r.ID, s.chatRequest(ir))
+	}
+	client, baseURL, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.CreateBatchWithUploadFile(ctx, openai.CreateBatchWithUploadFileRequest{
+		Endpoint:               openai.BatchEndpointChatCompletions,
+		CompletionWindow:       "24h",
+		UploadBatchFileRequest: file,
+	})
+	if err != nil {
+		return nil, apiError(ctx, err, baseURL+"/batches", model.ModelName)
+	}
+	return toLLMBatch(&resp.Batch), nil
+}
+
+// Batch returns the current state of the batch with the given ID.
+func (s *Service) Batch(ctx context.Context, id string) (*llm.Batch, error) {
+	b, err := s.retrieveBatch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toLLMBatch(b), nil
+}
+
+// CancelBatch cancels the batch with the given ID.
+func (s *Service) CancelBatch(ctx context.Context, id string) (*llm.Batch, error) {
+	client, baseURL, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.CancelBatch(ctx, id)
+	if err != nil {
+		return nil, apiError(ctx, err, baseURL+"/batches/"+id+"/cancel", cmp.Or(s.Model, DefaultModel).ModelName)
+	}
+	return toLLMBatch(&resp.Batch), nil
+}
+
+func (s *Service) retrieveBatch(ctx context.Context, id string) (*openai.Batch, error) {
+	client, baseURL, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.RetrieveBatch(ctx, id)
+	if err != nil {
+		return nil, apiError(ctx, err, baseURL+"/batches/"+id, cmp.Or(s.Model, DefaultModel).ModelName)
+	}
+	return &resp.Batch, nil
+}
+
+// toLLMBatch converts b to an llm.Batch. Requests a canceled or expired batch never processed
+// count as canceled or expired.
+func toLLMBatch(b *openai.Batch) *llm.Batch {
+	batch := &llm.Batch{
+		ID:        b.ID,
+		Status:    llm.BatchInProgress,
+		CreatedAt: time.Unix(int64(b.CreatedAt), 0),
+		Counts: llm.BatchCounts{
+			Succeeded: b.RequestCounts.Completed,
+			Errored:   b.RequestCounts.Failed,
+		},
+	}
+	rest := b.RequestCounts.Total - b.RequestCounts.Completed - b.RequestCounts.Failed
+	endedAt := func(at *int) {
+		batch.Status = llm.BatchEnded
+		if at != nil {
+			batch.EndedAt = time.Unix(int64(*at), 0)
+		}
+	}
+	switch b.Status {
+	case "cancelling":
+		batch.Status = llm.BatchCanceling
+		batch.Counts.Processing = rest
+	case "completed":
+		endedAt(b.CompletedAt)
+	case "failed":
+		endedAt(b.FailedAt)
+		batch.Counts.Errored += rest
+	case "expired":
+		endedAt(b.ExpiredAt)
+		batch.Counts.Expired = rest
+	case "cancelled":
+		endedAt(b.CancelledAt)
+		batch.Counts.Canceled = rest
+	default: // validating, in_progress, or finalizing
+		batch.Counts.Processing = rest
+	}
+	return batch
+}
+
+// BatchResults returns the results of the ended batch with the given ID, read from its output and error files.
+// Their cost is half that of the same requests sent one at a time.
+func (s *Service) BatchResults(ctx context.Context, id string) ([]llm.BatchResult, error) {
+	b, err := s.retrieveBatch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if b.Status == "failed" && b.Errors != nil {
+		var msgs []string
+		for _, e := range b.Errors.Data {
+			msgs = append(msgs, e.Message)
+		}
+		return nil, fmt.Errorf("batch %s failed: %s", id, strings.Join(msgs, "; "))
+	}
+	if toLLMBatch(b).Status != llm.BatchEnded {
+		return nil, fmt.Errorf("batch %s has no results yet; it is %s", id, b.Status)
+	}
+	var results []llm.BatchResult
+	for _, fileID := range []*string{b.OutputFileID, b.ErrorFileID} {
+		if fileID == nil || *fileID == "" {
+			continue
+		}
+		fileResults, err := s.batchFileResults(ctx, *fileID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fileResults...)
+	}
+	return results, nil
+}
+
+// batchFileResults returns the results in the batch output or error file with the given ID
+func (s *Service) batchFileResults(ctx context.Context, fileID string) ([]llm.BatchResult, error) {
+	client, baseURL, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	content, err := client.GetFileContent(ctx, fileID)
+	if err != nil {
+		return nil, apiError(ctx, err, baseURL+"/files/"+fileID+"/content", cmp.Or(s.Model, DefaultModel).ModelName)
+	}
+	defer content.Close()
+
+	var results []llm.BatchResult
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var out batchOutput
+		if err := json.Unmarshal(scanner.Bytes(), &out); err != nil {
+			return nil, fmt.Errorf("decoding batch result: %w", err)
+		}
+		results = append(results, s.toLLMBatchResult(&out))
+	}
+	return results, scanner.Err()
+}
+
+func (s *Service) toLLMBatchResult(out *batchOutput) llm.BatchResult {
+	result := llm.BatchResult{ID: out.CustomID}
+	switch {
+	case out.Response != nil && out.Response.StatusCode == http.StatusOK:
+		var resp openai.ChatCompletionResponse
+		if err := json.Unmarshal(out.Response.Body, &resp); err != nil {
+			result.Error = fmt.Errorf("decoding batch response: %w", err)
+			break
+		}
+		result.Response = s.toLLMResponse(&resp)
+		result.Response.Usage.CostUSD = listCost(cmp.Or(s.Model, DefaultModel), result.Response.Usage) / 2
+	case out.Response != nil:
+		var body struct {
+			Error openai.APIError `json:"error"`
+		}
+		json.Unmarshal(out.Response.Body, &body)
+		result.Error = &llm.StatusError{
+			StatusCode: out.Response.StatusCode,
+			Err:        fmt.Errorf("status %d: %s", out.Response.StatusCode, body.Error.Message),
+		}
+	case out.Error != nil:
+		result.Error = errors.New("batch request " + strings.TrimPrefix(out.Error.Code, "batch_") + ": " + out.Error.Message)
+	default:
+		result.Error = errors.New("batch request has neither a response nor an error")
+	}
+	return result
+}
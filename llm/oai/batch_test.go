@@ -0,0 +1,115 @@
+package oai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"shelley.exe.dev/llm"
+)
+
+func TestBatch(t *testing.T) {
+	polls := 0
+	batchJSON := func(status string) string {
+		return fmt.Sprintf(`{"id":"batch_1","object":"batch","endpoint":"/v1/chat/completions","status":%q,"created_at":1735787045,
+			"completed_at":1735790000,"output_file_id":"file-out","error_file_id":"file-err",
+			"request_counts":{"total":3,"completed":1,"failed":2}}`, status)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/files", func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(file)
+		if r.FormValue("purpose") != "batch" || strings.Count(string(data), `"custom_id":"page-`) != 2 || !strings.Contains(string(data), `"url":"/v1/chat/completions"`) {
+			t.Errorf("unexpected batch file: %s", data)
+		}
+		fmt.Fprint(w, `{"id":"file-in","object":"file","purpose":"batch"}`)
+	})
+	mux.HandleFunc("POST /v1/batches", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Authorization") != "Bearer test-key" || !strings.Contains(string(body), `"input_file_id":"file-in"`) {
+			t.Errorf("unexpected batch creation: %s", body)
+		}
+		fmt.Fprint(w, batchJSON("validating"))
+	})
+	mux.HandleFunc("GET /v1/batches/batch_1", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls == 1 {
+			fmt.Fprint(w, batchJSON("in_progress"))
+			return
+		}
+		fmt.Fprint(w, batchJSON("completed"))
+	})
+	mux.HandleFunc("GET /v1/files/file-out/content", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id":"req_1","custom_id":"page-1","response":{"status_code":200,"body":{"id":"chatcmpl-1","model":"gpt-4.1-2025-04-14",`+
+			`"choices":[{"index":0,"message":{"role":"assistant","content":"A summary"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1000,"completion_tokens":100}}},"error":null}`)
+	})
+	mux.HandleFunc("GET /v1/files/file-err/content", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id":"req_2","custom_id":"page-2","response":{"status_code":400,"body":{"error":{"message":"bad","type":"invalid_request_error"}}},"error":null}`)
+		fmt.Fprintln(w, `{"id":"req_3","custom_id":"page-3","response":null,"error":{"code":"batch_expired","message":"This request could not be executed before the completion window expired."}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s := &Service{APIKey: "test-key", ModelURL: srv.URL + "/v1"}
+	ctx := context.Background()
+	batch, err := s.SubmitBatch(ctx, []llm.BatchRequest{
+		{ID: "page-1", Request: &llm.Request{Messages: []llm.Message{llm.UserStringMessage("Summarize page 1")}}},
+		{ID: "page-2", Request: &llm.Request{Messages: []llm.Message{llm.UserStringMessage("Summarize page 2")}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.ID != "batch_1" || batch.Status != llm.BatchInProgress || batch.CreatedAt.Year() != 2025 {
+		t.Errorf("unexpected batch: %+v", batch)
+	}
+
+	results, err := llm.WaitBatch(ctx, s, batch.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 {
+		t.Errorf("polled %d times, want 2 until the batch ended and 1 for its results", polls)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if r := results[0]; r.ID != "page-1" || r.Error != nil || r.Response.Content[0].Text != "A summary" {
+		t.Errorf("unexpected successful result: %+v", r)
+	}
+	// Batches cost half the list price: 1000 input tokens at $2/MTok and 100 output tokens at $8/MTok, halved
+	if cost := results[0].Response.Usage.CostUSD; cost < 0.00139 || cost > 0.00141 {
+		t.Errorf("cost = %v, want 0.0014", cost)
+	}
+	var se *llm.StatusError
+	if r := results[1]; r.ID != "page-2" || !errors.As(r.Error, &se) || se.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected errored result: %+v", r)
+	}
+	if r := results[2]; r.ID != "page-3" || r.Error == nil || !strings.Contains(r.Error.Error(), "expired") {
+		t.Errorf("unexpected expired result: %+v", r)
+	}
+}
+
+func TestToLLMBatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"batch_1","status":"cancelled","created_at":1735787045,"cancelled_at":1735790000,
+			"request_counts":{"total":5,"completed":2,"failed":1}}`)
+	}))
+	defer srv.Close()
+	s := &Service{APIKey: "test-key", ModelURL: srv.URL}
+	batch, err := s.Batch(context.Background(), "batch_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := llm.BatchCounts{Succeeded: 2, Errored: 1, Canceled: 2}
+	if batch.Status != llm.BatchEnded || batch.Counts != want || batch.EndedAt.IsZero() {
+		t.Errorf("unexpected batch: %+v", batch)
+	}
+}
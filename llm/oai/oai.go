@@ -7,10 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand/v2"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"shelley.exe.dev/llm"
@@ -45,44 +43,50 @@ type Model struct {
 	APIKeyEnv          string // environment variable name for the API key
 	IsReasoningModel   bool   // whether this model is a reasoning model (e.g. O3, O4-mini)
 	UseSimplifiedPatch bool   // whether to use the simplified patch input schema; defaults to false
+	SupportsImages     bool   // whether the model accepts image input; images are omitted otherwise
 }
 
 var (
 	DefaultModel = GPT41
 
 	GPT41 = Model{
-		UserName:  "gpt4.1",
-		ModelName: "gpt-4.1-2025-04-14",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt4.1",
+		ModelName:      "gpt-4.1-2025-04-14",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	GPT4o = Model{
-		UserName:  "gpt4o",
-		ModelName: "gpt-4o-2024-08-06",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt4o",
+		ModelName:      "gpt-4o-2024-08-06",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	GPT4oMini = Model{
-		UserName:  "gpt4o-mini",
-		ModelName: "gpt-4o-mini-2024-07-18",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt4o-mini",
+		ModelName:      "gpt-4o-mini-2024-07-18",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	GPT41Mini = Model{
-		UserName:  "gpt4.1-mini",
-		ModelName: "gpt-4.1-mini-2025-04-14",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt4.1-mini",
+		ModelName:      "gpt-4.1-mini-2025-04-14",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	GPT41Nano = Model{
-		UserName:  "gpt4.1-nano",
-		ModelName: "gpt-4.1-nano-2025-04-14",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt4.1-nano",
+		ModelName:      "gpt-4.1-nano-2025-04-14",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	O3 = Model{
@@ -91,6 +95,7 @@ var (
 		URL:              OpenAIURL,
 		APIKeyEnv:        OpenAIAPIKeyEnv,
 		IsReasoningModel: true,
+		SupportsImages:   true,
 	}
 
 	O4Mini = Model{
@@ -99,13 +104,15 @@ var (
 		URL:              OpenAIURL,
 		APIKeyEnv:        OpenAIAPIKeyEnv,
 		IsReasoningModel: true,
+		SupportsImages:   true,
 	}
 
 	Gemini25Flash = Model{
-		UserName:  "gemini-flash-2.5",
-		ModelName: "gemini-2.5-flash-preview-04-17",
-		URL:       GeminiURL,
-		APIKeyEnv: GeminiAPIKeyEnv,
+		UserName:       "gemini-flash-2.5",
+		ModelName:      "gemini-2.5-flash-preview-04-17",
+		URL:            GeminiURL,
+		APIKeyEnv:      GeminiAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	Gemini25Pro = Model{
@@ -119,7 +126,8 @@ var (
 		// Whatever that means. Are we caching? I have no idea.
 		// How do you always manage to be the annoying one, Google?
 		// I'm not complicating things just for you.
-		APIKeyEnv: GeminiAPIKeyEnv,
+		APIKeyEnv:      GeminiAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	TogetherDeepseekV3 = Model{
@@ -249,45 +257,51 @@ var (
 	}
 
 	GPT5 = Model{
-		UserName:  "gpt-5-thinking",
-		ModelName: "gpt-5.1",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt-5-thinking",
+		ModelName:      "gpt-5.1",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	GPT5Mini = Model{
-		UserName:  "gpt-5-thinking-mini",
-		ModelName: "gpt-5.1-mini",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt-5-thinking-mini",
+		ModelName:      "gpt-5.1-mini",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	GPT5Nano = Model{
-		UserName:  "gpt-5-thinking-nano",
-		ModelName: "gpt-5.1-nano",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt-5-thinking-nano",
+		ModelName:      "gpt-5.1-nano",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	GPT5Codex = Model{
-		UserName:  "gpt-5.1-codex",
-		ModelName: "gpt-5.1-codex",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt-5.1-codex",
+		ModelName:      "gpt-5.1-codex",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	GPT52Codex = Model{
-		UserName:  "gpt-5.2-codex",
-		ModelName: "gpt-5.2-codex",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt-5.2-codex",
+		ModelName:      "gpt-5.2-codex",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	GPT53Codex = Model{
-		UserName:  "gpt-5.3-codex",
-		ModelName: "gpt-5.3-codex",
-		URL:       OpenAIURL,
-		APIKeyEnv: OpenAIAPIKeyEnv,
+		UserName:       "gpt-5.3-codex",
+		ModelName:      "gpt-5.3-codex",
+		URL:            OpenAIURL,
+		APIKeyEnv:      OpenAIAPIKeyEnv,
+		SupportsImages: true,
 	}
 
 	// Skaband-specific model names.
@@ -303,6 +317,39 @@ var (
 	}
 )
 
+// pricing is the list price of each model, by ModelName, used when no gateway reports the cost of requests.
+// See https://platform.openai.com/docs/pricing and https://ai.google.dev/gemini-api/docs/pricing
+var pricing = map[string]llm.Pricing{
+	GPT41.ModelName:         {Input: 2, Output: 8, CacheRead: 0.5},
+	GPT41Mini.ModelName:     {Input: 0.4, Output: 1.6, CacheRead: 0.1},
+	GPT41Nano.ModelName:     {Input: 0.1, Output: 0.4, CacheRead: 0.025},
+	GPT4o.ModelName:         {Input: 2.5, Output: 10, CacheRead: 1.25},
+	GPT4oMini.ModelName:     {Input: 0.15, Output: 0.6, CacheRead: 0.075},
+	O3.ModelName:            {Input: 2, Output: 8, CacheRead: 0.5},
+	O4Mini.ModelName:        {Input: 1.1, Output: 4.4, CacheRead: 0.275},
+	GPT5.ModelName:          {Input: 1.25, Output: 10, CacheRead: 0.125},
+	GPT5Mini.ModelName:      {Input: 0.25, Output: 2, CacheRead: 0.025},
+	GPT5Nano.ModelName:      {Input: 0.05, Output: 0.4, CacheRead: 0.005},
+	GPT5Codex.ModelName:     {Input: 1.25, Output: 10, CacheRead: 0.125},
+	GPT52Codex.ModelName:    {Input: 1.75, Output: 14, CacheRead: 0.175},
+	Gemini25Flash.ModelName: {Input: 0.3, Output: 2.5, CacheRead: 0.075},
+	Gemini25Pro.ModelName:   {Input: 1.25, Output: 10, CacheRead: 0.31},
+}
+
+// listCost returns the cost of u at model's list price, or zero if its price is unknown.
+// OpenAI counts cached tokens among the input tokens, so they are priced separately from the rest.
+func listCost(model Model, u llm.Usage) float64 {
+	p, ok := pricing[model.ModelName]
+	if !ok {
+		return 0
+	}
+	return p.Cost(llm.Usage{
+		InputTokens:          u.InputTokens - min(u.CacheReadInputTokens, u.InputTokens),
+		CacheReadInputTokens: u.CacheReadInputTokens,
+		OutputTokens:         u.OutputTokens,
+	})
+}
+
 // Service provides chat completions.
 // Fields should not be altered concurrently with calling any method on Service.
 type Service struct {
@@ -312,6 +359,7 @@ type Service struct {
 	ModelURL  string       // optional, overrides Model.URL
 	MaxTokens int          // defaults to DefaultMaxTokens if zero
 	Org       string       // optional - organization ID
+	Azure     bool         // whether ModelURL is an Azure OpenAI resource, in which Model.ModelName names a deployment
 }
 
 var _ llm.Service = (*Service)(nil)
@@ -465,15 +513,23 @@ func fromLLMMessage(msg llm.Message) []openai.ChatCompletionMessage {
 	}
 
 	// Process tool results as separate messages, but first
+	var toolImages []openai.ChatMessagePart
 	for _, tr := range toolResults {
 		// Convert toolresult array to a string for OpenAI
 		// Collect all text from content objects
 		var texts []string
+		var images []openai.ChatMessagePart
 		for _, result := range tr.ToolResult {
-			if strings.TrimSpace(result.Text) != "" {
+			if result.Type == llm.ContentTypeImage {
+				images = append(images, imagePart(result))
+			} else if strings.TrimSpace(result.Text) != "" {
 				texts = append(texts, result.Text)
 			}
 		}
+		if len(images) > 0 {
+			toolImages = append(toolImages, textPart(fmt.Sprintf("Images from tool call %s:", tr.ToolUseID)))
+			toolImages = append(toolImages, images...)
+		}
 		toolResultContent := strings.Join(texts, "\n")
 
 		// OpenAI doesn't have an explicit error field for tool results, so add it directly to the content.
@@ -492,6 +548,13 @@ func fromLLMMessage(msg llm.Message) []openai.ChatCompletionMessage {
 		}
 		messages = append(messages, m)
 	}
+	// Tool messages can only hold text, so images from tool results follow in a user message
+	if len(toolImages) > 0 {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:         openai.ChatMessageRoleUser,
+			MultiContent: toolImages,
+		})
+	}
 	// Process regular content second
 	if len(regularContent) > 0 {
 		m := openai.ChatCompletionMessage{
@@ -501,8 +564,16 @@ func fromLLMMessage(msg llm.Message) []openai.ChatCompletionMessage {
 		// For assistant messages that contain tool calls
 		var toolCalls []openai.ToolCall
 		var textContent string
+		// Content and MultiContent are exclusive, so messages with images are sent as parts
+		var parts []openai.ChatMessagePart
+		hasImages := false
 
 		for _, c := range regularContent {
+			if c.Type == llm.ContentTypeImage {
+				parts = append(parts, imagePart(c))
+				hasImages = true
+				continue
+			}
 			content, tools := fromLLMContent(c)
 			if len(tools) > 0 {
 				toolCalls = append(toolCalls, tools...)
@@ -511,10 +582,15 @@ func fromLLMMessage(msg llm.Message) []openai.ChatCompletionMessage {
 					textContent += "\n"
 				}
 				textContent += content
+				parts = append(parts, textPart(content))
 			}
 		}
 
-		m.Content = textContent
+		if hasImages {
+			m.MultiContent = parts
+		} else {
+			m.Content = textContent
+		}
 		m.ToolCalls = toolCalls
 
 		messages = append(messages, m)
@@ -523,6 +599,19 @@ func fromLLMMessage(msg llm.Message) []openai.ChatCompletionMessage {
 	return messages
 }
 
+// textPart returns text as an OpenAI message part
+func textPart(text string) openai.ChatMessagePart {
+	return openai.ChatMessagePart{Type: openai.ChatMessagePartTypeText, Text: text}
+}
+
+// imagePart returns the image c as an OpenAI message part
+func imagePart(c llm.Content) openai.ChatMessagePart {
+	return openai.ChatMessagePart{
+		Type:     openai.ChatMessagePartTypeImageURL,
+		ImageURL: &openai.ChatMessageImageURL{URL: imageURL(c)},
+	}
+}
+
 // fromLLMToolChoice converts llm.ToolChoice to the format expected by OpenAI.
 func fromLLMToolChoice(tc *llm.ToolChoice) any {
 	if tc == nil {
@@ -665,6 +754,9 @@ func (s *Service) toLLMUsage(au openai.Usage, headers http.Header) llm.Usage {
 		OutputTokens:             out,
 	}
 	u.CostUSD = llm.CostUSDFromResponse(headers)
+	if u.CostUSD == 0 {
+		u.CostUSD = listCost(cmp.Or(s.Model, DefaultModel), u)
+	}
 	return u
 }
 
@@ -746,31 +838,83 @@ func (s *Service) TokenContextWindow() int {
 	}
 }
 
-// MaxImageDimension returns the maximum allowed image dimension.
-// TODO: determine actual OpenAI image dimension limits
-func (s *Service) MaxImageDimension() int {
-	return 0 // No known limit
+// openAIImageLimits are OpenAI's documented image input limits: up to 500 images of up to 20MB each.
+// Larger images are scaled down to fit 2048x2048 anyway, so there is no point sending more.
+var openAIImageLimits = llm.ImageLimits{MaxDimension: 2048, MaxBytes: 20 * 1024 * 1024, MaxCount: 500}
+
+// ImageLimits returns OpenAI's image limits, or none if the model does not accept images.
+func (s *Service) ImageLimits() llm.ImageLimits {
+	return imageLimits(cmp.Or(s.Model, DefaultModel))
+}
+
+// imageLimits returns the image limits of model
+func imageLimits(model Model) llm.ImageLimits {
+	if !model.SupportsImages {
+		return llm.ImageLimits{}
+	}
+	return openAIImageLimits
+}
+
+// fitMedia returns ir with its images fit to OpenAI's limits, or omitted if model does not accept images,
+// and its documents omitted, as the APIs used here do not accept them
+func fitMedia(ir *llm.Request, model Model) (*llm.Request, error) {
+	ir = llm.OmitDocuments(ir)
+	if !model.SupportsImages {
+		return llm.OmitImages(ir), nil
+	}
+	return llm.FitImages(ir, openAIImageLimits)
+}
+
+// imageURL returns the image c as a data URL
+func imageURL(c llm.Content) string {
+	return "data:" + c.MediaType + ";base64," + c.Data
 }
 
 // Do sends a request to OpenAI using the go-openai package.
 func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
-	// Configure the OpenAI client
-	httpc := cmp.Or(s.HTTPC, http.DefaultClient)
 	model := cmp.Or(s.Model, DefaultModel)
+	ir, err := fitMedia(ir, model)
+	if err != nil {
+		return nil, err
+	}
+	client, baseURL, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	req := s.chatRequest(ir)
+	// Construct the full URL for logging and debugging
+	fullURL := baseURL + "/chat/completions"
+
+	resp, err := client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, apiError(ctx, err, fullURL, model.ModelName)
+	}
+	return s.toLLMResponse(&resp), nil
+}
 
+// client returns an OpenAI client for the service, and the base URL it sends requests to
+func (s *Service) client() (*openai.Client, string, error) {
 	// TODO: do this one during Service setup? maybe with a constructor instead?
 	config := openai.DefaultConfig(s.APIKey)
-	baseURL := cmp.Or(s.ModelURL, model.URL)
-	if baseURL != "" {
+	baseURL := cmp.Or(s.ModelURL, cmp.Or(s.Model, DefaultModel).URL)
+	if s.Azure {
+		var err error
+		config, err = azureConfig(s.APIKey, baseURL)
+		if err != nil {
+			return nil, "", err
+		}
+	} else if baseURL != "" {
 		config.BaseURL = baseURL
 	}
 	if s.Org != "" {
 		config.OrgID = s.Org
 	}
-	config.HTTPClient = httpc
-
-	client := openai.NewClientWithConfig(config)
+	config.HTTPClient = cmp.Or(s.HTTPC, http.DefaultClient)
+	return openai.NewClientWithConfig(config), baseURL, nil
+}
 
+// chatRequest converts ir, whose media already fit the model, to a Chat Completions request
+func (s *Service) chatRequest(ir *llm.Request) openai.ChatCompletionRequest {
 	// Start with system messages if provided
 	var allMessages []openai.ChatCompletionMessage
 	if len(ir.System) > 0 {
@@ -790,78 +934,32 @@ func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error
 		tools = append(tools, fromLLMTool(t))
 	}
 
-	// Create the OpenAI request
-	req := openai.ChatCompletionRequest{
-		Model:               model.ModelName,
+	return openai.ChatCompletionRequest{
+		Model:               cmp.Or(s.Model, DefaultModel).ModelName,
 		Messages:            allMessages,
 		Tools:               tools,
 		ToolChoice:          fromLLMToolChoice(ir.ToolChoice), // TODO: make fromLLMToolChoice return an error when a perfect translation is not possible
 		MaxCompletionTokens: cmp.Or(s.MaxTokens, DefaultMaxTokens),
 	}
-	// Construct the full URL for logging and debugging
-	fullURL := baseURL + "/chat/completions"
-
-	// Retry mechanism
-	backoff := []time.Duration{1 * time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second, 15 * time.Second}
-
-	// retry loop
-	var errs error // accumulated errors across all attempts
-	for attempts := 0; ; attempts++ {
-		if attempts > 10 {
-			return nil, fmt.Errorf("openai request failed after %d attempts (url=%s, model=%s): %w", attempts, fullURL, model.ModelName, errs)
-		}
-		if attempts > 0 {
-			sleep := backoff[min(attempts, len(backoff)-1)] + time.Duration(rand.Int64N(int64(time.Second)))
-			slog.WarnContext(ctx, "openai request sleep before retry", "sleep", sleep, "attempts", attempts)
-			time.Sleep(sleep)
-		}
-
-		resp, err := client.CreateChatCompletion(ctx, req)
-
-		// Handle successful response
-		if err == nil {
-			return s.toLLMResponse(&resp), nil
-		}
-
-		// Handle errors
-		// Check for TLS "bad record MAC" errors and retry once
-		if strings.Contains(err.Error(), "tls: bad record MAC") && attempts == 0 {
-			slog.WarnContext(ctx, "tls bad record MAC error, retrying once", "error", err.Error())
-			errs = errors.Join(errs, fmt.Errorf("TLS error (attempt %d): %w", attempts+1, err))
-			continue
-		}
-
-		var apiErr *openai.APIError
-		if ok := errors.As(err, &apiErr); !ok {
-			// Not an OpenAI API error, return immediately with accumulated errors
-			return nil, errors.Join(errs, fmt.Errorf("url=%s model=%s: %w", fullURL, model.ModelName, err))
-		}
+}
 
-		switch {
-		case apiErr.HTTPStatusCode >= 500:
-			// Server error, try again with backoff
-			slog.WarnContext(ctx, "openai_request_failed", "error", apiErr.Error(), "status_code", apiErr.HTTPStatusCode, "url", fullURL, "model", model.ModelName)
-			errs = errors.Join(errs, fmt.Errorf("status %d (url=%s, model=%s): %s", apiErr.HTTPStatusCode, fullURL, model.ModelName, apiErr.Error()))
-			continue
-
-		case apiErr.HTTPStatusCode == 429:
-			// Rate limited, accumulate error and retry
-			slog.WarnContext(ctx, "openai_request_rate_limited", "error", apiErr.Error(), "url", fullURL, "model", model.ModelName)
-			errs = errors.Join(errs, fmt.Errorf("status %d (rate limited, url=%s, model=%s): %s", apiErr.HTTPStatusCode, fullURL, model.ModelName, apiErr.Error()))
-			continue
-
-		case apiErr.HTTPStatusCode >= 400 && apiErr.HTTPStatusCode < 500:
-			// Client error, probably unrecoverable
-			slog.WarnContext(ctx, "openai_request_failed", "error", apiErr.Error(), "status_code", apiErr.HTTPStatusCode, "url", fullURL, "model", model.ModelName)
-			return nil, errors.Join(errs, fmt.Errorf("status %d (url=%s, model=%s): %s", apiErr.HTTPStatusCode, fullURL, model.ModelName, apiErr.Error()))
-
-		default:
-			// Other error, accumulate and retry
-			slog.WarnContext(ctx, "openai_request_failed", "error", apiErr.Error(), "status_code", apiErr.HTTPStatusCode, "url", fullURL, "model", model.ModelName)
-			errs = errors.Join(errs, fmt.Errorf("status %d (url=%s, model=%s): %s", apiErr.HTTPStatusCode, fullURL, model.ModelName, apiErr.Error()))
-			continue
-		}
+// apiError returns err with the HTTP status of the API's response, if it has one.
+func apiError(ctx context.Context, err error, url, model string) error {
+	var (
+		apiErr *openai.APIError
+		reqErr *openai.RequestError
+		status int
+	)
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		status = reqErr.HTTPStatusCode
+	default:
+		return fmt.Errorf("url=%s model=%s: %w", url, model, err)
 	}
+	slog.WarnContext(ctx, "openai_request_failed", "error", err.Error(), "status_code", status, "url", url, "model", model)
+	return &llm.StatusError{StatusCode: status, Err: fmt.Errorf("status %d (url=%s, model=%s): %w", status, url, model, err)}
 }
 
 func (s *Service) UseSimplifiedPatch() bool {
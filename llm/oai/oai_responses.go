@@ -5,14 +5,11 @@ import (
 	"cmp"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"math/rand/v2"
 	"net/http"
 	"strings"
-	"time"
 
 	"shelley.exe.dev/llm"
 )
@@ -42,25 +39,39 @@ type responsesRequest struct {
 	ToolChoice      any                  `json:"tool_choice,omitempty"`
 	MaxOutputTokens int                  `json:"max_output_tokens,omitempty"`
 	Reasoning       *responsesReasoning  `json:"reasoning,omitempty"`
+	Include         []string             `json:"include,omitempty"`
 }
 
 type responsesReasoning struct {
-	Effort string `json:"effort,omitempty"` // "low", "medium", "high"
+	Effort  string `json:"effort,omitempty"`  // "low", "medium", "high"
+	Summary string `json:"summary,omitempty"` // "auto", "concise", "detailed"
+}
+
+// responsesSummary is a part of the summary of a reasoning item
+type responsesSummary struct {
+	Type string `json:"type"` // "summary_text"
+	Text string `json:"text"`
 }
 
 type responsesInputItem struct {
-	Type      string             `json:"type"`                // "message", "function_call", "function_call_output"
+	Type      string             `json:"type"`                // "message", "reasoning", "function_call", "function_call_output"
+	ID        string             `json:"id,omitempty"`        // for reasoning
 	Role      string             `json:"role,omitempty"`      // for messages: "user", "assistant"
 	Content   []responsesContent `json:"content,omitempty"`   // for messages
 	CallID    string             `json:"call_id,omitempty"`   // for function_call and function_call_output
 	Name      string             `json:"name,omitempty"`      // for function_call
 	Arguments string             `json:"arguments,omitempty"` // for function_call
 	Output    string             `json:"output,omitempty"`    // for function_call_output
+
+	// for reasoning; the summary must be sent, if empty
+	Summary          *[]responsesSummary `json:"summary,omitempty"`
+	EncryptedContent string              `json:"encrypted_content,omitempty"`
 }
 
 type responsesContent struct {
-	Type string `json:"type"` // "input_text", "output_text"
-	Text string `json:"text"`
+	Type     string `json:"type"` // "input_text", "output_text", "input_image"
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"` // for input_image
 }
 
 type responsesTool struct {
@@ -90,7 +101,10 @@ type responsesOutputItem struct {
 	CallID    string             `json:"call_id,omitempty"`   // for function_call
 	Name      string             `json:"name,omitempty"`      // for function_call
 	Arguments string             `json:"arguments,omitempty"` // for function_call
-	Summary   []string           `json:"summary,omitempty"`   // for reasoning
+	Summary   []responsesSummary `json:"summary,omitempty"`   // for reasoning
+
+	// EncryptedContent is the model's reasoning, which it continues from when sent back in later requests
+	EncryptedContent string `json:"encrypted_content,omitempty"`
 }
 
 type responsesUsage struct {
@@ -133,14 +147,22 @@ func fromLLMMessageResponses(msg llm.Message) []responsesInputItem {
 	}
 
 	// Process tool results first - they need to come before the assistant message
+	var toolImages []responsesContent
 	for _, tr := range toolResults {
 		// Collect all text from content objects
 		var texts []string
+		var images []responsesContent
 		for _, result := range tr.ToolResult {
-			if strings.TrimSpace(result.Text) != "" {
+			if result.Type == llm.ContentTypeImage {
+				images = append(images, responsesContent{Type: "input_image", ImageURL: imageURL(result)})
+			} else if strings.TrimSpace(result.Text) != "" {
 				texts = append(texts, result.Text)
 			}
 		}
+		if len(images) > 0 {
+			toolImages = append(toolImages, responsesContent{Type: "input_text", Text: fmt.Sprintf("Images from tool call %s:", tr.ToolUseID)})
+			toolImages = append(toolImages, images...)
+		}
 		toolResultContent := strings.Join(texts, "\n")
 
 		// Add error prefix if needed
@@ -158,15 +180,39 @@ func fromLLMMessageResponses(msg llm.Message) []responsesInputItem {
 			Output: cmp.Or(toolResultContent, " "),
 		})
 	}
+	// Function call outputs can only hold text, so images from tool results follow in a user message
+	if len(toolImages) > 0 {
+		items = append(items, responsesInputItem{
+			Type:    "message",
+			Role:    "user",
+			Content: toolImages,
+		})
+	}
 
 	// Process regular content
 	if len(regularContent) > 0 {
 		var messageContent []responsesContent
 		var functionCalls []responsesInputItem
 
+		// Reasoning comes first, so the model continues from it; thinking from other providers is dropped
 		for _, c := range regularContent {
-			switch c.Type {
-			case llm.ContentTypeText:
+			if c.Type == llm.ContentTypeThinking && isReasoningItem(c) {
+				summary := []responsesSummary{}
+				if c.Thinking != "" {
+					summary = append(summary, responsesSummary{Type: "summary_text", Text: c.Thinking})
+				}
+				items = append(items, responsesInputItem{Type: "reasoning", ID: c.ID, Summary: &summary, EncryptedContent: c.Signature})
+			}
+		}
+
+		for _, c := range regularContent {
+			switch {
+			case c.Type == llm.ContentTypeImage:
+				messageContent = append(messageContent, responsesContent{
+					Type:     "input_image",
+					ImageURL: imageURL(c),
+				})
+			case c.Type == llm.ContentTypeText:
 				if c.Text != "" {
 					contentType := "input_text"
 					if msg.Role == llm.MessageRoleAssistant {
@@ -177,7 +223,7 @@ func fromLLMMessageResponses(msg llm.Message) []responsesInputItem {
 						Text: c.Text,
 					})
 				}
-			case llm.ContentTypeToolUse:
+			case c.Type == llm.ContentTypeToolUse:
 				// Tool use becomes a function_call in the input
 				functionCalls = append(functionCalls, responsesInputItem{
 					Type:      "function_call",
@@ -208,6 +254,12 @@ func fromLLMMessageResponses(msg llm.Message) []responsesInputItem {
 	return items
 }
 
+// isReasoningItem reports whether the thinking content c is a reasoning item of the Responses API,
+// which has an ID and encrypted content, unlike the thinking of other providers
+func isReasoningItem(c llm.Content) bool {
+	return c.ID != "" && c.Signature != ""
+}
+
 // fromLLMToolResponses converts llm.Tool to Responses API tool format
 func fromLLMToolResponses(t *llm.Tool) responsesTool {
 	return responsesTool{
@@ -279,14 +331,17 @@ func (s *ResponsesService) toLLMResponseFromResponses(resp *responsesResponse, h
 				}
 			}
 		case "reasoning":
-			// Convert reasoning to thinking content
-			if len(item.Summary) > 0 {
-				summaryText := strings.Join(item.Summary, "\n")
-				contents = append(contents, llm.Content{
-					Type: llm.ContentTypeThinking,
-					Text: summaryText,
-				})
+			// Convert reasoning to thinking content, keeping its ID and encrypted content to send back
+			var summary []string
+			for _, part := range item.Summary {
+				summary = append(summary, part.Text)
 			}
+			contents = append(contents, llm.Content{
+				ID:        item.ID,
+				Type:      llm.ContentTypeThinking,
+				Thinking:  strings.Join(summary, "\n\n"),
+				Signature: item.EncryptedContent,
+			})
 		case "function_call":
 			// Convert function call to tool use
 			contents = append(contents, llm.Content{
@@ -332,6 +387,9 @@ func (s *ResponsesService) toLLMUsageFromResponses(usage responsesUsage, headers
 		OutputTokens:             out,
 	}
 	u.CostUSD = llm.CostUSDFromResponse(headers)
+	if u.CostUSD == 0 {
+		u.CostUSD = listCost(cmp.Or(s.Model, DefaultModel), u)
+	}
 	return u
 }
 
@@ -356,16 +414,19 @@ func (s *ResponsesService) TokenContextWindow() int {
 	}
 }
 
-// MaxImageDimension returns the maximum allowed image dimension.
-// TODO: determine actual OpenAI image dimension limits
-func (s *ResponsesService) MaxImageDimension() int {
-	return 0 // No known limit
+// ImageLimits returns OpenAI's image limits, or none if the model does not accept images.
+func (s *ResponsesService) ImageLimits() llm.ImageLimits {
+	return imageLimits(cmp.Or(s.Model, DefaultModel))
 }
 
 // Do sends a request to OpenAI using the Responses API.
 func (s *ResponsesService) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
 	httpc := cmp.Or(s.HTTPC, http.DefaultClient)
 	model := cmp.Or(s.Model, DefaultModel)
+	ir, err := fitMedia(ir, model)
+	if err != nil {
+		return nil, err
+	}
 
 	// Start with system messages if provided
 	var allInput []responsesInputItem
@@ -398,7 +459,8 @@ func (s *ResponsesService) Do(ctx context.Context, ir *llm.Request) (*llm.Respon
 	if s.ThinkingLevel != llm.ThinkingLevelOff {
 		effort := s.ThinkingLevel.ThinkingEffort()
 		if effort != "" {
-			req.Reasoning = &responsesReasoning{Effort: effort}
+			req.Reasoning = &responsesReasoning{Effort: effort, Summary: "auto"}
+			req.Include = []string{"reasoning.encrypted_content"}
 		}
 	}
 
@@ -426,101 +488,69 @@ func (s *ResponsesService) Do(ctx context.Context, ir *llm.Request) (*llm.Respon
 		}
 	}
 
-	// Retry mechanism
-	backoff := []time.Duration{1 * time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second, 15 * time.Second}
-
-	// retry loop
-	var errs error // accumulated errors across all attempts
-	for attempts := 0; ; attempts++ {
-		if attempts > 10 {
-			return nil, fmt.Errorf("responses request failed after %d attempts (url=%s, model=%s): %w", attempts, fullURL, model.ModelName, errs)
-		}
-		if attempts > 0 {
-			sleep := backoff[min(attempts, len(backoff)-1)] + time.Duration(rand.Int64N(int64(time.Second)))
-			slog.WarnContext(ctx, "responses request sleep before retry", "sleep", sleep, "attempts", attempts)
-			time.Sleep(sleep)
-		}
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", fullURL, bytes.NewReader(reqJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-		// Create HTTP request
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", fullURL, bytes.NewReader(reqJSON))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+s.APIKey)
+	if s.Org != "" {
+		httpReq.Header.Set("OpenAI-Organization", s.Org)
+	}
 
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("Authorization", "Bearer "+s.APIKey)
-		if s.Org != "" {
-			httpReq.Header.Set("OpenAI-Organization", s.Org)
-		}
+	// Send request
+	httpResp, err := httpc.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
 
-		// Send request
-		httpResp, err := httpc.Do(httpReq)
-		if err != nil {
-			errs = errors.Join(errs, fmt.Errorf("attempt %d: %w", attempts+1, err))
-			continue
-		}
-		defer httpResp.Body.Close()
+	// Read response body
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
 
-		// Read response body
-		body, err := io.ReadAll(httpResp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %w", err)
+	// Handle non-200 responses
+	if httpResp.StatusCode != http.StatusOK {
+		message := string(body)
+		var apiErr responsesError
+		if jsonErr := json.Unmarshal(body, &struct {
+			Error *responsesError `json:"error"`
+		}{Error: &apiErr}); jsonErr == nil && apiErr.Message != "" {
+			message = apiErr.Message
 		}
-
-		// Handle non-200 responses
-		if httpResp.StatusCode != http.StatusOK {
-			var apiErr responsesError
-			if jsonErr := json.Unmarshal(body, &struct {
-				Error *responsesError `json:"error"`
-			}{Error: &apiErr}); jsonErr == nil && apiErr.Message != "" {
-				// We have a structured error
-				switch {
-				case httpResp.StatusCode >= 500:
-					// Server error, retry
-					slog.WarnContext(ctx, "responses_request_failed", "error", apiErr.Message, "status_code", httpResp.StatusCode, "url", fullURL, "model", model.ModelName)
-					errs = errors.Join(errs, fmt.Errorf("status %d (url=%s, model=%s): %s", httpResp.StatusCode, fullURL, model.ModelName, apiErr.Message))
-					continue
-
-				case httpResp.StatusCode == 429:
-					// Rate limited, retry
-					slog.WarnContext(ctx, "responses_request_rate_limited", "error", apiErr.Message, "url", fullURL, "model", model.ModelName)
-					errs = errors.Join(errs, fmt.Errorf("status %d (rate limited, url=%s, model=%s): %s", httpResp.StatusCode, fullURL, model.ModelName, apiErr.Message))
-					continue
-
-				case httpResp.StatusCode >= 400 && httpResp.StatusCode < 500:
-					// Client error, probably unrecoverable
-					slog.WarnContext(ctx, "responses_request_failed", "error", apiErr.Message, "status_code", httpResp.StatusCode, "url", fullURL, "model", model.ModelName)
-					return nil, errors.Join(errs, fmt.Errorf("status %d (url=%s, model=%s): %s", httpResp.StatusCode, fullURL, model.ModelName, apiErr.Message))
-				}
-			}
-
-			// No structured error, use the raw body
-			slog.WarnContext(ctx, "responses_request_failed", "status_code", httpResp.StatusCode, "url", fullURL, "model", model.ModelName, "body", string(body))
-			return nil, fmt.Errorf("status %d (url=%s, model=%s): %s", httpResp.StatusCode, fullURL, model.ModelName, string(body))
+		slog.WarnContext(ctx, "responses_request_failed", "error", message, "status_code", httpResp.StatusCode, "url", fullURL, "model", model.ModelName)
+		return nil, &llm.StatusError{
+			StatusCode: httpResp.StatusCode,
+			Err:        fmt.Errorf("status %d (url=%s, model=%s): %s", httpResp.StatusCode, fullURL, model.ModelName, message),
+			RetryAfter: llm.RetryAfter(httpResp.Header),
 		}
+	}
 
-		// Parse successful response
-		var resp responsesResponse
-		if err := json.Unmarshal(body, &resp); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-		}
+	// Parse successful response
+	var resp responsesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
 
-		// Check for errors in the response
-		if resp.Error != nil {
-			return nil, fmt.Errorf("response contains error: %s", resp.Error.Message)
-		}
+	// Check for errors in the response
+	if resp.Error != nil {
+		return nil, fmt.Errorf("response contains error: %s", resp.Error.Message)
+	}
 
-		// Dump response if enabled
-		if s.DumpLLM {
-			if respJSON, err := json.MarshalIndent(resp, "", "  "); err == nil {
-				if err := llm.DumpToFile("response", "", respJSON); err != nil {
-					slog.WarnContext(ctx, "failed to dump responses response to file", "error", err)
-				}
+	// Dump response if enabled
+	if s.DumpLLM {
+		if respJSON, err := json.MarshalIndent(resp, "", "  "); err == nil {
+			if err := llm.DumpToFile("response", "", respJSON); err != nil {
+				slog.WarnContext(ctx, "failed to dump responses response to file", "error", err)
 			}
 		}
-
-		return s.toLLMResponseFromResponses(&resp, httpResp.Header), nil
 	}
+
+	return s.toLLMResponseFromResponses(&resp, httpResp.Header), nil
 }
 
 func (s *ResponsesService) UseSimplifiedPatch() bool {
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"testing"
 
 	"shelley.exe.dev/llm"
@@ -125,6 +126,69 @@ func TestFromLLMMessageResponses(t *testing.T) {
 	}
 }
 
+func TestFromLLMMessageResponsesImages(t *testing.T) {
+	image := llm.Content{Type: llm.ContentTypeImage, MediaType: "image/png", Data: "aW1n"}
+	msg := llm.Message{
+		Role: llm.MessageRoleUser,
+		Content: []llm.Content{
+			{Type: llm.ContentTypeToolResult, ToolUseID: "call_1", ToolResult: []llm.Content{llm.StringContent("screenshot taken"), image}},
+			llm.StringContent("what is this?"),
+			image,
+		},
+	}
+	items := fromLLMMessageResponses(msg)
+	if len(items) != 3 {
+		t.Fatalf("Expected function output, tool image, and user message items, got %+v", items)
+	}
+	if items[0].Type != "function_call_output" || items[0].Output != "screenshot taken" {
+		t.Errorf("Expected function output with text only, got %+v", items[0])
+	}
+	image0 := responsesContent{Type: "input_image", ImageURL: "data:image/png;base64,aW1n"}
+	toolImages := items[1].Content
+	if items[1].Role != "user" || len(toolImages) != 2 || toolImages[0].Text != "Images from tool call call_1:" || toolImages[1] != image0 {
+		t.Errorf("Expected user message with the tool result image, got %+v", items[1])
+	}
+	content := items[2].Content
+	if len(content) != 2 || content[0].Text != "what is this?" || content[1] != image0 {
+		t.Errorf("Expected text and image content, got %+v", items[2])
+	}
+}
+
+func TestFromLLMMessageResponsesReasoning(t *testing.T) {
+	msg := llm.Message{
+		Role: llm.MessageRoleAssistant,
+		Content: []llm.Content{
+			{Type: llm.ContentTypeThinking, Thinking: "Claude's thoughts", Signature: "claude-signature"},
+			{ID: "rs_1", Type: llm.ContentTypeThinking, Thinking: "Checking the weather", Signature: "encrypted"},
+			llm.StringContent("Let me check."),
+			{ID: "call_1", Type: llm.ContentTypeToolUse, ToolName: "get_weather", ToolInput: json.RawMessage(`{}`)},
+		},
+	}
+	items := fromLLMMessageResponses(msg)
+	if len(items) != 3 {
+		t.Fatalf("Expected reasoning, message, and function call items, without Claude's thinking, got %+v", items)
+	}
+	reasoning := items[0]
+	if reasoning.Type != "reasoning" || reasoning.ID != "rs_1" || reasoning.EncryptedContent != "encrypted" ||
+		reasoning.Summary == nil || len(*reasoning.Summary) != 1 || (*reasoning.Summary)[0].Text != "Checking the weather" {
+		t.Errorf("Expected the reasoning item first, got %+v", reasoning)
+	}
+	if items[1].Type != "message" || items[2].Type != "function_call" {
+		t.Errorf("Expected the message and function call after the reasoning, got %+v", items[1:])
+	}
+
+	// A reasoning item without a summary still sends an empty one, which the API requires
+	data, err := json.Marshal(fromLLMMessageResponses(llm.Message{Role: llm.MessageRoleAssistant, Content: []llm.Content{
+		{ID: "rs_2", Type: llm.ContentTypeThinking, Signature: "encrypted"},
+	}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"type":"reasoning","id":"rs_2","summary":[],"encrypted_content":"encrypted"}]`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
 func TestFromLLMToolResponses(t *testing.T) {
 	tool := &llm.Tool{
 		Name:        "test_tool",
@@ -242,8 +306,10 @@ func TestToLLMResponseFromResponses(t *testing.T) {
 				Model: "gpt-5.1-codex",
 				Output: []responsesOutputItem{
 					{
-						Type:    "reasoning",
-						Summary: []string{"Let me think", "about this"},
+						ID:               "rs_123",
+						Type:             "reasoning",
+						Summary:          []responsesSummary{{Type: "summary_text", Text: "Let me think"}, {Type: "summary_text", Text: "about this"}},
+						EncryptedContent: "encrypted",
 					},
 					{
 						Type: "message",
@@ -277,6 +343,13 @@ func TestToLLMResponseFromResponses(t *testing.T) {
 			}
 		})
 	}
+
+	// Reasoning keeps what is needed to send it back
+	llmResp := svc.toLLMResponseFromResponses(tests[len(tests)-1].resp, nil)
+	want := llm.Content{ID: "rs_123", Type: llm.ContentTypeThinking, Thinking: "Let me think\n\nabout this", Signature: "encrypted"}
+	if !reflect.DeepEqual(llmResp.Content[0], want) {
+		t.Errorf("reasoning content = %+v, want %+v", llmResp.Content[0], want)
+	}
 }
 
 func TestResponsesServiceTokenContextWindow(t *testing.T) {
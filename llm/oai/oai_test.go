@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -187,22 +188,80 @@ func TestTokenContextWindow(t *testing.T) {
 	}
 }
 
-func TestMaxImageDimension(t *testing.T) {
+func TestImageLimits(t *testing.T) {
 	// Test both Service and ResponsesService
 	model := GPT41
 
-	// Test Service.MaxImageDimension
+	// Test Service.ImageLimits
 	service := &Service{Model: model}
-	result := service.MaxImageDimension()
-	if result != 0 {
-		t.Errorf("Service.MaxImageDimension() = %d, expected 0", result)
+	result := service.ImageLimits()
+	if result != openAIImageLimits {
+		t.Errorf("Service.ImageLimits() = %+v, expected %+v", result, openAIImageLimits)
 	}
 
-	// Test ResponsesService.MaxImageDimension
+	// Test ResponsesService.ImageLimits
 	responsesService := &ResponsesService{Model: model}
-	result2 := responsesService.MaxImageDimension()
-	if result2 != 0 {
-		t.Errorf("ResponsesService.MaxImageDimension() = %d, expected 0", result2)
+	result2 := responsesService.ImageLimits()
+	if result2 != openAIImageLimits {
+		t.Errorf("ResponsesService.ImageLimits() = %+v, expected %+v", result2, openAIImageLimits)
+	}
+
+	// Models that do not accept images have no limits
+	if result := (&Service{Model: TogetherDeepseekV3}).ImageLimits(); result != (llm.ImageLimits{}) {
+		t.Errorf("Service.ImageLimits() = %+v, expected no limits", result)
+	}
+}
+
+func TestFromLLMMessageImages(t *testing.T) {
+	image := llm.Content{Type: llm.ContentTypeImage, MediaType: "image/png", Data: "aW1n"}
+	msg := llm.Message{
+		Role: llm.MessageRoleUser,
+		Content: []llm.Content{
+			{Type: llm.ContentTypeToolResult, ToolUseID: "call_1", ToolResult: []llm.Content{llm.StringContent("screenshot taken"), image}},
+			llm.StringContent("what is this?"),
+			image,
+		},
+	}
+	got := fromLLMMessage(msg)
+	if len(got) != 3 {
+		t.Fatalf("Expected tool, tool image, and user messages, got %+v", got)
+	}
+	if got[0].Role != "tool" || got[0].Content != "screenshot taken" {
+		t.Errorf("Expected tool message with text only, got %+v", got[0])
+	}
+	wantURL := "data:image/png;base64,aW1n"
+	toolImages := got[1].MultiContent
+	if got[1].Role != openai.ChatMessageRoleUser || len(toolImages) != 2 || toolImages[0].Text != "Images from tool call call_1:" || toolImages[1].ImageURL.URL != wantURL {
+		t.Errorf("Expected user message with the tool result image, got %+v", got[1])
+	}
+	parts := got[2].MultiContent
+	if got[2].Content != "" || len(parts) != 2 || parts[0].Text != "what is this?" || parts[1].ImageURL.URL != wantURL {
+		t.Errorf("Expected text and image parts, got %+v", got[2])
+	}
+}
+
+func TestServiceDoOmitsImages(t *testing.T) {
+	var got openai.ChatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+			Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "ok"}, FinishReason: "stop"}},
+		})
+	}))
+	defer server.Close()
+
+	svc := &Service{APIKey: "test-api-key", Model: TogetherDeepseekV3, ModelURL: server.URL}
+	req := &llm.Request{Messages: []llm.Message{{
+		Role:    llm.MessageRoleUser,
+		Content: []llm.Content{{Type: llm.ContentTypeImage, MediaType: "image/png", Data: "aW1n"}},
+	}}}
+	if _, err := svc.Do(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Messages) != 1 || len(got.Messages[0].MultiContent) != 0 || !strings.Contains(got.Messages[0].Content, "does not accept images") {
+		t.Errorf("Expected image to be replaced with a note, got %+v", got.Messages)
 	}
 }
 
@@ -834,6 +893,20 @@ func TestToLLMUsage(t *testing.T) {
 	if usage.CacheReadInputTokens != 25 {
 		t.Errorf("toLLMUsage().CacheReadInputTokens = %d, expected 25", usage.CacheReadInputTokens)
 	}
+	// 75 uncached input tokens at $2/MTok, 25 cached at $0.50/MTok, and 50 output tokens at $8/MTok
+	if diff := usage.CostUSD - 0.0005625; diff > 1e-12 || diff < -1e-12 {
+		t.Errorf("toLLMUsage().CostUSD = %v, expected the list price 0.0005625", usage.CostUSD)
+	}
+
+	// A gateway's cost takes precedence, and models without a known price cost nothing
+	usage = service.toLLMUsage(openaiUsage, http.Header{"Skaband-Cost-Microcents": {"200000"}})
+	if usage.CostUSD != 0.002 {
+		t.Errorf("toLLMUsage().CostUSD = %v, expected the gateway's 0.002", usage.CostUSD)
+	}
+	usage = (&Service{Model: LlamaCPP}).toLLMUsage(openaiUsage, nil)
+	if usage.CostUSD != 0 {
+		t.Errorf("toLLMUsage().CostUSD = %v for a model without a price, expected 0", usage.CostUSD)
+	}
 }
 
 func TestToLLMResponse(t *testing.T) {
@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultPageBytes is the most text of a tool result OutputPages sends the model at once.
+const DefaultPageBytes = 50 * 1024
+
+// FetchMoreName is the name of the tool the model pages through large tool results with.
+const FetchMoreName = "fetch_more"
+
+// maxPagedOutputs is how many large outputs OutputPages keeps for the model to page through
+const maxPagedOutputs = 32
+
+// OutputPages sends the model tool results too large to send whole a page at a time,
+// with a continuation token it passes to the fetch_more tool for the rest.
+// Use its Middleware on a set of tools, along with its Tool. It is safe for concurrent use.
+type OutputPages struct {
+	PageBytes int // the most text to send at once; zero for DefaultPageBytes
+
+	mu      sync.Mutex
+	outputs map[string]string // by token
+	order   []string          // tokens, oldest first
+}
+
+type fetchMoreInput struct {
+	Token  string `json:"token" description:"The continuation token of the output"`
+	Offset int    `json:"offset" description:"The byte offset to continue from"`
+}
+
+// Tool returns the fetch_more tool, which returns the next page of a large tool result.
+func (p *OutputPages) Tool() *Tool {
+	return &Tool{
+		Name:        FetchMoreName,
+		Description: "Fetch more of a tool result that was too large to return whole, using the continuation token and offset given at the end of it",
+		InputSchema: SchemaFor[fetchMoreInput](),
+		Run:         p.fetchMoreRun,
+	}
+}
+
+func (p *OutputPages) fetchMoreRun(ctx context.Context, m json.RawMessage) ToolOut {
+	var input fetchMoreInput
+	if err := json.Unmarshal(m, &input); err != nil {
+		return ErrorfToolOut("invalid input: %w", err)
+	}
+	p.mu.Lock()
+	text, ok := p.outputs[input.Token]
+	p.mu.Unlock()
+	if !ok {
+		return ErrorfToolOut("no output with token %q; it may have expired, so run the tool again", input.Token)
+	}
+	if input.Offset < 0 || input.Offset >= len(text) {
+		return ErrorfToolOut("offset %d is outside the output, which has %d bytes", input.Offset, len(text))
+	}
+	return ToolOut{LLMContent: TextContent(p.page(text, input.Token, input.Offset))}
+}
+
+// Middleware replaces text tool results larger than a page with their first page, keeping them for fetch_more.
+// It is a ToolMiddleware.
+func (p *OutputPages) Middleware(tool *Tool, next ToolRunFunc) ToolRunFunc {
+	if tool.Name == FetchMoreName {
+		return next
+	}
+	return func(ctx context.Context, input json.RawMessage) ToolOut {
+		out := next(ctx, input)
+		var contents []Content
+		for i, c := range out.LLMContent {
+			if c.Type != ContentTypeText || len(c.Text) <= p.pageBytes() {
+				continue
+			}
+			if contents == nil {
+				contents = append([]Content(nil), out.LLMContent...)
+			}
+			contents[i].Text = p.page(c.Text, p.keep(c.Text), 0)
+		}
+		if contents != nil {
+			out.LLMContent = contents
+		}
+		return out
+	}
+}
+
+// keep keeps text for fetch_more, returning its token.
+// The token is derived from text, so the same output always has the same token.
+func (p *OutputPages) keep(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	token := hex.EncodeToString(sum[:8])
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.outputs == nil {
+		p.outputs = make(map[string]string)
+	}
+	if _, ok := p.outputs[token]; ok {
+		return token
+	}
+	if len(p.order) == maxPagedOutputs {
+		delete(p.outputs, p.order[0])
+		p.order = p.order[1:]
+	}
+	p.outputs[token] = text
+	p.order = append(p.order, token)
+	return token
+}
+
+// page returns the page of text at offset, with how to fetch the rest if there is more.
+// Pages start and end on rune boundaries, and hold at least one rune even if it is larger than a page.
+func (p *OutputPages) page(text, token string, offset int) string {
+	// The model may pass an offset within a rune
+	for offset > 0 && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+	end := min(offset+p.pageBytes(), len(text))
+	for end < len(text) && end > offset && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	if end == offset {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		end = offset + size
+	}
+	if end == len(text) {
+		return text[offset:]
+	}
+	return fmt.Sprintf("%s\n[output truncated: bytes %d-%d of %d shown; call %s with token %q and offset %d for more]",
+		text[offset:end], offset, end, len(text), FetchMoreName, token, end)
+}
+
+func (p *OutputPages) pageBytes() int {
+	if p.PageBytes > 0 {
+		return p.PageBytes
+	}
+	return DefaultPageBytes
+}
@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestOutputPages(t *testing.T) {
+	pages := &OutputPages{PageBytes: 10}
+	dump := &Tool{Name: "dump", Run: func(ctx context.Context, input json.RawMessage) ToolOut {
+		return ToolOut{LLMContent: []Content{
+			StringContent("short"),
+			StringContent("0123456789abcdefgh€xyz"),
+			{Type: ContentTypeImage, MediaType: "image/png", Data: strings.Repeat("A", 100)},
+		}}
+	}}
+	tools := WithMiddleware([]*Tool{dump, pages.Tool()}, pages.Middleware)
+
+	out := tools[0].Run(context.Background(), nil)
+	if out.LLMContent[0].Text != "short" || out.LLMContent[2].Data != strings.Repeat("A", 100) {
+		t.Errorf("expected small text and images to be left alone, got %+v", out.LLMContent)
+	}
+	first := out.LLMContent[1].Text
+	if !strings.HasPrefix(first, "0123456789\n[output truncated: bytes 0-10 of 24 shown") {
+		t.Fatalf("first page = %q", first)
+	}
+	token := regexp.MustCompile(`token "([0-9a-f]+)"`).FindStringSubmatch(first)[1]
+	if again := tools[0].Run(context.Background(), nil).LLMContent[1].Text; again != first {
+		t.Errorf("expected the same output to get the same token, got %q", again)
+	}
+
+	fetch := func(offset int) ToolOut {
+		return tools[1].Run(context.Background(), json.RawMessage(fmt.Sprintf(`{"token": %q, "offset": %d}`, token, offset)))
+	}
+	// The page ends before the euro sign rather than splitting it
+	if got := fetch(10).LLMContent[0].Text; !strings.HasPrefix(got, "abcdefgh\n[output truncated: bytes 10-18 of 24") || !strings.HasSuffix(got, "offset 18 for more]") {
+		t.Errorf("second page = %q", got)
+	}
+	if got := fetch(18).LLMContent[0].Text; got != "€xyz" {
+		t.Errorf("last page = %q, want the rest without a continuation", got)
+	}
+	// An offset within the euro sign starts at it
+	if got := fetch(19).LLMContent[0].Text; got != "€xyz" {
+		t.Errorf("page within a rune = %q, want the rest from its start", got)
+	}
+	if out := fetch(24); out.Error == nil {
+		t.Error("expected an error for an offset past the end")
+	}
+	if out := tools[1].Run(context.Background(), json.RawMessage(`{"token": "nope", "offset": 0}`)); out.Error == nil {
+		t.Error("expected an error for an unknown token")
+	}
+}
+
+func TestOutputPagesRuneLargerThanPage(t *testing.T) {
+	pages := &OutputPages{PageBytes: 2}
+	text := "€€x"
+	if got := pages.page(text, "t", 0); !strings.HasPrefix(got, "€\n[output truncated: bytes 0-3 of 7 shown") {
+		t.Errorf("first page = %q, want the whole first rune", got)
+	}
+	if got := pages.page(text, "t", 4); !strings.HasPrefix(got, "€\n[output truncated: bytes 3-6 of 7 shown") {
+		t.Errorf("page within a rune = %q, want the whole rune", got)
+	}
+	if got := pages.page(text, "t", 6); got != "x" {
+		t.Errorf("last page = %q", got)
+	}
+}
@@ -0,0 +1,162 @@
+// Package ratelimit paces LLM requests to stay within a provider's rate limits,
+// rather than sending them only to be turned away.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"shelley.exe.dev/llm"
+)
+
+// Limits are the most requests and tokens a provider accepts per minute. Zero means no limit.
+type Limits struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	TokensPerMinute   int `json:"tokens_per_minute"`
+}
+
+// Limiter paces requests to stay within Limits. One Limiter is meant to be shared
+// by all the services and conversations using a provider. It is safe for concurrent use.
+//
+// The tokens of a request are unknown until it completes, so they are counted afterwards:
+// requests wait while the tokens used in the last minute exceed the limit.
+// A burst of requests may go over the limit, but the requests after it wait for it to pass.
+type Limiter struct {
+	mu       sync.Mutex
+	requests bucket
+	tokens   bucket
+	now      func() time.Time                           // for tests; defaults to time.Now
+	sleep    func(context.Context, time.Duration) error // for tests; defaults to llm.Sleep
+}
+
+// NewLimiter returns a Limiter for limits.
+func NewLimiter(limits Limits) *Limiter {
+	return &Limiter{
+		requests: newBucket(limits.RequestsPerMinute),
+		tokens:   newBucket(limits.TokensPerMinute),
+	}
+}
+
+// Wait blocks until a request may be sent, or until ctx is done, in which case it returns ctx's error.
+func (l *Limiter) Wait(ctx context.Context) error {
+	// Take the request now, so requests go in the order they arrive
+	l.mu.Lock()
+	wait := l.requests.take(l.timeNow(), 1)
+	l.mu.Unlock()
+	if err := l.doSleep(ctx, wait); err != nil {
+		l.mu.Lock()
+		l.requests.level++
+		l.mu.Unlock()
+		return err
+	}
+
+	for {
+		l.mu.Lock()
+		wait := l.tokens.take(l.timeNow(), 0)
+		l.mu.Unlock()
+		if wait == 0 {
+			return nil
+		}
+		if err := l.doSleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Used counts the tokens of a completed request against the limit.
+// All the tokens a request processes count, including those read from a cache.
+func (l *Limiter) Used(usage llm.Usage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens.take(l.timeNow(), float64(usage.TotalInputTokens()+usage.OutputTokens))
+}
+
+func (l *Limiter) timeNow() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+func (l *Limiter) doSleep(ctx context.Context, d time.Duration) error {
+	if d == 0 {
+		return nil
+	}
+	if l.sleep != nil {
+		return l.sleep(ctx, d)
+	}
+	return llm.Sleep(ctx, d)
+}
+
+// bucket is a token bucket that refills at perMinute a minute, up to perMinute.
+// Its level goes below zero when more is taken than it holds, and must refill before more is taken.
+type bucket struct {
+	perMinute float64 // zero for no limit
+	level     float64
+	last      time.Time
+}
+
+func newBucket(perMinute int) bucket {
+	return bucket{perMinute: float64(perMinute), level: float64(perMinute)}
+}
+
+// take takes n from b, returning how long until b's level is no longer below zero.
+func (b *bucket) take(now time.Time, n float64) time.Duration {
+	if b.perMinute == 0 {
+		return 0
+	}
+	if !b.last.IsZero() {
+		b.level = min(b.perMinute, b.level+now.Sub(b.last).Minutes()*b.perMinute)
+	}
+	b.last = now
+	b.level -= n
+	if b.level >= 0 {
+		return 0
+	}
+	return time.Duration(-b.level / b.perMinute * float64(time.Minute))
+}
+
+// Service sends requests to another service as its Limiter allows.
+type Service struct {
+	Service llm.Service
+	Limiter *Limiter
+}
+
+var (
+	_ llm.Service           = (*Service)(nil)
+	_ llm.SimplifiedPatcher = (*Service)(nil)
+)
+
+// Do waits for the limiter, then sends ir to the service.
+func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	if err := s.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := s.Service.Do(ctx, ir)
+	if err != nil {
+		return nil, err
+	}
+	s.Limiter.Used(resp.Usage)
+	return resp, nil
+}
+
+// TokenContextWindow returns the context window of the service.
+func (s *Service) TokenContextWindow() int {
+	return s.Service.TokenContextWindow()
+}
+
+// ImageLimits returns the image limits of the service.
+func (s *Service) ImageLimits() llm.ImageLimits {
+	return s.Service.ImageLimits()
+}
+
+// UseSimplifiedPatch reports whether the service uses the simplified patch input schema.
+func (s *Service) UseSimplifiedPatch() bool {
+	return llm.UseSimplifiedPatch(s.Service)
+}
+
+// AcceptsDocuments reports whether the service sends documents to the model.
+func (s *Service) AcceptsDocuments() bool {
+	return llm.AcceptsDocuments(s.Service)
+}
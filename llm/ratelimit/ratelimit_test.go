@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"shelley.exe.dev/llm"
+)
+
+// newTestLimiter returns a Limiter on a fake clock, which its sleeps advance
+func newTestLimiter(limits Limits) (*Limiter, *time.Duration) {
+	l := NewLimiter(limits)
+	now := time.Now()
+	var slept time.Duration
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		slept += d
+		now = now.Add(d)
+		return nil
+	}
+	return l, &slept
+}
+
+func TestRequestsPerMinute(t *testing.T) {
+	l, slept := newTestLimiter(Limits{RequestsPerMinute: 60})
+	for range 60 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if *slept != 0 {
+		t.Errorf("Expected a minute's requests to go without waiting, waited %v", *slept)
+	}
+	for range 2 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if *slept != 2*time.Second {
+		t.Errorf("Expected requests past the limit to wait a second each, waited %v", *slept)
+	}
+}
+
+func TestTokensPerMinute(t *testing.T) {
+	l, slept := newTestLimiter(Limits{TokensPerMinute: 1000})
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	l.Used(llm.Usage{InputTokens: 1000, CacheReadInputTokens: 400, OutputTokens: 100})
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// 500 tokens over the limit take 30 seconds to pass
+	if *slept != 30*time.Second {
+		t.Errorf("Expected to wait for the tokens over the limit to pass, waited %v", *slept)
+	}
+}
+
+func TestNoLimits(t *testing.T) {
+	l, slept := newTestLimiter(Limits{})
+	for range 1000 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		l.Used(llm.Usage{InputTokens: 100000})
+	}
+	if *slept != 0 {
+		t.Errorf("Expected no waiting without limits, waited %v", *slept)
+	}
+}
+
+func TestWaitCanceled(t *testing.T) {
+	l, _ := newTestLimiter(Limits{RequestsPerMinute: 1})
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	l.sleep = func(ctx context.Context, d time.Duration) error { return context.Canceled }
+	if err := l.Wait(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() = %v, want context.Canceled", err)
+	}
+	// The canceled request gives back its place
+	if l.requests.level != 0 {
+		t.Errorf("Expected the canceled request to be given back, level is %v", l.requests.level)
+	}
+}
@@ -0,0 +1,153 @@
+// Package redact scrubs secrets, such as API keys and passwords, from tool outputs before they are sent to the model provider.
+//
+// A Redactor knows secrets two ways: by value, such as those of environment variables and .netrc passwords,
+// and by pattern, such as the well-known formats of API keys. Redaction is best effort:
+// a secret it does not know, or one encoded differently, such as in base64, gets through.
+package redact
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"shelley.exe.dev/llm"
+)
+
+// Placeholder replaces each secret redacted.
+const Placeholder = "[REDACTED]"
+
+// MinSecretLen is the length of the shortest secret value redacted;
+// redacting shorter values, such as "true" or "1", would garble unrelated text.
+const MinSecretLen = 8
+
+// DefaultPatterns match the formats of common API keys, tokens, and private keys.
+var DefaultPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`sk-[A-Za-z0-9_-]{20,}`),        // Anthropic and OpenAI
+	regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{36,}`),   // GitHub
+	regexp.MustCompile(`github_pat_[A-Za-z0-9_]{22,}`), // GitHub fine-grained
+	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),             // AWS access key ID
+	regexp.MustCompile(`AIza[0-9A-Za-z_-]{35}`),        // Google
+	regexp.MustCompile(`xox[abprs]-[A-Za-z0-9-]{10,}`), // Slack
+	regexp.MustCompile(`fw_[A-Za-z0-9]{20,}`),          // Fireworks
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----(?s:.*?)-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// secretEnvName matches the names of environment variables whose values are secrets:
+// those ending in a component such as _KEY or _TOKEN, and those naming a password
+var secretEnvName = regexp.MustCompile(`(?i)(^|_)(API_?KEY|KEY|TOKEN|SECRET|CREDENTIALS?|AUTH)$|PASSWORD|PASSWD`)
+
+// notSecretEnvNames are environment variables whose names suggest secrets but whose values are not
+var notSecretEnvNames = []string{"SSH_AUTH_SOCK", "XAUTHORITY", "PASSWORD_STORE_DIR", "PASSWORD_STORE_CLIP_TIME", "PASSWORD_STORE_GENERATED_LENGTH"}
+
+// Redactor replaces secrets in text with Placeholder. It is safe for concurrent use.
+type Redactor struct {
+	secrets  *strings.Replacer
+	patterns []*regexp.Regexp
+}
+
+// New returns a Redactor of secret values and patterns.
+func New(secrets []string, patterns []*regexp.Regexp) *Redactor {
+	secrets = slices.DeleteFunc(slices.Clone(secrets), func(s string) bool { return len(s) < MinSecretLen })
+	// The replacer tries secrets in order, so longest first keeps a secret containing another from being partly redacted
+	slices.SortFunc(secrets, func(a, b string) int { return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b)) })
+	secrets = slices.Compact(secrets)
+	var oldnew []string
+	for _, s := range secrets {
+		oldnew = append(oldnew, s, Placeholder)
+	}
+	return &Redactor{secrets: strings.NewReplacer(oldnew...), patterns: patterns}
+}
+
+// FromEnvironment returns a Redactor of the values of the environment variables whose names suggest secrets,
+// such as ANTHROPIC_API_KEY, the passwords in the .netrc file, and DefaultPatterns.
+// The .netrc file is $NETRC, or ~/.netrc; it need not exist.
+func FromEnvironment() (*Redactor, error) {
+	secrets := EnvSecrets(os.Environ())
+	path := os.Getenv("NETRC")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	secrets = append(secrets, NetrcSecrets(string(data))...)
+	return New(secrets, DefaultPatterns), nil
+}
+
+// EnvSecrets returns the values of the variables of environ, in the form of os.Environ, whose names suggest secrets.
+func EnvSecrets(environ []string) []string {
+	var secrets []string
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if ok && secretEnvName.MatchString(name) && !slices.Contains(notSecretEnvNames, strings.ToUpper(name)) {
+			secrets = append(secrets, value)
+		}
+	}
+	return secrets
+}
+
+// NetrcSecrets returns the passwords and accounts in data, the contents of a .netrc file.
+func NetrcSecrets(data string) []string {
+	var secrets []string
+	words := bufio.NewScanner(strings.NewReader(data))
+	words.Split(bufio.ScanWords)
+	for words.Scan() {
+		if w := words.Text(); (w == "password" || w == "account") && words.Scan() {
+			secrets = append(secrets, words.Text())
+		}
+	}
+	return secrets
+}
+
+// Redact returns s with the secrets it contains replaced with Placeholder.
+func (r *Redactor) Redact(s string) string {
+	s = r.secrets.Replace(s)
+	for _, p := range r.patterns {
+		s = p.ReplaceAllLiteralString(s, Placeholder)
+	}
+	return s
+}
+
+// Middleware redacts the text a tool sends the model: that of its LLMContent, and of its Error.
+// The Display of tools, which only the user sees, is left alone. It is an llm.ToolMiddleware.
+func (r *Redactor) Middleware(tool *llm.Tool, next llm.ToolRunFunc) llm.ToolRunFunc {
+	return func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+		out := next(ctx, input)
+		if out.Error != nil {
+			if msg := r.Redact(out.Error.Error()); msg != out.Error.Error() {
+				out.Error = errors.New(msg)
+			}
+		}
+		var contents []llm.Content
+		for i, c := range out.LLMContent {
+			if c.Type != llm.ContentTypeText {
+				continue
+			}
+			text := r.Redact(c.Text)
+			if text == c.Text {
+				continue
+			}
+			if contents == nil {
+				contents = slices.Clone(out.LLMContent)
+			}
+			contents[i].Text = text
+		}
+		if contents != nil {
+			out.LLMContent = contents
+		}
+		return out
+	}
+}
@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// validToolName matches the tool names providers accept
+var validToolName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// NamespacedName returns name prefixed with namespace, such as "mcp.github", for tools from sources
+// that do not choose their names to be unique. Dots in namespace become underscores,
+// as providers do not allow dots in tool names: "mcp.github" and "search" make "mcp_github_search".
+func NamespacedName(namespace, name string) string {
+	return strings.ReplaceAll(namespace, ".", "_") + "_" + name
+}
+
+// Namespaced returns copies of tools renamed to their NamespacedName in namespace.
+func Namespaced(namespace string, tools []*Tool) []*Tool {
+	renamed := make([]*Tool, len(tools))
+	for i, tool := range tools {
+		t := *tool
+		t.Name = NamespacedName(namespace, tool.Name)
+		renamed[i] = &t
+	}
+	return renamed
+}
+
+// ToolRegistry combines tools from several sources, such as the built-in tools, the browser tools, and those of MCP servers,
+// each under its own namespace, such as "builtin", "browser", or "mcp.github".
+// It refuses a tool whose name is taken rather than let one tool shadow another.
+// The zero ToolRegistry is empty and ready to use. It is not safe for concurrent use.
+type ToolRegistry struct {
+	// Middleware wraps the Run of each tool registered, the first outermost.
+	Middleware []ToolMiddleware
+
+	tools      []*Tool
+	namespaces []string // of tools
+}
+
+// Register adds tools under namespace, wrapped in the registry's middleware.
+// It adds none of them if any name is invalid or taken.
+func (r *ToolRegistry) Register(namespace string, tools ...*Tool) error {
+	for i, t := range tools {
+		if !validToolName.MatchString(t.Name) {
+			return fmt.Errorf("invalid tool name %q: tool names are 1 to 64 letters, digits, underscores, and hyphens", t.Name)
+		}
+		if j := slices.IndexFunc(r.tools, func(u *Tool) bool { return u.Name == t.Name }); j >= 0 {
+			return fmt.Errorf("tool %s from namespace %q collides with one from namespace %q", t.Name, namespace, r.namespaces[j])
+		}
+		if slices.ContainsFunc(tools[:i], func(u *Tool) bool { return u.Name == t.Name }) {
+			return fmt.Errorf("namespace %q has two tools named %s", namespace, t.Name)
+		}
+	}
+	r.tools = append(r.tools, WithMiddleware(tools, r.Middleware...)...)
+	for range tools {
+		r.namespaces = append(r.namespaces, namespace)
+	}
+	return nil
+}
+
+// Unregister removes the tools with the given names.
+func (r *ToolRegistry) Unregister(names ...string) {
+	r.remove(func(i int) bool { return slices.Contains(names, r.tools[i].Name) })
+}
+
+// UnregisterNamespace removes the tools of namespace.
+func (r *ToolRegistry) UnregisterNamespace(namespace string) {
+	r.remove(func(i int) bool { return r.namespaces[i] == namespace })
+}
+
+func (r *ToolRegistry) remove(match func(i int) bool) {
+	var tools []*Tool
+	var namespaces []string
+	for i := range r.tools {
+		if !match(i) {
+			tools = append(tools, r.tools[i])
+			namespaces = append(namespaces, r.namespaces[i])
+		}
+	}
+	r.tools, r.namespaces = tools, namespaces
+}
+
+// Tools returns the registered tools, in the order they were registered.
+func (r *ToolRegistry) Tools() []*Tool {
+	return slices.Clone(r.tools)
+}
+
+// Namespace returns the namespace of the tool named name, and whether there is such a tool.
+func (r *ToolRegistry) Namespace(name string) (string, bool) {
+	i := slices.IndexFunc(r.tools, func(t *Tool) bool { return t.Name == name })
+	if i < 0 {
+		return "", false
+	}
+	return r.namespaces[i], true
+}
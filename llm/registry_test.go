@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNamespacedName(t *testing.T) {
+	tests := []struct {
+		namespace, name, want string
+	}{
+		{"fs", "read", "fs_read"},
+		{"mcp.github", "search", "mcp_github_search"},
+	}
+	for _, tt := range tests {
+		if got := NamespacedName(tt.namespace, tt.name); got != tt.want {
+			t.Errorf("NamespacedName(%q, %q) = %q, want %q", tt.namespace, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestToolRegistry(t *testing.T) {
+	var wrapped []string
+	r := &ToolRegistry{Middleware: []ToolMiddleware{func(tool *Tool, next ToolRunFunc) ToolRunFunc {
+		wrapped = append(wrapped, tool.Name)
+		return next
+	}}}
+	names := func() []string {
+		var names []string
+		for _, tool := range r.Tools() {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	search := &Tool{Name: "search", Run: func(ctx context.Context, input json.RawMessage) ToolOut { return ToolOut{} }}
+
+	if err := r.Register("builtin", search); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("mcp.github", Namespaced("mcp.github", []*Tool{search, {Name: "issue"}})...); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"search", "mcp_github_search", "mcp_github_issue"}; !slices.Equal(names(), want) {
+		t.Errorf("tools = %v, want %v", names(), want)
+	}
+	if !slices.Equal(wrapped, names()) {
+		t.Errorf("middleware wrapped %v, want every tool under its namespaced name", wrapped)
+	}
+	if search.Name != "search" {
+		t.Errorf("Namespaced renamed the tool given it to %s; it should rename a copy", search.Name)
+	}
+	if ns, ok := r.Namespace("mcp_github_issue"); !ok || ns != "mcp.github" {
+		t.Errorf("Namespace(mcp_github_issue) = %q, %v", ns, ok)
+	}
+
+	err := r.Register("mcp.gitlab", &Tool{Name: "new"}, &Tool{Name: "mcp_github_search"})
+	if err == nil || !strings.Contains(err.Error(), `namespace "mcp.github"`) {
+		t.Errorf("Register of a colliding tool = %v, want an error naming the namespace it collides with", err)
+	}
+	if err := r.Register("x", &Tool{Name: "a"}, &Tool{Name: "a"}); err == nil {
+		t.Error("Register of two tools with the same name should fail")
+	}
+	if err := r.Register("builtin", &Tool{Name: "has.dot"}); err == nil {
+		t.Error("Register of an invalid name should fail")
+	}
+	if want := []string{"search", "mcp_github_search", "mcp_github_issue"}; !slices.Equal(names(), want) {
+		t.Errorf("failed registrations should add nothing; tools = %v", names())
+	}
+
+	r.UnregisterNamespace("mcp.github")
+	if want := []string{"search"}; !slices.Equal(names(), want) {
+		t.Errorf("after UnregisterNamespace, tools = %v, want %v", names(), want)
+	}
+	r.Unregister("search")
+	if len(r.Tools()) != 0 {
+		t.Errorf("after Unregister, tools = %v, want none", names())
+	}
+}
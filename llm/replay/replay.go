@@ -0,0 +1,201 @@
+// Package replay provides an llm.Service that records the exchanges of another service to a golden file
+// and replays them, so tests of agent behavior run without API keys and without nondeterminism.
+//
+// Tests call Open, which replays the golden file. To record it afresh against the real API, run:
+//
+//	go test -llmrecord=<regexp matching the file>
+//
+// Requests are recorded and compared as llm.RequestJSON, only what the model sees of them,
+// so golden files outlive changes to the fields shelley keeps for itself.
+//
+// Requests that carry machine-specific values, such as a system prompt naming the working directory,
+// match between machines once Normalize replaces those values with placeholders.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"shelley.exe.dev/llm"
+)
+
+var record = flag.String("llmrecord", "", "re-record replay golden files matching `regexp`")
+
+// exchange is a request and what the service answered, one per line of a golden file
+type exchange struct {
+	Request  json.RawMessage `json:"request"`
+	Response *llm.Response   `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	// Status and RetryAfter are those of an error that was an *llm.StatusError, so retries replay too
+	Status     int           `json:"status,omitempty"`
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+}
+
+// Service records or replays the exchanges of Service.
+// It is safe for concurrent use, but exchanges replay in the order they were recorded.
+type Service struct {
+	Service llm.Service
+
+	path      string
+	normalize *strings.Replacer
+	mu        sync.Mutex
+	file      *os.File    // when recording
+	exchanges []*exchange // when replaying
+	next      int
+}
+
+var (
+	_ llm.Service           = (*Service)(nil)
+	_ llm.SimplifiedPatcher = (*Service)(nil)
+)
+
+// Open replays the golden file at path, or records it from svc if the -llmrecord flag matches path.
+// svc is the service the test would use with a real API; when replaying, it is only asked for its limits.
+func Open(path string, svc llm.Service) (*Service, error) {
+	if *record != "" {
+		re, err := regexp.Compile(*record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -llmrecord: %w", err)
+		}
+		if re.MatchString(path) {
+			return Record(path, svc)
+		}
+	}
+	return Replay(path, svc)
+}
+
+// Record returns a Service that records the exchanges of svc to the golden file at path, replacing it.
+// Close the Service when done.
+func Record(path string, svc llm.Service) (*Service, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{Service: svc, path: path, file: f}, nil
+}
+
+// Replay returns a Service that replays the golden file at path.
+func Replay(path string, svc llm.Service) (*Service, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w (record it with -llmrecord)", err)
+	}
+	s := &Service{Service: svc, path: path}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		var e exchange
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, len(s.exchanges)+1, err)
+		}
+		s.exchanges = append(s.exchanges, &e)
+	}
+	return s, scanner.Err()
+}
+
+// Normalize replaces each old string with its new one in requests before they are recorded or compared,
+// given as old, new pairs as to strings.NewReplacer, e.g. a test's temporary directory and "/work".
+// Call it before Do.
+func (s *Service) Normalize(oldnew ...string) {
+	// Requests are compared as JSON, where the strings appear escaped
+	escaped := make([]string, len(oldnew))
+	for i, str := range oldnew {
+		quoted, _ := json.Marshal(str)
+		escaped[i] = string(quoted[1 : len(quoted)-1])
+	}
+	s.normalize = strings.NewReplacer(escaped...)
+}
+
+// Do records or replays ir and what the service answers.
+// When replaying, it fails if ir differs from the request recorded in its place.
+func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	req, err := llm.RequestJSON(ir)
+	if err != nil {
+		return nil, err
+	}
+	if s.normalize != nil {
+		req = json.RawMessage(s.normalize.Replace(string(req)))
+	}
+	if s.file != nil {
+		resp, err := s.Service.Do(ctx, ir)
+		e := &exchange{Request: req, Response: resp}
+		if err != nil {
+			e.Error = err.Error()
+			var se *llm.StatusError
+			if errors.As(err, &se) {
+				e.Status, e.RetryAfter = se.StatusCode, se.RetryAfter
+			}
+		}
+		line, marshalErr := json.Marshal(e)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, writeErr := s.file.Write(append(line, '\n')); writeErr != nil {
+			return nil, writeErr
+		}
+		return resp, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next == len(s.exchanges) {
+		return nil, fmt.Errorf("%s: no recorded exchange for request %d (re-record with -llmrecord)", s.path, s.next+1)
+	}
+	e := s.exchanges[s.next]
+	s.next++
+	if !bytes.Equal(req, e.Request) {
+		return nil, fmt.Errorf("%s: request %d differs from the recorded one (re-record with -llmrecord):\ngot  %s\nwant %s", s.path, s.next, req, e.Request)
+	}
+	if e.Status != 0 {
+		return nil, &llm.StatusError{StatusCode: e.Status, Err: errors.New(e.Error), RetryAfter: e.RetryAfter}
+	}
+	if e.Error != "" {
+		return nil, errors.New(e.Error)
+	}
+	return e.Response, nil
+}
+
+// Close finishes recording. When replaying, it reports any recorded exchanges that were not replayed.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil {
+		return s.file.Close()
+	}
+	if s.next < len(s.exchanges) {
+		return fmt.Errorf("%s: %d of %d recorded exchanges were not replayed", s.path, len(s.exchanges)-s.next, len(s.exchanges))
+	}
+	return nil
+}
+
+// TokenContextWindow returns the context window of the service.
+func (s *Service) TokenContextWindow() int {
+	return s.Service.TokenContextWindow()
+}
+
+// ImageLimits returns the image limits of the service.
+func (s *Service) ImageLimits() llm.ImageLimits {
+	return s.Service.ImageLimits()
+}
+
+// UseSimplifiedPatch reports whether the service uses the simplified patch input schema.
+func (s *Service) UseSimplifiedPatch() bool {
+	return llm.UseSimplifiedPatch(s.Service)
+}
+
+// AcceptsDocuments reports whether the service sends documents to the model.
+func (s *Service) AcceptsDocuments() bool {
+	return llm.AcceptsDocuments(s.Service)
+}
@@ -0,0 +1,137 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"shelley.exe.dev/llm"
+)
+
+// echoService answers each request with the text of its last message, and fails on "fail"
+type echoService struct{ calls int }
+
+func (e *echoService) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	e.calls++
+	text := ir.Messages[len(ir.Messages)-1].Content[0].Text
+	switch text {
+	case "fail":
+		return nil, errors.New("overloaded")
+	case "busy":
+		return nil, &llm.StatusError{StatusCode: 529, Err: errors.New("overloaded"), RetryAfter: time.Second}
+	}
+	return &llm.Response{Role: llm.MessageRoleAssistant, Content: []llm.Content{llm.StringContent("echo: " + text)}}, nil
+}
+
+func (e *echoService) TokenContextWindow() int      { return 1000 }
+func (e *echoService) ImageLimits() llm.ImageLimits { return llm.ImageLimits{} }
+
+func request(text string) *llm.Request {
+	now := time.Now()
+	return &llm.Request{Messages: []llm.Message{{Role: llm.MessageRoleUser, Content: []llm.Content{
+		llm.StringContent(text),
+		// Tool call times differ between recording and replaying, and are not sent to the LLM
+		{Type: llm.ContentTypeToolResult, ToolUseID: "call_1", ToolUseStartTime: &now, ToolResult: []llm.Content{llm.StringContent("ok")}},
+	}}}}
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "echo.jsonl")
+
+	rec, err := Record(path, &echoService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.Do(ctx, request("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.Do(ctx, request("fail")); err == nil {
+		t.Fatal("expected the service's error")
+	}
+	if _, err := rec.Do(ctx, request("busy")); err == nil {
+		t.Fatal("expected the service's error")
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &echoService{}
+	rep, err := Replay(path, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rep.Do(ctx, request("hello"))
+	if err != nil || resp.Content[0].Text != "echo: hello" {
+		t.Errorf("Do() = %v, %v; want the recorded response", resp, err)
+	}
+	if _, err := rep.Do(ctx, request("fail")); err == nil || err.Error() != "overloaded" {
+		t.Errorf("Do() error = %v, want the recorded error", err)
+	}
+	var se *llm.StatusError
+	if _, err := rep.Do(ctx, request("busy")); !errors.As(err, &se) || se.StatusCode != 529 || se.RetryAfter != time.Second || !llm.IsTransient(err) {
+		t.Errorf("Do() error = %#v, want the recorded *llm.StatusError", err)
+	}
+	if _, err := rep.Do(ctx, request("again")); err == nil || !strings.Contains(err.Error(), "no recorded exchange") {
+		t.Errorf("Do() error = %v, want no recorded exchange", err)
+	}
+	if svc.calls != 0 {
+		t.Errorf("replaying called the service %d times", svc.calls)
+	}
+	if rep.TokenContextWindow() != 1000 {
+		t.Errorf("TokenContextWindow() = %d, want the service's", rep.TokenContextWindow())
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "echo.jsonl")
+	rec, err := Record(path, &echoService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Do(ctx, request("hello"))
+	rec.Do(ctx, request("bye"))
+	rec.Close()
+
+	rep, err := Replay(path, &echoService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rep.Do(ctx, request("goodbye")); err == nil || !strings.Contains(err.Error(), "request 1 differs") {
+		t.Errorf("Do() error = %v, want a mismatch", err)
+	}
+	if err := rep.Close(); err == nil || !strings.Contains(err.Error(), "1 of 2 recorded exchanges were not replayed") {
+		t.Errorf("Close() = %v, want the unreplayed exchange reported", err)
+	}
+}
+
+func TestReplayNormalize(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "echo.jsonl")
+	rec, err := Record(path, &echoService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Normalize(`C:\Users\me`, "/work")
+	rec.Do(ctx, request(`cwd: C:\Users\me`))
+	rec.Close()
+
+	rep, err := Replay(path, &echoService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep.Normalize("/tmp/TestReplay123", "/work")
+	if _, err := rep.Do(ctx, request("cwd: /tmp/TestReplay123")); err != nil {
+		t.Errorf("Do() error = %v, want the request to match once normalized", err)
+	}
+}
+
+func TestReplayMissingFile(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.jsonl"), &echoService{}); err == nil || !strings.Contains(err.Error(), "-llmrecord") {
+		t.Errorf("Open() error = %v, want a hint to record", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Package retry provides an llm.Service that retries requests that fail transiently.
+package retry
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/telemetry"
+)
+
+// The defaults of a Policy, which retry for about two minutes.
+const (
+	DefaultMaxAttempts = 6
+	DefaultBaseDelay   = 5 * time.Second
+	DefaultMaxDelay    = time.Minute
+)
+
+// Policy says how often and how long to wait to retry a request. Zero fields take their defaults.
+type Policy struct {
+	MaxAttempts int           // the most attempts at a request, including the first
+	BaseDelay   time.Duration // the wait before the first retry, which doubles with each retry
+	MaxDelay    time.Duration // the longest wait before a retry, including one asked for by the API
+}
+
+// Service retries requests to another service that fail with transient errors (see llm.IsTransient),
+// waiting with jittered exponential backoff between attempts, or as long as the API asks with Retry-After.
+// A request whose Retry-After is longer than the policy's MaxDelay fails without waiting,
+// so callers such as a fallback.Service can go elsewhere.
+type Service struct {
+	Service llm.Service
+	Policy  Policy
+
+	sleep func(context.Context, time.Duration) error // for tests; defaults to llm.Sleep
+}
+
+var (
+	_ llm.Service           = (*Service)(nil)
+	_ llm.SimplifiedPatcher = (*Service)(nil)
+)
+
+// Do sends ir to the service, retrying transient failures until an attempt succeeds,
+// the attempts run out, or ctx is done.
+func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	maxAttempts := s.Policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := s.Service.Do(ctx, ir)
+		if err == nil || ctx.Err() != nil || !llm.IsTransient(err) {
+			return resp, err
+		}
+		if attempt == maxAttempts {
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		delay, ok := s.delay(attempt, err)
+		if !ok {
+			return nil, err
+		}
+		slog.WarnContext(ctx, "llm request failed, retrying", "attempt", attempt, "delay", delay, "error", err)
+		telemetry.RecordRetry(ctx, attempt, delay, err)
+		sleep := s.sleep
+		if sleep == nil {
+			sleep = llm.Sleep
+		}
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return nil, errors.Join(err, sleepErr)
+		}
+	}
+}
+
+// delay returns how long to wait before retrying after the given attempt failed with err,
+// and false if the API asked to wait longer than the policy allows.
+func (s *Service) delay(attempt int, err error) (time.Duration, bool) {
+	base := cmp.Or(s.Policy.BaseDelay, DefaultBaseDelay)
+	maxDelay := cmp.Or(s.Policy.MaxDelay, DefaultMaxDelay)
+
+	// Equal jitter: half the backoff, plus a random part of the other half,
+	// so that conversations failing together don't retry together
+	backoff := min(base<<min(attempt-1, 20), maxDelay)
+	delay := backoff/2 + rand.N(backoff/2+1)
+
+	var se *llm.StatusError
+	if errors.As(err, &se) && se.RetryAfter > 0 {
+		if se.RetryAfter > maxDelay {
+			return 0, false
+		}
+		delay = max(delay, se.RetryAfter)
+	}
+	return delay, true
+}
+
+// TokenContextWindow returns the context window of the service.
+func (s *Service) TokenContextWindow() int {
+	return s.Service.TokenContextWindow()
+}
+
+// ImageLimits returns the image limits of the service.
+func (s *Service) ImageLimits() llm.ImageLimits {
+	return s.Service.ImageLimits()
+}
+
+// UseSimplifiedPatch reports whether the service uses the simplified patch input schema.
+func (s *Service) UseSimplifiedPatch() bool {
+	return llm.UseSimplifiedPatch(s.Service)
+}
+
+// AcceptsDocuments reports whether the service sends documents to the model.
+func (s *Service) AcceptsDocuments() bool {
+	return llm.AcceptsDocuments(s.Service)
+}
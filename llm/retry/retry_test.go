@@ -0,0 +1,111 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"shelley.exe.dev/llm"
+)
+
+// fakeService fails with each of errs in turn, then succeeds
+type fakeService struct {
+	errs  []error
+	calls int
+}
+
+func (f *fakeService) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	return &llm.Response{Content: []llm.Content{llm.StringContent("ok")}}, nil
+}
+
+func (f *fakeService) TokenContextWindow() int      { return 200000 }
+func (f *fakeService) ImageLimits() llm.ImageLimits { return llm.ImageLimits{} }
+
+var (
+	rateLimited = &llm.StatusError{StatusCode: 429, Err: errors.New("rate limited")}
+	overloaded  = &llm.StatusError{StatusCode: 529, Err: errors.New("overloaded")}
+	badRequest  = &llm.StatusError{StatusCode: 400, Err: errors.New("bad request")}
+)
+
+// newService returns a Service that records its sleeps rather than sleeping
+func newService(f *fakeService, policy Policy) (*Service, *[]time.Duration) {
+	var sleeps []time.Duration
+	s := &Service{Service: f, Policy: policy}
+	s.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return s, &sleeps
+}
+
+func TestRetriesTransientErrors(t *testing.T) {
+	f := &fakeService{errs: []error{rateLimited, overloaded, context.DeadlineExceeded}}
+	s, sleeps := newService(f, Policy{BaseDelay: time.Second, MaxDelay: 3 * time.Second})
+	if _, err := s.Do(context.Background(), &llm.Request{}); err != nil {
+		t.Fatal(err)
+	}
+	if f.calls != 4 {
+		t.Errorf("Expected 4 attempts, got %d", f.calls)
+	}
+	// Each delay is between half and all of the backoff: 1s, 2s, then 4s capped at 3s
+	for i, backoff := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		if d := (*sleeps)[i]; d < backoff/2 || d > backoff {
+			t.Errorf("Delay %d = %v, want between %v and %v", i, d, backoff/2, backoff)
+		}
+	}
+}
+
+func TestDoesNotRetryRequestErrors(t *testing.T) {
+	f := &fakeService{errs: []error{badRequest}}
+	s, _ := newService(f, Policy{})
+	if _, err := s.Do(context.Background(), &llm.Request{}); !errors.Is(err, badRequest) || f.calls != 1 {
+		t.Errorf("Expected the request error without retrying, got %v after %d attempts", err, f.calls)
+	}
+}
+
+func TestGivesUpAfterMaxAttempts(t *testing.T) {
+	f := &fakeService{errs: []error{rateLimited, rateLimited, rateLimited}}
+	s, _ := newService(f, Policy{MaxAttempts: 2})
+	if _, err := s.Do(context.Background(), &llm.Request{}); !errors.Is(err, rateLimited) || f.calls != 2 {
+		t.Errorf("Expected to give up after 2 attempts, got %v after %d attempts", err, f.calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	asked := &llm.StatusError{StatusCode: 429, Err: errors.New("rate limited"), RetryAfter: 20 * time.Second}
+	f := &fakeService{errs: []error{asked}}
+	s, sleeps := newService(f, Policy{BaseDelay: time.Second, MaxDelay: 30 * time.Second})
+	if _, err := s.Do(context.Background(), &llm.Request{}); err != nil {
+		t.Fatal(err)
+	}
+	if (*sleeps)[0] != 20*time.Second {
+		t.Errorf("Expected to wait as long as Retry-After, got %v", (*sleeps)[0])
+	}
+
+	// Waits longer than the policy allows fail straight away
+	f = &fakeService{errs: []error{asked}}
+	s, sleeps = newService(f, Policy{MaxDelay: 10 * time.Second})
+	if _, err := s.Do(context.Background(), &llm.Request{}); !errors.Is(err, asked) || len(*sleeps) != 0 {
+		t.Errorf("Expected to fail without waiting, got %v after %d sleeps", err, len(*sleeps))
+	}
+}
+
+func TestStopsWhenContextIsDone(t *testing.T) {
+	f := &fakeService{errs: []error{rateLimited, rateLimited}}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Service{Service: f}
+	s.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+	if _, err := s.Do(ctx, &llm.Request{}); !errors.Is(err, context.Canceled) || !errors.Is(err, rateLimited) || f.calls != 1 {
+		t.Errorf("Expected to stop with the last error when canceled, got %v after %d attempts", err, f.calls)
+	}
+}
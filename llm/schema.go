@@ -0,0 +1,299 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SchemaFor returns the JSON schema of a tool's input, which T describes as a struct,
+// so the schema cannot drift from the struct the tool unmarshals its input into.
+//
+// Each exported field is a property named by its json tag, as encoding/json would
+// marshal it, and is required unless its json tag has omitempty or omitzero.
+// Fields of embedded structs are promoted, as with encoding/json.
+// A description tag describes the property, and an enum tag lists the values it
+// may take, separated by commas, or that its items may take if it is a slice.
+//
+// It panics if T is not a struct or has a field that JSON schema cannot describe.
+func SchemaFor[T any]() json.RawMessage {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		panic("tool input must be a struct, got " + t.String())
+	}
+	schema, err := json.Marshal(typeSchema(t))
+	if err != nil {
+		panic("failed to marshal JSON schema for " + t.String() + ": " + err.Error())
+	}
+	return MustSchema(string(schema))
+}
+
+// typeSchema returns the JSON schema of values of type t
+func typeSchema(t reflect.Type) map[string]any {
+	if t == reflect.TypeFor[json.RawMessage]() {
+		return map[string]any{}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Interface:
+		return map[string]any{}
+	case reflect.Slice, reflect.Array:
+		s := map[string]any{"type": "array"}
+		if items := typeSchema(t.Elem()); len(items) > 0 {
+			s["items"] = items
+		}
+		return s
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			panic("JSON schema map keys must be strings, got " + t.String())
+		}
+		s := map[string]any{"type": "object"}
+		if values := typeSchema(t.Elem()); len(values) > 0 {
+			s["additionalProperties"] = values
+		}
+		return s
+	case reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+		addProperties(t, properties, &required)
+		s := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	default:
+		panic(fmt.Sprintf("cannot describe %s in a JSON schema", t))
+	}
+}
+
+// addProperties adds the properties of struct type t to properties, and the names of those that are required to required.
+// As with encoding/json, the fields of embedded structs without a json name are promoted, unless t has a field of the same name.
+func addProperties(t reflect.Type, properties map[string]any, required *[]string) {
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if ft := indirect(field.Type); field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded = append(embedded, ft)
+			continue
+		}
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		s := typeSchema(field.Type)
+		if desc := field.Tag.Get("description"); desc != "" {
+			s["description"] = desc
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			if items, ok := s["items"].(map[string]any); ok {
+				items["enum"] = strings.Split(enum, ",")
+			} else {
+				s["enum"] = strings.Split(enum, ",")
+			}
+		}
+		properties[name] = s
+		if !strings.Contains(","+opts+",", ",omitempty,") && !strings.Contains(","+opts+",", ",omitzero,") {
+			*required = append(*required, name)
+		}
+	}
+	for _, et := range embedded {
+		promoted := map[string]any{}
+		var promotedRequired []string
+		addProperties(et, promoted, &promotedRequired)
+		// Required names are added in declaration order, so the schema is the same every time
+		for _, name := range promotedRequired {
+			if _, ok := properties[name]; !ok {
+				*required = append(*required, name)
+			}
+		}
+		for name, s := range promoted {
+			if _, ok := properties[name]; !ok {
+				properties[name] = s
+			}
+		}
+	}
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// InputError reports how a tool's input fails to match its InputSchema.
+// Its message is meant for the model, to correct its input and call the tool again.
+type InputError struct {
+	Tool     string
+	Problems []InputProblem
+}
+
+// InputProblem is one way a tool's input fails to match its InputSchema.
+type InputProblem struct {
+	Path    string // where in the input, e.g. "fields[0].value"; empty for the input as a whole
+	Message string
+}
+
+func (e *InputError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid input for %s:", e.Tool)
+	for _, p := range e.Problems {
+		b.WriteString("\n- ")
+		if p.Path != "" {
+			b.WriteString(p.Path + ": ")
+		}
+		b.WriteString(p.Message)
+	}
+	return b.String()
+}
+
+// ValidateInput checks input against the tool's InputSchema, returning an *InputError if it does not match.
+// It supports the parts of JSON schema tools use: type, properties, required, additionalProperties, items, and enum.
+// Tools with LenientInput or without an InputSchema accept any input.
+func (t *Tool) ValidateInput(input json.RawMessage) error {
+	if t.LenientInput || len(t.InputSchema) == 0 {
+		return nil
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(t.InputSchema, &schema); err != nil {
+		return fmt.Errorf("invalid input schema for %s: %w", t.Name, err)
+	}
+	if len(bytes.TrimSpace(input)) == 0 {
+		input = json.RawMessage("{}") // some providers send no arguments for tools without parameters
+	}
+	dec := json.NewDecoder(bytes.NewReader(input))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return &InputError{Tool: t.Name, Problems: []InputProblem{{Message: "not valid JSON: " + err.Error()}}}
+	}
+	var problems []InputProblem
+	validate(schema, v, "", &problems)
+	if len(problems) > 0 {
+		return &InputError{Tool: t.Name, Problems: problems}
+	}
+	return nil
+}
+
+// validate appends the ways v fails to match schema to problems
+func validate(schema map[string]any, v any, path string, problems *[]InputProblem) {
+	problem := func(format string, args ...any) {
+		*problems = append(*problems, InputProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var types []string
+	switch typ := schema["type"].(type) {
+	case string:
+		types = []string{typ}
+	case []any:
+		for _, t := range typ {
+			if s, ok := t.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	if got := jsonType(v); len(types) > 0 && !slices.ContainsFunc(types, func(want string) bool {
+		return want == got || want == "number" && got == "integer"
+	}) {
+		problem("expected %s, got %s", strings.Join(types, " or "), got)
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		got, _ := json.Marshal(v)
+		if !slices.ContainsFunc(enum, func(e any) bool {
+			want, _ := json.Marshal(e)
+			return bytes.Equal(got, want)
+		}) {
+			var values []string
+			for _, e := range enum {
+				value, _ := json.Marshal(e)
+				values = append(values, string(value))
+			}
+			problem("got %s, want one of %s", got, strings.Join(values, ", "))
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, ok := v[name]; !ok {
+					*problems = append(*problems, InputProblem{Path: joinPath(path, name), Message: "required but missing"})
+				}
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			if s, ok := properties[name].(map[string]any); ok {
+				// Models often send null for an optional property they mean to leave out
+				if v[name] == nil && !slices.Contains(required, any(name)) {
+					continue
+				}
+				validate(s, v[name], joinPath(path, name), problems)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					*problems = append(*problems, InputProblem{Path: joinPath(path, name), Message: "unknown property"})
+				}
+			case map[string]any:
+				validate(additional, v[name], joinPath(path, name), problems)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				validate(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+}
+
+// jsonType returns the JSON schema type of a value decoded with json.Decoder.UseNumber
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaFor(t *testing.T) {
+	type clip struct {
+		X     float64 `json:"x"`
+		Width float64 `json:"width"`
+	}
+	type shared struct {
+		Frame   string `json:"frame,omitempty" description:"iframe to target"`
+		Timeout string `json:"timeout" description:"shadowed"`
+	}
+	type input struct {
+		shared
+		URL     string          `json:"url" description:"The URL to navigate to"`
+		Mode    string          `json:"mode,omitempty" enum:"reject,accept"`
+		Count   int             `json:"count,omitzero"`
+		Await   *bool           `json:"await,omitempty"`
+		Tags    []string        `json:"tags,omitempty" enum:"a,b"`
+		Args    []any           `json:"args,omitempty"`
+		Clip    *clip           `json:"clip,omitempty"`
+		Headers map[string]int  `json:"headers,omitempty"`
+		Raw     json.RawMessage `json:"raw,omitempty"`
+		Timeout string          `json:"timeout,omitempty"`
+		Skipped string          `json:"-"`
+		hidden  string
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(SchemaFor[input](), &got); err != nil {
+		t.Fatal(err)
+	}
+	var want map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"frame": {"type": "string", "description": "iframe to target"},
+			"url": {"type": "string", "description": "The URL to navigate to"},
+			"mode": {"type": "string", "enum": ["reject", "accept"]},
+			"count": {"type": "integer"},
+			"await": {"type": "boolean"},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}},
+			"args": {"type": "array"},
+			"clip": {
+				"type": "object",
+				"properties": {"x": {"type": "number"}, "width": {"type": "number"}},
+				"required": ["x", "width"]
+			},
+			"headers": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"raw": {},
+			"timeout": {"type": "string"}
+		},
+		"required": ["url"]
+	}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SchemaFor() = %v, want %v", got, want)
+	}
+
+	type point struct {
+		X, Y, Z, W int
+	}
+	type promoted struct {
+		point
+		Name string
+	}
+	for range 10 {
+		if got := string(SchemaFor[promoted]()); !strings.HasSuffix(got, `"required":["Name","X","Y","Z","W"],"type":"object"}`) {
+			t.Fatalf("SchemaFor() = %s, want promoted fields required in declaration order", got)
+		}
+	}
+
+	if got := string(SchemaFor[struct{}]()); got != `{"properties":{},"type":"object"}` {
+		t.Errorf("SchemaFor[struct{}]() = %s", got)
+	}
+}
+
+func TestSchemaForPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a field JSON schema cannot describe")
+		}
+	}()
+	SchemaFor[struct {
+		C chan int `json:"c"`
+	}]()
+}
+
+func TestValidateInput(t *testing.T) {
+	tool := &Tool{Name: "fill", InputSchema: MustSchema(`{
+		"type": "object",
+		"properties": {
+			"fields": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {"name": {"type": "string"}, "value": {}},
+					"required": ["value"],
+					"additionalProperties": false
+				}
+			},
+			"mode": {"type": "string", "enum": ["fast", "slow"]},
+			"count": {"type": "integer"},
+			"scale": {"type": "number"}
+		},
+		"required": ["fields"]
+	}`)}
+
+	for _, input := range []string{
+		`{"fields": []}`,
+		`{"fields": [{"name": "a", "value": true}, {"value": ["x"]}], "mode": "slow", "count": 3, "scale": 2, "extra": 1}`,
+		`{"fields": [{"name": null, "value": 1}], "mode": null, "count": null}`,
+	} {
+		if err := tool.ValidateInput(json.RawMessage(input)); err != nil {
+			t.Errorf("ValidateInput(%s) = %v, want nil", input, err)
+		}
+	}
+
+	err := tool.ValidateInput(json.RawMessage(`{"fields": [{"name": 1, "other": 2}], "mode": "medium", "count": 1.5, "scale": "2"}`))
+	var inputErr *InputError
+	if !errors.As(err, &inputErr) {
+		t.Fatalf("ValidateInput() = %v, want an *InputError", err)
+	}
+	want := []InputProblem{
+		{Path: "count", Message: "expected integer, got number"},
+		{Path: "fields[0].value", Message: "required but missing"},
+		{Path: "fields[0].name", Message: "expected string, got integer"},
+		{Path: "fields[0].other", Message: "unknown property"},
+		{Path: "mode", Message: `got "medium", want one of "fast", "slow"`},
+		{Path: "scale", Message: "expected number, got string"},
+	}
+	if !reflect.DeepEqual(inputErr.Problems, want) {
+		t.Errorf("Problems = %+v, want %+v", inputErr.Problems, want)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "invalid input for fill:\n- count: expected integer, got number\n") {
+		t.Errorf("Error() = %q", msg)
+	}
+
+	if err := tool.ValidateInput(json.RawMessage(`{"fields": null}`)); !errors.As(err, &inputErr) || inputErr.Problems[0].Message != "expected array, got null" {
+		t.Errorf("ValidateInput(required null) = %v, want an *InputError", err)
+	}
+	if err := tool.ValidateInput(json.RawMessage(`{"fields": `)); !errors.As(err, &inputErr) {
+		t.Errorf("ValidateInput(truncated) = %v, want an *InputError", err)
+	}
+	if err := (&Tool{Name: "none", InputSchema: EmptySchema()}).ValidateInput(nil); err != nil {
+		t.Errorf("ValidateInput(nil) = %v, want nil for a tool without parameters", err)
+	}
+	if err := (&Tool{Name: "lenient", InputSchema: tool.InputSchema, LenientInput: true}).ValidateInput(json.RawMessage(`{}`)); err != nil {
+		t.Errorf("ValidateInput() = %v, want nil for a lenient tool", err)
+	}
+}
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"cmp"
+	"context"
+)
+
+// StreamDelta is an increment of a response, reported as the model generates it.
+// Applying a response's deltas in order rebuilds its Content.
+type StreamDelta struct {
+	// Reset means a new response has begun (possibly a retry), so earlier deltas should be discarded
+	Reset bool `json:"reset,omitempty"`
+	// Index is the position in Response.Content of the content the delta extends
+	Index int         `json:"index"`
+	Type  ContentType `json:"type"`
+	// ID and ToolName are set on the first delta of a tool use
+	ID       string `json:"id,omitempty"`
+	ToolName string `json:"tool_name,omitempty"`
+	// Text is text or thinking to append
+	Text string `json:"text,omitempty"`
+	// ToolInput is a fragment of a tool use's JSON input to append
+	ToolInput string `json:"tool_input,omitempty"`
+}
+
+// StreamFunc receives the deltas of responses in order. It is called on the goroutine making
+// the request, so it should return quickly.
+type StreamFunc func(StreamDelta)
+
+type streamFuncKey struct{}
+
+// WithStreamFunc returns a context in which services that support streaming report the deltas
+// of each response to fn as they arrive. Do still returns the complete response.
+func WithStreamFunc(ctx context.Context, fn StreamFunc) context.Context {
+	return context.WithValue(ctx, streamFuncKey{}, fn)
+}
+
+// StreamFuncFromContext returns the StreamFunc of ctx, or a no-op if there is none.
+func StreamFuncFromContext(ctx context.Context) StreamFunc {
+	if fn, ok := ctx.Value(streamFuncKey{}).(StreamFunc); ok && fn != nil {
+		return fn
+	}
+	return func(StreamDelta) {}
+}
+
+// MergeStreamDelta applies d to blocks, the deltas of a response so far merged into one per content
+// block, and returns the updated blocks. Unlike a partial Response, the merged blocks can be marshaled
+// while a tool use's input is still incomplete JSON.
+func MergeStreamDelta(blocks []StreamDelta, d StreamDelta) []StreamDelta {
+	if d.Reset {
+		return nil
+	}
+	for len(blocks) <= d.Index {
+		blocks = append(blocks, StreamDelta{Index: len(blocks)})
+	}
+	b := &blocks[d.Index]
+	b.Type = d.Type
+	b.ID = cmp.Or(d.ID, b.ID)
+	b.ToolName = cmp.Or(d.ToolName, b.ToolName)
+	b.Text += d.Text
+	b.ToolInput += d.ToolInput
+	return blocks
+}
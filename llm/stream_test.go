@@ -0,0 +1,42 @@
+package llm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestStreamFuncFromContext(t *testing.T) {
+	// Without a StreamFunc, deltas are discarded
+	StreamFuncFromContext(context.Background())(StreamDelta{Text: "ignored"})
+
+	var got []StreamDelta
+	ctx := WithStreamFunc(context.Background(), func(d StreamDelta) { got = append(got, d) })
+	StreamFuncFromContext(ctx)(StreamDelta{Text: "hi"})
+	if len(got) != 1 || got[0].Text != "hi" {
+		t.Errorf("got %+v, want one delta with text hi", got)
+	}
+}
+
+func TestMergeStreamDelta(t *testing.T) {
+	var blocks []StreamDelta
+	for _, d := range []StreamDelta{
+		{Reset: true},
+		{Index: 0, Type: ContentTypeText, Text: "stale"},
+		{Reset: true},
+		{Index: 0, Type: ContentTypeText, Text: "Let me "},
+		{Index: 0, Type: ContentTypeText, Text: "look."},
+		{Index: 1, Type: ContentTypeToolUse, ID: "t1", ToolName: "bash"},
+		{Index: 1, Type: ContentTypeToolUse, ToolInput: `{"command":`},
+		{Index: 1, Type: ContentTypeToolUse, ToolInput: `"ls"}`},
+	} {
+		blocks = MergeStreamDelta(blocks, d)
+	}
+	want := []StreamDelta{
+		{Index: 0, Type: ContentTypeText, Text: "Let me look."},
+		{Index: 1, Type: ContentTypeToolUse, ID: "t1", ToolName: "bash", ToolInput: `{"command":"ls"}`},
+	}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("MergeStreamDelta() = %+v, want %+v", blocks, want)
+	}
+}
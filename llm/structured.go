@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// structuredAttempts is how many times GenerateStruct asks for an answer before giving up
+const structuredAttempts = 3
+
+// answerToolName is the tool GenerateStruct has the model answer with
+const answerToolName = "answer"
+
+// GenerateStruct sends req to svc and returns the model's answer as a T, a struct describing the answer
+// (see SchemaFor). It is for internal callers that need a typed answer rather than prose.
+//
+// The model answers by calling a tool whose input schema is T's, which providers that can force a
+// tool call are made to do. Answers that don't match the schema, or that come as text from providers
+// that ignore the forced tool call, are validated, and the model is told what is wrong and asked again.
+func GenerateStruct[T any](ctx context.Context, svc Service, req *Request) (T, error) {
+	var answer T
+	tool := &Tool{
+		Name:        answerToolName,
+		Description: "Give your answer. Always answer by calling this tool.",
+		InputSchema: SchemaFor[T](),
+	}
+	r := *req
+	r.Messages = append([]Message(nil), req.Messages...)
+	r.Tools = []*Tool{tool}
+	r.ToolChoice = &ToolChoice{Type: ToolChoiceTypeTool, Name: tool.Name}
+
+	var problem error
+	for range structuredAttempts {
+		resp, err := svc.Do(ctx, &r)
+		if err != nil {
+			return answer, err
+		}
+		input, id := answerInput(resp)
+		if input == nil {
+			problem = errors.New("you gave no answer")
+		} else if problem = tool.ValidateInput(input); problem == nil {
+			if problem = json.Unmarshal(input, &answer); problem == nil {
+				return answer, nil
+			}
+		}
+
+		r.Messages = append(r.Messages, resp.ToMessage())
+		feedback := fmt.Sprintf("%v\nAnswer again by calling the %s tool.", problem, tool.Name)
+		if id == "" {
+			r.Messages = append(r.Messages, UserStringMessage(feedback))
+		} else {
+			r.Messages = append(r.Messages, Message{Role: MessageRoleUser, Content: []Content{{
+				Type:       ContentTypeToolResult,
+				ToolUseID:  id,
+				ToolError:  true,
+				ToolResult: []Content{StringContent(feedback)},
+			}}})
+		}
+	}
+	return answer, fmt.Errorf("no valid answer after %d attempts: %w", structuredAttempts, problem)
+}
+
+// answerInput returns the answer in resp: the input of its answer tool call and the call's ID,
+// or the JSON in its text if it has no such call, or nil if it has neither
+func answerInput(resp *Response) (json.RawMessage, string) {
+	var text strings.Builder
+	for _, c := range resp.Content {
+		switch {
+		case c.Type == ContentTypeToolUse && c.ToolName == answerToolName:
+			return c.ToolInput, c.ID
+		case c.Type == ContentTypeText:
+			text.WriteString(c.Text)
+		}
+	}
+	// Models answering in text often wrap JSON in a Markdown code block
+	s := strings.TrimSpace(text.String())
+	s = strings.TrimPrefix(s, "```json")
+	s = strings.TrimPrefix(s, "```")
+	s = strings.TrimSuffix(s, "```")
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, ""
+	}
+	return json.RawMessage(s), ""
+}
@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// scriptedService returns its responses in turn, recording the requests it gets
+type scriptedService struct {
+	responses []*Response
+	requests  []*Request
+}
+
+func (s *scriptedService) Do(ctx context.Context, req *Request) (*Response, error) {
+	r := *req
+	s.requests = append(s.requests, &r)
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+func (s *scriptedService) TokenContextWindow() int  { return 0 }
+func (s *scriptedService) ImageLimits() ImageLimits { return ImageLimits{} }
+
+func answerResponse(input string) *Response {
+	return &Response{
+		Role:       MessageRoleAssistant,
+		StopReason: StopReasonToolUse,
+		Content:    []Content{{ID: "call_" + input, Type: ContentTypeToolUse, ToolName: answerToolName, ToolInput: json.RawMessage(input)}},
+	}
+}
+
+type verdict struct {
+	Approved bool   `json:"approved"`
+	Package  string `json:"package,omitempty"`
+}
+
+func TestGenerateStruct(t *testing.T) {
+	svc := &scriptedService{responses: []*Response{
+		answerResponse(`{"package": "jq"}`),
+		answerResponse(`{"approved": true, "package": "jq"}`),
+	}}
+	got, err := GenerateStruct[verdict](context.Background(), svc, &Request{Messages: []Message{UserStringMessage("jq?")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (verdict{Approved: true, Package: "jq"}) {
+		t.Errorf("GenerateStruct() = %+v", got)
+	}
+
+	first := svc.requests[0]
+	if first.ToolChoice == nil || first.ToolChoice.Type != ToolChoiceTypeTool || first.ToolChoice.Name != answerToolName || len(first.Tools) != 1 {
+		t.Errorf("expected the request to force the answer tool, got %+v", first)
+	}
+	// The retry tells the model what was wrong with its answer
+	retry := svc.requests[1].Messages
+	if len(retry) != 3 || len(first.Messages) != 1 {
+		t.Fatalf("expected the retry to add the answer and feedback, got %d messages", len(retry))
+	}
+	feedback := retry[2].Content[0]
+	if feedback.Type != ContentTypeToolResult || feedback.ToolUseID != `call_{"package": "jq"}` || !strings.Contains(feedback.ToolResult[0].Text, "approved: required but missing") {
+		t.Errorf("unexpected feedback %+v", feedback)
+	}
+}
+
+func TestGenerateStructText(t *testing.T) {
+	svc := &scriptedService{responses: []*Response{
+		{Role: MessageRoleAssistant, Content: []Content{StringContent("```json\n{\"approved\": false}\n```")}},
+	}}
+	got, err := GenerateStruct[verdict](context.Background(), svc, &Request{})
+	if err != nil || got.Approved {
+		t.Errorf("GenerateStruct() = %+v, %v; want an answer from the text", got, err)
+	}
+}
+
+func TestGenerateStructGivesUp(t *testing.T) {
+	var responses []*Response
+	for range structuredAttempts {
+		responses = append(responses, &Response{Role: MessageRoleAssistant, Content: []Content{StringContent("I'm not sure")}})
+	}
+	_, err := GenerateStruct[verdict](context.Background(), &scriptedService{responses: responses}, &Request{})
+	if err == nil || !strings.Contains(err.Error(), "no valid answer after 3 attempts") {
+		t.Errorf("GenerateStruct() error = %v, want giving up", err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package telemetry records OpenTelemetry spans and metrics for LLM requests and tool calls,
+// so operators can see what the agent spent its time and tokens on.
+//
+// It uses the global tracer and meter providers, which do nothing until the program embedding
+// Shelley installs an OpenTelemetry SDK with otel.SetTracerProvider and otel.SetMeterProvider.
+// Spans are children of the span in the context they are given, so the spans of a turn's
+// requests and tool calls nest under whatever span the embedder started for it.
+//
+// Names and attributes follow the OpenTelemetry semantic conventions for generative AI.
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"shelley.exe.dev/llm"
+)
+
+const scope = "shelley.exe.dev/llm/telemetry"
+
+// metrics are the instruments the package records to
+type metrics struct {
+	requestDuration metric.Float64Histogram
+	tokens          metric.Int64Histogram
+	retries         metric.Int64Counter
+	toolDuration    metric.Float64Histogram
+}
+
+// instruments returns the metrics, created on first use from the global meter provider
+var instruments = sync.OnceValue(func() (i metrics) {
+	meter := otel.Meter(scope)
+	// The instruments are valid, if not working, even when creating them fails, so their errors go to the global handler
+	var err error
+	i.requestDuration, err = meter.Float64Histogram("gen_ai.client.operation.duration",
+		metric.WithUnit("s"), metric.WithDescription("Duration of LLM requests"))
+	handle(err)
+	i.tokens, err = meter.Int64Histogram("gen_ai.client.token.usage",
+		metric.WithUnit("{token}"), metric.WithDescription("Tokens used by LLM requests"))
+	handle(err)
+	i.retries, err = meter.Int64Counter("shelley.llm.retries",
+		metric.WithUnit("{retry}"), metric.WithDescription("LLM requests retried after transient failures"))
+	handle(err)
+	i.toolDuration, err = meter.Float64Histogram("shelley.tool.duration",
+		metric.WithUnit("s"), metric.WithDescription("Duration of tool calls"))
+	handle(err)
+	return i
+})
+
+func handle(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(scope)
+}
+
+// Service records a span and metrics for each request to Service: its latency, tokens, and error, if any.
+type Service struct {
+	Service  llm.Service
+	Provider string // recorded as gen_ai.system, e.g. "anthropic"
+	Model    string // recorded as gen_ai.request.model
+}
+
+var (
+	_ llm.Service           = (*Service)(nil)
+	_ llm.SimplifiedPatcher = (*Service)(nil)
+)
+
+// Do sends ir to the service within a span.
+func (s *Service) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("gen_ai.operation.name", "chat"),
+		attribute.String("gen_ai.system", s.Provider),
+		attribute.String("gen_ai.request.model", s.Model),
+	}
+	ctx, span := tracer().Start(ctx, "chat "+s.Model, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	start := time.Now()
+	resp, err := s.Service.Do(ctx, ir)
+	if err != nil {
+		attrs = append(attrs, errorType(err))
+		span.SetAttributes(errorType(err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	m := instruments()
+	m.requestDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	if err != nil {
+		return resp, err
+	}
+
+	input := resp.Usage.InputTokens + resp.Usage.CacheCreationInputTokens + resp.Usage.CacheReadInputTokens
+	span.SetAttributes(
+		attribute.String("gen_ai.response.model", resp.Model),
+		attribute.String("gen_ai.response.id", resp.ID),
+		attribute.StringSlice("gen_ai.response.finish_reasons", []string{resp.StopReason.String()}),
+		attribute.Int64("gen_ai.usage.input_tokens", int64(input)),
+		attribute.Int64("gen_ai.usage.output_tokens", int64(resp.Usage.OutputTokens)),
+		attribute.Int64("gen_ai.usage.cache_read_input_tokens", int64(resp.Usage.CacheReadInputTokens)),
+	)
+	m.tokens.Record(ctx, int64(input), metric.WithAttributes(append(attrs, attribute.String("gen_ai.token.type", "input"))...))
+	m.tokens.Record(ctx, int64(resp.Usage.OutputTokens), metric.WithAttributes(append(attrs, attribute.String("gen_ai.token.type", "output"))...))
+	return resp, nil
+}
+
+// TokenContextWindow returns the context window of the service.
+func (s *Service) TokenContextWindow() int {
+	return s.Service.TokenContextWindow()
+}
+
+// ImageLimits returns the image limits of the service.
+func (s *Service) ImageLimits() llm.ImageLimits {
+	return s.Service.ImageLimits()
+}
+
+// UseSimplifiedPatch reports whether the service uses the simplified patch input schema.
+func (s *Service) UseSimplifiedPatch() bool {
+	return llm.UseSimplifiedPatch(s.Service)
+}
+
+// AcceptsDocuments reports whether the service sends documents to the model.
+func (s *Service) AcceptsDocuments() bool {
+	return llm.AcceptsDocuments(s.Service)
+}
+
+// RecordRetry records that an LLM request is being retried after the given attempt failed with err,
+// as an event on the span in ctx and in the retries metric.
+func RecordRetry(ctx context.Context, attempt int, delay time.Duration, err error) {
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+		attribute.Int("attempt", attempt),
+		attribute.Float64("delay_seconds", delay.Seconds()),
+		attribute.String("error", err.Error()),
+	))
+	instruments().retries.Add(ctx, 1, metric.WithAttributes(errorType(err)))
+}
+
+// ToolMiddleware records a span and the duration of each call of a tool. It is an llm.ToolMiddleware.
+func ToolMiddleware(tool *llm.Tool, next llm.ToolRunFunc) llm.ToolRunFunc {
+	return func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+		attrs := []attribute.KeyValue{
+			attribute.String("gen_ai.operation.name", "execute_tool"),
+			attribute.String("gen_ai.tool.name", tool.Name),
+		}
+		ctx, span := tracer().Start(ctx, "execute_tool "+tool.Name, trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		start := time.Now()
+		out := next(ctx, input)
+		if out.Error != nil {
+			attrs = append(attrs, errorType(out.Error))
+			span.SetAttributes(errorType(out.Error))
+			span.RecordError(out.Error)
+			span.SetStatus(codes.Error, out.Error.Error())
+		}
+		instruments().toolDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+		return out
+	}
+}
+
+// errorType returns the error.type attribute of err: the HTTP status of an API error,
+// the context error of a canceled request, or the type of other errors
+func errorType(err error) attribute.KeyValue {
+	var se *llm.StatusError
+	switch {
+	case errors.As(err, &se):
+		return attribute.String("error.type", fmt.Sprint(se.StatusCode))
+	case errors.Is(err, context.Canceled):
+		return attribute.String("error.type", "canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return attribute.String("error.type", "timeout")
+	default:
+		return attribute.String("error.type", fmt.Sprintf("%T", err))
+	}
+}
@@ -0,0 +1,154 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"shelley.exe.dev/llm"
+)
+
+// The global providers can be set only once, so all tests share these
+var (
+	spans  = tracetest.NewSpanRecorder()
+	reader = sdkmetric.NewManualReader()
+)
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	os.Exit(m.Run())
+}
+
+type fakeService struct {
+	resp *llm.Response
+	err  error
+}
+
+func (f *fakeService) Do(ctx context.Context, ir *llm.Request) (*llm.Response, error) {
+	return f.resp, f.err
+}
+func (f *fakeService) TokenContextWindow() int      { return 1000 }
+func (f *fakeService) ImageLimits() llm.ImageLimits { return llm.ImageLimits{} }
+
+// collect returns the metrics recorded so far, by name
+func collect(t *testing.T) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			byName[m.Name] = m.Data
+		}
+	}
+	return byName
+}
+
+func attr(attrs []attribute.KeyValue, key string) attribute.Value {
+	for _, a := range attrs {
+		if string(a.Key) == key {
+			return a.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestService(t *testing.T) {
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "turn")
+	ok := &Service{Provider: "anthropic", Model: "claude", Service: &fakeService{resp: &llm.Response{
+		Model:      "claude-1",
+		StopReason: llm.StopReasonEndTurn,
+		Usage:      llm.Usage{InputTokens: 10, CacheReadInputTokens: 90, OutputTokens: 5},
+	}}}
+	if _, err := ok.Do(ctx, &llm.Request{}); err != nil {
+		t.Fatal(err)
+	}
+	failing := &Service{Provider: "anthropic", Model: "claude", Service: &fakeService{err: &llm.StatusError{StatusCode: 529, Err: errors.New("overloaded")}}}
+	if _, err := failing.Do(ctx, &llm.Request{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	RecordRetry(ctx, 1, 0, errors.New("overloaded"))
+	parent.End()
+
+	ended := spans.Ended()
+	var chats []sdktrace.ReadOnlySpan
+	for _, s := range ended {
+		if s.Name() == "chat claude" {
+			chats = append(chats, s)
+		}
+	}
+	if len(chats) != 2 {
+		t.Fatalf("expected 2 chat spans, got %d", len(chats))
+	}
+	for _, s := range chats {
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("%s: expected the span in ctx as parent", s.Name())
+		}
+	}
+	if got := attr(chats[0].Attributes(), "gen_ai.usage.input_tokens").AsInt64(); got != 100 {
+		t.Errorf("input tokens = %d, want 100 including cached tokens", got)
+	}
+	if got := attr(chats[0].Attributes(), "gen_ai.response.finish_reasons").AsStringSlice(); len(got) != 1 || got[0] != "StopReasonEndTurn" {
+		t.Errorf("finish reasons = %v", got)
+	}
+	if chats[1].Status().Code != codes.Error || attr(chats[1].Attributes(), "error.type").AsString() != "529" {
+		t.Errorf("failed request: status %v, error.type %v; want an error with type 529", chats[1].Status(), attr(chats[1].Attributes(), "error.type"))
+	}
+
+	m := collect(t)
+	if d, ok := m["gen_ai.client.operation.duration"].(metricdata.Histogram[float64]); !ok || len(d.DataPoints) != 2 {
+		t.Errorf("expected durations of a successful and a failed request, got %+v", m["gen_ai.client.operation.duration"])
+	}
+	tokens := map[string]int64{}
+	if d, ok := m["gen_ai.client.token.usage"].(metricdata.Histogram[int64]); ok {
+		for _, dp := range d.DataPoints {
+			v, _ := dp.Attributes.Value("gen_ai.token.type")
+			tokens[v.AsString()] += dp.Sum
+		}
+	}
+	if tokens["input"] != 100 || tokens["output"] != 5 {
+		t.Errorf("token usage = %v, want 100 input and 5 output", tokens)
+	}
+	if d, ok := m["shelley.llm.retries"].(metricdata.Sum[int64]); !ok || len(d.DataPoints) != 1 || d.DataPoints[0].Value != 1 {
+		t.Errorf("expected one retry, got %+v", m["shelley.llm.retries"])
+	}
+}
+
+func TestToolMiddleware(t *testing.T) {
+	tools := llm.WithMiddleware([]*llm.Tool{{
+		Name: "fail",
+		Run: func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+			return llm.ErrorfToolOut("no")
+		},
+	}}, ToolMiddleware)
+	tools[0].Run(context.Background(), json.RawMessage(`{}`))
+
+	var found bool
+	for _, s := range spans.Ended() {
+		if s.Name() == "execute_tool fail" {
+			found = true
+			if s.Status().Code != codes.Error || attr(s.Attributes(), "gen_ai.tool.name").AsString() != "fail" {
+				t.Errorf("tool span: status %v, attributes %v", s.Status(), s.Attributes())
+			}
+		}
+	}
+	if !found {
+		t.Error("expected a span for the tool call")
+	}
+	if d, ok := collect(t)["shelley.tool.duration"].(metricdata.Histogram[float64]); !ok || len(d.DataPoints) != 1 {
+		t.Errorf("expected a tool duration, got %+v", d)
+	}
+}
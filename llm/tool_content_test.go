@@ -12,8 +12,7 @@ func TestToolResultArray(t *testing.T) {
 	}
 
 	imageContent := Content{
-		Type:      ContentTypeText, // In the future, this could be ContentTypeImage
-		Text:      "",
+		Type:      ContentTypeImage,
 		MediaType: "image/jpeg",
 		Data:      "/9j/4AAQSkZJRg...", // Base64 encoded image sample
 	}
@@ -0,0 +1,136 @@
+package loop
+
+import (
+	"fmt"
+	"strings"
+
+	"shelley.exe.dev/llm"
+)
+
+// Compaction keeps requests within the model's context window by eliding old tool results,
+// which are most of a long conversation: screenshots, page HTML, command output.
+// It starts when a request would fill more than compactAbove of the window, and elides
+// the results of the oldest messages until the request fills no more than compactTo of it.
+// Messages stay elided for the rest of the loop, so requests keep a stable prefix for prompt caching
+// until the next compaction. The history itself is left whole, for the UI and the database.
+const (
+	compactAbove = 0.8
+	compactTo    = 0.5
+
+	// compactKeepRecent is how many of the latest messages are never elided
+	compactKeepRecent = 6
+	// elideTextAbove is the length in bytes above which an old text tool result is elided
+	elideTextAbove = 2000
+	// elidedTextKeep is how many bytes of an elided text tool result are kept
+	elidedTextKeep = 500
+	// imageTokens estimates the tokens of an image, which providers scale to about a megapixel
+	imageTokens = 1600
+	// documentBytesPerToken estimates the base64 bytes of a document per token it costs;
+	// PDF pages cost their text plus an image of the page
+	documentBytesPerToken = 32
+)
+
+// compact elides old tool results in req if it would fill too much of a context window of window tokens.
+// req's messages must be the history's, one for one, as compactedThrough counts messages of the history;
+// insertMissingToolResults, which adds and removes messages, runs after it.
+func (l *Loop) compact(req *llm.Request, window int) {
+	if window <= 0 {
+		return
+	}
+	for i := range min(l.compactedThrough, len(req.Messages)) {
+		elideToolResults(&req.Messages[i])
+	}
+	tokens := estimateRequestTokens(req)
+	if float64(tokens) <= compactAbove*float64(window) {
+		return
+	}
+	before := tokens
+	for l.compactedThrough < len(req.Messages)-compactKeepRecent && float64(tokens) > compactTo*float64(window) {
+		tokens -= elideToolResults(&req.Messages[l.compactedThrough])
+		l.compactedThrough++
+	}
+	l.logger.Info("compacted conversation context", "estimated_tokens_before", before, "estimated_tokens_after", tokens,
+		"context_window", window, "compacted_messages", l.compactedThrough)
+}
+
+// elideToolResults replaces the large tool results in msg with short notes, returning the tokens saved.
+// It copies what it changes, as msg shares its content with the loop's history.
+func elideToolResults(msg *llm.Message) int {
+	saved := 0
+	var content []llm.Content
+	for i, c := range msg.Content {
+		if c.Type != llm.ContentTypeToolResult {
+			continue
+		}
+		var results []llm.Content
+		for j, r := range c.ToolResult {
+			elided, ok := elide(r)
+			if !ok {
+				continue
+			}
+			if results == nil {
+				results = append([]llm.Content(nil), c.ToolResult...)
+			}
+			saved += estimateContentTokens(r) - estimateContentTokens(elided)
+			results[j] = elided
+		}
+		if results == nil {
+			continue
+		}
+		if content == nil {
+			content = append([]llm.Content(nil), msg.Content...)
+		}
+		content[i].ToolResult = results
+	}
+	if content != nil {
+		msg.Content = content
+	}
+	return saved
+}
+
+// elide returns a short note in place of the tool result part c, and whether c is large enough to elide
+func elide(c llm.Content) (llm.Content, bool) {
+	switch {
+	case c.Type == llm.ContentTypeDocument:
+		return llm.StringContent("[document elided to save context; read it again if you need it]"), true
+	case c.Type == llm.ContentTypeImage:
+		return llm.StringContent("[image elided to save context; take it again if you need it]"), true
+	case len(c.Text) > elideTextAbove:
+		kept := strings.ToValidUTF8(c.Text[:elidedTextKeep], "")
+		return llm.StringContent(fmt.Sprintf("%s\n[%d more bytes elided to save context; run the tool again if you need them]",
+			kept, len(c.Text)-elidedTextKeep)), true
+	default:
+		return c, false
+	}
+}
+
+// estimateRequestTokens estimates the tokens req fills in the context window, at about four bytes a token
+func estimateRequestTokens(req *llm.Request) int {
+	n := 0
+	for _, s := range req.System {
+		n += len(s.Text) / 4
+	}
+	for _, t := range req.Tools {
+		n += (len(t.Name) + len(t.Description) + len(t.InputSchema)) / 4
+	}
+	for _, m := range req.Messages {
+		for _, c := range m.Content {
+			n += estimateContentTokens(c)
+		}
+	}
+	return n
+}
+
+func estimateContentTokens(c llm.Content) int {
+	if c.Type == llm.ContentTypeDocument {
+		return len(c.Data) / documentBytesPerToken
+	}
+	if c.Type == llm.ContentTypeImage {
+		return imageTokens
+	}
+	n := (len(c.Text) + len(c.Thinking) + len(c.ToolInput)) / 4
+	for _, r := range c.ToolResult {
+		n += estimateContentTokens(r)
+	}
+	return n
+}
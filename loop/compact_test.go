@@ -0,0 +1,113 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"shelley.exe.dev/llm"
+)
+
+// compactTestHistory returns a conversation of n tool calls, each with a result of about 1000 tokens
+func compactTestHistory(n int) []llm.Message {
+	var history []llm.Message
+	for i := range n {
+		id := fmt.Sprintf("call_%d", i)
+		history = append(history,
+			llm.Message{Role: llm.MessageRoleAssistant, Content: []llm.Content{{ID: id, Type: llm.ContentTypeToolUse, ToolName: "bash", ToolInput: json.RawMessage(`{}`)}}},
+			llm.Message{Role: llm.MessageRoleUser, Content: []llm.Content{{Type: llm.ContentTypeToolResult, ToolUseID: id, ToolResult: []llm.Content{
+				llm.StringContent(strings.Repeat("x", 4000)),
+			}}}},
+		)
+	}
+	// The oldest result also has a screenshot
+	history[1].Content[0].ToolResult = append(history[1].Content[0].ToolResult, llm.Content{Type: llm.ContentTypeImage, MediaType: "image/png", Data: "iVBOR"})
+	return history
+}
+
+func isElided(msg llm.Message) bool {
+	return strings.Contains(msg.Content[0].ToolResult[0].Text, "elided to save context")
+}
+
+func TestCompact(t *testing.T) {
+	history := compactTestHistory(6)
+	l := &Loop{logger: slog.Default()}
+
+	// A request that fits comfortably is left alone
+	req := &llm.Request{Messages: append([]llm.Message(nil), history...)}
+	l.compact(req, 100000)
+	if l.compactedThrough != 0 || isElided(req.Messages[1]) {
+		t.Fatal("expected no compaction for a request well within the context window")
+	}
+
+	req = &llm.Request{Messages: append([]llm.Message(nil), history...)}
+	before := estimateRequestTokens(req)
+	l.compact(req, 7000)
+	if after := estimateRequestTokens(req); after > 3500 || after >= before {
+		t.Errorf("estimated tokens went from %d to %d, want at most 3500", before, after)
+	}
+	if !isElided(req.Messages[1]) || req.Messages[1].Content[0].ToolResult[1].MediaType != "" {
+		t.Errorf("expected the oldest tool result and its screenshot to be elided, got %+v", req.Messages[1].Content[0].ToolResult)
+	}
+	for _, msg := range req.Messages[len(req.Messages)-compactKeepRecent:] {
+		if msg.Role == llm.MessageRoleUser && isElided(msg) {
+			t.Error("expected the most recent messages to be left alone")
+		}
+	}
+	for _, msg := range history {
+		if msg.Role == llm.MessageRoleUser && isElided(msg) {
+			t.Fatal("expected the history to be left whole")
+		}
+	}
+
+	// Later requests elide the same messages, even when they would fit, so their prefix stays the same
+	compacted := l.compactedThrough
+	req = &llm.Request{Messages: append([]llm.Message(nil), history...)}
+	l.compact(req, 100000)
+	if l.compactedThrough != compacted || !isElided(req.Messages[1]) {
+		t.Errorf("expected the first %d messages to stay elided", compacted)
+	}
+}
+
+// TestCompactBeforeRepair tests that compaction counts messages of the history, not of the request,
+// from which insertMissingToolResults drops a message here, holding only the result of a call it does not follow
+func TestCompactBeforeRepair(t *testing.T) {
+	orphan := llm.Message{Role: llm.MessageRoleUser, Content: []llm.Content{{Type: llm.ContentTypeToolResult, ToolUseID: "gone", ToolResult: []llm.Content{llm.StringContent("done")}}}}
+	history := append([]llm.Message{orphan}, compactTestHistory(6)...)
+	service := NewPredictableService()
+	service.tokenContextWindow = 7000
+	l := NewLoop(Config{
+		LLM:     service,
+		History: history,
+		RecordMessage: func(ctx context.Context, message llm.Message, usage llm.Usage) error {
+			return nil
+		},
+	})
+	l.QueueUserMessage(llm.UserStringMessage("echo: hi"))
+	if err := l.ProcessOneTurn(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if l.compactedThrough == 0 {
+		t.Fatal("expected the request to be compacted")
+	}
+
+	// Each result in the request is elided if and only if its message in the history is one compacted
+	index := make(map[string]int) // history index by tool use ID of its result
+	for i, msg := range history {
+		if msg.Role == llm.MessageRoleUser {
+			index[msg.Content[0].ToolUseID] = i
+		}
+	}
+	for _, msg := range service.GetLastRequest().Messages {
+		if msg.Role != llm.MessageRoleUser || msg.Content[0].Type != llm.ContentTypeToolResult {
+			continue
+		}
+		i := index[msg.Content[0].ToolUseID]
+		if want := i < l.compactedThrough; isElided(msg) != want {
+			t.Errorf("history message %d elided = %v, want %v (compacted through %d)", i, isElided(msg), want, l.compactedThrough)
+		}
+	}
+}
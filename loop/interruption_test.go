@@ -343,8 +343,8 @@ func (s *customPredictableService) TokenContextWindow() int {
 	return 100000
 }
 
-func (s *customPredictableService) MaxImageDimension() int {
-	return 8000
+func (s *customPredictableService) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{MaxDimension: 8000}
 }
 
 // TestNoInterruptionNormalFlow verifies that normal tool chains work correctly
@@ -3,9 +3,7 @@ package loop
 import (
 	"context"
 	"fmt"
-	"io"
 	"log/slog"
-	"strings"
 	"sync"
 	"time"
 
@@ -35,6 +33,16 @@ type Config struct {
 	// If set, this is called at end of turn to check for git state changes.
 	// If nil, Config.WorkingDir is used as a static value.
 	GetWorkingDir func() string
+	// GetTools returns the tools to offer the LLM. If set, it is called before each request in place of
+	// using Config.Tools, so tools may be added or removed mid-conversation.
+	GetTools func() []*llm.Tool
+	// OnStreamDelta, if set, receives each LLM response's deltas as they are generated.
+	OnStreamDelta llm.StreamFunc
+	// BudgetUSD, if positive, is the most the conversation may cost.
+	// Once its cost reaches the budget, the loop stops making LLM requests.
+	BudgetUSD float64
+	// SpentUSD is what the conversation cost before this loop, which counts against BudgetUSD.
+	SpentUSD float64
 }
 
 // Loop manages a conversation turn with an LLM including tool execution and message recording.
@@ -42,6 +50,7 @@ type Config struct {
 type Loop struct {
 	llm              llm.Service
 	tools            []*llm.Tool
+	getTools         func() []*llm.Tool
 	recordMessage    MessageRecordFunc
 	history          []llm.Message
 	messageQueue     []llm.Message
@@ -53,6 +62,11 @@ type Loop struct {
 	onGitStateChange GitStateChangeFunc
 	getWorkingDir    func() string
 	lastGitState     *gitstate.GitState
+	onStreamDelta    llm.StreamFunc
+	budgetUSD        float64
+	spentUSD         float64
+	warnedUnpriced   bool // whether the loop warned that the model reports no cost, so budgetUSD is not enforced
+	compactedThrough int  // how many of the history's first messages have their tool results elided; see compact
 }
 
 // NewLoop creates a new Loop instance with the provided configuration
@@ -73,6 +87,7 @@ func NewLoop(config Config) *Loop {
 		llm:              config.LLM,
 		history:          config.History,
 		tools:            config.Tools,
+		getTools:         config.GetTools,
 		recordMessage:    config.RecordMessage,
 		messageQueue:     make([]llm.Message, 0),
 		logger:           logger,
@@ -81,6 +96,9 @@ func NewLoop(config Config) *Loop {
 		onGitStateChange: config.OnGitStateChange,
 		getWorkingDir:    config.GetWorkingDir,
 		lastGitState:     initialGitState,
+		onStreamDelta:    config.OnStreamDelta,
+		budgetUSD:        config.BudgetUSD,
+		spentUSD:         config.SpentUSD,
 	}
 }
 
@@ -124,7 +142,7 @@ func (l *Loop) Go(ctx context.Context) error {
 		return fmt.Errorf("no LLM service configured")
 	}
 
-	l.logger.Info("starting conversation loop", "tools", len(l.tools))
+	l.logger.Info("starting conversation loop", "tools", len(l.currentTools()))
 
 	for {
 		select {
@@ -193,11 +211,16 @@ func (l *Loop) ProcessOneTurn(ctx context.Context) error {
 func (l *Loop) processLLMRequest(ctx context.Context) error {
 	l.mu.Lock()
 	messages := append([]llm.Message(nil), l.history...)
-	tools := l.tools
+	tools := l.currentTools()
 	system := l.system
 	llmService := l.llm
+	spent := l.spentUSD + l.totalUsage.CostUSD
 	l.mu.Unlock()
 
+	if l.budgetUSD > 0 && spent >= l.budgetUSD {
+		return l.stopForBudget(ctx, spent)
+	}
+
 	// Enable prompt caching: set cache flag on last tool and last user message content
 	// See https://docs.anthropic.com/en/docs/build-with-claude/prompt-caching
 	if len(tools) > 0 {
@@ -229,6 +252,10 @@ func (l *Loop) processLLMRequest(ctx context.Context) error {
 		System:   system,
 	}
 
+	// Elide old tool results if the conversation is outgrowing the context window.
+	// This comes first, while the request's messages are still the history's, which compact counts in.
+	l.compact(req, llmService.TokenContextWindow())
+
 	// Insert missing tool results if the previous message had tool_use blocks
 	// without corresponding tool_result blocks. This can happen when a request
 	// is cancelled or fails after the LLM responds but before tools execute.
@@ -243,25 +270,11 @@ func (l *Loop) processLLMRequest(ctx context.Context) error {
 	// Add a timeout for the LLM request to prevent indefinite hangs
 	llmCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
-
-	// Retry LLM requests that fail with retryable errors (EOF, connection reset)
-	const maxRetries = 2
-	var resp *llm.Response
-	var err error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		resp, err = llmService.Do(llmCtx, req)
-		if err == nil {
-			break
-		}
-		if !isRetryableError(err) || attempt == maxRetries {
-			break
-		}
-		l.logger.Warn("LLM request failed with retryable error, retrying",
-			"error", err,
-			"attempt", attempt,
-			"max_retries", maxRetries)
-		time.Sleep(time.Second * time.Duration(attempt)) // Simple backoff
+	if l.onStreamDelta != nil {
+		llmCtx = llm.WithStreamFunc(llmCtx, l.onStreamDelta)
 	}
+
+	resp, err := llmService.Do(llmCtx, req)
 	if err != nil {
 		// Record the error as a message so it can be displayed in the UI
 		// EndOfTurn must be true so the agent working state is properly updated
@@ -287,7 +300,14 @@ func (l *Loop) processLLMRequest(ctx context.Context) error {
 	// Update total usage
 	l.mu.Lock()
 	l.totalUsage.Add(resp.Usage)
+	unpriced := l.budgetUSD > 0 && resp.Usage.CostUSD == 0 && resp.Usage.InputTokens+resp.Usage.OutputTokens > 0 && !l.warnedUnpriced
+	if unpriced {
+		l.warnedUnpriced = true
+	}
 	l.mu.Unlock()
+	if unpriced {
+		l.logger.Warn("the model reports no cost and has no known price, so the conversation budget is not enforced", "model", resp.Model, "budget_usd", l.budgetUSD)
+	}
 
 	// Handle max tokens truncation BEFORE adding to history - truncated responses
 	// should not be added to history normally (they get special handling)
@@ -323,6 +343,26 @@ func (l *Loop) processLLMRequest(ctx context.Context) error {
 	return nil
 }
 
+// stopForBudget records that the conversation has reached its budget, ending the turn without an LLM request.
+func (l *Loop) stopForBudget(ctx context.Context, spent float64) error {
+	l.logger.Warn("conversation reached its budget", "spent_usd", spent, "budget_usd", l.budgetUSD)
+	budgetMessage := llm.Message{
+		Role: llm.MessageRoleAssistant,
+		Content: []llm.Content{
+			{
+				Type: llm.ContentTypeText,
+				Text: fmt.Sprintf("Stopped: this conversation has cost $%.2f, reaching its budget of $%.2f.", spent, l.budgetUSD),
+			},
+		},
+		EndOfTurn: true,
+		ErrorType: llm.ErrorTypeBudget,
+	}
+	if err := l.recordMessage(ctx, budgetMessage, llm.Usage{}); err != nil {
+		l.logger.Error("failed to record budget message", "error", err)
+	}
+	return nil
+}
+
 // checkGitStateChange checks if the git state has changed and calls the callback if so.
 // This is called at the end of each turn.
 func (l *Loop) checkGitStateChange(ctx context.Context) {
@@ -408,69 +448,27 @@ func (l *Loop) handleMaxTokensTruncation(ctx context.Context, resp *llm.Response
 	return nil
 }
 
-// handleToolCalls processes tool calls from the LLM response
+// handleToolCalls processes tool calls from the LLM response.
+// The calls run concurrently, except that calls to tools with the same Serialize run one at a time, in order,
+// and calls to Exclusive tools run alone, like writers of a read/write lock the other calls hold for reading.
 func (l *Loop) handleToolCalls(ctx context.Context, content []llm.Content) error {
-	var toolResults []llm.Content
-
+	var toolUses []llm.Content
 	for _, c := range content {
-		if c.Type != llm.ContentTypeToolUse {
-			continue
-		}
-
-		l.logger.Debug("executing tool", "name", c.ToolName, "id", c.ID)
-
-		// Find the tool
-		var tool *llm.Tool
-		for _, t := range l.tools {
-			if t.Name == c.ToolName {
-				tool = t
-				break
-			}
-		}
-
-		if tool == nil {
-			l.logger.Error("tool not found", "name", c.ToolName)
-			toolResults = append(toolResults, llm.Content{
-				Type:      llm.ContentTypeToolResult,
-				ToolUseID: c.ID,
-				ToolError: true,
-				ToolResult: []llm.Content{
-					{Type: llm.ContentTypeText, Text: fmt.Sprintf("Tool '%s' not found", c.ToolName)},
-				},
-			})
-			continue
+		if c.Type == llm.ContentTypeToolUse {
+			toolUses = append(toolUses, c)
 		}
+	}
 
-		// Execute the tool with working directory set in context
-		toolCtx := ctx
-		if l.workingDir != "" {
-			toolCtx = claudetool.WithWorkingDir(ctx, l.workingDir)
+	toolResults := make([]llm.Content, len(toolUses))
+	start := 0
+	for i, c := range toolUses {
+		if tool := l.findTool(c.ToolName); tool != nil && tool.Exclusive {
+			l.runToolsConcurrently(ctx, toolUses[start:i], toolResults[start:i])
+			toolResults[i] = l.runTool(ctx, c)
+			start = i + 1
 		}
-		startTime := time.Now()
-		result := tool.Run(toolCtx, c.ToolInput)
-		endTime := time.Now()
-
-		var toolResultContent []llm.Content
-		if result.Error != nil {
-			l.logger.Error("tool execution failed", "name", c.ToolName, "error", result.Error)
-			toolResultContent = []llm.Content{
-				{Type: llm.ContentTypeText, Text: result.Error.Error()},
-			}
-		} else {
-			toolResultContent = result.LLMContent
-			l.logger.Debug("tool executed successfully", "name", c.ToolName, "duration", endTime.Sub(startTime))
-		}
-
-		toolResults = append(toolResults, llm.Content{
-			Type:             llm.ContentTypeToolResult,
-			ToolUseID:        c.ID,
-			ToolError:        result.Error != nil,
-			ToolResult:       toolResultContent,
-			ToolUseStartTime: &startTime,
-			ToolUseEndTime:   &endTime,
-			Display:          result.Display,
-		})
 	}
+	l.runToolsConcurrently(ctx, toolUses[start:], toolResults[start:])
 
 	if len(toolResults) > 0 {
 		// Add tool results to history as a user message
@@ -504,6 +502,109 @@ func (l *Loop) handleToolCalls(ctx context.Context, content []llm.Content) error
 	return nil
 }
 
+// runToolsConcurrently runs the tool calls toolUses, setting results to their results.
+// Calls to tools with the same Serialize run one at a time, in order.
+func (l *Loop) runToolsConcurrently(ctx context.Context, toolUses, results []llm.Content) {
+	// Queue the calls: one queue per Serialize, and one for each other call
+	var queues [][]int
+	serialized := make(map[string]int)
+	for i, c := range toolUses {
+		tool := l.findTool(c.ToolName)
+		if tool == nil || tool.Serialize == "" {
+			queues = append(queues, []int{i})
+			continue
+		}
+		q, ok := serialized[tool.Serialize]
+		if !ok {
+			q = len(queues)
+			serialized[tool.Serialize] = q
+			queues = append(queues, nil)
+		}
+		queues[q] = append(queues[q], i)
+	}
+
+	var wg sync.WaitGroup
+	for _, queue := range queues {
+		wg.Go(func() {
+			for _, i := range queue {
+				results[i] = l.runTool(ctx, toolUses[i])
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// currentTools returns the tools to offer the LLM now
+func (l *Loop) currentTools() []*llm.Tool {
+	if l.getTools != nil {
+		return l.getTools()
+	}
+	return l.tools
+}
+
+// findTool returns the tool named name, or nil if there is none
+func (l *Loop) findTool(name string) *llm.Tool {
+	for _, t := range l.currentTools() {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// runTool runs the tool call c, returning its result
+func (l *Loop) runTool(ctx context.Context, c llm.Content) llm.Content {
+	l.logger.Debug("executing tool", "name", c.ToolName, "id", c.ID)
+
+	tool := l.findTool(c.ToolName)
+	if tool == nil {
+		l.logger.Error("tool not found", "name", c.ToolName)
+		return llm.Content{
+			Type:      llm.ContentTypeToolResult,
+			ToolUseID: c.ID,
+			ToolError: true,
+			ToolResult: []llm.Content{
+				{Type: llm.ContentTypeText, Text: fmt.Sprintf("Tool '%s' not found", c.ToolName)},
+			},
+		}
+	}
+
+	// Execute the tool with working directory set in context
+	toolCtx := ctx
+	if l.workingDir != "" {
+		toolCtx = claudetool.WithWorkingDir(ctx, l.workingDir)
+	}
+	startTime := time.Now()
+	var result llm.ToolOut
+	if err := tool.ValidateInput(c.ToolInput); err != nil {
+		result = llm.ErrorToolOut(err)
+	} else {
+		result = tool.Run(toolCtx, c.ToolInput)
+	}
+	endTime := time.Now()
+
+	var toolResultContent []llm.Content
+	if result.Error != nil {
+		l.logger.Error("tool execution failed", "name", c.ToolName, "error", result.Error)
+		toolResultContent = []llm.Content{
+			{Type: llm.ContentTypeText, Text: result.Error.Error()},
+		}
+	} else {
+		toolResultContent = result.LLMContent
+		l.logger.Debug("tool executed successfully", "name", c.ToolName, "duration", endTime.Sub(startTime))
+	}
+
+	return llm.Content{
+		Type:             llm.ContentTypeToolResult,
+		ToolUseID:        c.ID,
+		ToolError:        result.Error != nil,
+		ToolResult:       toolResultContent,
+		ToolUseStartTime: &startTime,
+		ToolUseEndTime:   &endTime,
+		Display:          result.Display,
+	}
+}
+
 // insertMissingToolResults fixes tool_result issues in the conversation history:
 //  1. Adds error results for tool_uses that were requested but not included in the next message.
 //     This can happen when a request is cancelled or fails after the LLM responds with tool_use
@@ -666,31 +767,3 @@ func (l *Loop) insertMissingToolResults(req *llm.Request) {
 		}
 	}
 }
-
-// isRetryableError checks if an error is transient and should be retried.
-// This includes EOF errors (connection closed unexpectedly) and similar network issues.
-func isRetryableError(err error) bool {
-	if err == nil {
-		return false
-	}
-	// Check for io.EOF and io.ErrUnexpectedEOF
-	if err == io.EOF || err == io.ErrUnexpectedEOF {
-		return true
-	}
-	// Check error message for common retryable patterns
-	errStr := err.Error()
-	retryablePatterns := []string{
-		"EOF",
-		"connection reset",
-		"connection refused",
-		"no such host",
-		"network is unreachable",
-		"i/o timeout",
-	}
-	for _, pattern := range retryablePatterns {
-		if strings.Contains(errStr, pattern) {
-			return true
-		}
-	}
-	return false
-}
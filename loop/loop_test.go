@@ -1,13 +1,16 @@
 package loop
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -16,6 +19,7 @@ import (
 	"shelley.exe.dev/claudetool"
 	"shelley.exe.dev/gitstate"
 	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/retry"
 )
 
 func TestNewLoop(t *testing.T) {
@@ -277,6 +281,25 @@ func TestLoopWithPredictableService(t *testing.T) {
 	}
 }
 
+func TestLoopStreamDeltas(t *testing.T) {
+	var blocks []llm.StreamDelta
+	loop := NewLoop(Config{
+		LLM:           NewPredictableService(),
+		RecordMessage: func(ctx context.Context, message llm.Message, usage llm.Usage) error { return nil },
+		OnStreamDelta: func(d llm.StreamDelta) { blocks = llm.MergeStreamDelta(blocks, d) },
+	})
+	loop.QueueUserMessage(llm.Message{
+		Role:    llm.MessageRoleUser,
+		Content: []llm.Content{{Type: llm.ContentTypeText, Text: "hello"}},
+	})
+	if err := loop.ProcessOneTurn(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 1 || blocks[0].Text != "Well, hi there!" {
+		t.Errorf("streamed %+v, want the text of the response", blocks)
+	}
+}
+
 func TestLoopWithTools(t *testing.T) {
 	var toolCalls []string
 
@@ -1175,11 +1198,11 @@ func TestPredictableServiceTokenContextWindow(t *testing.T) {
 	}
 }
 
-func TestPredictableServiceMaxImageDimension(t *testing.T) {
+func TestPredictableServiceImageLimits(t *testing.T) {
 	service := NewPredictableService()
-	dimension := service.MaxImageDimension()
-	if dimension != 2000 {
-		t.Errorf("expected MaxImageDimension to return 2000, got %d", dimension)
+	limits := service.ImageLimits()
+	if limits.MaxDimension != 2000 {
+		t.Errorf("expected ImageLimits to have MaxDimension 2000, got %d", limits.MaxDimension)
 	}
 }
 
@@ -1489,6 +1512,74 @@ func TestPredictableServiceToolSmorgasbord(t *testing.T) {
 	}
 }
 
+func TestBudget(t *testing.T) {
+	var recordedMessages []llm.Message
+	loop := NewLoop(Config{
+		LLM: NewPredictableService(), // each response costs $0.001
+		RecordMessage: func(ctx context.Context, message llm.Message, usage llm.Usage) error {
+			recordedMessages = append(recordedMessages, message)
+			return nil
+		},
+		BudgetUSD: 1,
+		SpentUSD:  0.9995,
+	})
+
+	for range 2 {
+		loop.QueueUserMessage(llm.UserStringMessage("hello"))
+		if err := loop.ProcessOneTurn(context.Background()); err != nil {
+			t.Fatalf("ProcessOneTurn failed: %v", err)
+		}
+	}
+
+	if len(recordedMessages) != 2 {
+		t.Fatalf("expected a response and then a budget message, got %d messages", len(recordedMessages))
+	}
+	if recordedMessages[0].ErrorType != llm.ErrorTypeNone {
+		t.Errorf("expected a response while under budget, got %+v", recordedMessages[0])
+	}
+	stop := recordedMessages[1]
+	if stop.ErrorType != llm.ErrorTypeBudget || !stop.EndOfTurn {
+		t.Errorf("expected a budget message ending the turn, got %+v", stop)
+	}
+	if want := "this conversation has cost $1.00, reaching its budget of $1.00"; !strings.Contains(stop.Content[0].Text, want) {
+		t.Errorf("expected budget message to contain %q, got %q", want, stop.Content[0].Text)
+	}
+}
+
+// unpricedService is a PredictableService whose responses report no cost
+type unpricedService struct {
+	*PredictableService
+}
+
+func (s unpricedService) Do(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	resp, err := s.PredictableService.Do(ctx, req)
+	if resp != nil {
+		resp.Usage.CostUSD = 0
+	}
+	return resp, err
+}
+
+func TestBudgetUnpricedModel(t *testing.T) {
+	var logs bytes.Buffer
+	loop := NewLoop(Config{
+		LLM:           unpricedService{NewPredictableService()},
+		RecordMessage: func(ctx context.Context, message llm.Message, usage llm.Usage) error { return nil },
+		Logger:        slog.New(slog.NewTextHandler(&logs, nil)),
+		BudgetUSD:     1,
+	})
+
+	for range 2 {
+		loop.QueueUserMessage(llm.UserStringMessage("hello"))
+		if err := loop.ProcessOneTurn(context.Background()); err != nil {
+			t.Fatalf("ProcessOneTurn failed: %v", err)
+		}
+	}
+
+	if got := strings.Count(logs.String(), "budget is not enforced"); got != 1 {
+		t.Errorf("warned %d times that the budget is not enforced, want once; logs:\n%s", got, logs.String())
+	}
+}
+
 func TestProcessLLMRequestError(t *testing.T) {
 	// Test error handling when LLM service returns an error
 	errorService := &errorLLMService{err: fmt.Errorf("test LLM error")}
@@ -1565,11 +1656,11 @@ func (e *errorLLMService) TokenContextWindow() int {
 	return 200000
 }
 
-func (e *errorLLMService) MaxImageDimension() int {
-	return 2000
+func (e *errorLLMService) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{MaxDimension: 2000}
 }
 
-// retryableLLMService fails with a retryable error a specified number of times, then succeeds
+// retryableLLMService fails with a transient error a specified number of times, then succeeds
 type retryableLLMService struct {
 	failuresRemaining int
 	callCount         int
@@ -1582,7 +1673,7 @@ func (r *retryableLLMService) Do(ctx context.Context, req *llm.Request) (*llm.Re
 	if r.failuresRemaining > 0 {
 		r.failuresRemaining--
 		r.mu.Unlock()
-		return nil, fmt.Errorf("connection error: EOF")
+		return nil, fmt.Errorf("connection error: %w", io.EOF)
 	}
 	r.mu.Unlock()
 	return &llm.Response{
@@ -1597,8 +1688,8 @@ func (r *retryableLLMService) TokenContextWindow() int {
 	return 200000
 }
 
-func (r *retryableLLMService) MaxImageDimension() int {
-	return 2000
+func (r *retryableLLMService) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{MaxDimension: 2000}
 }
 
 func (r *retryableLLMService) getCallCount() int {
@@ -1607,8 +1698,11 @@ func (r *retryableLLMService) getCallCount() int {
 	return r.callCount
 }
 
+// retryPolicy makes two attempts a millisecond apart, so tests don't wait out real backoff
+var retryPolicy = retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
 func TestLLMRequestRetryOnEOF(t *testing.T) {
-	// Test that LLM requests are retried on EOF errors
+	// Test that the loop's LLM requests are retried on EOF errors by a retry.Service
 	retryService := &retryableLLMService{failuresRemaining: 1}
 
 	var recordedMessages []llm.Message
@@ -1618,24 +1712,18 @@ func TestLLMRequestRetryOnEOF(t *testing.T) {
 	}
 
 	loop := NewLoop(Config{
-		LLM:           retryService,
+		LLM:           &retry.Service{Service: retryService, Policy: retryPolicy},
 		History:       []llm.Message{},
 		Tools:         []*llm.Tool{},
 		RecordMessage: recordFunc,
 	})
 
-	// Queue a user message
-	userMessage := llm.Message{
-		Role:    llm.MessageRoleUser,
-		Content: []llm.Content{{Type: llm.ContentTypeText, Text: "test message"}},
-	}
-	loop.QueueUserMessage(userMessage)
+	loop.QueueUserMessage(llm.UserStringMessage("test message"))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	err := loop.ProcessOneTurn(ctx)
-	if err != nil {
+	if err := loop.ProcessOneTurn(ctx); err != nil {
 		t.Fatalf("expected no error after retry, got: %v", err)
 	}
 
@@ -1644,19 +1732,18 @@ func TestLLMRequestRetryOnEOF(t *testing.T) {
 		t.Errorf("expected 2 LLM calls (retry), got %d", retryService.getCallCount())
 	}
 
-	// Check that success message was recorded
+	// Check that only the success message was recorded
 	if len(recordedMessages) != 1 {
 		t.Fatalf("expected 1 recorded message (success), got %d", len(recordedMessages))
 	}
-
 	if !strings.Contains(recordedMessages[0].Content[0].Text, "Success after retry") {
 		t.Errorf("expected success message, got: %s", recordedMessages[0].Content[0].Text)
 	}
 }
 
 func TestLLMRequestRetryExhausted(t *testing.T) {
-	// Test that after max retries, error is returned
-	retryService := &retryableLLMService{failuresRemaining: 10} // More than maxRetries
+	// Test that once a retry.Service runs out of attempts, the loop records the error
+	retryService := &retryableLLMService{failuresRemaining: 10}
 
 	var recordedMessages []llm.Message
 	recordFunc := func(ctx context.Context, message llm.Message, usage llm.Usage) error {
@@ -1665,68 +1752,34 @@ func TestLLMRequestRetryExhausted(t *testing.T) {
 	}
 
 	loop := NewLoop(Config{
-		LLM:           retryService,
+		LLM:           &retry.Service{Service: retryService, Policy: retryPolicy},
 		History:       []llm.Message{},
 		Tools:         []*llm.Tool{},
 		RecordMessage: recordFunc,
 	})
 
-	userMessage := llm.Message{
-		Role:    llm.MessageRoleUser,
-		Content: []llm.Content{{Type: llm.ContentTypeText, Text: "test message"}},
-	}
-	loop.QueueUserMessage(userMessage)
+	loop.QueueUserMessage(llm.UserStringMessage("test message"))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	err := loop.ProcessOneTurn(ctx)
-	if err == nil {
+	if err := loop.ProcessOneTurn(ctx); err == nil {
 		t.Fatal("expected error after exhausting retries")
 	}
 
-	// Should have been called maxRetries times (2)
-	if retryService.getCallCount() != 2 {
-		t.Errorf("expected 2 LLM calls (maxRetries), got %d", retryService.getCallCount())
+	if retryService.getCallCount() != retryPolicy.MaxAttempts {
+		t.Errorf("expected %d LLM calls (MaxAttempts), got %d", retryPolicy.MaxAttempts, retryService.getCallCount())
 	}
 
 	// Check error message was recorded
 	if len(recordedMessages) != 1 {
 		t.Fatalf("expected 1 recorded message (error), got %d", len(recordedMessages))
 	}
-
 	if !strings.Contains(recordedMessages[0].Content[0].Text, "LLM request failed") {
 		t.Errorf("expected error message, got: %s", recordedMessages[0].Content[0].Text)
 	}
 }
 
-func TestIsRetryableError(t *testing.T) {
-	tests := []struct {
-		name      string
-		err       error
-		retryable bool
-	}{
-		{"nil error", nil, false},
-		{"io.EOF", io.EOF, true},
-		{"io.ErrUnexpectedEOF", io.ErrUnexpectedEOF, true},
-		{"EOF error string", fmt.Errorf("EOF"), true},
-		{"wrapped EOF", fmt.Errorf("connection error: EOF"), true},
-		{"connection reset", fmt.Errorf("connection reset by peer"), true},
-		{"connection refused", fmt.Errorf("connection refused"), true},
-		{"timeout", fmt.Errorf("i/o timeout"), true},
-		{"api error", fmt.Errorf("rate limit exceeded"), false},
-		{"generic error", fmt.Errorf("something went wrong"), false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := isRetryableError(tt.err); got != tt.retryable {
-				t.Errorf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.retryable)
-			}
-		})
-	}
-}
-
 func TestCheckGitStateChange(t *testing.T) {
 	// Create a test repo
 	tmpDir := t.TempDir()
@@ -1869,6 +1922,290 @@ func TestHandleToolCallsWithMissingTool(t *testing.T) {
 	}
 }
 
+func TestGetTools(t *testing.T) {
+	service := NewPredictableService()
+	tools := []*llm.Tool{{Name: "first", InputSchema: llm.EmptySchema()}}
+	loop := NewLoop(Config{
+		LLM:           service,
+		History:       []llm.Message{llm.UserStringMessage("hello")},
+		GetTools:      func() []*llm.Tool { return tools },
+		RecordMessage: func(ctx context.Context, message llm.Message, usage llm.Usage) error { return nil },
+	})
+
+	toolNames := func() []string {
+		var names []string
+		for _, tool := range service.GetLastRequest().Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	if err := loop.processLLMRequest(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if names := toolNames(); !slices.Equal(names, []string{"first"}) {
+		t.Errorf("first request offered %v, want [first]", names)
+	}
+
+	tools = []*llm.Tool{{Name: "second", InputSchema: llm.EmptySchema()}}
+	if err := loop.processLLMRequest(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if names := toolNames(); !slices.Equal(names, []string{"second"}) {
+		t.Errorf("request after the tools changed offered %v, want [second]", names)
+	}
+	if loop.findTool("first") != nil || loop.findTool("second") == nil {
+		t.Error("findTool should find the current tools only")
+	}
+}
+
+func TestHandleToolCallsConcurrently(t *testing.T) {
+	var recordedMessages []llm.Message
+	recordFunc := func(ctx context.Context, message llm.Message, usage llm.Usage) error {
+		recordedMessages = append(recordedMessages, message)
+		return nil
+	}
+
+	// Each parallel tool waits for the other to start, so they only finish if they run concurrently
+	parallelTool := func(name string, started, otherStarted chan struct{}) *llm.Tool {
+		return &llm.Tool{
+			Name:        name,
+			InputSchema: llm.EmptySchema(),
+			Run: func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+				close(started)
+				select {
+				case <-otherStarted:
+					return llm.ToolOut{LLMContent: llm.TextContent(name)}
+				case <-ctx.Done():
+					return llm.ErrorToolOut(ctx.Err())
+				}
+			},
+		}
+	}
+	aStarted, bStarted := make(chan struct{}), make(chan struct{})
+	a, b := parallelTool("a", aStarted, bStarted), parallelTool("b", bStarted, aStarted)
+
+	// Serialized tools must not overlap, and run in the order they were called
+	var mu sync.Mutex
+	var order []string
+	running := false
+	serialTool := func(name string) *llm.Tool {
+		return &llm.Tool{
+			Name:        name,
+			InputSchema: llm.EmptySchema(),
+			Serialize:   "shared",
+			Run: func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+				mu.Lock()
+				overlapped := running
+				running = true
+				order = append(order, name)
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					running = false
+					mu.Unlock()
+				}()
+				if overlapped {
+					return llm.ErrorfToolOut("%s overlapped another serialized call", name)
+				}
+				return llm.ToolOut{LLMContent: llm.TextContent(name)}
+			},
+		}
+	}
+
+	loop := NewLoop(Config{
+		LLM:           NewPredictableService(),
+		History:       []llm.Message{},
+		Tools:         []*llm.Tool{a, b, serialTool("s1"), serialTool("s2")},
+		RecordMessage: recordFunc,
+	})
+
+	var content []llm.Content
+	for _, name := range []string{"s1", "a", "s2", "b", "s1"} {
+		content = append(content, llm.Content{ID: fmt.Sprintf("%s_%d", name, len(content)), Type: llm.ContentTypeToolUse, ToolName: name, ToolInput: json.RawMessage(`{}`)})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := loop.handleToolCalls(ctx, content); err != nil {
+		t.Fatalf("handleToolCalls failed: %v", err)
+	}
+
+	if len(recordedMessages) < 1 || len(recordedMessages[0].Content) != len(content) {
+		t.Fatalf("expected a message with %d tool results, got %v", len(content), recordedMessages)
+	}
+	for i, result := range recordedMessages[0].Content {
+		if result.ToolUseID != content[i].ID {
+			t.Errorf("result %d is for %s, want %s", i, result.ToolUseID, content[i].ID)
+		}
+		if result.ToolError {
+			t.Errorf("result %d: unexpected error %v", i, result.ToolResult)
+		}
+	}
+	if got := strings.Join(order, ","); got != "s1,s2,s1" {
+		t.Errorf("serialized calls ran in order %s, want s1,s2,s1", got)
+	}
+}
+
+func TestHandleToolCallsExclusive(t *testing.T) {
+	var recordedMessages []llm.Message
+	recordFunc := func(ctx context.Context, message llm.Message, usage llm.Usage) error {
+		recordedMessages = append(recordedMessages, message)
+		return nil
+	}
+
+	// The exclusive tool fails if a shared call is running, and shared calls record that they ran
+	var mu sync.Mutex
+	var order []string
+	running := 0
+	shared := &llm.Tool{
+		Name:        "shared",
+		InputSchema: llm.EmptySchema(),
+		Run: func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+			mu.Lock()
+			running++
+			order = append(order, "shared")
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return llm.ToolOut{LLMContent: llm.TextContent("shared")}
+		},
+	}
+	exclusive := &llm.Tool{
+		Name:        "exclusive",
+		InputSchema: llm.EmptySchema(),
+		Exclusive:   true,
+		Run: func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, "exclusive")
+			if running > 0 {
+				return llm.ErrorfToolOut("ran alongside %d shared calls", running)
+			}
+			return llm.ToolOut{LLMContent: llm.TextContent("exclusive")}
+		},
+	}
+
+	loop := NewLoop(Config{
+		LLM:           NewPredictableService(),
+		History:       []llm.Message{},
+		Tools:         []*llm.Tool{shared, exclusive},
+		RecordMessage: recordFunc,
+	})
+
+	var content []llm.Content
+	for _, name := range []string{"shared", "shared", "exclusive", "shared", "exclusive"} {
+		content = append(content, llm.Content{ID: fmt.Sprintf("%s_%d", name, len(content)), Type: llm.ContentTypeToolUse, ToolName: name, ToolInput: json.RawMessage(`{}`)})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := loop.handleToolCalls(ctx, content); err != nil {
+		t.Fatalf("handleToolCalls failed: %v", err)
+	}
+
+	if len(recordedMessages) < 1 || len(recordedMessages[0].Content) != len(content) {
+		t.Fatalf("expected a message with %d tool results, got %v", len(content), recordedMessages)
+	}
+	for i, result := range recordedMessages[0].Content {
+		if result.ToolError {
+			t.Errorf("result %d: unexpected error %v", i, result.ToolResult)
+		}
+	}
+	if got := strings.Join(order, ","); got != "shared,shared,exclusive,shared,exclusive" {
+		t.Errorf("calls ran in order %s, want shared,shared,exclusive,shared,exclusive", got)
+	}
+}
+
+func TestHandleToolCallsChangeDir(t *testing.T) {
+	var recordedMessages []llm.Message
+	recordFunc := func(ctx context.Context, message llm.Message, usage llm.Usage) error {
+		recordedMessages = append(recordedMessages, message)
+		return nil
+	}
+
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wd := claudetool.NewMutableWorkingDir(dir)
+	bashTool := &claudetool.BashTool{WorkingDir: wd}
+	changeDirTool := &claudetool.ChangeDirTool{WorkingDir: wd}
+
+	loop := NewLoop(Config{
+		LLM:           NewPredictableService(),
+		History:       []llm.Message{},
+		Tools:         []*llm.Tool{bashTool.Tool(), changeDirTool.Tool()},
+		RecordMessage: recordFunc,
+	})
+
+	content := []llm.Content{
+		{ID: "before", Type: llm.ContentTypeToolUse, ToolName: "bash", ToolInput: json.RawMessage(`{"command":"pwd"}`)},
+		{ID: "cd", Type: llm.ContentTypeToolUse, ToolName: "change_dir", ToolInput: json.RawMessage(fmt.Sprintf(`{"path":%q}`, sub))},
+		{ID: "after", Type: llm.ContentTypeToolUse, ToolName: "bash", ToolInput: json.RawMessage(`{"command":"pwd"}`)},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := loop.handleToolCalls(ctx, content); err != nil {
+		t.Fatalf("handleToolCalls failed: %v", err)
+	}
+
+	if len(recordedMessages) < 1 || len(recordedMessages[0].Content) != len(content) {
+		t.Fatalf("expected a message with %d tool results, got %v", len(content), recordedMessages)
+	}
+	results := recordedMessages[0].Content
+	for i, want := range map[int]string{0: dir + "\n", 2: sub + "\n"} {
+		if results[i].ToolError || len(results[i].ToolResult) == 0 || !strings.HasSuffix(results[i].ToolResult[0].Text, want) {
+			t.Errorf("bash call %s ran with %+v, want it in %s", content[i].ID, results[i].ToolResult, strings.TrimSpace(want))
+		}
+	}
+}
+
+func TestHandleToolCallsPatchThenBash(t *testing.T) {
+	var recordedMessages []llm.Message
+	recordFunc := func(ctx context.Context, message llm.Message, usage llm.Usage) error {
+		recordedMessages = append(recordedMessages, message)
+		return nil
+	}
+
+	dir := t.TempDir()
+	wd := claudetool.NewMutableWorkingDir(dir)
+	bashTool := &claudetool.BashTool{WorkingDir: wd}
+	patchTool := &claudetool.PatchTool{WorkingDir: wd}
+
+	loop := NewLoop(Config{
+		LLM:           NewPredictableService(),
+		History:       []llm.Message{},
+		Tools:         []*llm.Tool{bashTool.Tool(), patchTool.Tool()},
+		RecordMessage: recordFunc,
+	})
+
+	// The bash call must see the first patch and not the second, as if they ran in the order called
+	overwrite := func(text string) json.RawMessage {
+		return json.RawMessage(fmt.Sprintf(`{"path":%q,"patches":[{"operation":"overwrite","newText":%q}]}`, filepath.Join(dir, "version.txt"), text))
+	}
+	content := []llm.Content{
+		{ID: "patch1", Type: llm.ContentTypeToolUse, ToolName: "patch", ToolInput: overwrite("first\n")},
+		{ID: "cat", Type: llm.ContentTypeToolUse, ToolName: "bash", ToolInput: json.RawMessage(`{"command":"cat version.txt"}`)},
+		{ID: "patch2", Type: llm.ContentTypeToolUse, ToolName: "patch", ToolInput: overwrite("second\n")},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := loop.handleToolCalls(ctx, content); err != nil {
+		t.Fatalf("handleToolCalls failed: %v", err)
+	}
+
+	if len(recordedMessages) < 1 || len(recordedMessages[0].Content) != len(content) {
+		t.Fatalf("expected a message with %d tool results, got %v", len(content), recordedMessages)
+	}
+	cat := recordedMessages[0].Content[1]
+	if cat.ToolError || len(cat.ToolResult) == 0 || !strings.HasSuffix(cat.ToolResult[0].Text, "first\n") {
+		t.Errorf("bash call ran with %+v, want it to see the first patch only", cat.ToolResult)
+	}
+}
+
 func TestHandleToolCallsWithErrorTool(t *testing.T) {
 	var recordedMessages []llm.Message
 	recordFunc := func(ctx context.Context, message llm.Message, usage llm.Usage) error {
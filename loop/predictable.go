@@ -54,13 +54,32 @@ func (s *PredictableService) TokenContextWindow() int {
 	return s.tokenContextWindow
 }
 
-// MaxImageDimension returns the maximum allowed image dimension.
-func (s *PredictableService) MaxImageDimension() int {
-	return 2000
+// ImageLimits returns the image limits, like Anthropic's for conversations with many images.
+func (s *PredictableService) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{MaxDimension: 2000}
 }
 
-// Do processes a request and returns a predictable response based on the input text
+// Do processes a request and returns a predictable response based on the input text,
+// streaming each content block of it whole
 func (s *PredictableService) Do(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	resp, err := s.respond(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	onDelta := llm.StreamFuncFromContext(ctx)
+	onDelta(llm.StreamDelta{Reset: true})
+	for i, c := range resp.Content {
+		d := llm.StreamDelta{Index: i, Type: c.Type, ID: c.ID, ToolName: c.ToolName, Text: c.Text + c.Thinking}
+		if c.Type == llm.ContentTypeToolUse {
+			d.ToolInput = string(c.ToolInput)
+		}
+		onDelta(d)
+	}
+	return resp, nil
+}
+
+// respond returns the predictable response to req
+func (s *PredictableService) respond(ctx context.Context, req *llm.Request) (*llm.Response, error) {
 	// Store request for testing inspection
 	s.mu.Lock()
 	delay := s.responseDelay
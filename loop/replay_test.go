@@ -0,0 +1,88 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/ant"
+	"shelley.exe.dev/llm/replay"
+)
+
+// TestLoopReplay runs a turn in which Claude reads a file, replaying testdata/read_file.jsonl.
+// Re-record it with: ANTHROPIC_API_KEY=... go test ./loop -run TestLoopReplay -llmrecord=read_file
+func TestLoopReplay(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "greeting.txt"), []byte("Hello from the replay test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc, err := replay.Open("testdata/read_file.jsonl", &ant.Service{
+		APIKey: os.Getenv("ANTHROPIC_API_KEY"),
+		Model:  ant.Claude45Haiku,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The system prompt names the temporary directory, which differs on every run
+	svc.Normalize(dir, "/work")
+
+	type readFileInput struct {
+		Path string `json:"path" description:"Path of the file, relative to the working directory"`
+	}
+	readFile := &llm.Tool{
+		Name:        "read_file",
+		Description: "Reads a text file",
+		InputSchema: llm.SchemaFor[readFileInput](),
+		Run: func(ctx context.Context, input json.RawMessage) llm.ToolOut {
+			var in readFileInput
+			if err := json.Unmarshal(input, &in); err != nil {
+				return llm.ErrorToolOut(err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, in.Path))
+			if err != nil {
+				return llm.ErrorToolOut(err)
+			}
+			return llm.ToolOut{LLMContent: llm.TextContent(string(data))}
+		},
+	}
+
+	var recordedMessages []llm.Message
+	loop := NewLoop(Config{
+		LLM:        svc,
+		History:    []llm.Message{},
+		Tools:      []*llm.Tool{readFile},
+		System:     []llm.SystemContent{{Text: "You are a terse assistant. The working directory is " + dir + "."}},
+		WorkingDir: dir,
+		RecordMessage: func(ctx context.Context, message llm.Message, usage llm.Usage) error {
+			recordedMessages = append(recordedMessages, message)
+			return nil
+		},
+	})
+	loop.QueueUserMessage(llm.UserStringMessage("What does greeting.txt say? Reply with its contents only."))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := loop.ProcessOneTurn(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The assistant's tool call, the tool result, and the assistant's answer
+	if len(recordedMessages) != 3 {
+		t.Fatalf("expected 3 recorded messages, got %d: %+v", len(recordedMessages), recordedMessages)
+	}
+	if result := recordedMessages[1].Content[0]; result.Type != llm.ContentTypeToolResult || result.ToolError {
+		t.Errorf("expected a successful tool result, got %+v", result)
+	}
+	answer := recordedMessages[2]
+	if answer.Role != llm.MessageRoleAssistant || answer.Content[0].Text != "Hello from the replay test" {
+		t.Errorf("unexpected answer: %+v", answer)
+	}
+}
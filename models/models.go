@@ -11,9 +11,14 @@ import (
 	"shelley.exe.dev/db/generated"
 	"shelley.exe.dev/llm"
 	"shelley.exe.dev/llm/ant"
+	"shelley.exe.dev/llm/cache"
+	"shelley.exe.dev/llm/fallback"
 	"shelley.exe.dev/llm/gem"
 	"shelley.exe.dev/llm/llmhttp"
 	"shelley.exe.dev/llm/oai"
+	"shelley.exe.dev/llm/ratelimit"
+	"shelley.exe.dev/llm/retry"
+	"shelley.exe.dev/llm/telemetry"
 	"shelley.exe.dev/loop"
 )
 
@@ -115,12 +120,36 @@ type Config struct {
 	// If set, model-specific suffixes will be appended
 	Gateway string
 
+	// Fallbacks maps model IDs to the models to fail over to, in order, when they are unavailable (optional)
+	Fallbacks map[string][]string
+
+	// Retry says how to retry requests that fail transiently (optional, see retry.Policy for defaults)
+	Retry retry.Policy
+
+	// RateLimits are the rate limits of providers, which requests to them are paced to stay within (optional)
+	RateLimits map[Provider]ratelimit.Limits
+
+	// ResponseCacheDir, if set, caches responses there and answers repeated requests from it,
+	// for development and test runs of the same scenario (optional)
+	ResponseCacheDir string
+
+	// ThinkingLevel is how much models that think or reason do so (optional, medium if nil)
+	ThinkingLevel *llm.ThinkingLevel
+
 	Logger *slog.Logger
 
 	// Database for recording LLM requests (optional)
 	DB *db.DB
 }
 
+// getThinkingLevel returns the configured thinking level, medium by default
+func (c *Config) getThinkingLevel() llm.ThinkingLevel {
+	if c.ThinkingLevel == nil {
+		return llm.ThinkingLevelMedium
+	}
+	return *c.ThinkingLevel
+}
+
 // getAnthropicURL returns the Anthropic API URL, with gateway suffix if gateway is set
 func (c *Config) getAnthropicURL() string {
 	if c.Gateway != "" {
@@ -166,7 +195,7 @@ func All() []Model {
 				if config.AnthropicAPIKey == "" {
 					return nil, fmt.Errorf("claude-opus-4.6 requires ANTHROPIC_API_KEY")
 				}
-				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude46Opus, HTTPC: httpc, ThinkingLevel: llm.ThinkingLevelMedium}
+				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude46Opus, HTTPC: httpc, ThinkingLevel: config.getThinkingLevel()}
 				if url := config.getAnthropicURL(); url != "" {
 					svc.URL = url
 				}
@@ -183,7 +212,7 @@ func All() []Model {
 				if config.AnthropicAPIKey == "" {
 					return nil, fmt.Errorf("claude-opus-4.5 requires ANTHROPIC_API_KEY")
 				}
-				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude45Opus, HTTPC: httpc, ThinkingLevel: llm.ThinkingLevelMedium}
+				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude45Opus, HTTPC: httpc, ThinkingLevel: config.getThinkingLevel()}
 				if url := config.getAnthropicURL(); url != "" {
 					svc.URL = url
 				}
@@ -200,7 +229,7 @@ func All() []Model {
 				if config.AnthropicAPIKey == "" {
 					return nil, fmt.Errorf("claude-sonnet-4.6 requires ANTHROPIC_API_KEY")
 				}
-				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude46Sonnet, HTTPC: httpc, ThinkingLevel: llm.ThinkingLevelMedium}
+				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude46Sonnet, HTTPC: httpc, ThinkingLevel: config.getThinkingLevel()}
 				if url := config.getAnthropicURL(); url != "" {
 					svc.URL = url
 				}
@@ -217,7 +246,7 @@ func All() []Model {
 				if config.AnthropicAPIKey == "" {
 					return nil, fmt.Errorf("claude-sonnet-4.5 requires ANTHROPIC_API_KEY")
 				}
-				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude45Sonnet, HTTPC: httpc, ThinkingLevel: llm.ThinkingLevelMedium}
+				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude45Sonnet, HTTPC: httpc, ThinkingLevel: config.getThinkingLevel()}
 				if url := config.getAnthropicURL(); url != "" {
 					svc.URL = url
 				}
@@ -235,7 +264,7 @@ func All() []Model {
 				if config.AnthropicAPIKey == "" {
 					return nil, fmt.Errorf("claude-haiku-4.5 requires ANTHROPIC_API_KEY")
 				}
-				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude45Haiku, HTTPC: httpc, ThinkingLevel: llm.ThinkingLevelMedium}
+				svc := &ant.Service{APIKey: config.AnthropicAPIKey, Model: ant.Claude45Haiku, HTTPC: httpc, ThinkingLevel: config.getThinkingLevel()}
 				if url := config.getAnthropicURL(); url != "" {
 					svc.URL = url
 				}
@@ -269,7 +298,7 @@ func All() []Model {
 				if config.OpenAIAPIKey == "" {
 					return nil, fmt.Errorf("gpt-5.3-codex requires OPENAI_API_KEY")
 				}
-				svc := &oai.ResponsesService{Model: oai.GPT53Codex, APIKey: config.OpenAIAPIKey, HTTPC: httpc, ThinkingLevel: llm.ThinkingLevelMedium}
+				svc := &oai.ResponsesService{Model: oai.GPT53Codex, APIKey: config.OpenAIAPIKey, HTTPC: httpc, ThinkingLevel: config.getThinkingLevel()}
 				if url := config.getOpenAIURL(); url != "" {
 					svc.ModelURL = url
 				}
@@ -286,7 +315,7 @@ func All() []Model {
 				if config.OpenAIAPIKey == "" {
 					return nil, fmt.Errorf("gpt-5.2-codex requires OPENAI_API_KEY")
 				}
-				svc := &oai.ResponsesService{Model: oai.GPT52Codex, APIKey: config.OpenAIAPIKey, HTTPC: httpc, ThinkingLevel: llm.ThinkingLevelMedium}
+				svc := &oai.ResponsesService{Model: oai.GPT52Codex, APIKey: config.OpenAIAPIKey, HTTPC: httpc, ThinkingLevel: config.getThinkingLevel()}
 				if url := config.getOpenAIURL(); url != "" {
 					svc.ModelURL = url
 				}
@@ -406,6 +435,10 @@ type Manager struct {
 	db         *db.DB       // for custom models and LLM request recording
 	httpc      *http.Client // HTTP client with recording middleware
 	cfg        *Config      // retained for refreshing custom models
+	// fallbacks are the fallback chains of models that have them configured, by model ID
+	fallbacks map[string]*fallback.Service
+	// limiters pace the requests to providers with rate limits, across all conversations
+	limiters map[Provider]*ratelimit.Limiter
 }
 
 type serviceEntry struct {
@@ -495,9 +528,9 @@ func (l *loggingService) TokenContextWindow() int {
 	return l.service.TokenContextWindow()
 }
 
-// MaxImageDimension delegates to the underlying service
-func (l *loggingService) MaxImageDimension() int {
-	return l.service.MaxImageDimension()
+// ImageLimits delegates to the underlying service
+func (l *loggingService) ImageLimits() llm.ImageLimits {
+	return l.service.ImageLimits()
 }
 
 // UseSimplifiedPatch delegates to the underlying service if it supports it
@@ -508,6 +541,11 @@ func (l *loggingService) UseSimplifiedPatch() bool {
 	return false
 }
 
+// AcceptsDocuments delegates to the underlying service
+func (l *loggingService) AcceptsDocuments() bool {
+	return llm.AcceptsDocuments(l.service)
+}
+
 // NewManager creates a new Manager with all models configured
 func NewManager(cfg *Config) (*Manager, error) {
 	manager := &Manager{
@@ -582,6 +620,11 @@ func NewManager(cfg *Config) (*Manager, error) {
 	manager.httpc = httpc
 	manager.cfg = cfg
 
+	manager.limiters = make(map[Provider]*ratelimit.Limiter)
+	for provider, limits := range cfg.RateLimits {
+		manager.limiters[provider] = ratelimit.NewLimiter(limits)
+	}
+
 	// Load built-in models first
 	useGateway := cfg.Gateway != ""
 	for _, model := range All() {
@@ -610,10 +653,37 @@ func NewManager(cfg *Config) (*Manager, error) {
 	if err := manager.loadCustomModels(); err != nil && cfg.Logger != nil {
 		cfg.Logger.Warn("Failed to load custom models", "error", err)
 	}
+	manager.buildFallbacks()
 
 	return manager, nil
 }
 
+// fallbackTimeout is how long a model in a fallback chain may take before the next one is tried.
+// It is long enough for large responses, but leaves the next model time within a turn's request timeout.
+const fallbackTimeout = 2 * time.Minute
+
+// buildFallbacks creates the fallback chains configured for available models.
+// Unavailable fallback models are left out of their chains.
+func (m *Manager) buildFallbacks() {
+	m.fallbacks = make(map[string]*fallback.Service)
+	for modelID, fallbackIDs := range m.cfg.Fallbacks {
+		var backends []fallback.Backend
+		for _, id := range append([]string{modelID}, fallbackIDs...) {
+			svc, err := m.service(id)
+			if err != nil {
+				if m.logger != nil {
+					m.logger.Warn("Fallback model unavailable", "model", modelID, "fallback", id)
+				}
+				continue
+			}
+			backends = append(backends, fallback.Backend{Name: id, Service: svc})
+		}
+		if len(backends) > 1 && backends[0].Name == modelID {
+			m.fallbacks[modelID] = &fallback.Service{Backends: backends, Timeout: fallbackTimeout}
+		}
+	}
+}
+
 // loadCustomModels loads custom models from the database into the manager.
 // It adds them after built-in models in the order.
 func (m *Manager) loadCustomModels() error {
@@ -670,28 +740,87 @@ func (m *Manager) RefreshCustomModels() error {
 	}
 	m.modelOrder = newOrder
 
-	// Reload custom models
-	return m.loadCustomModels()
+	// Reload custom models, which fallback chains may include
+	err := m.loadCustomModels()
+	m.buildFallbacks()
+	return err
 }
 
-// GetService returns the LLM service for the given model ID, wrapped with logging
+// GetService returns the LLM service for the given model ID, wrapped with logging,
+// failing over to the model's fallbacks if it has any, and retrying transient failures.
 func (m *Manager) GetService(modelID string) (llm.Service, error) {
+	if svc, ok := m.fallbacks[modelID]; ok {
+		return &retry.Service{Service: svc, Policy: m.cfg.Retry}, nil
+	}
+	svc, err := m.service(modelID)
+	if err != nil {
+		return nil, err
+	}
+	return &retry.Service{Service: svc, Policy: m.cfg.Retry}, nil
+}
+
+// GetBatcher returns the batch API of the given model's provider, for requests nobody is waiting on.
+func (m *Manager) GetBatcher(modelID string) (llm.Batcher, error) {
+	entry, ok := m.services[modelID]
+	if !ok {
+		return nil, fmt.Errorf("unsupported model: %s", modelID)
+	}
+	b, ok := entry.service.(llm.Batcher)
+	if o, isOAI := b.(*oai.Service); isOAI && !o.BatchesSupported() {
+		ok = false
+	}
+	if !ok {
+		return nil, fmt.Errorf("model %s does not support batches", modelID)
+	}
+	return b, nil
+}
+
+// service returns the LLM service for the given model ID, wrapped with logging
+func (m *Manager) service(modelID string) (llm.Service, error) {
 	entry, ok := m.services[modelID]
 	if !ok {
 		return nil, fmt.Errorf("unsupported model: %s", modelID)
 	}
 
+	var svc llm.Service = &telemetry.Service{Service: entry.service, Provider: string(entry.provider), Model: entry.modelID}
 	// Wrap with logging if we have a logger
 	if m.logger != nil {
-		return &loggingService{
-			service:  entry.service,
+		svc = &loggingService{
+			service:  svc,
 			logger:   m.logger,
 			modelID:  entry.modelID,
 			provider: entry.provider,
 			db:       m.db,
-		}, nil
+		}
 	}
-	return entry.service, nil
+	if limiter, ok := m.limiters[entry.provider]; ok {
+		svc = &ratelimit.Service{Service: svc, Limiter: limiter}
+	}
+	if m.cfg.ResponseCacheDir != "" {
+		svc = &cache.Service{Service: svc, Dir: m.cfg.ResponseCacheDir, Model: entry.modelID, Settings: cacheSettings(entry.service)}
+	}
+	return svc, nil
+}
+
+// cacheSettings returns the settings of a provider's service that change its responses, to key cached responses by
+func cacheSettings(svc llm.Service) any {
+	type settings struct {
+		URL           string            `json:"url,omitempty"`
+		Model         string            `json:"model,omitempty"`
+		MaxTokens     int               `json:"max_tokens,omitempty"`
+		ThinkingLevel llm.ThinkingLevel `json:"thinking_level,omitempty"`
+	}
+	switch s := svc.(type) {
+	case *ant.Service:
+		return settings{URL: s.URL, Model: s.Model, MaxTokens: s.MaxTokens, ThinkingLevel: s.ThinkingLevel}
+	case *oai.Service:
+		return settings{URL: s.ModelURL, Model: s.Model.ModelName, MaxTokens: s.MaxTokens}
+	case *oai.ResponsesService:
+		return settings{URL: s.ModelURL, Model: s.Model.ModelName, MaxTokens: s.MaxTokens, ThinkingLevel: s.ThinkingLevel}
+	case *gem.Service:
+		return settings{URL: s.URL, Model: s.Model}
+	}
+	return nil
 }
 
 // GetAvailableModels returns a list of available model IDs.
@@ -729,7 +858,8 @@ func (m *Manager) GetModelInfo(modelID string) *ModelInfo {
 	}
 }
 
-// createServiceFromModel creates an LLM service from a database model configuration
+// createServiceFromModel creates an LLM service from a database model configuration.
+// OpenAI-compatible models are assumed to accept images; one that doesn't rejects requests carrying them.
 func (m *Manager) createServiceFromModel(model *generated.Model) llm.Service {
 	switch model.ProviderType {
 	case "anthropic":
@@ -738,30 +868,41 @@ func (m *Manager) createServiceFromModel(model *generated.Model) llm.Service {
 			URL:           model.Endpoint,
 			Model:         model.ModelName,
 			HTTPC:         m.httpc,
-			ThinkingLevel: llm.ThinkingLevelMedium,
+			ThinkingLevel: m.cfg.getThinkingLevel(),
 		}
 	case "openai":
 		return &oai.Service{
 			APIKey:   model.ApiKey,
 			ModelURL: model.Endpoint,
 			Model: oai.Model{
-				ModelName: model.ModelName,
-				URL:       model.Endpoint,
+				ModelName:      model.ModelName,
+				URL:            model.Endpoint,
+				SupportsImages: true,
 			},
 			MaxTokens: int(model.MaxTokens),
 			HTTPC:     m.httpc,
 		}
+	case "azure-openai":
+		return &oai.Service{
+			APIKey:    model.ApiKey,
+			ModelURL:  model.Endpoint,
+			Model:     oai.Model{ModelName: model.ModelName, SupportsImages: true},
+			MaxTokens: int(model.MaxTokens),
+			HTTPC:     m.httpc,
+			Azure:     true,
+		}
 	case "openai-responses":
 		return &oai.ResponsesService{
 			APIKey:   model.ApiKey,
 			ModelURL: model.Endpoint,
 			Model: oai.Model{
-				ModelName: model.ModelName,
-				URL:       model.Endpoint,
+				ModelName:      model.ModelName,
+				URL:            model.Endpoint,
+				SupportsImages: true,
 			},
 			MaxTokens:     int(model.MaxTokens),
 			HTTPC:         m.httpc,
-			ThinkingLevel: llm.ThinkingLevelMedium,
+			ThinkingLevel: m.cfg.getThinkingLevel(),
 		}
 	case "gemini":
 		return &gem.Service{
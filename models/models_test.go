@@ -4,9 +4,16 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"slices"
 	"testing"
 
+	"shelley.exe.dev/db/generated"
 	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/cache"
+	"shelley.exe.dev/llm/fallback"
+	"shelley.exe.dev/llm/oai"
+	"shelley.exe.dev/llm/ratelimit"
+	"shelley.exe.dev/llm/retry"
 )
 
 func TestAll(t *testing.T) {
@@ -212,10 +219,10 @@ func TestLoggingService(t *testing.T) {
 		t.Errorf("TokenContextWindow returned %d, expected %d", window, mockService.TokenContextWindow())
 	}
 
-	// Test MaxImageDimension
-	dimension := loggingSvc.MaxImageDimension()
-	if dimension != mockService.MaxImageDimension() {
-		t.Errorf("MaxImageDimension returned %d, expected %d", dimension, mockService.MaxImageDimension())
+	// Test ImageLimits
+	limits := loggingSvc.ImageLimits()
+	if limits != mockService.ImageLimits() {
+		t.Errorf("ImageLimits returned %+v, expected %+v", limits, mockService.ImageLimits())
 	}
 
 	// Test UseSimplifiedPatch
@@ -228,7 +235,7 @@ func TestLoggingService(t *testing.T) {
 // mockLLMService implements llm.Service for testing
 type mockLLMService struct {
 	tokenContextWindow int
-	maxImageDimension  int
+	imageLimits        llm.ImageLimits
 	useSimplifiedPatch bool
 }
 
@@ -250,11 +257,11 @@ func (m *mockLLMService) TokenContextWindow() int {
 	return m.tokenContextWindow
 }
 
-func (m *mockLLMService) MaxImageDimension() int {
-	if m.maxImageDimension == 0 {
-		return 2048
+func (m *mockLLMService) ImageLimits() llm.ImageLimits {
+	if m.imageLimits == (llm.ImageLimits{}) {
+		return llm.ImageLimits{MaxDimension: 2048}
 	}
-	return m.maxImageDimension
+	return m.imageLimits
 }
 
 func (m *mockLLMService) UseSimplifiedPatch() bool {
@@ -286,6 +293,142 @@ func TestManagerGetService(t *testing.T) {
 	}
 }
 
+func TestCustomModelImages(t *testing.T) {
+	manager, err := NewManager(&Config{})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	for _, provider := range []string{"openai", "azure-openai", "openai-responses"} {
+		svc := manager.createServiceFromModel(&generated.Model{ProviderType: provider, ModelName: "custom", Endpoint: "https://example.com/v1"})
+		if svc.ImageLimits() == (llm.ImageLimits{}) {
+			t.Errorf("%s custom model drops images", provider)
+		}
+	}
+}
+
+func TestManagerGetServiceFallbacks(t *testing.T) {
+	cfg := &Config{
+		AnthropicAPIKey: "test-key",
+		Fallbacks: map[string][]string{
+			"claude-opus-4.6":   {"gpt-5.3-codex", "claude-sonnet-4.6"},
+			"claude-sonnet-4.6": {"gpt-5.3-codex"},
+		},
+	}
+	manager, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	svc, err := manager.GetService("claude-opus-4.6")
+	if err != nil {
+		t.Fatalf("GetService failed: %v", err)
+	}
+	chain, ok := svc.(*retry.Service).Service.(*fallback.Service)
+	if !ok {
+		t.Fatalf("GetService returned %T, want a fallback chain", svc.(*retry.Service).Service)
+	}
+	// gpt-5.3-codex is unavailable without an OpenAI key, so it is left out
+	var names []string
+	for _, b := range chain.Backends {
+		names = append(names, b.Name)
+	}
+	if !slices.Equal(names, []string{"claude-opus-4.6", "claude-sonnet-4.6"}) {
+		t.Errorf("Backends = %v, want claude-opus-4.6 then claude-sonnet-4.6", names)
+	}
+
+	// A chain with no available fallbacks is not needed
+	if svc, _ := manager.GetService("claude-sonnet-4.6"); svc == nil {
+		t.Error("GetService('claude-sonnet-4.6') returned nil service")
+	} else if _, ok := svc.(*retry.Service).Service.(*fallback.Service); ok {
+		t.Error("Expected no fallback chain when no fallbacks are available")
+	}
+}
+
+func TestManagerRateLimits(t *testing.T) {
+	cfg := &Config{
+		AnthropicAPIKey: "test-key",
+		RateLimits:      map[Provider]ratelimit.Limits{ProviderAnthropic: {RequestsPerMinute: 50}},
+	}
+	manager, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	limiter := func(modelID string) *ratelimit.Limiter {
+		svc, err := manager.GetService(modelID)
+		if err != nil {
+			t.Fatalf("GetService(%q) failed: %v", modelID, err)
+		}
+		limited, ok := svc.(*retry.Service).Service.(*ratelimit.Service)
+		if !ok {
+			return nil
+		}
+		return limited.Limiter
+	}
+	opus, sonnet := limiter("claude-opus-4.6"), limiter("claude-sonnet-4.6")
+	if opus == nil || opus != sonnet {
+		t.Error("Expected the models of a provider to share its rate limiter")
+	}
+	if limiter("predictable") != nil {
+		t.Error("Expected no rate limiter for a provider without rate limits")
+	}
+}
+
+func TestManagerResponseCache(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(&Config{ResponseCacheDir: dir})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	svc, err := manager.GetService("predictable")
+	if err != nil {
+		t.Fatalf("GetService failed: %v", err)
+	}
+	cached, ok := svc.(*retry.Service).Service.(*cache.Service)
+	if !ok || cached.Dir != dir || cached.Model != "predictable" {
+		t.Errorf("Expected the service to answer from the cache in %s, got %#v", dir, svc.(*retry.Service).Service)
+	}
+}
+
+func TestManagerResponseCacheSettings(t *testing.T) {
+	settings := func(level llm.ThinkingLevel) any {
+		manager, err := NewManager(&Config{AnthropicAPIKey: "test-key", ResponseCacheDir: t.TempDir(), ThinkingLevel: &level})
+		if err != nil {
+			t.Fatalf("NewManager failed: %v", err)
+		}
+		svc, err := manager.GetService("claude-opus-4.6")
+		if err != nil {
+			t.Fatalf("GetService failed: %v", err)
+		}
+		return svc.(*retry.Service).Service.(*cache.Service).Settings
+	}
+	if low, high := settings(llm.ThinkingLevelLow), settings(llm.ThinkingLevelHigh); low == high {
+		t.Errorf("Expected the thinking level in the cache settings, got %+v for both levels", low)
+	}
+}
+
+func TestManagerGetBatcher(t *testing.T) {
+	manager, err := NewManager(&Config{AnthropicAPIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, err := manager.GetBatcher("claude-opus-4.6"); err != nil {
+		t.Errorf("GetBatcher('claude-opus-4.6') failed: %v", err)
+	}
+	if _, err := manager.GetBatcher("predictable"); err == nil {
+		t.Error("GetBatcher('predictable') should fail, as the predictable service has no batches")
+	}
+
+	manager.services["custom-gpt"] = serviceEntry{service: &oai.Service{Model: oai.Model{ModelName: "gpt-4.1", URL: oai.OpenAIURL}}}
+	manager.services["custom-fireworks"] = serviceEntry{service: &oai.Service{Model: oai.Model{ModelName: "glm", URL: oai.FireworksURL}}}
+	if _, err := manager.GetBatcher("custom-gpt"); err != nil {
+		t.Errorf("GetBatcher('custom-gpt') failed: %v", err)
+	}
+	if _, err := manager.GetBatcher("custom-fireworks"); err == nil {
+		t.Error("GetBatcher('custom-fireworks') should fail, as Fireworks does not speak OpenAI batches")
+	}
+}
+
 func TestManagerHasModel(t *testing.T) {
 	cfg := &Config{}
 
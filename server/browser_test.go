@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"shelley.exe.dev/db/generated"
+)
+
+// TestEnableBrowser tests that POST /browser persists the choice and gives a conversation the browser tools when its loop starts
+func TestEnableBrowser(t *testing.T) {
+	server, database, _ := newTestServer(t)
+
+	conversation, err := database.CreateConversation(context.Background(), nil, true, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create conversation: %v", err)
+	}
+	conversationID := conversation.ConversationID
+
+	w := httptest.NewRecorder()
+	server.handleEnableBrowser(w, httptest.NewRequest("POST", "/api/conversation/"+conversationID+"/browser", nil), conversationID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var enabled generated.Conversation
+	if err := json.Unmarshal(w.Body.Bytes(), &enabled); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !enabled.BrowserEnabled {
+		t.Error("expected the response to have browser_enabled set")
+	}
+	// The choice outlives the conversation's manager
+	stored, err := database.GetConversationByID(context.Background(), conversationID)
+	if err != nil {
+		t.Fatalf("failed to get conversation: %v", err)
+	}
+	if !stored.BrowserEnabled {
+		t.Error("expected browser_enabled to be persisted")
+	}
+
+	chatBody, _ := json.Marshal(ChatRequest{Message: "echo: hello", Model: "predictable"})
+	req := httptest.NewRequest("POST", "/api/conversation/"+conversationID+"/chat", strings.NewReader(string(chatBody)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.handleChatConversation(w, req, conversationID)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	server.mu.Lock()
+	manager := server.activeConversations[conversationID]
+	server.mu.Unlock()
+	waitFor(t, 5*time.Second, func() bool {
+		manager.mu.Lock()
+		toolSet := manager.toolSet
+		manager.mu.Unlock()
+		if toolSet == nil {
+			return false
+		}
+		for _, tool := range toolSet.Tools() {
+			if tool.Name == "browser_navigate" {
+				return true
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"shelley.exe.dev/claudetool"
+	"shelley.exe.dev/db"
+	"shelley.exe.dev/db/generated"
+)
+
+// memStore is a db.ConversationStore in memory, of a single conversation
+type memStore struct {
+	conversation generated.Conversation
+	messages     []generated.Message
+}
+
+func (m *memStore) GetConversationByID(ctx context.Context, conversationID string) (*generated.Conversation, error) {
+	if conversationID != m.conversation.ConversationID {
+		return nil, sql.ErrNoRows
+	}
+	c := m.conversation
+	return &c, nil
+}
+
+func (m *memStore) UpdateConversationCwd(ctx context.Context, conversationID, cwd string) error {
+	m.conversation.Cwd = &cwd
+	return nil
+}
+
+func (m *memStore) UpdateConversationModel(ctx context.Context, conversationID, model string) error {
+	m.conversation.Model = &model
+	return nil
+}
+
+func (m *memStore) UpdateConversationTimestamp(ctx context.Context, conversationID string) error {
+	return nil
+}
+
+func (m *memStore) CreateMessage(ctx context.Context, params db.CreateMessageParams) (*generated.Message, error) {
+	data, err := json.Marshal(params.LLMData)
+	if err != nil {
+		return nil, err
+	}
+	llmData := string(data)
+	m.messages = append(m.messages, generated.Message{
+		MessageID:      fmt.Sprintf("%s-%d", params.ConversationID, len(m.messages)+1),
+		ConversationID: params.ConversationID,
+		SequenceID:     int64(len(m.messages) + 1),
+		Type:           string(params.Type),
+		LlmData:        &llmData,
+	})
+	return &m.messages[len(m.messages)-1], nil
+}
+
+func (m *memStore) ListMessages(ctx context.Context, conversationID string) ([]generated.Message, error) {
+	return m.messages, nil
+}
+
+func (m *memStore) ListMessagesForContext(ctx context.Context, conversationID string) ([]generated.Message, error) {
+	return m.messages, nil
+}
+
+func TestConversationManagerWithOtherStore(t *testing.T) {
+	cwd, model := t.TempDir(), "predictable"
+	store := &memStore{conversation: generated.Conversation{ConversationID: "c1", UserInitiated: true, Cwd: &cwd, Model: &model}}
+	cm := NewConversationManager("c1", store, nil, claudetool.ToolSetConfig{}, nil, nil)
+
+	if err := cm.Hydrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cm.GetModel() != model || cm.cwd != cwd {
+		t.Errorf("hydrated model %q and cwd %q, want %q and %q from the store", cm.GetModel(), cm.cwd, model, cwd)
+	}
+	if len(store.messages) != 1 || store.messages[0].Type != string(db.MessageTypeSystem) {
+		t.Errorf("store has messages %+v, want the system prompt", store.messages)
+	}
+}
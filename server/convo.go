@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
@@ -20,10 +21,14 @@ import (
 
 var errConversationModelMismatch = errors.New("conversation model mismatch")
 
+// streamBroadcastInterval is the least time between broadcasts of a response being generated,
+// so subscribers get a few updates a second rather than one per token
+const streamBroadcastInterval = 100 * time.Millisecond
+
 // ConversationManager manages a single active conversation
 type ConversationManager struct {
 	conversationID string
-	db             *db.DB
+	db             db.ConversationStore
 	loop           *loop.Loop
 	loopCancel     context.CancelFunc
 	loopCtx        context.Context
@@ -34,6 +39,9 @@ type ConversationManager struct {
 	logger         *slog.Logger
 	toolSetConfig  claudetool.ToolSetConfig
 	toolSet        *claudetool.ToolSet // created per-conversation when loop starts
+	browser        bool                // whether the user enabled the browser tools, which each new tool set then gets
+	budgetUSD      float64             // most the conversation may cost; no limit if zero
+	systemPrompt   *SystemPrompt       // generates the system prompt of a new top-level conversation
 
 	subpub *subpub.SubPub[StreamResponse]
 
@@ -48,10 +56,15 @@ type ConversationManager struct {
 	// onStateChange is called when the conversation state changes.
 	// This allows the server to broadcast state changes to all subscribers.
 	onStateChange func(state ConversationState)
+
+	// streamMu guards the LLM response being generated and when it was last broadcast.
+	streamMu        sync.Mutex
+	streamBlocks    []llm.StreamDelta
+	streamBroadcast time.Time
 }
 
 // NewConversationManager constructs a manager with dependencies but defers hydration until needed.
-func NewConversationManager(conversationID string, database *db.DB, baseLogger *slog.Logger, toolSetConfig claudetool.ToolSetConfig, recordMessage loop.MessageRecordFunc, onStateChange func(ConversationState)) *ConversationManager {
+func NewConversationManager(conversationID string, database db.ConversationStore, baseLogger *slog.Logger, toolSetConfig claudetool.ToolSetConfig, recordMessage loop.MessageRecordFunc, onStateChange func(ConversationState)) *ConversationManager {
 	logger := baseLogger
 	if logger == nil {
 		logger = slog.Default()
@@ -67,6 +80,7 @@ func NewConversationManager(conversationID string, database *db.DB, baseLogger *
 		toolSetConfig:  toolSetConfig,
 		subpub:         subpub.New[StreamResponse](),
 		onStateChange:  onStateChange,
+		systemPrompt:   DefaultSystemPrompt(),
 	}
 }
 
@@ -132,6 +146,7 @@ func (cm *ConversationManager) Hydrate(ctx context.Context) error {
 		cwd = *conversation.Cwd
 	}
 	cm.cwd = cwd
+	cm.browser = conversation.BrowserEnabled
 
 	// Load model from conversation if available
 	var modelID string
@@ -142,12 +157,7 @@ func (cm *ConversationManager) Hydrate(ctx context.Context) error {
 	// Generate system prompt if missing:
 	// - For user-initiated conversations: full system prompt
 	// - For subagent conversations (has parent): minimal subagent prompt
-	var messages []generated.Message
-	err = cm.db.Queries(ctx, func(q *generated.Queries) error {
-		var err error
-		messages, err = q.ListMessagesForContext(ctx, cm.conversationID)
-		return err
-	})
+	messages, err := cm.db.ListMessagesForContext(ctx, cm.conversationID)
 	if err != nil {
 		return fmt.Errorf("failed to get conversation history: %w", err)
 	}
@@ -251,7 +261,7 @@ func hasNonSystemMessages(messages []generated.Message) bool {
 }
 
 func (cm *ConversationManager) createSystemPrompt(ctx context.Context) (*generated.Message, error) {
-	systemPrompt, err := GenerateSystemPrompt(cm.cwd)
+	systemPrompt, err := cm.systemPrompt.Generate(cm.cwd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate system prompt: %w", err)
 	}
@@ -276,9 +286,7 @@ func (cm *ConversationManager) createSystemPrompt(ctx context.Context) (*generat
 		return nil, fmt.Errorf("failed to store system prompt: %w", err)
 	}
 
-	if err := cm.db.QueriesTx(ctx, func(q *generated.Queries) error {
-		return q.UpdateConversationTimestamp(ctx, cm.conversationID)
-	}); err != nil {
+	if err := cm.db.UpdateConversationTimestamp(ctx, cm.conversationID); err != nil {
 		cm.logger.Warn("Failed to update conversation timestamp after system prompt", "error", err)
 	}
 
@@ -383,27 +391,34 @@ func (cm *ConversationManager) ensureLoop(service llm.Service, modelID string) e
 	toolSetConfig := cm.toolSetConfig
 	conversationID := cm.conversationID
 	db := cm.db
+	budgetUSD := cm.budgetUSD
 	cm.mu.Unlock()
 
 	// Load conversation history fresh from the database. This is the canonical
 	// read — Hydrate only handles metadata and system prompt generation.
 	// Reading here ensures we always see messages added asynchronously
 	// (e.g. distillation results, subagent completions).
-	var dbMessages []generated.Message
-	err := db.Queries(context.Background(), func(q *generated.Queries) error {
-		var err error
-		dbMessages, err = q.ListMessagesForContext(context.Background(), conversationID)
-		return err
-	})
+	dbMessages, err := db.ListMessagesForContext(context.Background(), conversationID)
 	if err != nil {
 		return fmt.Errorf("failed to load conversation history: %w", err)
 	}
 	history, system := cm.partitionMessages(dbMessages)
 	cm.logSystemPromptState(system, len(dbMessages))
 
+	// Messages excluded from the context still count against the budget
+	var spentUSD float64
+	if budgetUSD > 0 {
+		allMessages, err := db.ListMessages(context.Background(), conversationID)
+		if err != nil {
+			return fmt.Errorf("failed to load conversation cost: %w", err)
+		}
+		spentUSD = conversationUsage(allMessages).Total.CostUSD
+	}
+
 	// Create tools for this conversation with the conversation's working directory
 	toolSetConfig.WorkingDir = cwd
 	toolSetConfig.ModelID = modelID
+	toolSetConfig.EnableDocuments = llm.AcceptsDocuments(service)
 	toolSetConfig.ConversationID = conversationID
 	toolSetConfig.ParentConversationID = conversationID // For subagent tool
 	toolSetConfig.OnWorkingDirChange = func(newDir string) {
@@ -419,18 +434,13 @@ func (cm *ConversationManager) ensureLoop(service llm.Service, modelID string) e
 		cm.mu.Unlock()
 
 		// Broadcast conversation update to subscribers so UI gets the new cwd
-		var conv generated.Conversation
-		err := db.Queries(context.Background(), func(q *generated.Queries) error {
-			var err error
-			conv, err = q.GetConversation(context.Background(), conversationID)
-			return err
-		})
+		conv, err := db.GetConversationByID(context.Background(), conversationID)
 		if err != nil {
 			logger.Error("failed to get conversation for cwd broadcast", "error", err)
 			return
 		}
 		cm.subpub.Broadcast(StreamResponse{
-			Conversation: conv,
+			Conversation: *conv,
 		})
 	}
 
@@ -442,7 +452,7 @@ func (cm *ConversationManager) ensureLoop(service llm.Service, modelID string) e
 	loopInstance := loop.NewLoop(loop.Config{
 		LLM:           service,
 		History:       history,
-		Tools:         toolSet.Tools(),
+		GetTools:      toolSet.Tools,
 		RecordMessage: recordMessage,
 		Logger:        logger,
 		System:        system,
@@ -451,6 +461,9 @@ func (cm *ConversationManager) ensureLoop(service llm.Service, modelID string) e
 		OnGitStateChange: func(ctx context.Context, state *gitstate.GitState) {
 			cm.recordGitStateChange(ctx, state)
 		},
+		OnStreamDelta: cm.handleStreamDelta,
+		BudgetUSD:     budgetUSD,
+		SpentUSD:      spentUSD,
 	})
 
 	cm.mu.Lock()
@@ -471,8 +484,15 @@ func (cm *ConversationManager) ensureLoop(service llm.Service, modelID string) e
 	cm.loopCtx = processCtx
 	cm.modelID = modelID
 	cm.toolSet = toolSet
+	browser := cm.browser
 	cm.mu.Unlock()
 
+	if browser {
+		if err := toolSet.EnableBrowser(processCtx); err != nil {
+			logger.Error("failed to enable browser tools", "error", err)
+		}
+	}
+
 	// Persist model for legacy conversations
 	if needsPersist {
 		if err := db.UpdateConversationModel(context.Background(), conversationID, modelID); err != nil {
@@ -512,6 +532,20 @@ func (cm *ConversationManager) stopLoop() {
 	}
 }
 
+// EnableBrowser gives the conversation the browser tools, from its next LLM request on.
+// If its loop is not running, they are added when it starts. Callers persist the choice,
+// which Hydrate loads, with db.EnableConversationBrowser.
+func (cm *ConversationManager) EnableBrowser() error {
+	cm.mu.Lock()
+	cm.browser = true
+	toolSet, loopCtx := cm.toolSet, cm.loopCtx
+	cm.mu.Unlock()
+	if toolSet == nil {
+		return nil
+	}
+	return toolSet.EnableBrowser(loopCtx)
+}
+
 // CancelConversation cancels the current conversation loop and records a cancelled tool result if a tool was in progress
 func (cm *ConversationManager) CancelConversation(ctx context.Context) error {
 	cm.mu.Lock()
@@ -700,12 +734,7 @@ func (cm *ConversationManager) recordGitStateChange(ctx context.Context, state *
 
 // notifyGitStateChange publishes a gitinfo message to subscribers.
 func (cm *ConversationManager) notifyGitStateChange(ctx context.Context, msg *generated.Message) {
-	var conversation generated.Conversation
-	err := cm.db.Queries(ctx, func(q *generated.Queries) error {
-		var err error
-		conversation, err = q.GetConversation(ctx, cm.conversationID)
-		return err
-	})
+	conversation, err := cm.db.GetConversationByID(ctx, cm.conversationID)
 	if err != nil {
 		cm.logger.Error("Failed to get conversation for git state notification", "error", err)
 		return
@@ -714,7 +743,20 @@ func (cm *ConversationManager) notifyGitStateChange(ctx context.Context, msg *ge
 	apiMessages := toAPIMessages([]generated.Message{*msg})
 	streamData := StreamResponse{
 		Messages:     apiMessages,
-		Conversation: conversation,
+		Conversation: *conversation,
 	}
 	cm.subpub.Publish(msg.SequenceID, streamData)
 }
+
+// handleStreamDelta merges d into the LLM response being generated and broadcasts the response so far,
+// at most once per streamBroadcastInterval. The complete response follows as a message.
+func (cm *ConversationManager) handleStreamDelta(d llm.StreamDelta) {
+	cm.streamMu.Lock()
+	defer cm.streamMu.Unlock()
+	cm.streamBlocks = llm.MergeStreamDelta(cm.streamBlocks, d)
+	if len(cm.streamBlocks) == 0 || time.Since(cm.streamBroadcast) < streamBroadcastInterval {
+		return
+	}
+	cm.streamBroadcast = time.Now()
+	cm.subpub.Broadcast(StreamResponse{StreamingContent: slices.Clone(cm.streamBlocks)})
+}
@@ -113,8 +113,8 @@ func (s *Server) handleCreateModel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate provider type
-	if req.ProviderType != "anthropic" && req.ProviderType != "openai" && req.ProviderType != "openai-responses" && req.ProviderType != "gemini" {
-		http.Error(w, "provider_type must be 'anthropic', 'openai', 'openai-responses', or 'gemini'", http.StatusBadRequest)
+	if req.ProviderType != "anthropic" && req.ProviderType != "openai" && req.ProviderType != "openai-responses" && req.ProviderType != "gemini" && req.ProviderType != "azure-openai" {
+		http.Error(w, "provider_type must be 'anthropic', 'openai', 'openai-responses', 'gemini', or 'azure-openai'", http.StatusBadRequest)
 		return
 	}
 
@@ -359,10 +359,18 @@ func (s *Server) handleTestModel(w http.ResponseWriter, r *http.Request) {
 			APIKey:   req.APIKey,
 			ModelURL: req.Endpoint,
 			Model: oai.Model{
-				ModelName: req.ModelName,
-				URL:       req.Endpoint,
+				ModelName:      req.ModelName,
+				URL:            req.Endpoint,
+				SupportsImages: true,
 			},
 		}
+	case "azure-openai":
+		service = &oai.Service{
+			APIKey:   req.APIKey,
+			ModelURL: req.Endpoint,
+			Model:    oai.Model{ModelName: req.ModelName, SupportsImages: true},
+			Azure:    true,
+		}
 	case "gemini":
 		service = &gem.Service{
 			APIKey: req.APIKey,
@@ -373,8 +381,9 @@ func (s *Server) handleTestModel(w http.ResponseWriter, r *http.Request) {
 		service = &oai.ResponsesService{
 			APIKey: req.APIKey,
 			Model: oai.Model{
-				ModelName: req.ModelName,
-				URL:       req.Endpoint,
+				ModelName:      req.ModelName,
+				URL:            req.Endpoint,
+				SupportsImages: true,
 			},
 		}
 	default:
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"shelley.exe.dev/db"
+)
+
+// maxImportSize limits the request body of an import. Exports inline their messages' LLM data, images included.
+const maxImportSize = 256 << 20
+
+// handleExportConversation handles GET /api/conversation/<id>/export, which downloads the conversation
+// with its messages and subagents as JSONL, in the format of db.ExportRecord.
+func (s *Server) handleExportConversation(w http.ResponseWriter, r *http.Request, conversationID string) {
+	ctx := r.Context()
+	conversation, err := s.db.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		http.Error(w, "Conversation not found", http.StatusNotFound)
+		return
+	}
+	name := conversationID
+	if conversation.Slug != nil {
+		name = *conversation.Slug
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + ".jsonl"}))
+	if err := s.db.ExportConversation(ctx, w, conversationID); err != nil {
+		// The export has started, so the client sees it cut short rather than an error status
+		s.logger.Error("Failed to export conversation", "conversationID", conversationID, "error", err)
+	}
+}
+
+// handleImportConversation handles POST /api/conversations/import, which adds a conversation
+// from the JSONL of an export in the request body and returns it.
+func (s *Server) handleImportConversation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	conversation, err := s.db.ImportConversation(r.Context(), r.Body)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		http.Error(w, "Conversation export too large", http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, db.ErrInvalidExport):
+		s.logger.Warn("Failed to import conversation", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		s.logger.Error("Failed to import conversation", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	s.logger.Info("Imported conversation", "conversationID", conversation.ConversationID)
+
+	go s.publishConversationListUpdate(ConversationListUpdate{
+		Type:         "update",
+		Conversation: conversation,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conversation)
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"shelley.exe.dev/db"
+	"shelley.exe.dev/db/generated"
+)
+
+func TestExportImportConversation(t *testing.T) {
+	server, database, _ := newTestServer(t)
+	ctx := context.Background()
+	slug := "export-me"
+	conv, err := database.CreateConversation(ctx, &slug, true, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.CreateMessage(ctx, db.CreateMessageParams{ConversationID: conv.ConversationID, Type: db.MessageTypeUser, UserData: map[string]string{"text": "hi"}}); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	server.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/conversation/"+conv.ConversationID+"/export", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("export: status %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename=export-me.jsonl` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	export := w.Body.String()
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/api/conversations/import", strings.NewReader(export)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("import: status %d: %s", w.Code, w.Body.String())
+	}
+	var imported generated.Conversation
+	if err := json.NewDecoder(w.Body).Decode(&imported); err != nil {
+		t.Fatal(err)
+	}
+	if imported.ConversationID == conv.ConversationID || *imported.Slug != "export-me-2" {
+		t.Errorf("imported conversation = %+v, want a copy with a new ID and slug", imported)
+	}
+	if messages, err := database.ListMessages(ctx, imported.ConversationID); err != nil || len(messages) != 1 {
+		t.Errorf("imported messages = %+v, %v; want the one message", messages, err)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/api/conversations/import", strings.NewReader("not an export")))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("import of garbage: status %d, want 400", w.Code)
+	}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/conversation/nope/export", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("export of a missing conversation: status %d, want 404", w.Code)
+	}
+
+	// A valid export the database fails to store is the server's fault, not the client's
+	if err := database.Pool().Tx(ctx, func(ctx context.Context, tx *db.Tx) error {
+		_, err := tx.Exec("CREATE TRIGGER fail_messages BEFORE INSERT ON messages BEGIN SELECT RAISE(ABORT, 'out of space'); END")
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/api/conversations/import", strings.NewReader(export)))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("import with the database closed: status %d, want 500", w.Code)
+	}
+}
@@ -538,6 +538,9 @@ func (s *Server) conversationMux() *http.ServeMux {
 	mux.HandleFunc("POST /{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
 		s.handleCancelConversation(w, r, r.PathValue("id"))
 	})
+	mux.HandleFunc("POST /{id}/browser", func(w http.ResponseWriter, r *http.Request) {
+		s.handleEnableBrowser(w, r, r.PathValue("id"))
+	})
 	mux.HandleFunc("POST /{id}/archive", func(w http.ResponseWriter, r *http.Request) {
 		s.handleArchiveConversation(w, r, r.PathValue("id"))
 	})
@@ -553,6 +556,13 @@ func (s *Server) conversationMux() *http.ServeMux {
 	mux.HandleFunc("GET /{id}/subagents", func(w http.ResponseWriter, r *http.Request) {
 		s.handleGetSubagents(w, r, r.PathValue("id"))
 	})
+	mux.HandleFunc("GET /{id}/usage", func(w http.ResponseWriter, r *http.Request) {
+		s.handleGetConversationUsage(w, r, r.PathValue("id"))
+	})
+	// GET /api/conversation/<id>/export - the conversation as JSONL (can be large, compress)
+	mux.Handle("GET /{id}/export", gzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.handleExportConversation(w, r, r.PathValue("id"))
+	})))
 	return mux
 }
 
@@ -1013,6 +1023,37 @@ func (s *Server) handleCancelConversation(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(map[string]string{"status": "cancelled"})
 }
 
+// handleEnableBrowser handles POST /conversation/<id>/browser, giving the conversation the browser tools
+// once the user grants it browser access
+func (s *Server) handleEnableBrowser(w http.ResponseWriter, r *http.Request, conversationID string) {
+	manager, err := s.getOrCreateConversationManager(r.Context(), conversationID)
+	if err != nil {
+		s.logger.Error("Failed to get conversation manager", "conversationID", conversationID, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	conversation, err := s.db.EnableConversationBrowser(r.Context(), conversationID)
+	if err != nil {
+		s.logger.Error("Failed to record browser access", "conversationID", conversationID, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if err := manager.EnableBrowser(); err != nil {
+		s.logger.Error("Failed to enable browser tools", "conversationID", conversationID, "error", err)
+		http.Error(w, "Failed to enable browser tools", http.StatusInternalServerError)
+		return
+	}
+
+	// Notify conversation list subscribers
+	go s.publishConversationListUpdate(ConversationListUpdate{
+		Type:         "update",
+		Conversation: conversation,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conversation)
+}
+
 // handleStreamConversation handles GET /conversation/<id>/stream
 // Query parameters:
 //   - last_sequence_id: Resume from this sequence ID (skip messages up to and including this ID)
@@ -4,6 +4,9 @@ import (
 	"log/slog"
 
 	"shelley.exe.dev/db"
+	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/ratelimit"
+	"shelley.exe.dev/llm/retry"
 )
 
 // Link represents a custom link to be displayed in the UI
@@ -37,6 +40,24 @@ type LLMConfig struct {
 	// Each entry is a map with at least a "type" key, plus channel-specific fields.
 	NotificationChannels []map[string]any
 
+	// ModelFallbacks maps model IDs to the models to fail over to, in order, when they are unavailable (optional)
+	ModelFallbacks map[string][]string
+
+	// Retry says how to retry LLM requests that fail transiently (optional, see retry.Policy for defaults)
+	Retry retry.Policy
+
+	// RateLimits are the rate limits of providers by name, e.g. "anthropic" (optional)
+	RateLimits map[string]ratelimit.Limits
+
+	// ResponseCacheDir, if set, caches LLM responses there and answers repeated requests from it (optional, for development)
+	ResponseCacheDir string
+
+	// ThinkingLevel is how much models that think or reason do so (optional, medium if nil)
+	ThinkingLevel *llm.ThinkingLevel
+
+	// ConversationBudgetUSD is the most a conversation may cost before its agent stops (optional, no limit if zero)
+	ConversationBudgetUSD float64
+
 	// DB is the database for recording LLM requests (optional)
 	DB *db.DB
 
@@ -23,6 +23,7 @@ import (
 	"shelley.exe.dev/db"
 	"shelley.exe.dev/db/generated"
 	"shelley.exe.dev/llm"
+	"shelley.exe.dev/llm/ratelimit"
 	"shelley.exe.dev/models"
 	"shelley.exe.dev/server/notifications"
 	"shelley.exe.dev/ui"
@@ -71,6 +72,9 @@ type StreamResponse struct {
 	Heartbeat bool `json:"heartbeat,omitempty"`
 	// NotificationEvent is set when a notification-worthy event occurs (e.g. agent finished).
 	NotificationEvent *notifications.Event `json:"notification_event,omitempty"`
+	// StreamingContent is the LLM response being generated, one merged delta per content block.
+	// Updates carrying it have no other fields set.
+	StreamingContent []llm.StreamDelta `json:"streaming_content,omitempty"`
 }
 
 // LLMProvider is an interface for getting LLM services
@@ -86,13 +90,21 @@ type LLMProvider interface {
 func NewLLMServiceManager(cfg *LLMConfig) LLMProvider {
 	// Convert LLMConfig to models.Config
 	modelConfig := &models.Config{
-		AnthropicAPIKey: cfg.AnthropicAPIKey,
-		OpenAIAPIKey:    cfg.OpenAIAPIKey,
-		GeminiAPIKey:    cfg.GeminiAPIKey,
-		FireworksAPIKey: cfg.FireworksAPIKey,
-		Gateway:         cfg.Gateway,
-		Logger:          cfg.Logger,
-		DB:              cfg.DB,
+		AnthropicAPIKey:  cfg.AnthropicAPIKey,
+		OpenAIAPIKey:     cfg.OpenAIAPIKey,
+		GeminiAPIKey:     cfg.GeminiAPIKey,
+		FireworksAPIKey:  cfg.FireworksAPIKey,
+		Gateway:          cfg.Gateway,
+		Fallbacks:        cfg.ModelFallbacks,
+		Retry:            cfg.Retry,
+		RateLimits:       make(map[models.Provider]ratelimit.Limits),
+		ResponseCacheDir: cfg.ResponseCacheDir,
+		ThinkingLevel:    cfg.ThinkingLevel,
+		Logger:           cfg.Logger,
+		DB:               cfg.DB,
+	}
+	for provider, limits := range cfg.RateLimits {
+		modelConfig.RateLimits[models.Provider(provider)] = limits
 	}
 
 	manager, err := models.NewManager(modelConfig)
@@ -234,6 +246,8 @@ type Server struct {
 	versionChecker      *VersionChecker
 	notifDispatcher     *notifications.Dispatcher
 	shutdownCh          chan struct{} // Signals background routines to stop
+	conversationBudget  float64       // most a conversation may cost in USD; no limit if zero
+	systemPrompt        *SystemPrompt // system prompt of new top-level conversations
 }
 
 // NewServer creates a new server instance
@@ -252,6 +266,7 @@ func NewServer(database *db.DB, llmManager LLMProvider, toolSetConfig claudetool
 		versionChecker:      NewVersionChecker(),
 		notifDispatcher:     notifications.NewDispatcher(logger),
 		shutdownCh:          make(chan struct{}),
+		systemPrompt:        DefaultSystemPrompt(),
 	}
 
 	// Set up subagent support
@@ -262,6 +277,22 @@ func NewServer(database *db.DB, llmManager LLMProvider, toolSetConfig claudetool
 	return s
 }
 
+// SetConversationBudget sets the most each conversation may cost in USD, or no limit if zero.
+// It applies to conversations whose agent loop starts afterwards.
+func (s *Server) SetConversationBudget(usd float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conversationBudget = usd
+}
+
+// SetSystemPrompt sets the system prompt of top-level conversations, for embedders that extend
+// DefaultSystemPrompt. It applies to conversations whose agent loop starts afterwards.
+func (s *Server) SetSystemPrompt(p *SystemPrompt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.systemPrompt = p
+}
+
 // RegisterNotificationChannel adds a backend notification channel to the dispatcher.
 func (s *Server) RegisterNotificationChannel(ch notifications.Channel) {
 	s.notifDispatcher.Register(ch)
@@ -276,6 +307,7 @@ func (s *Server) RegisterRoutes(mux *http.ServeMux) {
 	mux.Handle("/api/conversations/new", http.HandlerFunc(s.handleNewConversation))           // Small response
 	mux.Handle("/api/conversations/continue", http.HandlerFunc(s.handleContinueConversation)) // Small response
 	mux.Handle("/api/conversations/distill", http.HandlerFunc(s.handleDistillConversation))   // Small response
+	mux.Handle("/api/conversations/import", http.HandlerFunc(s.handleImportConversation))     // Small response
 	mux.Handle("/api/conversation/", http.StripPrefix("/api/conversation", s.conversationMux()))
 	mux.Handle("/api/conversation-by-slug/", gzipHandler(http.HandlerFunc(s.handleConversationBySlug)))
 	mux.Handle("/api/validate-cwd", http.HandlerFunc(s.handleValidateCwd)) // Small response
@@ -680,6 +712,8 @@ func (s *Server) getOrCreateConversationManager(ctx context.Context, conversatio
 		}
 
 		manager := NewConversationManager(conversationID, s.db, s.logger, s.toolSetConfig, recordMessage, onStateChange)
+		manager.budgetUSD = s.conversationBudget
+		manager.systemPrompt = s.systemPrompt
 		if err := manager.Hydrate(ctx); err != nil {
 			return nil, err
 		}
@@ -718,6 +752,7 @@ func (s *Server) getOrCreateSubagentConversationManager(ctx context.Context, con
 		subagentConfig.SubagentDepth = s.toolSetConfig.SubagentDepth + 1
 
 		manager := NewConversationManager(conversationID, s.db, s.logger, subagentConfig, recordMessage, onStateChange)
+		manager.budgetUSD = s.conversationBudget
 		if err := manager.Hydrate(ctx); err != nil {
 			return nil, err
 		}
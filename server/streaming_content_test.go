@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"shelley.exe.dev/claudetool"
+	"shelley.exe.dev/llm"
+)
+
+func TestHandleStreamDeltaBroadcastsPartialResponse(t *testing.T) {
+	cm := NewConversationManager("conv", nil, nil, claudetool.ToolSetConfig{}, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	next := cm.subpub.Subscribe(ctx, -1)
+
+	cm.handleStreamDelta(llm.StreamDelta{Reset: true})
+	cm.handleStreamDelta(llm.StreamDelta{Index: 0, Type: llm.ContentTypeText, Text: "Hel"})
+	// Too soon after the last broadcast to be sent on its own
+	cm.handleStreamDelta(llm.StreamDelta{Index: 0, Type: llm.ContentTypeText, Text: "lo"})
+	cancel()
+
+	update, ok := next()
+	if !ok {
+		t.Fatal("expected a broadcast of the partial response")
+	}
+	if len(update.StreamingContent) != 1 || update.StreamingContent[0].Text != "Hel" {
+		t.Errorf("StreamingContent = %+v, want the first delta", update.StreamingContent)
+	}
+	if update, ok := next(); ok {
+		t.Errorf("expected throttled delta not to be broadcast, got %+v", update)
+	}
+	if got := cm.streamBlocks[0].Text; got != "Hello" {
+		t.Errorf("merged text = %q, want %q", got, "Hello")
+	}
+}
@@ -2,11 +2,12 @@ package server
 
 import (
 	"context"
-	_ "embed"
+	"embed"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 	"time"
@@ -14,57 +15,114 @@ import (
 	"shelley.exe.dev/skills"
 )
 
-//go:embed system_prompt.txt
-var systemPromptTemplate string
+//go:embed system_prompt
+var systemPromptFS embed.FS
+
+// defaultPromptSections are the sections of the default system prompt, in order; each is in system_prompt/<name>.txt
+var defaultPromptSections = []string{"identity", "environment", "exe_dev", "codebase", "skills", "previous_conversations"}
 
 //go:embed subagent_system_prompt.txt
 var subagentSystemPromptTemplate string
 
-// SystemPromptData contains all the data needed to render the system prompt template
+// SystemPromptData contains all the data needed to render the system prompt sections
 type SystemPromptData struct {
 	WorkingDirectory string
 	GitInfo          *GitInfo
 	Codebase         *CodebaseInfo
 	IsExeDev         bool
 	IsSudoAvailable  bool
-	Hostname         string // For exe.dev, the public hostname (e.g., "vmname.exe.xyz")
-	ShelleyDBPath    string // Path to the shelley database
-	SkillsXML        string // XML block for available skills
+	Hostname         string            // For exe.dev, the public hostname (e.g., "vmname.exe.xyz")
+	ShelleyDBPath    string            // Path to the shelley database
+	SkillsXML        string            // XML block for available skills
+	Vars             map[string]string // Variables set with SystemPrompt.SetVar
 }
 
-// DBPath is the path to the shelley database, set at startup
-var DBPath string
+// SystemPrompt composes the system prompt from named sections, in order.
+// Each section is a text/template executed with a SystemPromptData.
+// Embedders extend the prompt by adding, replacing, or removing sections, and give their sections
+// values through SetVar. Set it up before handing it to the Server; Generate is safe for concurrent use.
+type SystemPrompt struct {
+	sections []*template.Template
+	vars     map[string]string
+}
 
-type GitInfo struct {
-	Root string
+// DefaultSystemPrompt returns Shelley's system prompt, whose sections are
+// identity, environment, exe_dev, codebase, skills, and previous_conversations.
+func DefaultSystemPrompt() *SystemPrompt {
+	p := &SystemPrompt{}
+	for _, name := range defaultPromptSections {
+		text, err := systemPromptFS.ReadFile("system_prompt/" + name + ".txt")
+		if err != nil {
+			panic(err)
+		}
+		if err := p.Set(name, string(text)); err != nil {
+			panic(err)
+		}
+	}
+	return p
 }
 
-type CodebaseInfo struct {
-	InjectFiles        []string
-	InjectFileContents map[string]string
-	GuidanceFiles      []string
+// Set sets the section called name to the template text,
+// replacing the section of that name if there is one, and adding it at the end otherwise.
+func (p *SystemPrompt) Set(name, text string) error {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse system prompt section %s: %w", name, err)
+	}
+	if i := slices.IndexFunc(p.sections, func(t *template.Template) bool { return t.Name() == name }); i >= 0 {
+		p.sections[i] = tmpl
+	} else {
+		p.sections = append(p.sections, tmpl)
+	}
+	return nil
+}
+
+// Remove removes the section called name, if there is one.
+func (p *SystemPrompt) Remove(name string) {
+	p.sections = slices.DeleteFunc(p.sections, func(t *template.Template) bool { return t.Name() == name })
 }
 
-// GenerateSystemPrompt generates the system prompt using the embedded template.
-// If workingDir is empty, it uses the current working directory.
-func GenerateSystemPrompt(workingDir string) (string, error) {
+// SetVar sets the variable name, which sections use as {{.Vars.name}}.
+func (p *SystemPrompt) SetVar(name, value string) {
+	if p.vars == nil {
+		p.vars = make(map[string]string)
+	}
+	p.vars[name] = value
+}
+
+// Generate renders the system prompt for a conversation in workingDir, or the current directory if it is empty.
+// Sections that render empty are left out.
+func (p *SystemPrompt) Generate(workingDir string) (string, error) {
 	data, err := collectSystemData(workingDir)
 	if err != nil {
 		return "", fmt.Errorf("failed to collect system data: %w", err)
 	}
+	data.Vars = p.vars
 
-	tmpl, err := template.New("system_prompt").Parse(systemPromptTemplate)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
+	var sections []string
+	for _, tmpl := range p.sections {
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("failed to execute system prompt section %s: %w", tmpl.Name(), err)
+		}
+		if section := strings.TrimSpace(buf.String()); section != "" {
+			sections = append(sections, section)
+		}
 	}
+	return strings.Join(sections, "\n\n") + "\n", nil
+}
 
-	var buf strings.Builder
-	err = tmpl.Execute(&buf, data)
-	if err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
-	}
+// DBPath is the path to the shelley database, set at startup
+var DBPath string
 
-	return buf.String(), nil
+type GitInfo struct {
+	Root string
+}
+
+type CodebaseInfo struct {
+	InjectFiles        []string
+	InjectFileContents map[string]string
+	GuidanceFiles      []string
 }
 
 func collectSystemData(workingDir string) (*SystemPromptData, error) {
@@ -26,9 +26,9 @@ func TestSystemPromptIncludesCwdGuidanceFiles(t *testing.T) {
 	}
 
 	// Generate system prompt for this directory
-	prompt, err := GenerateSystemPrompt(tmpDir)
+	prompt, err := DefaultSystemPrompt().Generate(tmpDir)
 	if err != nil {
-		t.Fatalf("GenerateSystemPrompt failed: %v", err)
+		t.Fatalf("Generate failed: %v", err)
 	}
 
 	// Verify the unique content from AGENTS.md is included in the prompt
@@ -45,7 +45,7 @@ func TestSystemPromptIncludesCwdGuidanceFiles(t *testing.T) {
 }
 
 // TestSystemPromptEmptyCwdFallsBackToCurrentDir verifies that an empty workingDir
-// causes GenerateSystemPrompt to use the current directory.
+// causes Generate to use the current directory.
 func TestSystemPromptEmptyCwdFallsBackToCurrentDir(t *testing.T) {
 	// Get current directory for comparison
 	currentDir, err := os.Getwd()
@@ -54,9 +54,9 @@ func TestSystemPromptEmptyCwdFallsBackToCurrentDir(t *testing.T) {
 	}
 
 	// Generate system prompt with empty workingDir
-	prompt, err := GenerateSystemPrompt("")
+	prompt, err := DefaultSystemPrompt().Generate("")
 	if err != nil {
-		t.Fatalf("GenerateSystemPrompt failed: %v", err)
+		t.Fatalf("Generate failed: %v", err)
 	}
 
 	// Verify the current directory is mentioned in the prompt
@@ -89,9 +89,9 @@ func TestSystemPromptDetectsGitInWorkingDir(t *testing.T) {
 	}
 
 	// Generate system prompt for the git repo directory
-	prompt, err := GenerateSystemPrompt(tmpDir)
+	prompt, err := DefaultSystemPrompt().Generate(tmpDir)
 	if err != nil {
-		t.Fatalf("GenerateSystemPrompt failed: %v", err)
+		t.Fatalf("Generate failed: %v", err)
 	}
 
 	// The prompt should say "Git repository root:" not "Not in a git repository"
@@ -134,9 +134,9 @@ func TestSystemPromptIncludesSkillsFromAnyWorkingDir(t *testing.T) {
 
 	// Generate system prompt from a directory completely unrelated to home
 	unrelatedDir := t.TempDir()
-	prompt, err := GenerateSystemPrompt(unrelatedDir)
+	prompt, err := DefaultSystemPrompt().Generate(unrelatedDir)
 	if err != nil {
-		t.Fatalf("GenerateSystemPrompt failed: %v", err)
+		t.Fatalf("Generate failed: %v", err)
 	}
 
 	if !strings.Contains(prompt, "test-skill") {
@@ -146,3 +146,43 @@ func TestSystemPromptIncludesSkillsFromAnyWorkingDir(t *testing.T) {
 		t.Error("system prompt should contain the skill description")
 	}
 }
+
+func TestSystemPromptSections(t *testing.T) {
+	p := DefaultSystemPrompt()
+	p.Remove("skills")
+	if err := p.Set("identity", "You are {{.Vars.name}}."); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Set("team", "Ask {{.Vars.team}} for help."); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Set("empty", "{{if false}}never{{end}}"); err != nil {
+		t.Fatal(err)
+	}
+	p.SetVar("name", "Bob")
+	p.SetVar("team", "#infra")
+
+	prompt, err := p.Generate(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(prompt, "You are Bob.\n\nInitial pwd:") {
+		t.Errorf("expected the replaced identity section first, got %q", prompt[:min(len(prompt), 200)])
+	}
+	if !strings.HasSuffix(prompt, "\n\nAsk #infra for help.\n") {
+		t.Errorf("expected the added section last, got %q", prompt[max(0, len(prompt)-200):])
+	}
+	if strings.Contains(prompt, "<skills>") || strings.Contains(prompt, "never") {
+		t.Errorf("expected removed and empty sections to be left out, got %q", prompt)
+	}
+
+	if err := p.Set("bad", "{{.Vars.unset}}"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Generate(t.TempDir()); err == nil || !strings.Contains(err.Error(), "section bad") {
+		t.Errorf("expected an error for an unset variable, got %v", err)
+	}
+	if err := p.Set("broken", "{{"); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
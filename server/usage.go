@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"shelley.exe.dev/db/generated"
+	"shelley.exe.dev/llm"
+)
+
+// ConversationUsage is the token usage and cost of a conversation's LLM requests.
+type ConversationUsage struct {
+	Total  llm.Usage            `json:"total"`
+	Models map[string]llm.Usage `json:"models"` // by the model that served the requests
+	Tools  map[string]ToolUsage `json:"tools"`  // by tool name
+}
+
+// ToolUsage is the usage attributed to a tool: the cost of the LLM responses that called it,
+// shared equally between the calls when a response calls several tools.
+type ToolUsage struct {
+	Calls   int     `json:"calls"`
+	CostUSD float64 `json:"cost_usd"`
+}
+
+// conversationUsage aggregates the usage recorded with messages.
+func conversationUsage(messages []generated.Message) ConversationUsage {
+	cu := ConversationUsage{Models: map[string]llm.Usage{}, Tools: map[string]ToolUsage{}}
+	for _, msg := range messages {
+		if msg.UsageData == nil {
+			continue
+		}
+		var usage llm.Usage
+		if err := json.Unmarshal([]byte(*msg.UsageData), &usage); err != nil || usage.IsZero() {
+			continue
+		}
+		cu.Total.Add(usage)
+		modelUsage := cu.Models[usage.Model]
+		modelUsage.Add(usage)
+		cu.Models[usage.Model] = modelUsage
+
+		var tools []string
+		if msg.LlmData != nil {
+			var message llm.Message
+			if err := json.Unmarshal([]byte(*msg.LlmData), &message); err == nil {
+				for _, c := range message.Content {
+					if c.Type == llm.ContentTypeToolUse {
+						tools = append(tools, c.ToolName)
+					}
+				}
+			}
+		}
+		for _, name := range tools {
+			tu := cu.Tools[name]
+			tu.Calls++
+			tu.CostUSD += usage.CostUSD / float64(len(tools))
+			cu.Tools[name] = tu
+		}
+	}
+	return cu
+}
+
+// handleGetConversationUsage handles GET /conversation/<id>/usage
+func (s *Server) handleGetConversationUsage(w http.ResponseWriter, r *http.Request, conversationID string) {
+	ctx := r.Context()
+	var messages []generated.Message
+	err := s.db.Queries(ctx, func(q *generated.Queries) error {
+		var err error
+		messages, err = q.ListMessages(ctx, conversationID)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to get conversation messages", "conversationID", conversationID, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conversationUsage(messages))
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"shelley.exe.dev/db/generated"
+	"shelley.exe.dev/llm"
+)
+
+func usageTestMessage(t *testing.T, message llm.Message, usage llm.Usage) generated.Message {
+	t.Helper()
+	llmData, err := json.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usageData, err := json.Marshal(usage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	llmStr, usageStr := string(llmData), string(usageData)
+	return generated.Message{LlmData: &llmStr, UsageData: &usageStr}
+}
+
+func TestConversationUsage(t *testing.T) {
+	toolUse := func(name string) llm.Content {
+		return llm.Content{Type: llm.ContentTypeToolUse, ToolName: name}
+	}
+	messages := []generated.Message{
+		usageTestMessage(t, llm.UserStringMessage("hi"), llm.Usage{}),
+		usageTestMessage(t, llm.Message{Role: llm.MessageRoleAssistant, Content: []llm.Content{toolUse("bash"), toolUse("patch")}},
+			llm.Usage{InputTokens: 100, OutputTokens: 10, CostUSD: 0.02, Model: "sonnet"}),
+		usageTestMessage(t, llm.Message{Role: llm.MessageRoleAssistant, Content: []llm.Content{toolUse("bash")}},
+			llm.Usage{InputTokens: 200, OutputTokens: 20, CostUSD: 0.03, Model: "opus"}),
+		usageTestMessage(t, llm.Message{Role: llm.MessageRoleAssistant, Content: []llm.Content{llm.StringContent("done")}},
+			llm.Usage{InputTokens: 300, OutputTokens: 30, CostUSD: 0.04, Model: "sonnet"}),
+	}
+
+	cu := conversationUsage(messages)
+	if cu.Total.InputTokens != 600 || cu.Total.OutputTokens != 60 || !approxEqual(cu.Total.CostUSD, 0.09) {
+		t.Errorf("Total = %+v, want 600 input tokens, 60 output tokens, $0.09", cu.Total)
+	}
+	if got := cu.Models["sonnet"]; got.InputTokens != 400 || !approxEqual(got.CostUSD, 0.06) {
+		t.Errorf("Models[sonnet] = %+v, want 400 input tokens, $0.06", got)
+	}
+	if got := cu.Tools["bash"]; got.Calls != 2 || !approxEqual(got.CostUSD, 0.04) {
+		t.Errorf("Tools[bash] = %+v, want 2 calls, $0.04", got)
+	}
+	if got := cu.Tools["patch"]; got.Calls != 1 || !approxEqual(got.CostUSD, 0.01) {
+		t.Errorf("Tools[patch] = %+v, want 1 call, $0.01", got)
+	}
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
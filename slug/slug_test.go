@@ -88,8 +88,8 @@ func (m *MockLLMService) TokenContextWindow() int {
 	return 8192 // Mock token limit
 }
 
-func (m *MockLLMService) MaxImageDimension() int {
-	return 0 // No limit for mock
+func (m *MockLLMService) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{}
 }
 
 // MockLLMProvider provides a mock LLM provider for testing
@@ -201,8 +201,8 @@ func (m *MockLLMServiceWithError) TokenContextWindow() int {
 	return 8192
 }
 
-func (m *MockLLMServiceWithError) MaxImageDimension() int {
-	return 0
+func (m *MockLLMServiceWithError) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{}
 }
 
 // MockLLMProviderWithError provides a mock LLM provider that returns errors for all models
@@ -317,8 +317,8 @@ func (m *MockLLMServiceEmptyResponse) TokenContextWindow() int {
 	return 8192
 }
 
-func (m *MockLLMServiceEmptyResponse) MaxImageDimension() int {
-	return 0
+func (m *MockLLMServiceEmptyResponse) ImageLimits() llm.ImageLimits {
+	return llm.ImageLimits{}
 }
 
 // TestGenerateSlug_SanitizationError tests error handling when slug is empty after sanitization
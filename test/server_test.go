@@ -991,7 +991,7 @@ func TestScreenshotRouteServesImage(t *testing.T) {
 
 	// Create a fake screenshot file in the expected location
 	id := "testshot"
-	path := browse.GetScreenshotPath(id)
+	path := browse.GetScreenshotPath(id, "png")
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		t.Fatalf("Failed to create screenshot dir: %v", err)
 	}
@@ -646,6 +646,14 @@ function App() {
               console.error("Failed to archive conversation:", err);
             }
           }}
+          onEnableBrowser={async (conversationId: string) => {
+            try {
+              const conversation = await api.enableBrowser(conversationId);
+              updateConversation(conversation);
+            } catch (err) {
+              console.error("Failed to enable browser tools:", err);
+            }
+          }}
           onOpenDiffViewer={() => {
             setDiffViewerTrigger((prev) => prev + 1);
             setCommandPaletteOpen(false);
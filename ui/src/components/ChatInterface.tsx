@@ -1,10 +1,11 @@
-import React, { useState, useEffect, useRef, useCallback } from "react";
+import React, { useState, useEffect, useRef, useCallback, useMemo } from "react";
 import {
   Message,
   Conversation,
   StreamResponse,
   LLMContent,
   ConversationListUpdate,
+  StreamingBlock,
   isDistillStatusMessage,
 } from "../types";
 import { api } from "../services/api";
@@ -42,6 +43,7 @@ import SystemPromptView from "./SystemPromptView";
 interface ContextUsageBarProps {
   contextWindowSize: number;
   maxContextTokens: number;
+  costUSD: number;
   conversationId?: string | null;
   modelName?: string;
   onContinueConversation?: () => void;
@@ -51,6 +53,7 @@ interface ContextUsageBarProps {
 function ContextUsageBar({
   contextWindowSize,
   maxContextTokens,
+  costUSD,
   conversationId,
   modelName,
   onContinueConversation,
@@ -171,6 +174,7 @@ function ContextUsageBar({
           )}
           {formatTokens(contextWindowSize)} / {formatTokens(maxContextTokens)} (
           {percentage.toFixed(1)}%) tokens used
+          {costUSD > 0 && <div>${costUSD.toFixed(2)} spent</div>}
           {showLongConversationWarning && (
             <div style={{ marginTop: "6px", color: "var(--warning-text, #f59e0b)" }}>
               This conversation is getting long.
@@ -478,6 +482,38 @@ function AnimatedWorkingStatus() {
   );
 }
 
+// streamingMessage presents an LLM response still being generated as an agent message.
+// Tool inputs are shown once their JSON is complete.
+function streamingMessage(blocks: StreamingBlock[], conversationId: string): Message {
+  const content: LLMContent[] = blocks.map((block) => {
+    let toolInput: unknown = undefined;
+    if (block.tool_input) {
+      try {
+        toolInput = JSON.parse(block.tool_input);
+      } catch {
+        // Still arriving
+      }
+    }
+    const isThinking = block.type === 3;
+    return {
+      ID: block.id || "",
+      Type: block.type,
+      Text: isThinking ? undefined : block.text,
+      Thinking: isThinking ? block.text : undefined,
+      ToolName: block.tool_name,
+      ToolInput: toolInput,
+    };
+  });
+  return {
+    message_id: "streaming",
+    conversation_id: conversationId,
+    sequence_id: -1,
+    type: "agent",
+    llm_data: JSON.stringify({ Role: 1, Content: content }),
+    created_at: new Date().toISOString(),
+  };
+}
+
 interface ConversationStateUpdate {
   conversation_id: string;
   working: boolean;
@@ -658,8 +694,23 @@ function ChatInterface({
   const [diffViewerCwd, setDiffViewerCwd] = useState<string | undefined>(undefined);
   const [diffCommentText, setDiffCommentText] = useState("");
   const [agentWorking, setAgentWorking] = useState(false);
+  // The LLM response being generated, until it arrives as a message
+  const [streamingBlocks, setStreamingBlocks] = useState<StreamingBlock[] | null>(null);
   const [cancelling, setCancelling] = useState(false);
   const [contextWindowSize, setContextWindowSize] = useState(0);
+  const costUSD = useMemo(
+    () =>
+      messages.reduce((total, msg) => {
+        if (!msg.usage_data) return total;
+        try {
+          const usage = JSON.parse(msg.usage_data);
+          return total + (usage.cost_usd || 0);
+        } catch {
+          return total;
+        }
+      }, 0),
+    [messages],
+  );
   const terminalURL = window.__SHELLEY_INIT__?.terminal_url || null;
   const links = window.__SHELLEY_INIT__?.links || [];
   const hostname = window.__SHELLEY_INIT__?.hostname || "localhost";
@@ -727,6 +778,7 @@ function ChatInterface({
 
   // Load messages and set up streaming
   useEffect(() => {
+    setStreamingBlocks(null);
     if (conversationId) {
       setAgentWorking(false);
       loadMessages();
@@ -927,6 +979,13 @@ function ChatInterface({
 
       try {
         const streamResponse: StreamResponse = JSON.parse(event.data);
+
+        // A response still being generated carries nothing else
+        if (streamResponse.streaming_content) {
+          setStreamingBlocks(streamResponse.streaming_content);
+          return;
+        }
+
         const incomingMessages = Array.isArray(streamResponse.messages)
           ? streamResponse.messages
           : [];
@@ -942,6 +1001,7 @@ function ChatInterface({
         // Merge new messages without losing existing ones.
         // If no new messages (e.g., only conversation/slug update or heartbeat), keep existing list.
         if (incomingMessages.length > 0) {
+          setStreamingBlocks(null);
           setMessages((prev) => {
             const byId = new Map<string, Message>();
             for (const m of prev) byId.set(m.message_id, m);
@@ -975,6 +1035,9 @@ function ChatInterface({
           // Update local state if this is for our conversation
           if (streamResponse.conversation_state.conversation_id === conversationId) {
             setAgentWorking(streamResponse.conversation_state.working);
+            if (!streamResponse.conversation_state.working) {
+              setStreamingBlocks(null);
+            }
             // Update selected model from conversation (ensures consistency across sessions)
             if (streamResponse.conversation_state.model) {
               setSelectedModel(streamResponse.conversation_state.model);
@@ -1832,6 +1895,12 @@ function ChatInterface({
           ) : (
             <div className="messages-list">
               {renderMessages()}
+              {streamingBlocks && streamingBlocks.length > 0 && conversationId && (
+                <MessageComponent
+                  key="streaming"
+                  message={streamingMessage(streamingBlocks, conversationId)}
+                />
+              )}
 
               <div ref={messagesEndRef} />
             </div>
@@ -1933,6 +2002,7 @@ function ChatInterface({
               </div>
               <ContextUsageBar
                 contextWindowSize={contextWindowSize}
+                costUSD={costUSD}
                 maxContextTokens={
                   models.find((m) => m.id === selectedModel)?.max_context_tokens || 200000
                 }
@@ -1986,6 +2056,7 @@ function ChatInterface({
               <span className="status-message status-ready">Ready on {hostname}</span>
               <ContextUsageBar
                 contextWindowSize={contextWindowSize}
+                costUSD={costUSD}
                 maxContextTokens={
                   models.find((m) => m.id === selectedModel)?.max_context_tokens || 200000
                 }
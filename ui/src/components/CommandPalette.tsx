@@ -22,6 +22,7 @@ interface CommandPaletteProps {
   onNewConversationWithCwd: (cwd: string) => void;
   onSelectConversation: (conversation: ConversationWithState) => void;
   onArchiveConversation: (conversationId: string) => void;
+  onEnableBrowser: (conversationId: string) => void;
   onOpenDiffViewer: () => void;
   onOpenModelsModal: () => void;
   onOpenNotificationsModal: () => void;
@@ -76,6 +77,7 @@ function CommandPalette({
   onNewConversationWithCwd,
   onSelectConversation,
   onArchiveConversation,
+  onEnableBrowser,
   onOpenDiffViewer,
   onOpenModelsModal,
   onOpenNotificationsModal,
@@ -358,6 +360,31 @@ function CommandPalette({
       });
     }
 
+    // Give the current conversation the browser tools
+    if (currentConversation && !currentConversation.browser_enabled) {
+      items.push({
+        id: "enable-browser",
+        type: "action",
+        title: "Enable Browser Tools",
+        subtitle: "Let the agent browse the web in this conversation",
+        icon: (
+          <svg fill="none" stroke="currentColor" viewBox="0 0 24 24" width="16" height="16">
+            <path
+              strokeLinecap="round"
+              strokeLinejoin="round"
+              strokeWidth={2}
+              d="M21 12a9 9 0 01-9 9m9-9a9 9 0 00-9-9m9 9H3m9 9a9 9 0 01-9-9m9 9c1.657 0 3-4.03 3-9s-1.343-9-3-9m0 18c-1.657 0-3-4.03-3-9s1.343-9 3-9m-9 9a9 9 0 019-9"
+            />
+          </svg>
+        ),
+        action: () => {
+          onEnableBrowser(currentConversation.conversation_id);
+          onClose();
+        },
+        keywords: ["browser", "web", "navigate", "screenshot", "chrome", "tools"],
+      });
+    }
+
     // New conversation in repo root (only when current cwd is a worktree)
     if (currentConversation?.git_worktree_root) {
       items.push({
@@ -430,6 +457,7 @@ function CommandPalette({
     onOpenModelsModal,
     onOpenNotificationsModal,
     onArchiveConversation,
+    onEnableBrowser,
     onNewConversationWithCwd,
     onClose,
     hasCwd,
@@ -325,7 +325,7 @@ function Message({ message, onOpenDiffViewer, onCommentTextChange }: MessageProp
   // Based on llm/llm.go constants (iota continues across types in same const block):
   // MessageRoleUser = 0, MessageRoleAssistant = 1,
   // ContentTypeText = 2, ContentTypeThinking = 3, ContentTypeRedactedThinking = 4,
-  // ContentTypeToolUse = 5, ContentTypeToolResult = 6
+  // ContentTypeToolUse = 5, ContentTypeToolResult = 6, ContentTypeDocument = 7, ContentTypeImage = 8
   const getContentType = (type: number): string => {
     switch (type) {
       case 0:
@@ -342,6 +342,10 @@ function Message({ message, onOpenDiffViewer, onCommentTextChange }: MessageProp
         return "tool_use";
       case 6:
         return "tool_result";
+      case 7:
+        return "document";
+      case 8:
+        return "image";
       default:
         return "unknown";
     }
@@ -806,6 +810,21 @@ function Message({ message, onOpenDiffViewer, onCommentTextChange }: MessageProp
       }
       case "redacted_thinking":
         return <div className="text-tertiary italic text-sm">[Thinking content hidden]</div>;
+      case "image":
+        return (
+          <img
+            src={`data:${content.MediaType};base64,${content.Data}`}
+            alt="Image"
+            className="rounded border"
+            style={{ maxWidth: "100%", height: "auto", maxHeight: "300px" }}
+          />
+        );
+      case "document":
+        return (
+          <div className="text-tertiary italic text-sm">
+            [Document: {content.Text || content.MediaType}]
+          </div>
+        );
       case "thinking": {
         const thinkingText = content.Thinking || content.Text || "";
         if (!thinkingText) return null;
@@ -13,13 +13,14 @@ interface ModelsModalProps {
   onModelsChanged?: () => void;
 }
 
-type ProviderType = "anthropic" | "openai" | "openai-responses" | "gemini";
+type ProviderType = "anthropic" | "openai" | "openai-responses" | "gemini" | "azure-openai";
 
 const DEFAULT_ENDPOINTS: Record<ProviderType, string> = {
   anthropic: "https://api.anthropic.com/v1/messages",
   openai: "https://api.openai.com/v1",
   "openai-responses": "https://api.openai.com/v1",
   gemini: "https://generativelanguage.googleapis.com/v1beta",
+  "azure-openai": "https://YOUR-RESOURCE.openai.azure.com",
 };
 
 const PROVIDER_LABELS: Record<ProviderType, string> = {
@@ -27,6 +28,7 @@ const PROVIDER_LABELS: Record<ProviderType, string> = {
   openai: "OpenAI (Chat API)",
   "openai-responses": "OpenAI (Responses API)",
   gemini: "Google Gemini",
+  "azure-openai": "Azure OpenAI",
 };
 
 const DEFAULT_MODELS: Record<ProviderType, { name: string; model_name: string }[]> = {
@@ -41,6 +43,8 @@ const DEFAULT_MODELS: Record<ProviderType, { name: string; model_name: string }[
     { name: "Gemini 3 Pro", model_name: "gemini-3-pro-preview" },
     { name: "Gemini 3 Flash", model_name: "gemini-3-flash-preview" },
   ],
+  // Azure models are addressed by deployment names, which each resource chooses
+  "azure-openai": [],
 };
 
 // Built-in model info from init data
@@ -123,6 +127,8 @@ function ModelsModal({ isOpen, onClose, onModelsChanged }: ModelsModalProps) {
       ...prev,
       provider_type: provider,
       endpoint: prev.endpoint_custom ? prev.endpoint : DEFAULT_ENDPOINTS[provider],
+      // Each Azure resource has its own endpoint, so the default is only a template to edit
+      endpoint_custom: prev.endpoint_custom || provider === "azure-openai",
     }));
   };
 
@@ -301,18 +307,18 @@ function ModelsModal({ isOpen, onClose, onModelsChanged }: ModelsModalProps) {
             <div className="form-group">
               <label>Provider / API Format</label>
               <div className="provider-buttons">
-                {(["anthropic", "openai", "openai-responses", "gemini"] as ProviderType[]).map(
-                  (p) => (
-                    <button
-                      key={p}
-                      type="button"
-                      className={`provider-btn ${form.provider_type === p ? "selected" : ""}`}
-                      onClick={() => handleProviderChange(p)}
-                    >
-                      {PROVIDER_LABELS[p]}
-                    </button>
-                  ),
-                )}
+                {(
+                  ["anthropic", "openai", "openai-responses", "gemini", "azure-openai"] as ProviderType[]
+                ).map((p) => (
+                  <button
+                    key={p}
+                    type="button"
+                    className={`provider-btn ${form.provider_type === p ? "selected" : ""}`}
+                    onClick={() => handleProviderChange(p)}
+                  >
+                    {PROVIDER_LABELS[p]}
+                  </button>
+                ))}
               </div>
             </div>
 
@@ -367,7 +373,11 @@ function ModelsModal({ isOpen, onClose, onModelsChanged }: ModelsModalProps) {
                 type="text"
                 value={form.model_name}
                 onChange={(e) => setForm((prev) => ({ ...prev, model_name: e.target.value }))}
-                placeholder="Model name (e.g., claude-sonnet-4-5)"
+                placeholder={
+                  form.provider_type === "azure-openai"
+                    ? "Deployment name"
+                    : "Model name (e.g., claude-sonnet-4-5)"
+                }
                 className="form-input"
               />
             </div>
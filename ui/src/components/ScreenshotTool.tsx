@@ -65,11 +65,16 @@ function ScreenshotTool({
   // Use display data passed as prop (from tool_result Content.Display)
   const displayData = display;
 
-  // Construct image URL
+  // Construct image URL, and the URL of a smaller thumbnail to show inline
   let imageUrl: string | undefined = undefined;
+  let thumbnailUrl: string | undefined = undefined;
   if (displayData && typeof displayData === "object" && displayData !== null) {
     const url =
       "url" in displayData && typeof displayData.url === "string" ? displayData.url : undefined;
+    thumbnailUrl =
+      "thumbnail_url" in displayData && typeof displayData.thumbnail_url === "string"
+        ? displayData.thumbnail_url
+        : undefined;
     const path =
       "path" in displayData && typeof displayData.path === "string" ? displayData.path : undefined;
     const id =
@@ -138,7 +143,7 @@ function ScreenshotTool({
               <div className="screenshot-tool-image-container">
                 <a href={imageUrl} target="_blank" rel="noopener noreferrer">
                   <img
-                    src={imageUrl}
+                    src={thumbnailUrl || imageUrl}
                     alt={`Screenshot: ${filename}`}
                     style={{ maxWidth: "100%", height: "auto" }}
                   />
@@ -13,6 +13,7 @@ export interface Conversation {
   archived: boolean;
   parent_conversation_id: string | null;
   model: string | null;
+  browser_enabled: boolean;
 }
 
 export interface Usage {
@@ -70,6 +71,7 @@ export interface ConversationWithStateForTS {
   archived: boolean;
   parent_conversation_id: string | null;
   model: string | null;
+  browser_enabled: boolean;
   working: boolean;
   git_repo_root?: string;
   git_worktree_root?: string;
@@ -141,6 +141,16 @@ class ApiService {
     }
   }
 
+  async enableBrowser(conversationId: string): Promise<Conversation> {
+    const response = await fetch(`${this.baseUrl}/conversation/${conversationId}/browser`, {
+      method: "POST",
+    });
+    if (!response.ok) {
+      throw new Error(`Failed to enable browser tools: ${response.statusText}`);
+    }
+    return response.json();
+  }
+
   async validateCwd(path: string): Promise<{ valid: boolean; error?: string }> {
     const response = await fetch(`${this.baseUrl}/validate-cwd?path=${encodeURIComponent(path)}`);
     if (!response.ok) {
@@ -365,7 +375,7 @@ export const api = new ApiService();
 export interface CustomModel {
   model_id: string;
   display_name: string;
-  provider_type: "anthropic" | "openai" | "openai-responses" | "gemini";
+  provider_type: "anthropic" | "openai" | "openai-responses" | "gemini" | "azure-openai";
   endpoint: string;
   api_key: string;
   model_name: string;
@@ -375,7 +385,7 @@ export interface CustomModel {
 
 export interface CreateCustomModelRequest {
   display_name: string;
-  provider_type: "anthropic" | "openai" | "openai-responses" | "gemini";
+  provider_type: "anthropic" | "openai" | "openai-responses" | "gemini" | "azure-openai";
   endpoint: string;
   api_key: string;
   model_name: string;
@@ -385,7 +395,7 @@ export interface CreateCustomModelRequest {
 
 export interface TestCustomModelRequest {
   model_id?: string; // If provided with empty api_key, use stored key
-  provider_type: "anthropic" | "openai" | "openai-responses" | "gemini";
+  provider_type: "anthropic" | "openai" | "openai-responses" | "gemini" | "azure-openai";
   endpoint: string;
   api_key: string;
   model_name: string;
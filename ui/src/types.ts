@@ -75,6 +75,17 @@ export interface StreamResponse extends Omit<StreamResponseForTS, "messages"> {
   conversation_list_update?: ConversationListUpdate;
   heartbeat?: boolean;
   notification_event?: NotificationEvent;
+  streaming_content?: StreamingBlock[];
+}
+
+// StreamingBlock is a content block of an LLM response still being generated (llm.StreamDelta)
+export interface StreamingBlock {
+  index: number;
+  type: number; // same values as LLMContent.Type
+  id?: string;
+  tool_name?: string;
+  text?: string;
+  tool_input?: string; // possibly incomplete JSON
 }
 
 // Link represents a custom link that can be added to the UI